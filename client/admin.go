@@ -0,0 +1,319 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	dos "github.com/mrowaha/dos/client"
+)
+
+// admin subcommands
+var adminCommands = []command{
+	{"nodes", "admin nodes", "list the registered datanodes", runAdminNodes},
+	{"node", "admin node <name>", "show a datanode, its objects and its command backlog", runAdminNode},
+	{"ghosts", "admin ghosts", "list the ghosts waiting to take over a failed datanode", runAdminGhosts},
+	{"locate", "admin locate <object>", "show the datanodes holding an object", runAdminLocate},
+	{"summary", "admin summary", "summarize the cluster", runAdminSummary},
+	{"decommission", "admin decommission [-wait] <name>", "drain a datanode and let it leave the cluster", runAdminDecommission},
+	{"maintenance", "admin maintenance start [-window d]|end <name>", "take a datanode out of placement for a while", runAdminMaintenance},
+	{"rebalance", "admin rebalance [-dry-run]", "move replicas to even out datanode sizes", runAdminRebalance},
+}
+
+func runAdmin(ctx context.Context, client *dos.DosClient, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	for _, cmd := range adminCommands {
+		if cmd.name == args[0] {
+			return cmd.run(ctx, client, args[1:])
+		}
+	}
+	return errUsage
+}
+
+func formatTime(t time.Time) string {
+	return t.Local().Format(time.DateTime)
+}
+
+func runAdminNodes(ctx context.Context, client *dos.DosClient, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	nodes, err := client.Admin().ListNodes(ctx)
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, 0, len(nodes))
+	for _, node := range nodes {
+		rows = append(rows, []string{
+			node.Name,
+			node.NodeId,
+			node.Leaser,
+			node.State,
+			formatLabel(node.Zone),
+			formatLabel(node.Rack),
+			strconv.FormatFloat(float64(node.Size), 'f', -1, 32),
+			strconv.Itoa(node.Objects),
+			formatTime(node.Joined),
+		})
+	}
+	return render(nodes, []string{"NAME", "NODE ID", "LEASER", "STATE", "ZONE", "RACK", "SIZE", "OBJECTS", "JOINED"}, rows)
+}
+
+func runAdminNode(ctx context.Context, client *dos.DosClient, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	node, err := client.Admin().NodeInfo(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return render(node, []string{"FIELD", "VALUE"}, [][]string{
+		{"name", node.Name},
+		{"node id", node.NodeId},
+		{"leaser", node.Leaser},
+		{"state", node.State},
+		{"drain", formatDrain(node.Drain)},
+		{"maintenance", formatMaintenance(node.Maintenance)},
+		{"size", strconv.FormatFloat(float64(node.Size), 'f', -1, 32)},
+		{"capacity", strconv.FormatFloat(float64(node.Capacity), 'f', -1, 32)},
+		{"zone", formatLabel(node.Zone)},
+		{"rack", formatLabel(node.Rack)},
+		{"labels", formatLabels(node.Labels)},
+		{"fence", strconv.FormatUint(node.Fence, 10)},
+		{"joined", formatTime(node.Joined)},
+		{"queued", strconv.Itoa(node.Queued)},
+		{"awaiting", strconv.Itoa(node.Awaiting)},
+		{"objects", strings.Join(node.ObjectNames, ",")},
+	})
+}
+
+func runAdminGhosts(ctx context.Context, client *dos.DosClient, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	ghosts, err := client.Admin().ListGhosts(ctx)
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, 0, len(ghosts))
+	for _, ghost := range ghosts {
+		rows = append(rows, []string{ghost.Name, formatTime(ghost.Since)})
+	}
+	return render(ghosts, []string{"NAME", "SINCE"}, rows)
+}
+
+func runAdminLocate(ctx context.Context, client *dos.DosClient, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	location, err := client.Admin().ObjectLocations(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, 0, len(location.Replicas))
+	for _, replica := range location.Replicas {
+		rows = append(rows, []string{location.Name, replica.Node, replica.Leaser})
+	}
+	if err := render(location, []string{"OBJECT", "NODE", "LEASER"}, rows); err != nil {
+		return err
+	}
+	if output == "json" {
+		return nil
+	}
+	if len(location.Replicas) < location.Replication {
+		fmt.Printf("%s has %d of %d replicas\n", location.Name, len(location.Replicas), location.Replication)
+	}
+	if location.Constraints != nil {
+		fmt.Printf("%s requires %s, prefers %s\n", location.Name, formatLabels(location.Constraints.Required), formatLabels(location.Constraints.Preferred))
+	}
+	return nil
+}
+
+func runAdminSummary(ctx context.Context, client *dos.DosClient, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	summary, err := client.Admin().ClusterSummary(ctx)
+	if err != nil {
+		return err
+	}
+	return render(summary, []string{"FIELD", "VALUE"}, [][]string{
+		{"cluster", summary.ClusterId},
+		{"lamport", summary.Lamport.String()},
+		{"nodes", strconv.Itoa(summary.Nodes)},
+		{"ghosts", strconv.Itoa(summary.Ghosts)},
+		{"objects", strconv.Itoa(summary.Objects)},
+		{"under replicated", strconv.Itoa(summary.UnderReplicated)},
+		{"snapshots", strconv.Itoa(summary.Snapshots)},
+		{"replication", strconv.Itoa(summary.Replication)},
+		{"tolerance", strconv.Itoa(summary.Tolerance)},
+		{"decommissioning", strconv.Itoa(summary.Decommissioning)},
+		{"maintenance", strconv.Itoa(summary.Maintenance)},
+		{"rebalancing", strconv.FormatBool(summary.Rebalancing)},
+	})
+}
+
+func formatDrain(drain *dos.DrainProgress) string {
+	if drain == nil {
+		return "-"
+	}
+	progress := fmt.Sprintf("%d/%d moved, %d failed", drain.Moved, drain.Total, drain.Failed)
+	if drain.Running {
+		progress += ", running"
+	}
+	if len(drain.Error) != 0 {
+		progress += ", last error: " + drain.Error
+	}
+	return progress
+}
+
+func runAdminDecommission(ctx context.Context, client *dos.DosClient, args []string) error {
+	var wait bool
+	args, err := parse("decommission", args, func(fs *flag.FlagSet) {
+		fs.BoolVar(&wait, "wait", false, "wait until the node is drained and has left")
+	})
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	node, err := client.Admin().Decommission(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	if !wait {
+		return render(node, []string{"NAME", "STATE", "DRAIN"}, [][]string{{node.Name, node.State, formatDrain(node.Drain)}})
+	}
+
+	last := ""
+	for node.State != "decommissioned" {
+		if node.Drain != nil && !node.Drain.Running && node.Drain.Failed != 0 {
+			return fmt.Errorf("drain of %s stopped with %d objects left behind: %s", node.Name, node.Drain.Failed, node.Drain.Error)
+		}
+		if progress := formatDrain(node.Drain); progress != last && output != "json" {
+			fmt.Printf("%s: %s\n", node.Name, progress)
+			last = progress
+		}
+
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		detail, err := client.Admin().NodeInfo(ctx, args[0])
+		if errors.Is(err, dos.ErrNotFound) {
+			// the node deregistered after its drain
+			node.State = "decommissioned"
+			break
+		}
+		if err != nil {
+			return err
+		}
+		node = detail.NodeStatus
+	}
+	return render(node, []string{"NAME", "STATE", "DRAIN"}, [][]string{{node.Name, node.State, formatDrain(node.Drain)}})
+}
+
+func formatLabel(label string) string {
+	if len(label) == 0 {
+		return "-"
+	}
+	return label
+}
+
+// formatLabels lists labels as sorted key=value pairs
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "-"
+	}
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func formatMaintenance(maintenance *dos.MaintenanceStatus) string {
+	if maintenance == nil {
+		return "-"
+	}
+	status := "until " + formatTime(maintenance.Until)
+	if maintenance.Away {
+		status += fmt.Sprintf(", away with %d commands buffered", maintenance.Buffered)
+	}
+	if maintenance.Overflowed {
+		status += ", missed too much to catch up"
+	}
+	return status
+}
+
+func runAdminMaintenance(ctx context.Context, client *dos.DosClient, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	var window time.Duration
+	action := args[0]
+	args, err := parse("maintenance "+action, args[1:], func(fs *flag.FlagSet) {
+		if action == "start" {
+			fs.DurationVar(&window, "window", 0, "length of the window, the namenode's default when unset")
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	var node dos.NodeStatus
+	switch action {
+	case "start":
+		node, err = client.Admin().StartMaintenance(ctx, args[0], window)
+	case "end":
+		node, err = client.Admin().EndMaintenance(ctx, args[0])
+	default:
+		return errUsage
+	}
+	if err != nil {
+		return err
+	}
+	return render(node, []string{"NAME", "STATE", "MAINTENANCE"}, [][]string{{node.Name, node.State, formatMaintenance(node.Maintenance)}})
+}
+
+func runAdminRebalance(ctx context.Context, client *dos.DosClient, args []string) error {
+	var dryRun bool
+	args, err := parse("rebalance", args, func(fs *flag.FlagSet) {
+		fs.BoolVar(&dryRun, "dry-run", false, "show the moves without running them")
+	})
+	if err != nil {
+		return err
+	}
+	if len(args) != 0 {
+		return errUsage
+	}
+	plan, err := client.Admin().Rebalance(ctx, dryRun)
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, 0, len(plan.Moves))
+	for _, move := range plan.Moves {
+		rows = append(rows, []string{move.Object, move.From, move.To, strconv.Itoa(move.Size)})
+	}
+	if err := render(plan, []string{"OBJECT", "FROM", "TO", "SIZE"}, rows); err != nil {
+		return err
+	}
+	if len(plan.Moves) == 0 && output != "json" {
+		fmt.Println("datanodes are balanced")
+	}
+	return nil
+}
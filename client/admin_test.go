@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mrowaha/dos/api"
+	dos "github.com/mrowaha/dos/client"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// stubAdmin is an admin service of one datanode that leaves once it is asked about after its decommission
+type stubAdmin struct {
+	api.UnimplementedAdminServiceServer
+	lock    sync.Mutex
+	node    *api.NodeSummary
+	left    bool
+	windows []time.Duration
+}
+
+func (s *stubAdmin) NodeInfo(ctx context.Context, req *api.NodeInfoReq) (*api.NodeInfoRes, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if req.Name != s.node.Name || s.left {
+		return nil, status.Error(codes.NotFound, "datanode not found")
+	}
+	if s.node.State == api.NodeSummary_DECOMMISSIONING && s.node.Drain.Running {
+		s.left = true
+	}
+	return &api.NodeInfoRes{Node: s.node, Objects: []string{"a", "b"}}, nil
+}
+
+func (s *stubAdmin) ObjectLocations(ctx context.Context, req *api.ObjectLocationsReq) (*api.ObjectLocationsRes, error) {
+	return &api.ObjectLocationsRes{
+		Name:        req.Name,
+		Replicas:    []*api.ObjectReplica{{Node: s.node.Name, Leaser: s.node.Leaser}},
+		Replication: 2,
+		Constraints: &api.PlacementConstraints{Required: map[string]string{"disk": "ssd"}},
+	}, nil
+}
+
+func (s *stubAdmin) Decommission(ctx context.Context, req *api.DecommissionReq) (*api.DecommissionRes, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.node.State = api.NodeSummary_DECOMMISSIONING
+	return &api.DecommissionRes{Node: s.node}, nil
+}
+
+func (s *stubAdmin) StartMaintenance(ctx context.Context, req *api.StartMaintenanceReq) (*api.StartMaintenanceRes, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.windows = append(s.windows, req.Window.AsDuration())
+	s.node.State = api.NodeSummary_MAINTENANCE
+	s.node.Maintenance = &api.Maintenance{Until: timestamppb.New(time.Now().Add(time.Hour))}
+	return &api.StartMaintenanceRes{Node: s.node}, nil
+}
+
+// newStubAdmin serves an admin service of node and returns a client of it
+func newStubAdmin(t *testing.T, node *api.NodeSummary) (*dos.DosClient, *stubAdmin) {
+	t.Helper()
+	admin := &stubAdmin{node: node}
+	return serve(t, func(server *grpc.Server) { api.RegisterAdminServiceServer(server, admin) }), admin
+}
+
+func TestFormatAdmin(t *testing.T) {
+	until := time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "no label", got: formatLabel(""), want: "-"},
+		{name: "no labels", got: formatLabels(nil), want: "-"},
+		{name: "sorted labels", got: formatLabels(map[string]string{"zone": "z1", "disk": "ssd"}), want: "disk=ssd,zone=z1"},
+		{name: "no drain", got: formatDrain(nil), want: "-"},
+		{name: "running drain", got: formatDrain(&dos.DrainProgress{Total: 3, Moved: 1, Running: true}), want: "1/3 moved, 0 failed, running"},
+		{
+			name: "failed drain",
+			got:  formatDrain(&dos.DrainProgress{Total: 3, Moved: 2, Failed: 1, Error: "no target"}),
+			want: "2/3 moved, 1 failed, last error: no target",
+		},
+		{name: "no maintenance", got: formatMaintenance(nil), want: "-"},
+		{
+			name: "away in maintenance",
+			got:  formatMaintenance(&dos.MaintenanceStatus{Until: until, Away: true, Buffered: 4, Overflowed: true}),
+			want: "until 2024-01-01 12:00:00, away with 4 commands buffered, missed too much to catch up",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdminArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "nodes with an argument", args: []string{"nodes", "a"}},
+		{name: "node without a name", args: []string{"node"}},
+		{name: "locate without an object", args: []string{"locate"}},
+		{name: "summary with an argument", args: []string{"summary", "a"}},
+		{name: "decommission without a name", args: []string{"decommission", "-wait"}},
+		{name: "maintenance without an action", args: []string{"maintenance"}},
+		{name: "maintenance with an unknown action", args: []string{"maintenance", "pause", "a"}},
+		{name: "window of a maintenance end", args: []string{"maintenance", "end", "-window", "1h", "a"}},
+		{name: "rebalance with an argument", args: []string{"rebalance", "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := run(t, runAdmin, nil, "", tt.args...); !errors.Is(err, errUsage) {
+				t.Errorf("err = %v, want %v", err, errUsage)
+			}
+		})
+	}
+}
+
+func TestAdminNode(t *testing.T) {
+	client, _ := newStubAdmin(t, &api.NodeSummary{Name: "node-0", Labels: map[string]string{"disk": "ssd"}, Joined: timestamppb.Now()})
+
+	out, err := run(t, runAdmin, client, "", "node", "node-0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"labels       disk=ssd", "objects      a,b"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output %q lacks %q", out, want)
+		}
+	}
+	if _, err := run(t, runAdmin, client, "", "node", "node-1"); !errors.Is(err, dos.ErrNotFound) {
+		t.Errorf("err = %v, want %v", err, dos.ErrNotFound)
+	}
+}
+
+func TestAdminLocate(t *testing.T) {
+	client, _ := newStubAdmin(t, &api.NodeSummary{Name: "node-0", Leaser: "tcp://node-0:5555"})
+	tests := []struct {
+		format string
+		want   []string
+		absent string
+	}{
+		{format: "table", want: []string{"object  node-0  tcp://node-0:5555", "object has 1 of 2 replicas", "object requires disk=ssd, prefers -"}},
+		{format: "json", want: []string{`"replication":2`}, absent: "of 2 replicas"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			withOutput(t, tt.format)
+			out, err := run(t, runAdmin, client, "", "locate", "object")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output %q lacks %q", out, want)
+				}
+			}
+			if tt.absent != "" && strings.Contains(out, tt.absent) {
+				t.Errorf("output %q has %q", out, tt.absent)
+			}
+		})
+	}
+}
+
+func TestAdminDecommission(t *testing.T) {
+	tests := []struct {
+		name  string
+		drain *api.DrainProgress
+		want  string
+		err   string
+	}{
+		{name: "drained", drain: &api.DrainProgress{Total: 2, Running: true}, want: "decommissioned"},
+		{name: "objects left behind", drain: &api.DrainProgress{Total: 2, Moved: 1, Failed: 1, Error: "no target"}, err: "1 objects left behind"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newStubAdmin(t, &api.NodeSummary{Name: "node-0", Drain: tt.drain})
+			out, err := run(t, runAdmin, client, "", "decommission", "-wait", "node-0")
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("err = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("output %q lacks %q", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdminMaintenance(t *testing.T) {
+	client, admin := newStubAdmin(t, &api.NodeSummary{Name: "node-0"})
+	for _, args := range [][]string{{"maintenance", "start", "node-0"}, {"maintenance", "start", "-window", "2h", "node-0"}} {
+		out, err := run(t, runAdmin, client, "", args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out, "maintenance  until ") {
+			t.Errorf("output %q lacks the window", out)
+		}
+	}
+	// an unset window is left for the namenode to default
+	if len(admin.windows) != 2 || admin.windows[0] != 0 || admin.windows[1] != 2*time.Hour {
+		t.Errorf("windows = %v", admin.windows)
+	}
+}
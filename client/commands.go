@@ -0,0 +1,398 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	dos "github.com/mrowaha/dos/client"
+)
+
+// objects are named dos:<name> where cp also takes local files
+const objectScheme = "dos:"
+
+// parse parses the flags of a command and returns its arguments
+func parse(name string, args []string, define func(fs *flag.FlagSet)) ([]string, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	if define != nil {
+		define(fs)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, errUsage
+	}
+	return fs.Args(), nil
+}
+
+// readLocal reads a file, - is stdin
+func readLocal(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// writeLocal writes a file, - is stdout
+func writeLocal(path string, data []byte) error {
+	if path == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0666)
+}
+
+func runPut(ctx context.Context, client *dos.DosClient, args []string) error {
+	opts := dos.CreateOptions{
+		Constraints: dos.PlacementConstraints{
+			Required:  make(map[string]string),
+			Preferred: make(map[string]string),
+		},
+	}
+	create := func(ctx context.Context, name string, data []byte) error {
+		return client.CreateWith(ctx, name, data, opts)
+	}
+	return mutate(ctx, "put", args, create, "created", func(fs *flag.FlagSet) {
+		fs.Func("require", "key=value label every node holding the object must carry, may be repeated", labelFlag(opts.Constraints.Required))
+		fs.Func("prefer", "key=value label nodes holding the object should carry, may be repeated", labelFlag(opts.Constraints.Preferred))
+		fs.IntVar(&opts.Replication, "replication", 0, "replicas the object keeps, 0 for the cluster's replication")
+	})
+}
+
+func runUpdate(ctx context.Context, client *dos.DosClient, args []string) error {
+	return mutate(ctx, "update", args, client.Update, "updated", nil)
+}
+
+// labelFlag parses key=value flags into labels
+func labelFlag(labels map[string]string) func(string) error {
+	return func(label string) error {
+		key, value, ok := strings.Cut(label, "=")
+		if !ok || len(key) == 0 {
+			return fmt.Errorf("label %q is not key=value", label)
+		}
+		labels[key] = value
+		return nil
+	}
+}
+
+// mutate applies data from -d, a file or stdin to the named object
+// define adds the flags of the command besides -d
+func mutate(ctx context.Context, cmd string, args []string, apply func(context.Context, string, []byte) error, status string, define func(fs *flag.FlagSet)) error {
+	var data string
+	inline := false
+	args, err := parse(cmd, args, func(fs *flag.FlagSet) {
+		fs.Func("d", "data of the object, instead of a file or stdin", func(value string) error {
+			data = value
+			inline = true
+			return nil
+		})
+		if define != nil {
+			define(fs)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if len(args) < 1 || len(args) > 2 || (inline && len(args) == 2) {
+		return errUsage
+	}
+
+	content := []byte(data)
+	if !inline {
+		src := "-"
+		if len(args) == 2 {
+			src = args[1]
+		}
+		if content, err = readLocal(src); err != nil {
+			return err
+		}
+	}
+	if err := apply(ctx, args[0], content); err != nil {
+		return err
+	}
+	return render(objectResult{Name: args[0], Status: status}, nil, nil)
+}
+
+func runGet(ctx context.Context, client *dos.DosClient, args []string) error {
+	var snapshot string
+	args, err := parse("get", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&snapshot, "snapshot", "", "read the object as of this snapshot")
+	})
+	if err != nil {
+		return err
+	}
+	if len(args) < 1 || len(args) > 2 {
+		return errUsage
+	}
+
+	data, err := client.Get(ctx, args[0], snapshot)
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 || args[1] == "-" {
+		// the data is the output
+		return writeLocal("-", data)
+	}
+	if err := writeLocal(args[1], data); err != nil {
+		return err
+	}
+	return render(objectResult{Name: args[0], Status: "read"}, nil, nil)
+}
+
+func runRm(ctx context.Context, client *dos.DosClient, args []string) error {
+	args, err := parse("rm", args, nil)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	// like rm, one missing object does not keep the others from being deleted
+	var errs []error
+	for _, name := range args {
+		if err := client.Delete(ctx, name); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := render(objectResult{Name: name, Status: "deleted"}, nil, nil); err != nil {
+			return err
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type replicationResult struct {
+	Name        string `json:"name"`
+	Replicas    int    `json:"replicas"`
+	Replication int    `json:"replication"`
+}
+
+func runSetrep(ctx context.Context, client *dos.DosClient, args []string) error {
+	var wait bool
+	args, err := parse("setrep", args, func(fs *flag.FlagSet) {
+		fs.BoolVar(&wait, "wait", false, "wait until the object has as many replicas")
+	})
+	if err != nil {
+		return err
+	}
+	if len(args) != 2 {
+		return errUsage
+	}
+	replication, err := strconv.Atoi(args[1])
+	if err != nil {
+		return errUsage
+	}
+
+	replicas, err := client.SetReplication(ctx, args[0], replication)
+	if err != nil {
+		return err
+	}
+	// the replicas are added or removed by the namenode in the background
+	for wait && replicas != replication {
+		if output != "json" {
+			fmt.Printf("%s: %d of %d replicas\n", args[0], replicas, replication)
+		}
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		location, err := client.Admin().ObjectLocations(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		if location.Replication != replication {
+			return fmt.Errorf("replication of %s was changed to %d meanwhile", args[0], location.Replication)
+		}
+		replicas = len(location.Replicas)
+	}
+	result := replicationResult{Name: args[0], Replicas: replicas, Replication: replication}
+	return render(result, []string{"NAME", "REPLICAS", "REPLICATION"}, [][]string{
+		{result.Name, strconv.Itoa(result.Replicas), strconv.Itoa(result.Replication)},
+	})
+}
+
+func runLs(ctx context.Context, client *dos.DosClient, args []string) error {
+	args, err := parse("ls", args, nil)
+	if err != nil {
+		return err
+	}
+	if len(args) > 1 {
+		return errUsage
+	}
+	prefix := ""
+	if len(args) == 1 {
+		prefix = args[0]
+	}
+
+	names, err := client.List(ctx, prefix)
+	if err != nil {
+		return err
+	}
+	if names == nil {
+		// an empty listing is an empty list in json
+		names = []string{}
+	}
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, []string{name})
+	}
+	return render(names, []string{"NAME"}, rows)
+}
+
+func runStat(ctx context.Context, client *dos.DosClient, args []string) error {
+	args, err := parse("stat", args, nil)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	fsys := client.FS(ctx)
+	results := make([]statResult, 0, len(args))
+	rows := make([][]string, 0, len(args))
+	var errs []error
+	for _, name := range args {
+		info, err := fsys.Stat(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		result := statResult{Name: name, Type: "object", Size: info.Size()}
+		if info.IsDir() {
+			result.Type = "dir"
+		}
+		results = append(results, result)
+		rows = append(rows, []string{result.Name, result.Type, strconv.FormatInt(result.Size, 10)})
+	}
+	if err := render(results, []string{"NAME", "TYPE", "SIZE"}, rows); err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+func runWatch(ctx context.Context, client *dos.DosClient, args []string) error {
+	args, err := parse("watch", args, nil)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	events, cancel, err := client.Subscribe(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	for event := range events {
+		if event.Err != nil {
+			return event.Err
+		}
+		if output == "json" {
+			err = render(eventResult{
+				Object:   event.Object,
+				Kind:     event.Kind.String(),
+				Sequence: event.Sequence,
+				Data:     string(event.Data),
+			}, nil, nil)
+		} else {
+			// events are printed as they come, a table would wait for the watch to end
+			_, err = fmt.Printf("%d\t%s\t%s\t%s\n", event.Sequence, event.Kind, event.Object, event.Data)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// the watch ends with an interrupt
+	return nil
+}
+
+func runCp(ctx context.Context, client *dos.DosClient, args []string) error {
+	args, err := parse("cp", args, nil)
+	if err != nil {
+		return err
+	}
+	if len(args) != 2 {
+		return errUsage
+	}
+	src, dst := args[0], args[1]
+	if !strings.HasPrefix(src, objectScheme) && !strings.HasPrefix(dst, objectScheme) {
+		return fmt.Errorf("%w: one of %s and %s must be an object, name it %s<name>", dos.ErrInvalidArgument, src, dst, objectScheme)
+	}
+
+	var r io.ReadCloser
+	switch {
+	case strings.HasPrefix(src, objectScheme):
+		r, err = client.OpenReader(ctx, strings.TrimPrefix(src, objectScheme))
+	case src == "-":
+		r = io.NopCloser(os.Stdin)
+	default:
+		r, err = os.Open(src)
+	}
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	var w io.WriteCloser
+	switch {
+	case strings.HasPrefix(dst, objectScheme):
+		w = client.OpenWriter(ctx, strings.TrimPrefix(dst, objectScheme))
+	case dst == "-":
+		w = os.Stdout
+	default:
+		w, err = os.Create(dst)
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(w, r); err != nil {
+		if w != os.Stdout {
+			w.Close()
+		}
+		return err
+	}
+	if w == os.Stdout {
+		return nil
+	}
+	// closing an object writer creates the object
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return render(objectResult{Name: dst, Status: "copied"}, nil, nil)
+}
+
+func runSnapshot(ctx context.Context, client *dos.DosClient, args []string) error {
+	args, err := parse("snapshot", args, nil)
+	if err != nil {
+		return err
+	}
+	if len(args) != 2 {
+		return errUsage
+	}
+
+	name := args[1]
+	switch args[0] {
+	case "create":
+		lamport, err := client.CreateSnapshot(ctx, name)
+		if err != nil {
+			return err
+		}
+		return render(snapshotResult{Name: name, Status: "created", Lamport: lamport.String()},
+			[]string{"SNAPSHOT", "LAMPORT"}, [][]string{{name, lamport.String()}})
+	case "rm":
+		if err := client.DeleteSnapshot(ctx, name); err != nil {
+			return err
+		}
+		return render(snapshotResult{Name: name, Status: "deleted"}, nil, nil)
+	}
+	return errUsage
+}
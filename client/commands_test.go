@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dos "github.com/mrowaha/dos/client"
+)
+
+func TestCommandArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  func(ctx context.Context, client *dos.DosClient, args []string) error
+		args []string
+		want error
+	}{
+		{name: "put without a name", cmd: runPut, want: errUsage},
+		{name: "put with too many arguments", cmd: runPut, args: []string{"a", "file", "more"}, want: errUsage},
+		{name: "put with data and a file", cmd: runPut, args: []string{"-d", "data", "a", "file"}, want: errUsage},
+		{name: "put with an unknown flag", cmd: runPut, args: []string{"-bogus", "a"}, want: errUsage},
+		{name: "put with a label that is not key=value", cmd: runPut, args: []string{"-require", "ssd", "a"}, want: errUsage},
+		{name: "update without a name", cmd: runUpdate, want: errUsage},
+		{name: "get without a name", cmd: runGet, want: errUsage},
+		{name: "get with too many arguments", cmd: runGet, args: []string{"a", "file", "more"}, want: errUsage},
+		{name: "rm without a name", cmd: runRm, want: errUsage},
+		{name: "setrep without a replication", cmd: runSetrep, args: []string{"a"}, want: errUsage},
+		{name: "setrep with a replication that is not a number", cmd: runSetrep, args: []string{"a", "three"}, want: errUsage},
+		{name: "ls with two prefixes", cmd: runLs, args: []string{"a", "b"}, want: errUsage},
+		{name: "stat without a name", cmd: runStat, want: errUsage},
+		{name: "watch two objects", cmd: runWatch, args: []string{"a", "b"}, want: errUsage},
+		{name: "cp without a destination", cmd: runCp, args: []string{"dos:a"}, want: errUsage},
+		{name: "cp between local files", cmd: runCp, args: []string{"a", "b"}, want: dos.ErrInvalidArgument},
+		{name: "snapshot without a name", cmd: runSnapshot, args: []string{"create"}, want: errUsage},
+		{name: "snapshot with an unknown action", cmd: runSnapshot, args: []string{"list", "s"}, want: errUsage},
+		{name: "admin without a command", cmd: runAdmin, want: errUsage},
+		{name: "admin with an unknown command", cmd: runAdmin, args: []string{"reboot"}, want: errUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the arguments are refused before the client is used
+			if _, err := run(t, tt.cmd, nil, "", tt.args...); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPut(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, []byte("from a file"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		args   []string
+		stdin  string
+		output string
+		want   string // data of the object
+		stdout string
+	}{
+		{name: "inline", args: []string{"-d", "inline", "a"}, output: "table", want: "inline"},
+		{name: "from stdin", args: []string{"a"}, stdin: "from stdin", output: "table", want: "from stdin"},
+		{name: "from stdin named", args: []string{"a", "-"}, stdin: "from stdin", output: "table", want: "from stdin"},
+		{name: "from a file", args: []string{"a", file}, output: "json", want: "from a file", stdout: `{"name":"a","status":"created"}` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withOutput(t, tt.output)
+			client, names := newStubClient(t)
+			stdout, err := run(t, runPut, client, tt.stdin, tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(names.objects["a"]); got != tt.want {
+				t.Errorf("object = %q, want %q", got, tt.want)
+			}
+			if stdout != tt.stdout {
+				t.Errorf("stdout = %q, want %q", stdout, tt.stdout)
+			}
+		})
+	}
+}
+
+func TestPutPlacement(t *testing.T) {
+	withOutput(t, "table")
+	client, names := newStubClient(t)
+	_, err := run(t, runPut, client, "", "-d", "data", "-replication", "2", "-require", "disk=ssd", "-prefer", "tier=fast", "-prefer", "zone=a", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := names.created[0]
+	if req.Replication != 2 || req.Constraints.Required["disk"] != "ssd" || len(req.Constraints.Preferred) != 2 {
+		t.Errorf("created with replication %d, constraints %v", req.Replication, req.Constraints)
+	}
+
+	if _, err := run(t, runPut, client, "", "-d", "data", "a"); !errors.Is(err, dos.ErrAlreadyExists) {
+		t.Errorf("put of an existing object = %v, want %v", err, dos.ErrAlreadyExists)
+	}
+}
+
+func TestGet(t *testing.T) {
+	withOutput(t, "json")
+	client, _ := newStubClient(t, "a")
+
+	// the data is the output, whatever the format
+	stdout, err := run(t, runGet, client, "", "a")
+	if err != nil || stdout != "a" {
+		t.Errorf("get to stdout = %q, %v", stdout, err)
+	}
+	file := filepath.Join(t.TempDir(), "file")
+	stdout, err = run(t, runGet, client, "", "a", file)
+	if err != nil || stdout != `{"name":"a","status":"read"}`+"\n" {
+		t.Errorf("get to a file = %q, %v", stdout, err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != "a" {
+		t.Errorf("file = %q", data)
+	}
+	if _, err := run(t, runGet, client, "", "missing"); !errors.Is(err, dos.ErrNotFound) {
+		t.Errorf("get of a missing object = %v, want %v", err, dos.ErrNotFound)
+	}
+	if _, err := run(t, runGet, client, "", "-snapshot", "missing", "a"); !errors.Is(err, dos.ErrNotFound) {
+		t.Errorf("get of a missing snapshot = %v, want %v", err, dos.ErrNotFound)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	withOutput(t, "table")
+	client, names := newStubClient(t, "a")
+	if _, err := run(t, runUpdate, client, "updated", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(names.objects["a"]); got != "updated" {
+		t.Errorf("object = %q", got)
+	}
+	if _, err := run(t, runUpdate, client, "", "-d", "data", "missing"); !errors.Is(err, dos.ErrNotFound) {
+		t.Errorf("update of a missing object = %v, want %v", err, dos.ErrNotFound)
+	}
+}
+
+func TestRm(t *testing.T) {
+	withOutput(t, "json")
+	client, names := newStubClient(t, "a", "b")
+
+	// one missing object does not keep the others from being deleted
+	stdout, err := run(t, runRm, client, "", "a", "missing", "b")
+	if !errors.Is(err, dos.ErrNotFound) {
+		t.Errorf("err = %v, want %v", err, dos.ErrNotFound)
+	}
+	if want := `{"name":"a","status":"deleted"}` + "\n" + `{"name":"b","status":"deleted"}` + "\n"; stdout != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+	if len(names.objects) != 0 {
+		t.Errorf("objects left: %v", names.objects)
+	}
+}
+
+func TestLs(t *testing.T) {
+	tests := []struct {
+		output string
+		args   []string
+		want   string
+	}{
+		{output: "table", want: "NAME\na\ndir/b\ndir/c\n"},
+		{output: "table", args: []string{"dir/"}, want: "NAME\ndir/b\ndir/c\n"},
+		{output: "json", args: []string{"dir/"}, want: `["dir/b","dir/c"]` + "\n"},
+		{output: "json", args: []string{"missing"}, want: "[]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			withOutput(t, tt.output)
+			client, _ := newStubClient(t, "a", "dir/b", "dir/c")
+			stdout, err := run(t, runLs, client, "", tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stdout != tt.want {
+				t.Errorf("stdout = %q, want %q", stdout, tt.want)
+			}
+		})
+	}
+}
+
+func TestStat(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{output: "table", want: "NAME  TYPE    SIZE\na     object  1\ndir   dir     0\n"},
+		{output: "json", want: `[{"name":"a","type":"object","size":1},{"name":"dir","type":"dir","size":0}]` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			withOutput(t, tt.output)
+			client, _ := newStubClient(t, "a", "dir/b")
+			// the names that exist are still shown
+			stdout, err := run(t, runStat, client, "", "a", "missing", "dir")
+			if !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("err = %v, want %v", err, os.ErrNotExist)
+			}
+			if stdout != tt.want {
+				t.Errorf("stdout = %q, want %q", stdout, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetrep(t *testing.T) {
+	withOutput(t, "table")
+	client, _ := newStubClient(t, "a")
+	stdout, err := run(t, runSetrep, client, "", "a", "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "NAME  REPLICAS  REPLICATION\na     2         2\n"; stdout != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+	if _, err := run(t, runSetrep, client, "", "a", "0"); !errors.Is(err, dos.ErrInvalidArgument) {
+		t.Errorf("setrep to 0 = %v, want %v", err, dos.ErrInvalidArgument)
+	}
+	if _, err := run(t, runSetrep, client, "", "a", "5"); !errors.Is(err, dos.ErrInvalidArgument) {
+		t.Errorf("setrep beyond the nodes = %v, want %v", err, dos.ErrInvalidArgument)
+	}
+}
+
+func TestCp(t *testing.T) {
+	withOutput(t, "json")
+	client, names := newStubClient(t, "a")
+	dir := t.TempDir()
+	local := filepath.Join(dir, "local")
+	if err := os.WriteFile(local, []byte("local"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		src    string
+		dst    string
+		stdin  string
+		stdout string
+		check  func() string // what landed at dst
+		want   string
+	}{
+		{
+			name:   "file to object",
+			src:    local,
+			dst:    "dos:b",
+			stdout: `{"name":"dos:b","status":"copied"}` + "\n",
+			check:  func() string { return string(names.objects["b"]) },
+			want:   "local",
+		},
+		{
+			name:   "stdin to object",
+			src:    "-",
+			dst:    "dos:c",
+			stdin:  "piped",
+			stdout: `{"name":"dos:c","status":"copied"}` + "\n",
+			check:  func() string { return string(names.objects["c"]) },
+			want:   "piped",
+		},
+		{
+			name:   "object to stdout",
+			src:    "dos:a",
+			dst:    "-",
+			stdout: "a",
+		},
+		{
+			name:   "object to file",
+			src:    "dos:a",
+			dst:    filepath.Join(dir, "copy"),
+			stdout: `{"name":"` + filepath.Join(dir, "copy") + `","status":"copied"}` + "\n",
+			check: func() string {
+				data, _ := os.ReadFile(filepath.Join(dir, "copy"))
+				return string(data)
+			},
+			want: "a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, err := run(t, runCp, client, tt.stdin, tt.src, tt.dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stdout != tt.stdout {
+				t.Errorf("stdout = %q, want %q", stdout, tt.stdout)
+			}
+			if tt.check != nil {
+				if got := tt.check(); got != tt.want {
+					t.Errorf("copied %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+
+	if _, err := run(t, runCp, client, "", "dos:missing", "-"); !errors.Is(err, dos.ErrNotFound) {
+		t.Errorf("cp of a missing object = %v, want %v", err, dos.ErrNotFound)
+	}
+	if _, err := run(t, runCp, client, "", filepath.Join(dir, "missing"), "dos:d"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("cp of a missing file = %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	withOutput(t, "table")
+	client, _ := newStubClient(t)
+	stdout, err := run(t, runSnapshot, client, "", "create", "s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SNAPSHOT  LAMPORT\ns         1.7\n"; stdout != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+}
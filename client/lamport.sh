@@ -1,13 +1,13 @@
 #!/bin/bash
 
 # Execute the first command to create object named hello
-go run . -cmd=1 -object=hello
+go run . put -d "test data" hello
 
 # Execute the second command to create object named world
-go run . -cmd=1 -object=world
+go run . put -d "test data" world
 
 # Execute the third commad to delete object named hello
-go run . -cmd=2 -object=hello
+go run . rm hello
 
 # Execute the fourth command to delete object named world
-go run . -cmd=2 -object=world
+go run . rm world
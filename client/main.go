@@ -1,61 +1,163 @@
 package main
 
+/**
+	dosctl is the command line client of the object store
+	usage: dosctl [global flags] <command> [flags] [args]
+	data is read from files or stdin and written to stdout, failures exit with a code telling
+	what went wrong so scripts can branch on them
+**/
+
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
-
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"os"
+	"os/signal"
+	"time"
 
 	dos "github.com/mrowaha/dos/client"
 )
 
+// exit codes
+const (
+	exitOk              = 0
+	exitFailure         = 1
+	exitUsage           = 2
+	exitNotFound        = 3
+	exitAlreadyExists   = 4
+	exitUnavailable     = 5
+	exitInvalidArgument = 6
+	exitInterrupted     = 130
+)
+
+var (
+	errUsage = errors.New("usage")
+)
+
 var (
-	port   int
-	object string
-	data   string
-	cmd    int
+	addr     string
+	timeout  time.Duration
+	attempts int
+	output   string
+	verbose  bool
 )
 
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(ctx context.Context, client *dos.DosClient, args []string) error
+}
+
+var commands = []command{
+	{"put", "put [-d data] [-replication n] [-require k=v]... [-prefer k=v]... <name> [file|-]", "create an object from a file, stdin or -d, placed on nodes with the labels", runPut},
+	{"update", "update [-d data] <name> [file|-]", "replace the data of an object", runUpdate},
+	{"get", "get [-snapshot name] <name> [file|-]", "write an object to a file or stdout", runGet},
+	{"rm", "rm <name>...", "delete objects", runRm},
+	{"setrep", "setrep [-wait] <name> <replication>", "set how many replicas an object keeps", runSetrep},
+	{"ls", "ls [prefix]", "list objects starting with prefix", runLs},
+	{"stat", "stat <name>...", "show the size of objects and directories", runStat},
+	{"watch", "watch <name>", "stream the changes of an object until interrupted", runWatch},
+	{"cp", "cp <src> <dst>", "copy between local files (- for stdin/stdout) and objects (dos:<name>)", runCp},
+	{"snapshot", "snapshot create|rm <name>", "take or delete a snapshot", runSnapshot},
+	{"admin", "admin <command> [args]", "inspect and operate the cluster", runAdmin},
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "usage: dosctl [global flags] <command> [flags] [args]\n\ncommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %-45s %s\n", cmd.usage, cmd.summary)
+	}
+	fmt.Fprintf(out, "\nadmin commands:\n")
+	for _, cmd := range adminCommands {
+		fmt.Fprintf(out, "  %-45s %s\n", cmd.usage, cmd.summary)
+	}
+	fmt.Fprintf(out, "\nglobal flags:\n")
+	flag.PrintDefaults()
+}
+
 func main() {
-	flag.IntVar(&port, "port", 50051, "port of name node service")
-	flag.StringVar(&object, "object", "test", "name of object")
-	flag.StringVar(&data, "data", "test data", "data of object")
-	flag.IntVar(&cmd, "cmd", 1, "Create = 1, Delete = 2, Update = 3, Lease = 4")
+	flag.StringVar(&addr, "addr", "localhost:50051", "address of the name node service")
+	flag.DurationVar(&timeout, "timeout", 10*time.Second, "timeout of every attempt of a call")
+	flag.IntVar(&attempts, "attempts", 3, "attempts per call, failed calls are retried with backoff")
+	flag.StringVar(&output, "o", "table", "output format, table or json")
+	flag.BoolVar(&verbose, "v", false, "log client calls to stderr")
+	flag.Usage = usage
 	flag.Parse()
 
-	conn, err := grpc.NewClient(fmt.Sprintf("localhost:%d", port), grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		log.Fatal(err)
+	if output != "table" && output != "json" {
+		fmt.Fprintf(os.Stderr, "dosctl: unknown output format %s\n", output)
+		os.Exit(exitUsage)
+	}
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(exitUsage)
+	}
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == flag.Arg(0) {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "dosctl: unknown command %s\n", flag.Arg(0))
+		usage()
+		os.Exit(exitUsage)
 	}
-	defer conn.Close()
 
-	client := dos.NewDosClient(conn)
+	// an interrupt cancels the running call, or ends a watch
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-	// wg := sync.WaitGroup{}
-	// wg.Add(1)
-	// wg.Add(1)
-	// go func() {
-	// 	client.Create(object, []byte(data))
-	// 	wg.Done()
-	// }()
+	logger := log.New(io.Discard, "", 0)
+	if verbose {
+		logger = log.New(os.Stderr, "[client]", log.Ltime)
+	}
+	client, err := dos.Dial(addr,
+		dos.WithLogger(logger),
+		dos.WithTimeout(timeout),
+		dos.WithRetryPolicy(dos.RetryPolicy{
+			Attempts:   max(attempts, 1),
+			BackoffMin: 200 * time.Millisecond,
+			BackoffMax: 2 * time.Second,
+		}))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "dosctl: %v\n", err)
+		os.Exit(exitUnavailable)
+	}
 
-	// time.Sleep(2 * time.Second)
-	// go func() {
-	// 	client.Delete(object)
-	// 	wg.Done()
-	// }()
+	err = cmd.run(ctx, client, flag.Args()[1:])
+	client.Close()
+	stop()
+	os.Exit(exitCode(cmd, err))
+}
 
-	// wg.Wait()
+// exitCode reports err on stderr and maps it to the exit code of the process
+func exitCode(cmd *command, err error) int {
+	switch {
+	case err == nil:
+		return exitOk
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(os.Stderr, "usage: dosctl %s\n", cmd.usage)
+		return exitUsage
+	}
 
-	if cmd == 1 {
-		client.Create(object, []byte(data))
-	} else if cmd == 2 {
-		client.Delete(object)
-	} else if cmd == 3 {
-		client.Update(object, []byte(data))
-	} else if cmd == 4 {
-		client.Lease(object)
+	fmt.Fprintf(os.Stderr, "dosctl %s: %v\n", cmd.name, err)
+	switch {
+	case errors.Is(err, context.Canceled):
+		return exitInterrupted
+	case errors.Is(err, dos.ErrNotFound), errors.Is(err, os.ErrNotExist):
+		return exitNotFound
+	case errors.Is(err, dos.ErrAlreadyExists):
+		return exitAlreadyExists
+	case errors.Is(err, dos.ErrUnavailable), errors.Is(err, context.DeadlineExceeded):
+		return exitUnavailable
+	case errors.Is(err, dos.ErrInvalidArgument):
+		return exitInvalidArgument
 	}
+	return exitFailure
 }
@@ -2,10 +2,10 @@
 
 
 # first we are going to create two objects
-go run . -cmd=1 -object=me -data=rowaha
+go run . put -d rowaha me
 
 # ... next object
-go run . -cmd=1 -object=hello -data=world
+go run . put -d world hello
 
 
 echo completed order_1
@@ -6,7 +6,7 @@
   then it is advised to used sqlite3 exec to valdiate results
 '
 
-go run . -cmd=1 -object=me -data=rowaha
-go run . -cmd=1 -object=hello -data=world
+go run . put -d rowaha me
+go run . put -d world hello
 echo [order_2] checkpoint: attempted create objects
-go run . -cmd=2 -object=me
+go run . rm me
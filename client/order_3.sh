@@ -7,16 +7,16 @@
 
 
 # create first object
-go run . -object=me -data=rowaha
+go run . put -d rowaha me
 
 # create second object
-go run . -object=hello -data=world
+go run . put -d world hello
 
 echo [object_3] checkpoint: attempted creating objects
 
 
 # attempt update first object
-go run . -cmd=3 -object=me -data=not_rowaha
+go run . update -d not_rowaha me
 
 # attempt update second object
-go run . -cmd=3 -object=hello -data=not_world
+go run . update -d not_world hello
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// render prints v as one line of json, or rows under header as a table
+// a table without a header prints nothing, mutations only report themselves in json
+func render(v any, header []string, rows [][]string) error {
+	if output == "json" {
+		return json.NewEncoder(os.Stdout).Encode(v)
+	}
+	if len(header) == 0 {
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+type objectResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+type statResult struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Size int64  `json:"size"`
+}
+
+type eventResult struct {
+	Object   string `json:"object"`
+	Kind     string `json:"kind"`
+	Sequence int    `json:"sequence"`
+	Data     string `json:"data,omitempty"`
+}
+
+type snapshotResult struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Lamport string `json:"lamport,omitempty"`
+}
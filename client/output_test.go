@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	dos "github.com/mrowaha/dos/client"
+	"google.golang.org/grpc/codes"
+)
+
+func TestRender(t *testing.T) {
+	result := objectResult{Name: "a", Status: "created"}
+	tests := []struct {
+		name   string
+		output string
+		header []string
+		rows   [][]string
+		want   string
+	}{
+		{name: "json", output: "json", header: []string{"NAME"}, rows: [][]string{{"a"}}, want: `{"name":"a","status":"created"}` + "\n"},
+		{name: "table", output: "table", header: []string{"NAME", "STATUS"}, rows: [][]string{{"a", "created"}, {"longer", "x"}}, want: "NAME    STATUS\na       created\nlonger  x\n"},
+		{name: "table without rows", output: "table", header: []string{"NAME"}, want: "NAME\n"},
+		// mutations only report themselves in json
+		{name: "table without a header", output: "table"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withOutput(t, tt.output)
+			stdout, err := run(t, func(context.Context, *dos.DosClient, []string) error {
+				return render(result, tt.header, tt.rows)
+			}, nil, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stdout != tt.want {
+				t.Errorf("stdout = %q, want %q", stdout, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	// the error is reported on stderr
+	stderr := os.Stderr
+	devnull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devnull.Close()
+	os.Stderr = devnull
+	defer func() { os.Stderr = stderr }()
+
+	clientErr := func(code codes.Code) error {
+		client, _ := newStubClient(t)
+		var err error
+		switch code {
+		case codes.NotFound:
+			_, err = client.Get(context.Background(), "missing", "")
+		case codes.AlreadyExists:
+			client.Create(context.Background(), "a", nil)
+			err = client.Create(context.Background(), "a", nil)
+		case codes.FailedPrecondition:
+			_, err = client.SetReplication(context.Background(), "a", 5)
+		case codes.Unavailable:
+			// nothing listens on the port
+			down, dialErr := dos.Dial("127.0.0.1:1", dos.WithLogger(nil), dos.WithRetryPolicy(dos.RetryPolicy{Attempts: 1, BackoffMin: time.Millisecond, BackoffMax: time.Millisecond}))
+			if dialErr != nil {
+				t.Fatal(dialErr)
+			}
+			defer down.Close()
+			_, err = down.Get(context.Background(), "a", "")
+		}
+		return err
+	}
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cmd := &commands[0]
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "success", want: exitOk},
+		{name: "usage", err: errUsage, want: exitUsage},
+		{name: "missing object", err: clientErr(codes.NotFound), want: exitNotFound},
+		{name: "missing file", err: &os.PathError{Op: "open", Path: "file", Err: os.ErrNotExist}, want: exitNotFound},
+		{name: "existing object", err: clientErr(codes.AlreadyExists), want: exitAlreadyExists},
+		{name: "refused argument", err: clientErr(codes.FailedPrecondition), want: exitInvalidArgument},
+		{name: "unavailable namenode", err: clientErr(codes.Unavailable), want: exitUnavailable},
+		{name: "timed out", err: fmt.Errorf("get: %w", context.DeadlineExceeded), want: exitUnavailable},
+		{name: "interrupted", err: cancelled.Err(), want: exitInterrupted},
+		{name: "anything else", err: errors.New("disk on fire"), want: exitFailure},
+		{name: "joined", err: errors.Join(errors.New("disk on fire"), clientErr(codes.NotFound)), want: exitNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(cmd, tt.err); got != tt.want {
+				t.Errorf("exit code of %v = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mrowaha/dos/api"
+	dos "github.com/mrowaha/dos/client"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// stubNames is a name service keeping objects in memory
+type stubNames struct {
+	api.UnimplementedNameServiceServer
+	lock    sync.Mutex
+	objects map[string][]byte
+	created []*api.CreateObjectRequest
+}
+
+func (s *stubNames) CreateObject(ctx context.Context, req *api.CreateObjectRequest) (*api.CreateObjectResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.objects[req.Name]; ok {
+		return nil, status.Error(codes.AlreadyExists, "object already exists")
+	}
+	s.objects[req.Name] = req.Data
+	s.created = append(s.created, req)
+	return &api.CreateObjectResponse{}, nil
+}
+
+func (s *stubNames) UpdateObject(ctx context.Context, req *api.UpdateObjectReq) (*api.UpdateObjectRes, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.objects[req.Name]; !ok {
+		return nil, status.Error(codes.NotFound, "object does not exist")
+	}
+	s.objects[req.Name] = req.Data
+	return &api.UpdateObjectRes{}, nil
+}
+
+func (s *stubNames) DeleteObject(ctx context.Context, req *api.DeleteObjectRequest) (*api.DeleteObjectResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.objects[req.Name]; !ok {
+		return nil, status.Error(codes.NotFound, "object does not exist")
+	}
+	delete(s.objects, req.Name)
+	return &api.DeleteObjectResponse{}, nil
+}
+
+func (s *stubNames) GetObject(ctx context.Context, req *api.GetObjectReq) (*api.GetObjectRes, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(req.Snapshot) != 0 {
+		return nil, status.Error(codes.NotFound, "snapshot does not exist")
+	}
+	data, ok := s.objects[req.Name]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "object does not exist")
+	}
+	return &api.GetObjectRes{Data: data}, nil
+}
+
+func (s *stubNames) ListObjects(ctx context.Context, req *api.ListObjectsReq) (*api.ListObjectsRes, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	names := make([]string, 0)
+	for name := range s.objects {
+		if strings.HasPrefix(name, req.Prefix) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return &api.ListObjectsRes{Names: names}, nil
+}
+
+func (s *stubNames) SetReplication(ctx context.Context, req *api.SetReplicationReq) (*api.SetReplicationRes, error) {
+	if req.Replication > 3 {
+		return nil, status.Error(codes.FailedPrecondition, "not enough datanodes")
+	}
+	return &api.SetReplicationRes{Replicas: req.Replication}, nil
+}
+
+func (s *stubNames) CreateSnapshot(ctx context.Context, req *api.CreateSnapshotReq) (*api.CreateSnapshotRes, error) {
+	return &api.CreateSnapshotRes{Lamport: 1<<32 | 7}, nil
+}
+
+// newStubClient serves the stub name service holding objects and returns a client of it
+func newStubClient(t *testing.T, objects ...string) (*dos.DosClient, *stubNames) {
+	t.Helper()
+	names := &stubNames{objects: make(map[string][]byte)}
+	for _, object := range objects {
+		names.objects[object] = []byte(object)
+	}
+	return serve(t, func(server *grpc.Server) { api.RegisterNameServiceServer(server, names) }), names
+}
+
+// serve serves the services register adds and returns a client of them that does not retry
+func serve(t *testing.T, register func(server *grpc.Server)) *dos.DosClient {
+	t.Helper()
+	server := grpc.NewServer()
+	register(server)
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///namenode",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return dos.NewDosClient(conn,
+		dos.WithLogger(nil),
+		dos.WithRetryPolicy(dos.RetryPolicy{Attempts: 1, BackoffMin: time.Millisecond, BackoffMax: time.Millisecond}),
+	)
+}
+
+// withOutput sets the output format for the test
+func withOutput(t *testing.T, format string) {
+	t.Helper()
+	previous := output
+	output = format
+	t.Cleanup(func() { output = previous })
+}
+
+// run runs the command with stdin and returns what it wrote to stdout
+func run(t *testing.T, cmd func(ctx context.Context, client *dos.DosClient, args []string) error, client *dos.DosClient, stdin string, args ...string) (string, error) {
+	t.Helper()
+	in, err := os.CreateTemp(t.TempDir(), "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	in.WriteString(stdin)
+	in.Seek(0, io.SeekStart)
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdinBefore, stdoutBefore := os.Stdin, os.Stdout
+	os.Stdin, os.Stdout = in, w
+	defer func() {
+		os.Stdin, os.Stdout = stdinBefore, stdoutBefore
+		in.Close()
+	}()
+
+	out := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		out <- string(data)
+	}()
+	err = cmd(context.Background(), client, args)
+	w.Close()
+	return <-out, err
+}
@@ -13,14 +13,14 @@ daemon_dir = Path.cwd()
 def main(*, args: Args):
     lamport = 0
     if args['mode'] == Mode.GHOST:
-        spawner : spawn.ISpawner = spawn.Spawner(args['process'], args['port'], args['store'])
+        spawner : spawn.ISpawner = spawn.Spawner(args['process'], args['port'], args['store'], args['zone'], args['rack'])
         lamport = spawner.run()
         print(f"lamport updated to {lamport}")
 
     while True:
         env = os.environ.copy()
         try:
-            exec = ["go", "run", ".", f"-store={args['store']}", f"-process={args['process']}", f"-lease={args['lease']}", f"-lamport={lamport}"]
+            exec = ["go", "run", ".", f"-store={args['store']}", f"-process={args['process']}", f"-lease={args['lease']}", f"-lamport={lamport}", f"-token={args['token']}", f"-zone={args['zone']}", f"-rack={args['rack']}"]
             print(f'execution command: {" ".join(exec)}')
             process = subprocess.Popen(
                 exec,
@@ -36,7 +36,7 @@ def main(*, args: Args):
                 error = process.stderr.readline()
                 print(f"[daemon] {error}",end="")
 
-            spawner : spawn.ISpawner = spawn.Spawner(args['process'], args['port'], args['store'])
+            spawner : spawn.ISpawner = spawn.Spawner(args['process'], args['port'], args['store'], args['zone'], args['rack'])
             spawner.run()
         except Exception as e:
             print(f"An error occurred in daemon: {e}")
@@ -47,6 +47,9 @@ if __name__ == "__main__":
     ap.add_argument("-p", "--port", type=int, help="port of the name node service", default=50051)
     ap.add_argument("-proc", "--process", type=str, help="name of the process", required=True)
     ap.add_argument("-l", "--lease", type=str, help="lease address", required=True)
+    ap.add_argument("-t", "--token", type=str, help="join token of the cluster", default="")
+    ap.add_argument("-z", "--zone", type=str, help="zone of the node", default="")
+    ap.add_argument("-r", "--rack", type=str, help="rack of the node within its zone", default="")
     ap.add_argument("-m", "--mode", type=validate_mode, help="ghost or data mode", default=Mode.GHOST)
     args : Args =  Args(**vars(ap.parse_args()))
     print(args)
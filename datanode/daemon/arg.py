@@ -14,4 +14,7 @@ class Args(TypedDict):
     port: int
     process: str
     lease: str
+    token: str
+    zone: str
+    rack: str
     mode: Mode
\ No newline at end of file
@@ -0,0 +1,92 @@
+# -*- coding: utf-8 -*-
+# Generated by the protocol buffer compiler.  DO NOT EDIT!
+# NO CHECKED-IN PROTOBUF GENCODE
+# source: admin.proto
+# Protobuf Python Version: 5.28.1
+"""Generated protocol buffer code."""
+from google.protobuf import descriptor as _descriptor
+from google.protobuf import descriptor_pool as _descriptor_pool
+from google.protobuf import runtime_version as _runtime_version
+from google.protobuf import symbol_database as _symbol_database
+from google.protobuf.internal import builder as _builder
+_runtime_version.ValidateProtobufRuntimeVersion(
+    _runtime_version.Domain.PUBLIC,
+    5,
+    28,
+    1,
+    '',
+    'admin.proto'
+)
+# @@protoc_insertion_point(imports)
+
+_sym_db = _symbol_database.Default()
+
+
+from google.protobuf import timestamp_pb2 as google_dot_protobuf_dot_timestamp__pb2
+from google.protobuf import duration_pb2 as google_dot_protobuf_dot_duration__pb2
+from . import namenode_pb2 as namenode__pb2
+
+
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0b\x61\x64min.proto\x12\x05proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/duration.proto\x1a\x0enamenode.proto\"]\n\rDrainProgress\x12\r\n\x05total\x18\x01 \x01(\r\x12\r\n\x05moved\x18\x02 \x01(\r\x12\x0e\n\x06\x66\x61iled\x18\x03 \x01(\r\x12\r\n\x05\x65rror\x18\x04 \x01(\t\x12\x0f\n\x07running\x18\x05 \x01(\x08\"l\n\x0bMaintenance\x12)\n\x05until\x18\x01 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12\x0c\n\x04\x61way\x18\x02 \x01(\x08\x12\x10\n\x08\x62uffered\x18\x03 \x01(\r\x12\x12\n\noverflowed\x18\x04 \x01(\x08\"\xe8\x03\n\x0bNodeSummary\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0e\n\x06nodeId\x18\x02 \x01(\t\x12\x0e\n\x06leaser\x18\x03 \x01(\t\x12\x0c\n\x04size\x18\x04 \x01(\x02\x12\r\n\x05\x66\x65nce\x18\x05 \x01(\x04\x12\x0f\n\x07objects\x18\x06 \x01(\r\x12*\n\x06joined\x18\x07 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12\'\n\x05state\x18\x08 \x01(\x0e\x32\x18.proto.NodeSummary.State\x12#\n\x05\x64rain\x18\t \x01(\x0b\x32\x14.proto.DrainProgress\x12\'\n\x0bmaintenance\x18\n \x01(\x0b\x32\x12.proto.Maintenance\x12\x10\n\x08\x63\x61pacity\x18\x0b \x01(\x02\x12\x0c\n\x04zone\x18\x0c \x01(\t\x12\x0c\n\x04rack\x18\r \x01(\t\x12.\n\x06labels\x18\x0e \x03(\x0b\x32\x1e.proto.NodeSummary.LabelsEntry\x1a-\n\x0bLabelsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"M\n\x05State\x12\n\n\x06\x41\x43TIVE\x10\x00\x12\x13\n\x0f\x44\x45\x43OMMISSIONING\x10\x01\x12\x12\n\x0e\x44\x45\x43OMMISSIONED\x10\x02\x12\x0f\n\x0bMAINTENANCE\x10\x03\"\x0e\n\x0cListNodesReq\"1\n\x0cListNodesRes\x12!\n\x05nodes\x18\x01 \x03(\x0b\x32\x12.proto.NodeSummary\"\x1b\n\x0bNodeInfoReq\x12\x0c\n\x04name\x18\x01 \x01(\t\"b\n\x0bNodeInfoRes\x12 \n\x04node\x18\x01 \x01(\x0b\x32\x12.proto.NodeSummary\x12\x0f\n\x07objects\x18\x02 \x03(\t\x12\x0e\n\x06queued\x18\x03 \x01(\r\x12\x10\n\x08\x61waiting\x18\x04 \x01(\r\"G\n\x0cGhostSummary\x12\x0c\n\x04name\x18\x01 \x01(\t\x12)\n\x05since\x18\x02 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\"\x0f\n\rListGhostsReq\"4\n\rListGhostsRes\x12#\n\x06ghosts\x18\x01 \x03(\x0b\x32\x13.proto.GhostSummary\"-\n\rObjectReplica\x12\x0c\n\x04node\x18\x01 \x01(\t\x12\x0e\n\x06leaser\x18\x02 \x01(\t\"\"\n\x12ObjectLocationsReq\x12\x0c\n\x04name\x18\x01 \x01(\t\"\x91\x01\n\x12ObjectLocationsRes\x12\x0c\n\x04name\x18\x01 \x01(\t\x12&\n\x08replicas\x18\x02 \x03(\x0b\x32\x14.proto.ObjectReplica\x12\x13\n\x0breplication\x18\x03 \x01(\r\x12\x30\n\x0b\x63onstraints\x18\x04 \x01(\x0b\x32\x1b.proto.PlacementConstraints\"\x1f\n\x0f\x44\x65\x63ommissionReq\x12\x0c\n\x04name\x18\x01 \x01(\t\"3\n\x0f\x44\x65\x63ommissionRes\x12 \n\x04node\x18\x01 \x01(\x0b\x32\x12.proto.NodeSummary\"N\n\x13StartMaintenanceReq\x12\x0c\n\x04name\x18\x01 \x01(\t\x12)\n\x06window\x18\x02 \x01(\x0b\x32\x19.google.protobuf.Duration\"7\n\x13StartMaintenanceRes\x12 \n\x04node\x18\x01 \x01(\x0b\x32\x12.proto.NodeSummary\"!\n\x11\x45ndMaintenanceReq\x12\x0c\n\x04name\x18\x01 \x01(\t\"5\n\x11\x45ndMaintenanceRes\x12 \n\x04node\x18\x01 \x01(\x0b\x32\x12.proto.NodeSummary\"E\n\x0bReplicaMove\x12\x0e\n\x06object\x18\x01 \x01(\t\x12\x0c\n\x04\x66rom\x18\x02 \x01(\t\x12\n\n\x02to\x18\x03 \x01(\t\x12\x0c\n\x04size\x18\x04 \x01(\x04\"\x1e\n\x0cRebalanceReq\x12\x0e\n\x06\x64ryRun\x18\x01 \x01(\x08\"?\n\x0cRebalanceRes\x12!\n\x05moves\x18\x01 \x03(\x0b\x32\x12.proto.ReplicaMove\x12\x0c\n\x04mean\x18\x02 \x01(\x01\"\x13\n\x11\x43lusterSummaryReq\"\xfe\x01\n\x11\x43lusterSummaryRes\x12\x11\n\tclusterId\x18\x01 \x01(\t\x12\x0f\n\x07lamport\x18\x02 \x01(\x04\x12\r\n\x05nodes\x18\x03 \x01(\r\x12\x0e\n\x06ghosts\x18\x04 \x01(\r\x12\x0f\n\x07objects\x18\x05 \x01(\r\x12\x17\n\x0funderReplicated\x18\x06 \x01(\r\x12\x11\n\tsnapshots\x18\x07 \x01(\r\x12\x13\n\x0breplication\x18\x08 \x01(\r\x12\x11\n\ttolerance\x18\t \x01(\r\x12\x17\n\x0f\x64\x65\x63ommissioning\x18\n \x01(\r\x12\x13\n\x0bmaintenance\x18\x0b \x01(\r\x12\x13\n\x0brebalancing\x18\x0c \x01(\x08\x32\xcb\x04\n\x0c\x41\x64minService\x12\x35\n\tListNodes\x12\x13.proto.ListNodesReq\x1a\x13.proto.ListNodesRes\x12\x32\n\x08NodeInfo\x12\x12.proto.NodeInfoReq\x1a\x12.proto.NodeInfoRes\x12\x38\n\nListGhosts\x12\x14.proto.ListGhostsReq\x1a\x14.proto.ListGhostsRes\x12G\n\x0fObjectLocations\x12\x19.proto.ObjectLocationsReq\x1a\x19.proto.ObjectLocationsRes\x12\x44\n\x0e\x43lusterSummary\x12\x18.proto.ClusterSummaryReq\x1a\x18.proto.ClusterSummaryRes\x12>\n\x0c\x44\x65\x63ommission\x12\x16.proto.DecommissionReq\x1a\x16.proto.DecommissionRes\x12J\n\x10StartMaintenance\x12\x1a.proto.StartMaintenanceReq\x1a\x1a.proto.StartMaintenanceRes\x12\x44\n\x0e\x45ndMaintenance\x12\x18.proto.EndMaintenanceReq\x1a\x18.proto.EndMaintenanceRes\x12\x35\n\tRebalance\x12\x13.proto.RebalanceReq\x1a\x13.proto.RebalanceResB\x0cZ\n../api;apib\x06proto3')
+
+_globals = globals()
+_builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
+_builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'admin_pb2', _globals)
+if not _descriptor._USE_C_DESCRIPTORS:
+  _globals['DESCRIPTOR']._loaded_options = None
+  _globals['DESCRIPTOR']._serialized_options = b'Z\n../api;api'
+  _globals['_DRAINPROGRESS']._serialized_start=103
+  _globals['_DRAINPROGRESS']._serialized_end=196
+  _globals['_MAINTENANCE']._serialized_start=198
+  _globals['_MAINTENANCE']._serialized_end=306
+  _globals['_NODESUMMARY']._serialized_start=309
+  _globals['_NODESUMMARY']._serialized_end=797
+  _globals['_NODESUMMARY_LABELSENTRY']._serialized_start=673
+  _globals['_NODESUMMARY_LABELSENTRY']._serialized_end=718
+  _globals['_NODESUMMARY_STATE']._serialized_start=720
+  _globals['_NODESUMMARY_STATE']._serialized_end=797
+  _globals['_LISTNODESREQ']._serialized_start=799
+  _globals['_LISTNODESREQ']._serialized_end=813
+  _globals['_LISTNODESRES']._serialized_start=815
+  _globals['_LISTNODESRES']._serialized_end=864
+  _globals['_NODEINFOREQ']._serialized_start=866
+  _globals['_NODEINFOREQ']._serialized_end=893
+  _globals['_NODEINFORES']._serialized_start=895
+  _globals['_NODEINFORES']._serialized_end=993
+  _globals['_GHOSTSUMMARY']._serialized_start=995
+  _globals['_GHOSTSUMMARY']._serialized_end=1066
+  _globals['_LISTGHOSTSREQ']._serialized_start=1068
+  _globals['_LISTGHOSTSREQ']._serialized_end=1083
+  _globals['_LISTGHOSTSRES']._serialized_start=1085
+  _globals['_LISTGHOSTSRES']._serialized_end=1137
+  _globals['_OBJECTREPLICA']._serialized_start=1139
+  _globals['_OBJECTREPLICA']._serialized_end=1184
+  _globals['_OBJECTLOCATIONSREQ']._serialized_start=1186
+  _globals['_OBJECTLOCATIONSREQ']._serialized_end=1220
+  _globals['_OBJECTLOCATIONSRES']._serialized_start=1223
+  _globals['_OBJECTLOCATIONSRES']._serialized_end=1368
+  _globals['_DECOMMISSIONREQ']._serialized_start=1370
+  _globals['_DECOMMISSIONREQ']._serialized_end=1401
+  _globals['_DECOMMISSIONRES']._serialized_start=1403
+  _globals['_DECOMMISSIONRES']._serialized_end=1454
+  _globals['_STARTMAINTENANCEREQ']._serialized_start=1456
+  _globals['_STARTMAINTENANCEREQ']._serialized_end=1534
+  _globals['_STARTMAINTENANCERES']._serialized_start=1536
+  _globals['_STARTMAINTENANCERES']._serialized_end=1591
+  _globals['_ENDMAINTENANCEREQ']._serialized_start=1593
+  _globals['_ENDMAINTENANCEREQ']._serialized_end=1626
+  _globals['_ENDMAINTENANCERES']._serialized_start=1628
+  _globals['_ENDMAINTENANCERES']._serialized_end=1681
+  _globals['_REPLICAMOVE']._serialized_start=1683
+  _globals['_REPLICAMOVE']._serialized_end=1752
+  _globals['_REBALANCEREQ']._serialized_start=1754
+  _globals['_REBALANCEREQ']._serialized_end=1784
+  _globals['_REBALANCERES']._serialized_start=1786
+  _globals['_REBALANCERES']._serialized_end=1849
+  _globals['_CLUSTERSUMMARYREQ']._serialized_start=1851
+  _globals['_CLUSTERSUMMARYREQ']._serialized_end=1870
+  _globals['_CLUSTERSUMMARYRES']._serialized_start=1873
+  _globals['_CLUSTERSUMMARYRES']._serialized_end=2127
+  _globals['_ADMINSERVICE']._serialized_start=2130
+  _globals['_ADMINSERVICE']._serialized_end=2717
+# @@protoc_insertion_point(module_scope)
@@ -0,0 +1,447 @@
+# Generated by the gRPC Python protocol compiler plugin. DO NOT EDIT!
+"""Client and server classes corresponding to protobuf-defined services."""
+import grpc
+import warnings
+
+from . import admin_pb2 as admin__pb2
+
+GRPC_GENERATED_VERSION = '1.68.1'
+GRPC_VERSION = grpc.__version__
+_version_not_supported = False
+
+try:
+    from grpc._utilities import first_version_is_lower
+    _version_not_supported = first_version_is_lower(GRPC_VERSION, GRPC_GENERATED_VERSION)
+except ImportError:
+    _version_not_supported = True
+
+if _version_not_supported:
+    raise RuntimeError(
+        f'The grpc package installed is at version {GRPC_VERSION},'
+        + f' but the generated code in admin_pb2_grpc.py depends on'
+        + f' grpcio>={GRPC_GENERATED_VERSION}.'
+        + f' Please upgrade your grpc module to grpcio>={GRPC_GENERATED_VERSION}'
+        + f' or downgrade your generated code using grpcio-tools<={GRPC_VERSION}.'
+    )
+
+
+class AdminServiceStub(object):
+    """Missing associated documentation comment in .proto file."""
+
+    def __init__(self, channel):
+        """Constructor.
+
+        Args:
+            channel: A grpc.Channel.
+        """
+        self.ListNodes = channel.unary_unary(
+                '/proto.AdminService/ListNodes',
+                request_serializer=admin__pb2.ListNodesReq.SerializeToString,
+                response_deserializer=admin__pb2.ListNodesRes.FromString,
+                _registered_method=True)
+        self.NodeInfo = channel.unary_unary(
+                '/proto.AdminService/NodeInfo',
+                request_serializer=admin__pb2.NodeInfoReq.SerializeToString,
+                response_deserializer=admin__pb2.NodeInfoRes.FromString,
+                _registered_method=True)
+        self.ListGhosts = channel.unary_unary(
+                '/proto.AdminService/ListGhosts',
+                request_serializer=admin__pb2.ListGhostsReq.SerializeToString,
+                response_deserializer=admin__pb2.ListGhostsRes.FromString,
+                _registered_method=True)
+        self.ObjectLocations = channel.unary_unary(
+                '/proto.AdminService/ObjectLocations',
+                request_serializer=admin__pb2.ObjectLocationsReq.SerializeToString,
+                response_deserializer=admin__pb2.ObjectLocationsRes.FromString,
+                _registered_method=True)
+        self.ClusterSummary = channel.unary_unary(
+                '/proto.AdminService/ClusterSummary',
+                request_serializer=admin__pb2.ClusterSummaryReq.SerializeToString,
+                response_deserializer=admin__pb2.ClusterSummaryRes.FromString,
+                _registered_method=True)
+        self.Decommission = channel.unary_unary(
+                '/proto.AdminService/Decommission',
+                request_serializer=admin__pb2.DecommissionReq.SerializeToString,
+                response_deserializer=admin__pb2.DecommissionRes.FromString,
+                _registered_method=True)
+        self.StartMaintenance = channel.unary_unary(
+                '/proto.AdminService/StartMaintenance',
+                request_serializer=admin__pb2.StartMaintenanceReq.SerializeToString,
+                response_deserializer=admin__pb2.StartMaintenanceRes.FromString,
+                _registered_method=True)
+        self.EndMaintenance = channel.unary_unary(
+                '/proto.AdminService/EndMaintenance',
+                request_serializer=admin__pb2.EndMaintenanceReq.SerializeToString,
+                response_deserializer=admin__pb2.EndMaintenanceRes.FromString,
+                _registered_method=True)
+        self.Rebalance = channel.unary_unary(
+                '/proto.AdminService/Rebalance',
+                request_serializer=admin__pb2.RebalanceReq.SerializeToString,
+                response_deserializer=admin__pb2.RebalanceRes.FromString,
+                _registered_method=True)
+
+
+class AdminServiceServicer(object):
+    """Missing associated documentation comment in .proto file."""
+
+    def ListNodes(self, request, context):
+        """this service defines procedures to inspect and operate the cluster
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def NodeInfo(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ListGhosts(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ObjectLocations(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ClusterSummary(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def Decommission(self, request, context):
+        """the datanode is drained in the background, its progress is reported on its node summary
+        calling it again resumes a drain that failed
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def StartMaintenance(self, request, context):
+        """starting maintenance on a node in maintenance starts its window over
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def EndMaintenance(self, request, context):
+        """a node that is still away is treated as failed, a node not in maintenance is left as it is
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def Rebalance(self, request, context):
+        """plans a rebalance round and runs it in the background, unless a round is running already
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+
+def add_AdminServiceServicer_to_server(servicer, server):
+    rpc_method_handlers = {
+            'ListNodes': grpc.unary_unary_rpc_method_handler(
+                    servicer.ListNodes,
+                    request_deserializer=admin__pb2.ListNodesReq.FromString,
+                    response_serializer=admin__pb2.ListNodesRes.SerializeToString,
+            ),
+            'NodeInfo': grpc.unary_unary_rpc_method_handler(
+                    servicer.NodeInfo,
+                    request_deserializer=admin__pb2.NodeInfoReq.FromString,
+                    response_serializer=admin__pb2.NodeInfoRes.SerializeToString,
+            ),
+            'ListGhosts': grpc.unary_unary_rpc_method_handler(
+                    servicer.ListGhosts,
+                    request_deserializer=admin__pb2.ListGhostsReq.FromString,
+                    response_serializer=admin__pb2.ListGhostsRes.SerializeToString,
+            ),
+            'ObjectLocations': grpc.unary_unary_rpc_method_handler(
+                    servicer.ObjectLocations,
+                    request_deserializer=admin__pb2.ObjectLocationsReq.FromString,
+                    response_serializer=admin__pb2.ObjectLocationsRes.SerializeToString,
+            ),
+            'ClusterSummary': grpc.unary_unary_rpc_method_handler(
+                    servicer.ClusterSummary,
+                    request_deserializer=admin__pb2.ClusterSummaryReq.FromString,
+                    response_serializer=admin__pb2.ClusterSummaryRes.SerializeToString,
+            ),
+            'Decommission': grpc.unary_unary_rpc_method_handler(
+                    servicer.Decommission,
+                    request_deserializer=admin__pb2.DecommissionReq.FromString,
+                    response_serializer=admin__pb2.DecommissionRes.SerializeToString,
+            ),
+            'StartMaintenance': grpc.unary_unary_rpc_method_handler(
+                    servicer.StartMaintenance,
+                    request_deserializer=admin__pb2.StartMaintenanceReq.FromString,
+                    response_serializer=admin__pb2.StartMaintenanceRes.SerializeToString,
+            ),
+            'EndMaintenance': grpc.unary_unary_rpc_method_handler(
+                    servicer.EndMaintenance,
+                    request_deserializer=admin__pb2.EndMaintenanceReq.FromString,
+                    response_serializer=admin__pb2.EndMaintenanceRes.SerializeToString,
+            ),
+            'Rebalance': grpc.unary_unary_rpc_method_handler(
+                    servicer.Rebalance,
+                    request_deserializer=admin__pb2.RebalanceReq.FromString,
+                    response_serializer=admin__pb2.RebalanceRes.SerializeToString,
+            ),
+    }
+    generic_handler = grpc.method_handlers_generic_handler(
+            'proto.AdminService', rpc_method_handlers)
+    server.add_generic_rpc_handlers((generic_handler,))
+    server.add_registered_method_handlers('proto.AdminService', rpc_method_handlers)
+
+
+ # This class is part of an EXPERIMENTAL API.
+class AdminService(object):
+    """Missing associated documentation comment in .proto file."""
+
+    @staticmethod
+    def ListNodes(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/proto.AdminService/ListNodes',
+            admin__pb2.ListNodesReq.SerializeToString,
+            admin__pb2.ListNodesRes.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def NodeInfo(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/proto.AdminService/NodeInfo',
+            admin__pb2.NodeInfoReq.SerializeToString,
+            admin__pb2.NodeInfoRes.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def ListGhosts(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/proto.AdminService/ListGhosts',
+            admin__pb2.ListGhostsReq.SerializeToString,
+            admin__pb2.ListGhostsRes.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def ObjectLocations(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/proto.AdminService/ObjectLocations',
+            admin__pb2.ObjectLocationsReq.SerializeToString,
+            admin__pb2.ObjectLocationsRes.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def ClusterSummary(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/proto.AdminService/ClusterSummary',
+            admin__pb2.ClusterSummaryReq.SerializeToString,
+            admin__pb2.ClusterSummaryRes.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def Decommission(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/proto.AdminService/Decommission',
+            admin__pb2.DecommissionReq.SerializeToString,
+            admin__pb2.DecommissionRes.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def StartMaintenance(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/proto.AdminService/StartMaintenance',
+            admin__pb2.StartMaintenanceReq.SerializeToString,
+            admin__pb2.StartMaintenanceRes.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def EndMaintenance(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/proto.AdminService/EndMaintenance',
+            admin__pb2.EndMaintenanceReq.SerializeToString,
+            admin__pb2.EndMaintenanceRes.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def Rebalance(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/proto.AdminService/Rebalance',
+            admin__pb2.RebalanceReq.SerializeToString,
+            admin__pb2.RebalanceRes.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
@@ -25,7 +25,7 @@ _sym_db = _symbol_database.Default()
 from google.protobuf import timestamp_pb2 as google_dot_protobuf_dot_timestamp__pb2
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0enamenode.proto\x12\x05proto\x1a\x1fgoogle/protobuf/timestamp.proto\"5\n\x0bRequestMeta\x12&\n\x02ts\x18\x01 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\"\x93\x01\n\x0cResponseMeta\x12&\n\x02ts\x18\x01 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12*\n\x06status\x18\x02 \x01(\x0e\x32\x1a.proto.ResponseMeta.Status\"/\n\x06Status\x12\x0b\n\x07\x43REATED\x10\x00\x12\x0b\n\x07\x44\x45LETED\x10\x01\x12\x0b\n\x07UPDATED\x10\x02\"S\n\x13\x43reateObjectRequest\x12 \n\x04meta\x18\x01 \x01(\x0b\x32\x12.proto.RequestMeta\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\x0c\n\x04\x64\x61ta\x18\x03 \x01(\x0c\"9\n\x14\x43reateObjectResponse\x12!\n\x04meta\x18\x01 \x01(\x0b\x32\x13.proto.ResponseMeta\"E\n\x13\x44\x65leteObjectRequest\x12 \n\x04meta\x18\x01 \x01(\x0b\x32\x12.proto.RequestMeta\x12\x0c\n\x04name\x18\x02 \x01(\t\"9\n\x14\x44\x65leteObjectResponse\x12!\n\x04meta\x18\x01 \x01(\x0b\x32\x13.proto.ResponseMeta\"O\n\x0fUpdateObjectReq\x12 \n\x04meta\x18\x01 \x01(\x0b\x32\x12.proto.RequestMeta\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\x0c\n\x04\x64\x61ta\x18\x03 \x01(\x0c\"4\n\x0fUpdateObjectRes\x12!\n\x04meta\x18\x01 \x01(\x0b\x32\x13.proto.ResponseMeta\"@\n\x0eLeaseObjectReq\x12 \n\x04meta\x18\x01 \x01(\x0b\x32\x12.proto.RequestMeta\x12\x0c\n\x04name\x18\x02 \x01(\t\"D\n\x0eLeaseObjectRes\x12!\n\x04meta\x18\x01 \x01(\x0b\x32\x13.proto.ResponseMeta\x12\x0f\n\x07leasers\x18\x02 \x03(\t\"\x95\x02\n\rNodeHeartBeat\x12\'\n\x04type\x18\x07 \x01(\x0e\x32\x19.proto.NodeHeartBeat.Type\x12\n\n\x02id\x18\x01 \x01(\t\x12\x0c\n\x04size\x18\x03 \x01(\x02\x12\x0f\n\x07objects\x18\x04 \x03(\t\x12\x15\n\rleaserService\x18\x05 \x01(\t\x12\x12\n\nmessageTag\x18\x06 \x01(\t\x12/\n\nobjectData\x18\x08 \x03(\x0b\x32\x1b.proto.NodeHeartBeat.Object\x1a$\n\x06Object\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0c\n\x04\x64\x61ta\x18\x02 \x01(\x0c\".\n\x04Type\x12\x07\n\x03\x41\x43K\x10\x00\x12\x08\n\x04\x42\x45\x41T\x10\x01\x12\x13\n\x0f\x44ISTRIUTED_READ\x10\x02\"\xa6\x03\n\x0e\x43ommandNodeRes\x12!\n\x04meta\x18\x01 \x01(\x0b\x32\x13.proto.ResponseMeta\x12.\n\x07\x63ommand\x18\x02 \x01(\x0e\x32\x1d.proto.CommandNodeRes.Command\x12$\n\x06\x63reate\x18\x03 \x01(\x0b\x32\x14.proto.CreateCommand\x12$\n\x06\x63ommit\x18\x04 \x01(\x0b\x32\x14.proto.CommitCommand\x12$\n\x06\x64\x65lete\x18\x05 \x01(\x0b\x32\x14.proto.DeleteCommand\x12$\n\x06update\x18\x06 \x01(\x0b\x32\x14.proto.UpdateCommand\x12\x12\n\nmessageTag\x18\x07 \x01(\t\x12\x36\n\x0f\x64istributedRead\x18\x08 \x01(\x0b\x32\x1d.proto.DistributedReadCommand\"]\n\x07\x43ommand\x12\x0c\n\x08REGISTER\x10\x00\x12\n\n\x06\x43REATE\x10\x01\x12\n\n\x06\x43OMMIT\x10\x02\x12\n\n\x06\x44\x45LETE\x10\x03\x12\n\n\x06UPDATE\x10\x04\x12\x14\n\x10\x44ISTRIBUTED_READ\x10\x05\"=\n\rCreateCommand\x12\x12\n\nobjectName\x18\x01 \x01(\t\x12\x12\n\nobjectData\x18\x03 \x01(\x0cJ\x04\x08\x02\x10\x03\"H\n\rUpdateCommand\x12\x12\n\nobjectName\x18\x01 \x01(\t\x12\x12\n\nobjectData\x18\x02 \x01(\x0c\x12\x0f\n\x07lamport\x18\x03 \x01(\x05\":\n\rCommitCommand\x12\x0f\n\x07lamport\x18\x02 \x01(\x05\x12\x12\n\nobjectName\x18\x03 \x01(\tJ\x04\x08\x01\x10\x02\"4\n\rDeleteCommand\x12\x0f\n\x07lamport\x18\x01 \x01(\x05\x12\x12\n\nobjectName\x18\x02 \x01(\t\":\n\x16\x44istributedReadCommand\x12\x0f\n\x07objects\x18\x01 \x03(\t\x12\x0f\n\x07lamport\x18\x02 \x01(\x05\x32\x9c\x02\n\x0bNameService\x12G\n\x0c\x43reateObject\x12\x1a.proto.CreateObjectRequest\x1a\x1b.proto.CreateObjectResponse\x12G\n\x0c\x44\x65leteObject\x12\x1a.proto.DeleteObjectRequest\x1a\x1b.proto.DeleteObjectResponse\x12>\n\x0cUpdateObject\x12\x16.proto.UpdateObjectReq\x1a\x16.proto.UpdateObjectRes\x12;\n\x0bLeaseObject\x12\x15.proto.LeaseObjectReq\x1a\x15.proto.LeaseObjectRes2N\n\x0b\x44\x61taService\x12?\n\x0cRegisterNode\x12\x14.proto.NodeHeartBeat\x1a\x15.proto.CommandNodeRes(\x01\x30\x01\x42\x0cZ\n../api;apib\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0enamenode.proto\x12\x05proto\x1a\x1fgoogle/protobuf/timestamp.proto\"M\n\x0bRequestMeta\x12&\n\x02ts\x18\x01 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12\x16\n\x0eidempotencyKey\x18\x02 \x01(\t\"\xba\x01\n\x0cResponseMeta\x12&\n\x02ts\x18\x01 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12*\n\x06status\x18\x02 \x01(\x0e\x32\x1a.proto.ResponseMeta.Status\"V\n\x06Status\x12\x0b\n\x07\x43REATED\x10\x00\x12\x0b\n\x07\x44\x45LETED\x10\x01\x12\x0b\n\x07UPDATED\x10\x02\x12\x08\n\x04READ\x10\x03\x12\x0f\n\x0bSNAPSHOTTED\x10\x04\x12\n\n\x06LISTED\x10\x05\"\xf5\x01\n\x14PlacementConstraints\x12;\n\x08required\x18\x01 \x03(\x0b\x32).proto.PlacementConstraints.RequiredEntry\x12=\n\tpreferred\x18\x02 \x03(\x0b\x32*.proto.PlacementConstraints.PreferredEntry\x1a/\n\rRequiredEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\x1a\x30\n\x0ePreferredEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"\x9a\x01\n\x13\x43reateObjectRequest\x12 \n\x04meta\x18\x01 \x01(\x0b\x32\x12.proto.RequestMeta\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\x0c\n\x04\x64\x61ta\x18\x03 \x01(\x0c\x12\x30\n\x0b\x63onstraints\x18\x04 \x01(\x0b\x32\x1b.proto.PlacementConstraints\x12\x13\n\x0breplication\x18\x05 \x01(\r\"9\n\x14\x43reateObjectResponse\x12!\n\x04meta\x18\x01 \x01(\x0b\x32\x13.proto.ResponseMeta\"E\n\x13\x44\x65leteObjectRequest\x12 \n\x04meta\x18\x01 \x01(\x0b\x32\x12.proto.RequestMeta\x12\x0c\n\x04name\x18\x02 \x01(\t\"9\n\x14\x44\x65leteObjectResponse\x12!\n\x04meta\x18\x01 \x01(\x0b\x32\x13.proto.ResponseMeta\"O\n\x0fUpdateObjectReq\x12 \n\x04meta\x18\x01 \x01(\x0b\x32\x12.proto.RequestMeta\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\x0c\n\x04\x64\x61ta\x18\x03 \x01(\x0c\"4\n\x0fUpdateObjectRes\x12!\n\x04meta\x18\x01 \x01(\x0b\x32\x13.proto.ResponseMeta\"@\n\x0eLeaseObjectReq\x12 \n\x04meta\x18\x01 \x01(\x0b\x32\x12.proto.RequestMeta\x12\x0c\n\x04name\x18\x02 \x01(\t\"T\n\x0eLeaseObjectRes\x12!\n\x04meta\x18\x01 \x01(\x0b\x32\x13.proto.ResponseMeta\x12\x0f\n\x07leasers\x18\x02 \x03(\t\x12\x0e\n\x06\x66\x65nces\x18\x03 \x03(\x04\"P\n\x0cGetObjectReq\x12 \n\x04meta\x18\x01 \x01(\x0b\x32\x12.proto.RequestMeta\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\x10\n\x08snapshot\x18\x03 \x01(\t\"?\n\x0cGetObjectRes\x12!\n\x04meta\x18\x01 \x01(\x0b\x32\x13.proto.ResponseMeta\x12\x0c\n\x04\x64\x61ta\x18\x02 \x01(\x0c\"C\n\x11\x43reateSnapshotReq\x12 \n\x04meta\x18\x01 \x01(\x0b\x32\x12.proto.RequestMeta\x12\x0c\n\x04name\x18\x02 \x01(\t\"G\n\x11\x43reateSnapshotRes\x12!\n\x04meta\x18\x01 \x01(\x0b\x32\x13.proto.ResponseMeta\x12\x0f\n\x07lamport\x18\x02 \x01(\x04\"C\n\x11\x44\x65leteSnapshotReq\x12 \n\x04meta\x18\x01 \x01(\x0b\x32\x12.proto.RequestMeta\x12\x0c\n\x04name\x18\x02 \x01(\t\"6\n\x11\x44\x65leteSnapshotRes\x12!\n\x04meta\x18\x01 \x01(\x0b\x32\x13.proto.ResponseMeta\"X\n\x11SetReplicationReq\x12 \n\x04meta\x18\x01 \x01(\x0b\x32\x12.proto.RequestMeta\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\x13\n\x0breplication\x18\x03 \x01(\r\"H\n\x11SetReplicationRes\x12!\n\x04meta\x18\x01 \x01(\x0b\x32\x13.proto.ResponseMeta\x12\x10\n\x08replicas\x18\x02 \x01(\r\"B\n\x0eListObjectsReq\x12 \n\x04meta\x18\x01 \x01(\x0b\x32\x12.proto.RequestMeta\x12\x0e\n\x06prefix\x18\x02 \x01(\t\"B\n\x0eListObjectsRes\x12!\n\x04meta\x18\x01 \x01(\x0b\x32\x13.proto.ResponseMeta\x12\r\n\x05names\x18\x02 \x03(\t\"\xb2\x05\n\rNodeHeartBeat\x12\'\n\x04type\x18\x07 \x01(\x0e\x32\x19.proto.NodeHeartBeat.Type\x12\n\n\x02id\x18\x01 \x01(\t\x12\x0c\n\x04size\x18\x03 \x01(\x02\x12\x0f\n\x07objects\x18\x04 \x03(\t\x12\x15\n\rleaserService\x18\x05 \x01(\t\x12/\n\nobjectData\x18\x08 \x03(\x0b\x32\x1b.proto.NodeHeartBeat.Object\x12#\n\x05\x62\x61tch\x18\t \x03(\x0b\x32\x14.proto.NodeHeartBeat\x12\x11\n\trequestId\x18\n \x01(\x04\x12\x0e\n\x06nodeId\x18\x0b \x01(\t\x12\x11\n\tclusterId\x18\x0c \x01(\t\x12\x11\n\tjoinToken\x18\r \x01(\t\x12\x0f\n\x07lamport\x18\x0e \x01(\x04\x12\'\n\x04\x63ode\x18\x0f \x01(\x0e\x32\x19.proto.NodeHeartBeat.Code\x12\r\n\x05\x65rror\x18\x10 \x01(\t\x12\x10\n\x08\x63\x61pacity\x18\x11 \x01(\x02\x12\x0c\n\x04zone\x18\x12 \x01(\t\x12\x0c\n\x04rack\x18\x13 \x01(\t\x12\x30\n\x06labels\x18\x14 \x03(\x0b\x32 .proto.NodeHeartBeat.LabelsEntry\x12\r\n\x05\x66\x65nce\x18\x15 \x01(\x04\x1a$\n\x06Object\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0c\n\x04\x64\x61ta\x18\x02 \x01(\x0c\x1a-\n\x0bLabelsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"9\n\x04Type\x12\x07\n\x03\x41\x43K\x10\x00\x12\x08\n\x04\x42\x45\x41T\x10\x01\x12\x13\n\x0f\x44ISTRIUTED_READ\x10\x02\x12\t\n\x05\x42\x41TCH\x10\x03\"I\n\x04\x43ode\x12\x06\n\x02OK\x10\x00\x12\n\n\x06\x46\x41ILED\x10\x01\x12\r\n\tDISK_FULL\x10\x02\x12\x0e\n\nCONSTRAINT\x10\x03\x12\x0e\n\nCORRUPTION\x10\x04J\x04\x08\x06\x10\x07\"\x84\x05\n\x0e\x43ommandNodeRes\x12!\n\x04meta\x18\x01 \x01(\x0b\x32\x13.proto.ResponseMeta\x12.\n\x07\x63ommand\x18\x02 \x01(\x0e\x32\x1d.proto.CommandNodeRes.Command\x12$\n\x06\x63reate\x18\x03 \x01(\x0b\x32\x14.proto.CreateCommand\x12$\n\x06\x63ommit\x18\x04 \x01(\x0b\x32\x14.proto.CommitCommand\x12$\n\x06\x64\x65lete\x18\x05 \x01(\x0b\x32\x14.proto.DeleteCommand\x12$\n\x06update\x18\x06 \x01(\x0b\x32\x14.proto.UpdateCommand\x12\x36\n\x0f\x64istributedRead\x18\x08 \x01(\x0b\x32\x1d.proto.DistributedReadCommand\x12(\n\x08snapshot\x18\t \x01(\x0b\x32\x16.proto.SnapshotCommand\x12$\n\x05\x62\x61tch\x18\n \x03(\x0b\x32\x15.proto.CommandNodeRes\x12\x11\n\trequestId\x18\x0b \x01(\x04\x12\r\n\x05\x65poch\x18\x0c \x01(\r\x12(\n\x08register\x18\r \x01(\x0b\x32\x16.proto.RegisterCommand\x12\r\n\x05\x66\x65nce\x18\x0e \x01(\x04\"\x9d\x01\n\x07\x43ommand\x12\x0c\n\x08REGISTER\x10\x00\x12\n\n\x06\x43REATE\x10\x01\x12\n\n\x06\x43OMMIT\x10\x02\x12\n\n\x06\x44\x45LETE\x10\x03\x12\n\n\x06UPDATE\x10\x04\x12\x14\n\x10\x44ISTRIBUTED_READ\x10\x05\x12\x0c\n\x08SNAPSHOT\x10\x06\x12\x13\n\x0f\x44\x45LETE_SNAPSHOT\x10\x07\x12\t\n\x05\x42\x41TCH\x10\x08\x12\x10\n\x0c\x44\x45\x43OMMISSION\x10\tJ\x04\x08\x07\x10\x08\"=\n\rCreateCommand\x12\x12\n\nobjectName\x18\x01 \x01(\t\x12\x12\n\nobjectData\x18\x03 \x01(\x0cJ\x04\x08\x02\x10\x03\"H\n\rUpdateCommand\x12\x12\n\nobjectName\x18\x01 \x01(\t\x12\x12\n\nobjectData\x18\x02 \x01(\x0c\x12\x0f\n\x07lamport\x18\x03 \x01(\x04\"I\n\rCommitCommand\x12\x0f\n\x07lamport\x18\x02 \x01(\x04\x12\x12\n\nobjectName\x18\x03 \x01(\t\x12\r\n\x05nodes\x18\x04 \x03(\tJ\x04\x08\x01\x10\x02\"B\n\rDeleteCommand\x12\x0f\n\x07lamport\x18\x01 \x01(\x04\x12\x12\n\nobjectName\x18\x02 \x01(\t\x12\x0c\n\x04node\x18\x03 \x01(\t\"L\n\x16\x44istributedReadCommand\x12\x0f\n\x07objects\x18\x01 \x03(\t\x12\x0f\n\x07lamport\x18\x02 \x01(\x04\x12\x10\n\x08snapshot\x18\x03 \x01(\t\"E\n\x0fRegisterCommand\x12\x0f\n\x07lamport\x18\x01 \x01(\x04\x12\x11\n\tclusterId\x18\x02 \x01(\t\x12\x0e\n\x06resync\x18\x03 \x01(\x08\"0\n\x0fSnapshotCommand\x12\x0f\n\x07lamport\x18\x01 \x01(\x04\x12\x0c\n\x04name\x18\x02 \x01(\t2\xe2\x04\n\x0bNameService\x12G\n\x0c\x43reateObject\x12\x1a.proto.CreateObjectRequest\x1a\x1b.proto.CreateObjectResponse\x12G\n\x0c\x44\x65leteObject\x12\x1a.proto.DeleteObjectRequest\x1a\x1b.proto.DeleteObjectResponse\x12>\n\x0cUpdateObject\x12\x16.proto.UpdateObjectReq\x1a\x16.proto.UpdateObjectRes\x12;\n\x0bLeaseObject\x12\x15.proto.LeaseObjectReq\x1a\x15.proto.LeaseObjectRes\x12\x35\n\tGetObject\x12\x13.proto.GetObjectReq\x1a\x13.proto.GetObjectRes\x12\x44\n\x0e\x43reateSnapshot\x12\x18.proto.CreateSnapshotReq\x1a\x18.proto.CreateSnapshotRes\x12\x44\n\x0e\x44\x65leteSnapshot\x12\x18.proto.DeleteSnapshotReq\x1a\x18.proto.DeleteSnapshotRes\x12;\n\x0bListObjects\x12\x15.proto.ListObjectsReq\x1a\x15.proto.ListObjectsRes\x12\x44\n\x0eSetReplication\x12\x18.proto.SetReplicationReq\x1a\x18.proto.SetReplicationRes2N\n\x0b\x44\x61taService\x12?\n\x0cRegisterNode\x12\x14.proto.NodeHeartBeat\x1a\x15.proto.CommandNodeRes(\x01\x30\x01\x42\x0cZ\n../api;apib\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -34,49 +34,83 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['DESCRIPTOR']._loaded_options = None
   _globals['DESCRIPTOR']._serialized_options = b'Z\n../api;api'
   _globals['_REQUESTMETA']._serialized_start=58
-  _globals['_REQUESTMETA']._serialized_end=111
-  _globals['_RESPONSEMETA']._serialized_start=114
-  _globals['_RESPONSEMETA']._serialized_end=261
-  _globals['_RESPONSEMETA_STATUS']._serialized_start=214
-  _globals['_RESPONSEMETA_STATUS']._serialized_end=261
-  _globals['_CREATEOBJECTREQUEST']._serialized_start=263
-  _globals['_CREATEOBJECTREQUEST']._serialized_end=346
-  _globals['_CREATEOBJECTRESPONSE']._serialized_start=348
-  _globals['_CREATEOBJECTRESPONSE']._serialized_end=405
-  _globals['_DELETEOBJECTREQUEST']._serialized_start=407
-  _globals['_DELETEOBJECTREQUEST']._serialized_end=476
-  _globals['_DELETEOBJECTRESPONSE']._serialized_start=478
-  _globals['_DELETEOBJECTRESPONSE']._serialized_end=535
-  _globals['_UPDATEOBJECTREQ']._serialized_start=537
-  _globals['_UPDATEOBJECTREQ']._serialized_end=616
-  _globals['_UPDATEOBJECTRES']._serialized_start=618
-  _globals['_UPDATEOBJECTRES']._serialized_end=670
-  _globals['_LEASEOBJECTREQ']._serialized_start=672
-  _globals['_LEASEOBJECTREQ']._serialized_end=736
-  _globals['_LEASEOBJECTRES']._serialized_start=738
-  _globals['_LEASEOBJECTRES']._serialized_end=806
-  _globals['_NODEHEARTBEAT']._serialized_start=809
-  _globals['_NODEHEARTBEAT']._serialized_end=1086
-  _globals['_NODEHEARTBEAT_OBJECT']._serialized_start=1002
-  _globals['_NODEHEARTBEAT_OBJECT']._serialized_end=1038
-  _globals['_NODEHEARTBEAT_TYPE']._serialized_start=1040
-  _globals['_NODEHEARTBEAT_TYPE']._serialized_end=1086
-  _globals['_COMMANDNODERES']._serialized_start=1089
-  _globals['_COMMANDNODERES']._serialized_end=1511
-  _globals['_COMMANDNODERES_COMMAND']._serialized_start=1418
-  _globals['_COMMANDNODERES_COMMAND']._serialized_end=1511
-  _globals['_CREATECOMMAND']._serialized_start=1513
-  _globals['_CREATECOMMAND']._serialized_end=1574
-  _globals['_UPDATECOMMAND']._serialized_start=1576
-  _globals['_UPDATECOMMAND']._serialized_end=1648
-  _globals['_COMMITCOMMAND']._serialized_start=1650
-  _globals['_COMMITCOMMAND']._serialized_end=1708
-  _globals['_DELETECOMMAND']._serialized_start=1710
-  _globals['_DELETECOMMAND']._serialized_end=1762
-  _globals['_DISTRIBUTEDREADCOMMAND']._serialized_start=1764
-  _globals['_DISTRIBUTEDREADCOMMAND']._serialized_end=1822
-  _globals['_NAMESERVICE']._serialized_start=1825
-  _globals['_NAMESERVICE']._serialized_end=2109
-  _globals['_DATASERVICE']._serialized_start=2111
-  _globals['_DATASERVICE']._serialized_end=2189
+  _globals['_REQUESTMETA']._serialized_end=135
+  _globals['_RESPONSEMETA']._serialized_start=138
+  _globals['_RESPONSEMETA']._serialized_end=324
+  _globals['_RESPONSEMETA_STATUS']._serialized_start=238
+  _globals['_RESPONSEMETA_STATUS']._serialized_end=324
+  _globals['_PLACEMENTCONSTRAINTS']._serialized_start=327
+  _globals['_PLACEMENTCONSTRAINTS']._serialized_end=572
+  _globals['_PLACEMENTCONSTRAINTS_REQUIREDENTRY']._serialized_start=475
+  _globals['_PLACEMENTCONSTRAINTS_REQUIREDENTRY']._serialized_end=522
+  _globals['_PLACEMENTCONSTRAINTS_PREFERREDENTRY']._serialized_start=524
+  _globals['_PLACEMENTCONSTRAINTS_PREFERREDENTRY']._serialized_end=572
+  _globals['_CREATEOBJECTREQUEST']._serialized_start=575
+  _globals['_CREATEOBJECTREQUEST']._serialized_end=729
+  _globals['_CREATEOBJECTRESPONSE']._serialized_start=731
+  _globals['_CREATEOBJECTRESPONSE']._serialized_end=788
+  _globals['_DELETEOBJECTREQUEST']._serialized_start=790
+  _globals['_DELETEOBJECTREQUEST']._serialized_end=859
+  _globals['_DELETEOBJECTRESPONSE']._serialized_start=861
+  _globals['_DELETEOBJECTRESPONSE']._serialized_end=918
+  _globals['_UPDATEOBJECTREQ']._serialized_start=920
+  _globals['_UPDATEOBJECTREQ']._serialized_end=999
+  _globals['_UPDATEOBJECTRES']._serialized_start=1001
+  _globals['_UPDATEOBJECTRES']._serialized_end=1053
+  _globals['_LEASEOBJECTREQ']._serialized_start=1055
+  _globals['_LEASEOBJECTREQ']._serialized_end=1119
+  _globals['_LEASEOBJECTRES']._serialized_start=1121
+  _globals['_LEASEOBJECTRES']._serialized_end=1205
+  _globals['_GETOBJECTREQ']._serialized_start=1207
+  _globals['_GETOBJECTREQ']._serialized_end=1287
+  _globals['_GETOBJECTRES']._serialized_start=1289
+  _globals['_GETOBJECTRES']._serialized_end=1352
+  _globals['_CREATESNAPSHOTREQ']._serialized_start=1354
+  _globals['_CREATESNAPSHOTREQ']._serialized_end=1421
+  _globals['_CREATESNAPSHOTRES']._serialized_start=1423
+  _globals['_CREATESNAPSHOTRES']._serialized_end=1494
+  _globals['_DELETESNAPSHOTREQ']._serialized_start=1496
+  _globals['_DELETESNAPSHOTREQ']._serialized_end=1563
+  _globals['_DELETESNAPSHOTRES']._serialized_start=1565
+  _globals['_DELETESNAPSHOTRES']._serialized_end=1619
+  _globals['_SETREPLICATIONREQ']._serialized_start=1621
+  _globals['_SETREPLICATIONREQ']._serialized_end=1709
+  _globals['_SETREPLICATIONRES']._serialized_start=1711
+  _globals['_SETREPLICATIONRES']._serialized_end=1783
+  _globals['_LISTOBJECTSREQ']._serialized_start=1785
+  _globals['_LISTOBJECTSREQ']._serialized_end=1851
+  _globals['_LISTOBJECTSRES']._serialized_start=1853
+  _globals['_LISTOBJECTSRES']._serialized_end=1919
+  _globals['_NODEHEARTBEAT']._serialized_start=1922
+  _globals['_NODEHEARTBEAT']._serialized_end=2612
+  _globals['_NODEHEARTBEAT_OBJECT']._serialized_start=2389
+  _globals['_NODEHEARTBEAT_OBJECT']._serialized_end=2425
+  _globals['_NODEHEARTBEAT_LABELSENTRY']._serialized_start=2427
+  _globals['_NODEHEARTBEAT_LABELSENTRY']._serialized_end=2472
+  _globals['_NODEHEARTBEAT_TYPE']._serialized_start=2474
+  _globals['_NODEHEARTBEAT_TYPE']._serialized_end=2531
+  _globals['_NODEHEARTBEAT_CODE']._serialized_start=2533
+  _globals['_NODEHEARTBEAT_CODE']._serialized_end=2606
+  _globals['_COMMANDNODERES']._serialized_start=2615
+  _globals['_COMMANDNODERES']._serialized_end=3259
+  _globals['_COMMANDNODERES_COMMAND']._serialized_start=3096
+  _globals['_COMMANDNODERES_COMMAND']._serialized_end=3253
+  _globals['_CREATECOMMAND']._serialized_start=3261
+  _globals['_CREATECOMMAND']._serialized_end=3322
+  _globals['_UPDATECOMMAND']._serialized_start=3324
+  _globals['_UPDATECOMMAND']._serialized_end=3396
+  _globals['_COMMITCOMMAND']._serialized_start=3398
+  _globals['_COMMITCOMMAND']._serialized_end=3471
+  _globals['_DELETECOMMAND']._serialized_start=3473
+  _globals['_DELETECOMMAND']._serialized_end=3539
+  _globals['_DISTRIBUTEDREADCOMMAND']._serialized_start=3541
+  _globals['_DISTRIBUTEDREADCOMMAND']._serialized_end=3617
+  _globals['_REGISTERCOMMAND']._serialized_start=3619
+  _globals['_REGISTERCOMMAND']._serialized_end=3688
+  _globals['_SNAPSHOTCOMMAND']._serialized_start=3690
+  _globals['_SNAPSHOTCOMMAND']._serialized_end=3738
+  _globals['_NAMESERVICE']._serialized_start=3741
+  _globals['_NAMESERVICE']._serialized_end=4351
+  _globals['_DATASERVICE']._serialized_start=4353
+  _globals['_DATASERVICE']._serialized_end=4431
 # @@protoc_insertion_point(module_scope)
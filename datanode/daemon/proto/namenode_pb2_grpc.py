@@ -54,6 +54,31 @@ class NameServiceStub(object):
                 request_serializer=namenode__pb2.LeaseObjectReq.SerializeToString,
                 response_deserializer=namenode__pb2.LeaseObjectRes.FromString,
                 _registered_method=True)
+        self.GetObject = channel.unary_unary(
+                '/proto.NameService/GetObject',
+                request_serializer=namenode__pb2.GetObjectReq.SerializeToString,
+                response_deserializer=namenode__pb2.GetObjectRes.FromString,
+                _registered_method=True)
+        self.CreateSnapshot = channel.unary_unary(
+                '/proto.NameService/CreateSnapshot',
+                request_serializer=namenode__pb2.CreateSnapshotReq.SerializeToString,
+                response_deserializer=namenode__pb2.CreateSnapshotRes.FromString,
+                _registered_method=True)
+        self.DeleteSnapshot = channel.unary_unary(
+                '/proto.NameService/DeleteSnapshot',
+                request_serializer=namenode__pb2.DeleteSnapshotReq.SerializeToString,
+                response_deserializer=namenode__pb2.DeleteSnapshotRes.FromString,
+                _registered_method=True)
+        self.ListObjects = channel.unary_unary(
+                '/proto.NameService/ListObjects',
+                request_serializer=namenode__pb2.ListObjectsReq.SerializeToString,
+                response_deserializer=namenode__pb2.ListObjectsRes.FromString,
+                _registered_method=True)
+        self.SetReplication = channel.unary_unary(
+                '/proto.NameService/SetReplication',
+                request_serializer=namenode__pb2.SetReplicationReq.SerializeToString,
+                response_deserializer=namenode__pb2.SetReplicationRes.FromString,
+                _registered_method=True)
 
 
 class NameServiceServicer(object):
@@ -84,6 +109,36 @@ class NameServiceServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def GetObject(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def CreateSnapshot(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def DeleteSnapshot(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ListObjects(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def SetReplication(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_NameServiceServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -107,6 +162,31 @@ def add_NameServiceServicer_to_server(servicer, server):
                     request_deserializer=namenode__pb2.LeaseObjectReq.FromString,
                     response_serializer=namenode__pb2.LeaseObjectRes.SerializeToString,
             ),
+            'GetObject': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetObject,
+                    request_deserializer=namenode__pb2.GetObjectReq.FromString,
+                    response_serializer=namenode__pb2.GetObjectRes.SerializeToString,
+            ),
+            'CreateSnapshot': grpc.unary_unary_rpc_method_handler(
+                    servicer.CreateSnapshot,
+                    request_deserializer=namenode__pb2.CreateSnapshotReq.FromString,
+                    response_serializer=namenode__pb2.CreateSnapshotRes.SerializeToString,
+            ),
+            'DeleteSnapshot': grpc.unary_unary_rpc_method_handler(
+                    servicer.DeleteSnapshot,
+                    request_deserializer=namenode__pb2.DeleteSnapshotReq.FromString,
+                    response_serializer=namenode__pb2.DeleteSnapshotRes.SerializeToString,
+            ),
+            'ListObjects': grpc.unary_unary_rpc_method_handler(
+                    servicer.ListObjects,
+                    request_deserializer=namenode__pb2.ListObjectsReq.FromString,
+                    response_serializer=namenode__pb2.ListObjectsRes.SerializeToString,
+            ),
+            'SetReplication': grpc.unary_unary_rpc_method_handler(
+                    servicer.SetReplication,
+                    request_deserializer=namenode__pb2.SetReplicationReq.FromString,
+                    response_serializer=namenode__pb2.SetReplicationRes.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'proto.NameService', rpc_method_handlers)
@@ -226,6 +306,141 @@ class NameService(object):
             metadata,
             _registered_method=True)
 
+    @staticmethod
+    def GetObject(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/proto.NameService/GetObject',
+            namenode__pb2.GetObjectReq.SerializeToString,
+            namenode__pb2.GetObjectRes.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def CreateSnapshot(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/proto.NameService/CreateSnapshot',
+            namenode__pb2.CreateSnapshotReq.SerializeToString,
+            namenode__pb2.CreateSnapshotRes.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def DeleteSnapshot(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/proto.NameService/DeleteSnapshot',
+            namenode__pb2.DeleteSnapshotReq.SerializeToString,
+            namenode__pb2.DeleteSnapshotRes.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def ListObjects(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/proto.NameService/ListObjects',
+            namenode__pb2.ListObjectsReq.SerializeToString,
+            namenode__pb2.ListObjectsRes.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def SetReplication(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/proto.NameService/SetReplication',
+            namenode__pb2.SetReplicationReq.SerializeToString,
+            namenode__pb2.SetReplicationRes.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
 
 class DataServiceStub(object):
     """Missing associated documentation comment in .proto file."""
@@ -20,17 +20,20 @@ class ISpawner(Protocol):
     def run(): None
 
 class Spawner:
-    def __init__(self, name: str, port: int, db: str):
+    def __init__(self, name: str, port: int, db: str, zone: str = "", rack: str = ""):
         self.namenode = port
         self.name = name
         self.db = db
+        # failure domain of the datanode this ghost boots, the namenode spreads replicas over them
+        self.zone = zone
+        self.rack = rack
 
     def run(self: "Spawner") -> int:
         # Create a channel to the server
         print("running spawner")
         with grpc.insecure_channel(f'localhost:{self.namenode}') as channel:
             stub = ghostservice.GhostServiceStub(channel)
-            request = ghost.SpawnWait(name=self.name)
+            request = ghost.SpawnWait(name=self.name, zone=self.zone, rack=self.rack)
 
             try:
                 response_stream = stub.Spawn(request)
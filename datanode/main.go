@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"google.golang.org/grpc"
@@ -16,17 +18,42 @@ import (
 var (
 	port    int
 	store   string
+	backend string
 	process string
 	lease   string
-	lamport int
+	lamport uint64
+	queue   string
+	qfile   string
+	ident   string
+	token   string
+	capa    float64
+	zone    string
+	rack    string
+	labels  = make(map[string]string)
 )
 
 func main() {
 	flag.IntVar(&port, "port", 50051, "port of name node service")
-	flag.StringVar(&store, "store", "data.db", "store .db file name")
+	flag.StringVar(&store, "store", "data.db", "store .db file name, or store directory for the file backend")
+	flag.StringVar(&backend, "backend", "sql", "storage backend: sql, file or memory")
 	flag.StringVar(&process, "process", "", "name of the process. required for unique queues in redis")
+	flag.StringVar(&queue, "queue", "sql", "command queue: sql or redis")
+	flag.StringVar(&qfile, "queuefile", "", "sqlite file of the sql queue. defaults to the store")
 	flag.StringVar(&lease, "lease", "", "lease address")
-	flag.IntVar(&lamport, "lamport", 0, "initial lamport")
+	flag.Uint64Var(&lamport, "lamport", 0, "initial lamport, used when it is ahead of the lamport recorded in the store")
+	flag.StringVar(&ident, "identity", "", "identity file of the node. defaults to <process>.identity.json")
+	flag.StringVar(&token, "token", "", "join token of the cluster")
+	flag.Float64Var(&capa, "capacity", 0, "bytes the store may hold, reported for placement. 0 for unbounded")
+	flag.StringVar(&zone, "zone", "", "zone of the node, replicas are spread over zones and racks")
+	flag.StringVar(&rack, "rack", "", "rack of the node within its zone")
+	flag.Func("label", "key=value label of the node, matched by placement constraints. may be repeated", func(label string) error {
+		key, value, ok := strings.Cut(label, "=")
+		if !ok || len(key) == 0 {
+			return fmt.Errorf("label %q is not key=value", label)
+		}
+		labels[key] = value
+		return nil
+	})
 	flag.Parse()
 
 	if len(process) == 0 {
@@ -46,13 +73,61 @@ func main() {
 		}()
 	}
 
-	client := dos.NewDosDataNode(
+	var storeOpt dos.DNodeConfigFunc
+	switch dos.StoreBackend(backend) {
+	case dos.SqlBackend:
+		storeOpt = dos.WithDBFile(store)
+	case dos.FileBackend:
+		storeOpt = dos.WithFileStore(store)
+	case dos.MemoryBackend:
+		storeOpt = dos.WithMemoryStore()
+	default:
+		log.Fatalf("unknown -backend %s", backend)
+	}
+
+	var dnQueue dos.DataNodeQueue
+	switch queue {
+	case "sql":
+		if len(qfile) == 0 {
+			qfile = defaultQueueFile()
+		}
+		dnQueue, err = dos.NewDataNodeSqlQueue(qfile)
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "redis":
+		dnQueue = NewRedisDataNodeQueue(process)
+	default:
+		log.Fatalf("unknown -queue %s", queue)
+	}
+
+	client, err := dos.NewDosDataNode(
 		conn,
-		NewRedisDataNodeQueue(),
-		dos.WithDBFile(store),
+		dnQueue,
+		storeOpt,
 		dos.WithLeaser(lease),
 		dos.WithName(process),
 		dos.WithLamport(lamport),
+		dos.WithIdentityFile(ident),
+		dos.WithJoinToken(token),
+		dos.WithCapacity(float32(capa)),
+		dos.WithTopology(zone, rack),
+		dos.WithLabels(labels),
 	)
+	if err != nil {
+		log.Fatal(err)
+	}
 	client.Register()
 }
+
+// the sql queue shares the sqlite store file, or lives next to the objects of the file store
+func defaultQueueFile() string {
+	switch dos.StoreBackend(backend) {
+	case dos.FileBackend:
+		return filepath.Join(store, "queue.db")
+	case dos.MemoryBackend:
+		return ":memory:"
+	default:
+		return store
+	}
+}
@@ -0,0 +1,439 @@
+// this proto file contains definitions for inspecting and operating the cluster through the name node
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.21.12
+// source: admin.proto
+
+package api
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AdminService_ListNodes_FullMethodName        = "/proto.AdminService/ListNodes"
+	AdminService_NodeInfo_FullMethodName         = "/proto.AdminService/NodeInfo"
+	AdminService_ListGhosts_FullMethodName       = "/proto.AdminService/ListGhosts"
+	AdminService_ObjectLocations_FullMethodName  = "/proto.AdminService/ObjectLocations"
+	AdminService_ClusterSummary_FullMethodName   = "/proto.AdminService/ClusterSummary"
+	AdminService_Decommission_FullMethodName     = "/proto.AdminService/Decommission"
+	AdminService_StartMaintenance_FullMethodName = "/proto.AdminService/StartMaintenance"
+	AdminService_EndMaintenance_FullMethodName   = "/proto.AdminService/EndMaintenance"
+	AdminService_Rebalance_FullMethodName        = "/proto.AdminService/Rebalance"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminServiceClient interface {
+	// this service defines procedures to inspect and operate the cluster
+	ListNodes(ctx context.Context, in *ListNodesReq, opts ...grpc.CallOption) (*ListNodesRes, error)
+	NodeInfo(ctx context.Context, in *NodeInfoReq, opts ...grpc.CallOption) (*NodeInfoRes, error)
+	ListGhosts(ctx context.Context, in *ListGhostsReq, opts ...grpc.CallOption) (*ListGhostsRes, error)
+	ObjectLocations(ctx context.Context, in *ObjectLocationsReq, opts ...grpc.CallOption) (*ObjectLocationsRes, error)
+	ClusterSummary(ctx context.Context, in *ClusterSummaryReq, opts ...grpc.CallOption) (*ClusterSummaryRes, error)
+	// the datanode is drained in the background, its progress is reported on its node summary
+	// calling it again resumes a drain that failed
+	Decommission(ctx context.Context, in *DecommissionReq, opts ...grpc.CallOption) (*DecommissionRes, error)
+	// starting maintenance on a node in maintenance starts its window over
+	StartMaintenance(ctx context.Context, in *StartMaintenanceReq, opts ...grpc.CallOption) (*StartMaintenanceRes, error)
+	// a node that is still away is treated as failed, a node not in maintenance is left as it is
+	EndMaintenance(ctx context.Context, in *EndMaintenanceReq, opts ...grpc.CallOption) (*EndMaintenanceRes, error)
+	// plans a rebalance round and runs it in the background, unless a round is running already
+	Rebalance(ctx context.Context, in *RebalanceReq, opts ...grpc.CallOption) (*RebalanceRes, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) ListNodes(ctx context.Context, in *ListNodesReq, opts ...grpc.CallOption) (*ListNodesRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNodesRes)
+	err := c.cc.Invoke(ctx, AdminService_ListNodes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) NodeInfo(ctx context.Context, in *NodeInfoReq, opts ...grpc.CallOption) (*NodeInfoRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeInfoRes)
+	err := c.cc.Invoke(ctx, AdminService_NodeInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListGhosts(ctx context.Context, in *ListGhostsReq, opts ...grpc.CallOption) (*ListGhostsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGhostsRes)
+	err := c.cc.Invoke(ctx, AdminService_ListGhosts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ObjectLocations(ctx context.Context, in *ObjectLocationsReq, opts ...grpc.CallOption) (*ObjectLocationsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ObjectLocationsRes)
+	err := c.cc.Invoke(ctx, AdminService_ObjectLocations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ClusterSummary(ctx context.Context, in *ClusterSummaryReq, opts ...grpc.CallOption) (*ClusterSummaryRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClusterSummaryRes)
+	err := c.cc.Invoke(ctx, AdminService_ClusterSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) Decommission(ctx context.Context, in *DecommissionReq, opts ...grpc.CallOption) (*DecommissionRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecommissionRes)
+	err := c.cc.Invoke(ctx, AdminService_Decommission_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) StartMaintenance(ctx context.Context, in *StartMaintenanceReq, opts ...grpc.CallOption) (*StartMaintenanceRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartMaintenanceRes)
+	err := c.cc.Invoke(ctx, AdminService_StartMaintenance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) EndMaintenance(ctx context.Context, in *EndMaintenanceReq, opts ...grpc.CallOption) (*EndMaintenanceRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EndMaintenanceRes)
+	err := c.cc.Invoke(ctx, AdminService_EndMaintenance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) Rebalance(ctx context.Context, in *RebalanceReq, opts ...grpc.CallOption) (*RebalanceRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RebalanceRes)
+	err := c.cc.Invoke(ctx, AdminService_Rebalance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility.
+type AdminServiceServer interface {
+	// this service defines procedures to inspect and operate the cluster
+	ListNodes(context.Context, *ListNodesReq) (*ListNodesRes, error)
+	NodeInfo(context.Context, *NodeInfoReq) (*NodeInfoRes, error)
+	ListGhosts(context.Context, *ListGhostsReq) (*ListGhostsRes, error)
+	ObjectLocations(context.Context, *ObjectLocationsReq) (*ObjectLocationsRes, error)
+	ClusterSummary(context.Context, *ClusterSummaryReq) (*ClusterSummaryRes, error)
+	// the datanode is drained in the background, its progress is reported on its node summary
+	// calling it again resumes a drain that failed
+	Decommission(context.Context, *DecommissionReq) (*DecommissionRes, error)
+	// starting maintenance on a node in maintenance starts its window over
+	StartMaintenance(context.Context, *StartMaintenanceReq) (*StartMaintenanceRes, error)
+	// a node that is still away is treated as failed, a node not in maintenance is left as it is
+	EndMaintenance(context.Context, *EndMaintenanceReq) (*EndMaintenanceRes, error)
+	// plans a rebalance round and runs it in the background, unless a round is running already
+	Rebalance(context.Context, *RebalanceReq) (*RebalanceRes, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServiceServer struct{}
+
+func (UnimplementedAdminServiceServer) ListNodes(context.Context, *ListNodesReq) (*ListNodesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNodes not implemented")
+}
+func (UnimplementedAdminServiceServer) NodeInfo(context.Context, *NodeInfoReq) (*NodeInfoRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NodeInfo not implemented")
+}
+func (UnimplementedAdminServiceServer) ListGhosts(context.Context, *ListGhostsReq) (*ListGhostsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGhosts not implemented")
+}
+func (UnimplementedAdminServiceServer) ObjectLocations(context.Context, *ObjectLocationsReq) (*ObjectLocationsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ObjectLocations not implemented")
+}
+func (UnimplementedAdminServiceServer) ClusterSummary(context.Context, *ClusterSummaryReq) (*ClusterSummaryRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClusterSummary not implemented")
+}
+func (UnimplementedAdminServiceServer) Decommission(context.Context, *DecommissionReq) (*DecommissionRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Decommission not implemented")
+}
+func (UnimplementedAdminServiceServer) StartMaintenance(context.Context, *StartMaintenanceReq) (*StartMaintenanceRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartMaintenance not implemented")
+}
+func (UnimplementedAdminServiceServer) EndMaintenance(context.Context, *EndMaintenanceReq) (*EndMaintenanceRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EndMaintenance not implemented")
+}
+func (UnimplementedAdminServiceServer) Rebalance(context.Context, *RebalanceReq) (*RebalanceRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rebalance not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_ListNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNodesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListNodes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListNodes(ctx, req.(*ListNodesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_NodeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeInfoReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).NodeInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_NodeInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).NodeInfo(ctx, req.(*NodeInfoReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListGhosts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGhostsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListGhosts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListGhosts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListGhosts(ctx, req.(*ListGhostsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ObjectLocations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ObjectLocationsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ObjectLocations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ObjectLocations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ObjectLocations(ctx, req.(*ObjectLocationsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ClusterSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClusterSummaryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ClusterSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ClusterSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ClusterSummary(ctx, req.(*ClusterSummaryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_Decommission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecommissionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).Decommission(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_Decommission_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).Decommission(ctx, req.(*DecommissionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_StartMaintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartMaintenanceReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).StartMaintenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_StartMaintenance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).StartMaintenance(ctx, req.(*StartMaintenanceReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_EndMaintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EndMaintenanceReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).EndMaintenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_EndMaintenance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).EndMaintenance(ctx, req.(*EndMaintenanceReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_Rebalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RebalanceReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).Rebalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_Rebalance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).Rebalance(ctx, req.(*RebalanceReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListNodes",
+			Handler:    _AdminService_ListNodes_Handler,
+		},
+		{
+			MethodName: "NodeInfo",
+			Handler:    _AdminService_NodeInfo_Handler,
+		},
+		{
+			MethodName: "ListGhosts",
+			Handler:    _AdminService_ListGhosts_Handler,
+		},
+		{
+			MethodName: "ObjectLocations",
+			Handler:    _AdminService_ObjectLocations_Handler,
+		},
+		{
+			MethodName: "ClusterSummary",
+			Handler:    _AdminService_ClusterSummary_Handler,
+		},
+		{
+			MethodName: "Decommission",
+			Handler:    _AdminService_Decommission_Handler,
+		},
+		{
+			MethodName: "StartMaintenance",
+			Handler:    _AdminService_StartMaintenance_Handler,
+		},
+		{
+			MethodName: "EndMaintenance",
+			Handler:    _AdminService_EndMaintenance_Handler,
+		},
+		{
+			MethodName: "Rebalance",
+			Handler:    _AdminService_Rebalance_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin.proto",
+}
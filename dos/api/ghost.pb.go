@@ -122,7 +122,7 @@ type SpawnCommand struct {
 	unknownFields protoimpl.UnknownFields
 
 	Status   SpawnCommand_Status `protobuf:"varint,2,opt,name=status,proto3,enum=proto.SpawnCommand_Status" json:"status,omitempty"`
-	Lamport  int32               `protobuf:"varint,3,opt,name=lamport,proto3" json:"lamport,omitempty"`
+	Lamport  uint64              `protobuf:"varint,3,opt,name=lamport,proto3" json:"lamport,omitempty"`
 	Commands []*CreateCommand    `protobuf:"bytes,1,rep,name=commands,proto3" json:"commands,omitempty"`
 }
 
@@ -163,7 +163,7 @@ func (x *SpawnCommand) GetStatus() SpawnCommand_Status {
 	return SpawnCommand_WAIT
 }
 
-func (x *SpawnCommand) GetLamport() int32 {
+func (x *SpawnCommand) GetLamport() uint64 {
 	if x != nil {
 		return x.Lamport
 	}
@@ -185,6 +185,9 @@ type SpawnWait struct {
 	// this message is used by the ghost node to wait for a spawn command
 	// by the ghost node
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// failure domain the datanode it turns into registers in, a failed node's objects go to the ghost that spreads them the most
+	Zone string `protobuf:"bytes,2,opt,name=zone,proto3" json:"zone,omitempty"`
+	Rack string `protobuf:"bytes,3,opt,name=rack,proto3" json:"rack,omitempty"`
 }
 
 func (x *SpawnWait) Reset() {
@@ -224,6 +227,20 @@ func (x *SpawnWait) GetName() string {
 	return ""
 }
 
+func (x *SpawnWait) GetZone() string {
+	if x != nil {
+		return x.Zone
+	}
+	return ""
+}
+
+func (x *SpawnWait) GetRack() string {
+	if x != nil {
+		return x.Rack
+	}
+	return ""
+}
+
 var File_ghost_proto protoreflect.FileDescriptor
 
 var file_ghost_proto_rawDesc = []byte{
@@ -236,20 +253,23 @@ var file_ghost_proto_rawDesc = []byte{
 	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x70,
 	0x61, 0x77, 0x6e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75,
 	0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x61, 0x6d,
-	0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x6c, 0x61, 0x6d, 0x70,
+	0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6c, 0x61, 0x6d, 0x70,
 	0x6f, 0x72, 0x74, 0x12, 0x30, 0x0a, 0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x18,
 	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72,
 	0x65, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x08, 0x63, 0x6f, 0x6d,
 	0x6d, 0x61, 0x6e, 0x64, 0x73, 0x22, 0x1c, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
 	0x08, 0x0a, 0x04, 0x57, 0x41, 0x49, 0x54, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x44, 0x4f, 0x4e,
-	0x45, 0x10, 0x01, 0x22, 0x1f, 0x0a, 0x09, 0x53, 0x70, 0x61, 0x77, 0x6e, 0x57, 0x61, 0x69, 0x74,
+	0x45, 0x10, 0x01, 0x22, 0x47, 0x0a, 0x09, 0x53, 0x70, 0x61, 0x77, 0x6e, 0x57, 0x61, 0x69, 0x74,
 	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x32, 0x40, 0x0a, 0x0c, 0x47, 0x68, 0x6f, 0x73, 0x74, 0x53, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x53, 0x70, 0x61, 0x77, 0x6e, 0x12, 0x10, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x70, 0x61, 0x77, 0x6e, 0x57, 0x61, 0x69, 0x74, 0x1a,
-	0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x70, 0x61, 0x77, 0x6e, 0x43, 0x6f, 0x6d,
-	0x6d, 0x61, 0x6e, 0x64, 0x30, 0x01, 0x42, 0x0c, 0x5a, 0x0a, 0x2e, 0x2e, 0x2f, 0x61, 0x70, 0x69,
-	0x3b, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x7a, 0x6f, 0x6e, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x61, 0x63, 0x6b,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x61, 0x63, 0x6b, 0x32, 0x40, 0x0a, 0x0c,
+	0x47, 0x68, 0x6f, 0x73, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x30, 0x0a, 0x05,
+	0x53, 0x70, 0x61, 0x77, 0x6e, 0x12, 0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x70,
+	0x61, 0x77, 0x6e, 0x57, 0x61, 0x69, 0x74, 0x1a, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x53, 0x70, 0x61, 0x77, 0x6e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x30, 0x01, 0x42, 0x0c,
+	0x5a, 0x0a, 0x2e, 0x2e, 0x2f, 0x61, 0x70, 0x69, 0x3b, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -26,9 +26,12 @@ const (
 type ResponseMeta_Status int32
 
 const (
-	ResponseMeta_CREATED ResponseMeta_Status = 0
-	ResponseMeta_DELETED ResponseMeta_Status = 1
-	ResponseMeta_UPDATED ResponseMeta_Status = 2
+	ResponseMeta_CREATED     ResponseMeta_Status = 0
+	ResponseMeta_DELETED     ResponseMeta_Status = 1
+	ResponseMeta_UPDATED     ResponseMeta_Status = 2
+	ResponseMeta_READ        ResponseMeta_Status = 3
+	ResponseMeta_SNAPSHOTTED ResponseMeta_Status = 4
+	ResponseMeta_LISTED      ResponseMeta_Status = 5
 )
 
 // Enum value maps for ResponseMeta_Status.
@@ -37,11 +40,17 @@ var (
 		0: "CREATED",
 		1: "DELETED",
 		2: "UPDATED",
+		3: "READ",
+		4: "SNAPSHOTTED",
+		5: "LISTED",
 	}
 	ResponseMeta_Status_value = map[string]int32{
-		"CREATED": 0,
-		"DELETED": 1,
-		"UPDATED": 2,
+		"CREATED":     0,
+		"DELETED":     1,
+		"UPDATED":     2,
+		"READ":        3,
+		"SNAPSHOTTED": 4,
+		"LISTED":      5,
 	}
 )
 
@@ -78,6 +87,7 @@ const (
 	NodeHeartBeat_ACK             NodeHeartBeat_Type = 0
 	NodeHeartBeat_BEAT            NodeHeartBeat_Type = 1
 	NodeHeartBeat_DISTRIUTED_READ NodeHeartBeat_Type = 2
+	NodeHeartBeat_BATCH           NodeHeartBeat_Type = 3 // acks and read results are in batch, in the order of the commands
 )
 
 // Enum value maps for NodeHeartBeat_Type.
@@ -86,11 +96,13 @@ var (
 		0: "ACK",
 		1: "BEAT",
 		2: "DISTRIUTED_READ",
+		3: "BATCH",
 	}
 	NodeHeartBeat_Type_value = map[string]int32{
 		"ACK":             0,
 		"BEAT":            1,
 		"DISTRIUTED_READ": 2,
+		"BATCH":           3,
 	}
 )
 
@@ -118,7 +130,63 @@ func (x NodeHeartBeat_Type) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use NodeHeartBeat_Type.Descriptor instead.
 func (NodeHeartBeat_Type) EnumDescriptor() ([]byte, []int) {
-	return file_namenode_proto_rawDescGZIP(), []int{10, 0}
+	return file_namenode_proto_rawDescGZIP(), []int{21, 0}
+}
+
+// outcome of the command an ack or read result answers
+type NodeHeartBeat_Code int32
+
+const (
+	NodeHeartBeat_OK         NodeHeartBeat_Code = 0
+	NodeHeartBeat_FAILED     NodeHeartBeat_Code = 1 // any failure none of the codes below describes
+	NodeHeartBeat_DISK_FULL  NodeHeartBeat_Code = 2
+	NodeHeartBeat_CONSTRAINT NodeHeartBeat_Code = 3 // the command conflicts with what the store holds
+	NodeHeartBeat_CORRUPTION NodeHeartBeat_Code = 4
+)
+
+// Enum value maps for NodeHeartBeat_Code.
+var (
+	NodeHeartBeat_Code_name = map[int32]string{
+		0: "OK",
+		1: "FAILED",
+		2: "DISK_FULL",
+		3: "CONSTRAINT",
+		4: "CORRUPTION",
+	}
+	NodeHeartBeat_Code_value = map[string]int32{
+		"OK":         0,
+		"FAILED":     1,
+		"DISK_FULL":  2,
+		"CONSTRAINT": 3,
+		"CORRUPTION": 4,
+	}
+)
+
+func (x NodeHeartBeat_Code) Enum() *NodeHeartBeat_Code {
+	p := new(NodeHeartBeat_Code)
+	*p = x
+	return p
+}
+
+func (x NodeHeartBeat_Code) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NodeHeartBeat_Code) Descriptor() protoreflect.EnumDescriptor {
+	return file_namenode_proto_enumTypes[2].Descriptor()
+}
+
+func (NodeHeartBeat_Code) Type() protoreflect.EnumType {
+	return &file_namenode_proto_enumTypes[2]
+}
+
+func (x NodeHeartBeat_Code) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NodeHeartBeat_Code.Descriptor instead.
+func (NodeHeartBeat_Code) EnumDescriptor() ([]byte, []int) {
+	return file_namenode_proto_rawDescGZIP(), []int{21, 1}
 }
 
 type CommandNodeRes_Command int32
@@ -130,6 +198,10 @@ const (
 	CommandNodeRes_DELETE           CommandNodeRes_Command = 3
 	CommandNodeRes_UPDATE           CommandNodeRes_Command = 4
 	CommandNodeRes_DISTRIBUTED_READ CommandNodeRes_Command = 5
+	CommandNodeRes_SNAPSHOT         CommandNodeRes_Command = 6
+	CommandNodeRes_DELETE_SNAPSHOT  CommandNodeRes_Command = 7
+	CommandNodeRes_BATCH            CommandNodeRes_Command = 8 // the commands are in batch and are applied in order
+	CommandNodeRes_DECOMMISSION     CommandNodeRes_Command = 9 // the datanode was drained, it leaves the cluster and stops registering
 )
 
 // Enum value maps for CommandNodeRes_Command.
@@ -141,6 +213,10 @@ var (
 		3: "DELETE",
 		4: "UPDATE",
 		5: "DISTRIBUTED_READ",
+		6: "SNAPSHOT",
+		7: "DELETE_SNAPSHOT",
+		8: "BATCH",
+		9: "DECOMMISSION",
 	}
 	CommandNodeRes_Command_value = map[string]int32{
 		"REGISTER":         0,
@@ -149,6 +225,10 @@ var (
 		"DELETE":           3,
 		"UPDATE":           4,
 		"DISTRIBUTED_READ": 5,
+		"SNAPSHOT":         6,
+		"DELETE_SNAPSHOT":  7,
+		"BATCH":            8,
+		"DECOMMISSION":     9,
 	}
 )
 
@@ -163,11 +243,11 @@ func (x CommandNodeRes_Command) String() string {
 }
 
 func (CommandNodeRes_Command) Descriptor() protoreflect.EnumDescriptor {
-	return file_namenode_proto_enumTypes[2].Descriptor()
+	return file_namenode_proto_enumTypes[3].Descriptor()
 }
 
 func (CommandNodeRes_Command) Type() protoreflect.EnumType {
-	return &file_namenode_proto_enumTypes[2]
+	return &file_namenode_proto_enumTypes[3]
 }
 
 func (x CommandNodeRes_Command) Number() protoreflect.EnumNumber {
@@ -176,7 +256,7 @@ func (x CommandNodeRes_Command) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use CommandNodeRes_Command.Descriptor instead.
 func (CommandNodeRes_Command) EnumDescriptor() ([]byte, []int) {
-	return file_namenode_proto_rawDescGZIP(), []int{11, 0}
+	return file_namenode_proto_rawDescGZIP(), []int{22, 0}
 }
 
 type RequestMeta struct {
@@ -185,6 +265,9 @@ type RequestMeta struct {
 	unknownFields protoimpl.UnknownFields
 
 	Ts *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=ts,proto3" json:"ts,omitempty"`
+	// generated by the client once per mutation and sent again on every retry of it,
+	// the namenode answers a retry with the result of the first attempt
+	IdempotencyKey string `protobuf:"bytes,2,opt,name=idempotencyKey,proto3" json:"idempotencyKey,omitempty"`
 }
 
 func (x *RequestMeta) Reset() {
@@ -224,6 +307,13 @@ func (x *RequestMeta) GetTs() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *RequestMeta) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
 type ResponseMeta struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -278,19 +368,75 @@ func (x *ResponseMeta) GetStatus() ResponseMeta_Status {
 }
 
 // Object Creation Messsage Primitives ///////////////
+// label selectors restricting where the replicas of an object are placed
+type PlacementConstraints struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Required  map[string]string `protobuf:"bytes,1,rep,name=required,proto3" json:"required,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`   // only nodes with every one of these labels hold the object
+	Preferred map[string]string `protobuf:"bytes,2,rep,name=preferred,proto3" json:"preferred,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // nodes with more of these labels are picked first
+}
+
+func (x *PlacementConstraints) Reset() {
+	*x = PlacementConstraints{}
+	mi := &file_namenode_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlacementConstraints) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlacementConstraints) ProtoMessage() {}
+
+func (x *PlacementConstraints) ProtoReflect() protoreflect.Message {
+	mi := &file_namenode_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlacementConstraints.ProtoReflect.Descriptor instead.
+func (*PlacementConstraints) Descriptor() ([]byte, []int) {
+	return file_namenode_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PlacementConstraints) GetRequired() map[string]string {
+	if x != nil {
+		return x.Required
+	}
+	return nil
+}
+
+func (x *PlacementConstraints) GetPreferred() map[string]string {
+	if x != nil {
+		return x.Preferred
+	}
+	return nil
+}
+
 type CreateObjectRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Meta *RequestMeta `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
-	Name string       `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Data []byte       `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Meta        *RequestMeta          `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Name        string                `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Data        []byte                `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Constraints *PlacementConstraints `protobuf:"bytes,4,opt,name=constraints,proto3" json:"constraints,omitempty"`  // kept with the object, honoured whenever its replicas are placed
+	Replication uint32                `protobuf:"varint,5,opt,name=replication,proto3" json:"replication,omitempty"` // replicas the object keeps, zero for the cluster's replication
 }
 
 func (x *CreateObjectRequest) Reset() {
 	*x = CreateObjectRequest{}
-	mi := &file_namenode_proto_msgTypes[2]
+	mi := &file_namenode_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -302,7 +448,7 @@ func (x *CreateObjectRequest) String() string {
 func (*CreateObjectRequest) ProtoMessage() {}
 
 func (x *CreateObjectRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_namenode_proto_msgTypes[2]
+	mi := &file_namenode_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -315,7 +461,7 @@ func (x *CreateObjectRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateObjectRequest.ProtoReflect.Descriptor instead.
 func (*CreateObjectRequest) Descriptor() ([]byte, []int) {
-	return file_namenode_proto_rawDescGZIP(), []int{2}
+	return file_namenode_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *CreateObjectRequest) GetMeta() *RequestMeta {
@@ -339,6 +485,20 @@ func (x *CreateObjectRequest) GetData() []byte {
 	return nil
 }
 
+func (x *CreateObjectRequest) GetConstraints() *PlacementConstraints {
+	if x != nil {
+		return x.Constraints
+	}
+	return nil
+}
+
+func (x *CreateObjectRequest) GetReplication() uint32 {
+	if x != nil {
+		return x.Replication
+	}
+	return 0
+}
+
 type CreateObjectResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -349,7 +509,7 @@ type CreateObjectResponse struct {
 
 func (x *CreateObjectResponse) Reset() {
 	*x = CreateObjectResponse{}
-	mi := &file_namenode_proto_msgTypes[3]
+	mi := &file_namenode_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -361,7 +521,7 @@ func (x *CreateObjectResponse) String() string {
 func (*CreateObjectResponse) ProtoMessage() {}
 
 func (x *CreateObjectResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_namenode_proto_msgTypes[3]
+	mi := &file_namenode_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -374,7 +534,7 @@ func (x *CreateObjectResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateObjectResponse.ProtoReflect.Descriptor instead.
 func (*CreateObjectResponse) Descriptor() ([]byte, []int) {
-	return file_namenode_proto_rawDescGZIP(), []int{3}
+	return file_namenode_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *CreateObjectResponse) GetMeta() *ResponseMeta {
@@ -396,7 +556,7 @@ type DeleteObjectRequest struct {
 
 func (x *DeleteObjectRequest) Reset() {
 	*x = DeleteObjectRequest{}
-	mi := &file_namenode_proto_msgTypes[4]
+	mi := &file_namenode_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -408,7 +568,7 @@ func (x *DeleteObjectRequest) String() string {
 func (*DeleteObjectRequest) ProtoMessage() {}
 
 func (x *DeleteObjectRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_namenode_proto_msgTypes[4]
+	mi := &file_namenode_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -421,7 +581,7 @@ func (x *DeleteObjectRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteObjectRequest.ProtoReflect.Descriptor instead.
 func (*DeleteObjectRequest) Descriptor() ([]byte, []int) {
-	return file_namenode_proto_rawDescGZIP(), []int{4}
+	return file_namenode_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *DeleteObjectRequest) GetMeta() *RequestMeta {
@@ -448,7 +608,7 @@ type DeleteObjectResponse struct {
 
 func (x *DeleteObjectResponse) Reset() {
 	*x = DeleteObjectResponse{}
-	mi := &file_namenode_proto_msgTypes[5]
+	mi := &file_namenode_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -460,7 +620,7 @@ func (x *DeleteObjectResponse) String() string {
 func (*DeleteObjectResponse) ProtoMessage() {}
 
 func (x *DeleteObjectResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_namenode_proto_msgTypes[5]
+	mi := &file_namenode_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -473,7 +633,7 @@ func (x *DeleteObjectResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteObjectResponse.ProtoReflect.Descriptor instead.
 func (*DeleteObjectResponse) Descriptor() ([]byte, []int) {
-	return file_namenode_proto_rawDescGZIP(), []int{5}
+	return file_namenode_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *DeleteObjectResponse) GetMeta() *ResponseMeta {
@@ -496,7 +656,7 @@ type UpdateObjectReq struct {
 
 func (x *UpdateObjectReq) Reset() {
 	*x = UpdateObjectReq{}
-	mi := &file_namenode_proto_msgTypes[6]
+	mi := &file_namenode_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -508,7 +668,7 @@ func (x *UpdateObjectReq) String() string {
 func (*UpdateObjectReq) ProtoMessage() {}
 
 func (x *UpdateObjectReq) ProtoReflect() protoreflect.Message {
-	mi := &file_namenode_proto_msgTypes[6]
+	mi := &file_namenode_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -521,7 +681,7 @@ func (x *UpdateObjectReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateObjectReq.ProtoReflect.Descriptor instead.
 func (*UpdateObjectReq) Descriptor() ([]byte, []int) {
-	return file_namenode_proto_rawDescGZIP(), []int{6}
+	return file_namenode_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *UpdateObjectReq) GetMeta() *RequestMeta {
@@ -555,7 +715,7 @@ type UpdateObjectRes struct {
 
 func (x *UpdateObjectRes) Reset() {
 	*x = UpdateObjectRes{}
-	mi := &file_namenode_proto_msgTypes[7]
+	mi := &file_namenode_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -567,7 +727,7 @@ func (x *UpdateObjectRes) String() string {
 func (*UpdateObjectRes) ProtoMessage() {}
 
 func (x *UpdateObjectRes) ProtoReflect() protoreflect.Message {
-	mi := &file_namenode_proto_msgTypes[7]
+	mi := &file_namenode_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -580,7 +740,7 @@ func (x *UpdateObjectRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateObjectRes.ProtoReflect.Descriptor instead.
 func (*UpdateObjectRes) Descriptor() ([]byte, []int) {
-	return file_namenode_proto_rawDescGZIP(), []int{7}
+	return file_namenode_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *UpdateObjectRes) GetMeta() *ResponseMeta {
@@ -602,7 +762,7 @@ type LeaseObjectReq struct {
 
 func (x *LeaseObjectReq) Reset() {
 	*x = LeaseObjectReq{}
-	mi := &file_namenode_proto_msgTypes[8]
+	mi := &file_namenode_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -614,7 +774,7 @@ func (x *LeaseObjectReq) String() string {
 func (*LeaseObjectReq) ProtoMessage() {}
 
 func (x *LeaseObjectReq) ProtoReflect() protoreflect.Message {
-	mi := &file_namenode_proto_msgTypes[8]
+	mi := &file_namenode_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -627,7 +787,7 @@ func (x *LeaseObjectReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LeaseObjectReq.ProtoReflect.Descriptor instead.
 func (*LeaseObjectReq) Descriptor() ([]byte, []int) {
-	return file_namenode_proto_rawDescGZIP(), []int{8}
+	return file_namenode_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *LeaseObjectReq) GetMeta() *RequestMeta {
@@ -651,11 +811,12 @@ type LeaseObjectRes struct {
 
 	Meta    *ResponseMeta `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
 	Leasers []string      `protobuf:"bytes,2,rep,name=leasers,proto3" json:"leasers,omitempty"`
+	Fences  []uint64      `protobuf:"varint,3,rep,packed,name=fences,proto3" json:"fences,omitempty"` // fencing token of each leaser, its publications carry it
 }
 
 func (x *LeaseObjectRes) Reset() {
 	*x = LeaseObjectRes{}
-	mi := &file_namenode_proto_msgTypes[9]
+	mi := &file_namenode_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -667,7 +828,7 @@ func (x *LeaseObjectRes) String() string {
 func (*LeaseObjectRes) ProtoMessage() {}
 
 func (x *LeaseObjectRes) ProtoReflect() protoreflect.Message {
-	mi := &file_namenode_proto_msgTypes[9]
+	mi := &file_namenode_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -680,7 +841,7 @@ func (x *LeaseObjectRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LeaseObjectRes.ProtoReflect.Descriptor instead.
 func (*LeaseObjectRes) Descriptor() ([]byte, []int) {
-	return file_namenode_proto_rawDescGZIP(), []int{9}
+	return file_namenode_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *LeaseObjectRes) GetMeta() *ResponseMeta {
@@ -697,36 +858,39 @@ func (x *LeaseObjectRes) GetLeasers() []string {
 	return nil
 }
 
-// Register Data Node Primitives //////////////////
-type NodeHeartBeat struct {
+func (x *LeaseObjectRes) GetFences() []uint64 {
+	if x != nil {
+		return x.Fences
+	}
+	return nil
+}
+
+// Object Read Message Primitives //////////////////
+type GetObjectReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Type          NodeHeartBeat_Type      `protobuf:"varint,7,opt,name=type,proto3,enum=proto.NodeHeartBeat_Type" json:"type,omitempty"`
-	Id            string                  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                       // data node name
-	Size          float32                 `protobuf:"fixed32,3,opt,name=size,proto3" json:"size,omitempty"`                 // data node store size
-	Objects       []string                `protobuf:"bytes,4,rep,name=objects,proto3" json:"objects,omitempty"`             // objects contained in this node's store
-	LeaserService string                  `protobuf:"bytes,5,opt,name=leaserService,proto3" json:"leaserService,omitempty"` // addr of lease pub service
-	MessageTag    string                  `protobuf:"bytes,6,opt,name=messageTag,proto3" json:"messageTag,omitempty"`
-	ObjectData    []*NodeHeartBeat_Object `protobuf:"bytes,8,rep,name=objectData,proto3" json:"objectData,omitempty"`
+	Meta     *RequestMeta `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Name     string       `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Snapshot string       `protobuf:"bytes,3,opt,name=snapshot,proto3" json:"snapshot,omitempty"` // if set, the object is read as of this snapshot
 }
 
-func (x *NodeHeartBeat) Reset() {
-	*x = NodeHeartBeat{}
-	mi := &file_namenode_proto_msgTypes[10]
+func (x *GetObjectReq) Reset() {
+	*x = GetObjectReq{}
+	mi := &file_namenode_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NodeHeartBeat) String() string {
+func (x *GetObjectReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NodeHeartBeat) ProtoMessage() {}
+func (*GetObjectReq) ProtoMessage() {}
 
-func (x *NodeHeartBeat) ProtoReflect() protoreflect.Message {
-	mi := &file_namenode_proto_msgTypes[10]
+func (x *GetObjectReq) ProtoReflect() protoreflect.Message {
+	mi := &file_namenode_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -737,90 +901,110 @@ func (x *NodeHeartBeat) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NodeHeartBeat.ProtoReflect.Descriptor instead.
-func (*NodeHeartBeat) Descriptor() ([]byte, []int) {
-	return file_namenode_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use GetObjectReq.ProtoReflect.Descriptor instead.
+func (*GetObjectReq) Descriptor() ([]byte, []int) {
+	return file_namenode_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *NodeHeartBeat) GetType() NodeHeartBeat_Type {
+func (x *GetObjectReq) GetMeta() *RequestMeta {
 	if x != nil {
-		return x.Type
+		return x.Meta
 	}
-	return NodeHeartBeat_ACK
+	return nil
 }
 
-func (x *NodeHeartBeat) GetId() string {
+func (x *GetObjectReq) GetName() string {
 	if x != nil {
-		return x.Id
+		return x.Name
 	}
 	return ""
 }
 
-func (x *NodeHeartBeat) GetSize() float32 {
+func (x *GetObjectReq) GetSnapshot() string {
 	if x != nil {
-		return x.Size
+		return x.Snapshot
 	}
-	return 0
+	return ""
 }
 
-func (x *NodeHeartBeat) GetObjects() []string {
-	if x != nil {
-		return x.Objects
-	}
-	return nil
+type GetObjectRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta *ResponseMeta `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Data []byte        `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
 }
 
-func (x *NodeHeartBeat) GetLeaserService() string {
+func (x *GetObjectRes) Reset() {
+	*x = GetObjectRes{}
+	mi := &file_namenode_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetObjectRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetObjectRes) ProtoMessage() {}
+
+func (x *GetObjectRes) ProtoReflect() protoreflect.Message {
+	mi := &file_namenode_proto_msgTypes[12]
 	if x != nil {
-		return x.LeaserService
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *NodeHeartBeat) GetMessageTag() string {
+// Deprecated: Use GetObjectRes.ProtoReflect.Descriptor instead.
+func (*GetObjectRes) Descriptor() ([]byte, []int) {
+	return file_namenode_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetObjectRes) GetMeta() *ResponseMeta {
 	if x != nil {
-		return x.MessageTag
+		return x.Meta
 	}
-	return ""
+	return nil
 }
 
-func (x *NodeHeartBeat) GetObjectData() []*NodeHeartBeat_Object {
+func (x *GetObjectRes) GetData() []byte {
 	if x != nil {
-		return x.ObjectData
+		return x.Data
 	}
 	return nil
 }
 
-type CommandNodeRes struct {
+// Snapshot Message Primitives //////////////////////
+type CreateSnapshotReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Meta            *ResponseMeta           `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
-	Command         CommandNodeRes_Command  `protobuf:"varint,2,opt,name=command,proto3,enum=proto.CommandNodeRes_Command" json:"command,omitempty"`
-	Create          *CreateCommand          `protobuf:"bytes,3,opt,name=create,proto3" json:"create,omitempty"`
-	Commit          *CommitCommand          `protobuf:"bytes,4,opt,name=commit,proto3" json:"commit,omitempty"`
-	Delete          *DeleteCommand          `protobuf:"bytes,5,opt,name=delete,proto3" json:"delete,omitempty"`
-	Update          *UpdateCommand          `protobuf:"bytes,6,opt,name=update,proto3" json:"update,omitempty"`
-	MessageTag      string                  `protobuf:"bytes,7,opt,name=messageTag,proto3" json:"messageTag,omitempty"`
-	DistributedRead *DistributedReadCommand `protobuf:"bytes,8,opt,name=distributedRead,proto3" json:"distributedRead,omitempty"`
+	Meta *RequestMeta `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Name string       `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 }
 
-func (x *CommandNodeRes) Reset() {
-	*x = CommandNodeRes{}
-	mi := &file_namenode_proto_msgTypes[11]
+func (x *CreateSnapshotReq) Reset() {
+	*x = CreateSnapshotReq{}
+	mi := &file_namenode_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CommandNodeRes) String() string {
+func (x *CreateSnapshotReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CommandNodeRes) ProtoMessage() {}
+func (*CreateSnapshotReq) ProtoMessage() {}
 
-func (x *CommandNodeRes) ProtoReflect() protoreflect.Message {
-	mi := &file_namenode_proto_msgTypes[11]
+func (x *CreateSnapshotReq) ProtoReflect() protoreflect.Message {
+	mi := &file_namenode_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -831,37 +1015,667 @@ func (x *CommandNodeRes) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CommandNodeRes.ProtoReflect.Descriptor instead.
-func (*CommandNodeRes) Descriptor() ([]byte, []int) {
-	return file_namenode_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use CreateSnapshotReq.ProtoReflect.Descriptor instead.
+func (*CreateSnapshotReq) Descriptor() ([]byte, []int) {
+	return file_namenode_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *CommandNodeRes) GetMeta() *ResponseMeta {
+func (x *CreateSnapshotReq) GetMeta() *RequestMeta {
 	if x != nil {
 		return x.Meta
 	}
 	return nil
 }
 
-func (x *CommandNodeRes) GetCommand() CommandNodeRes_Command {
+func (x *CreateSnapshotReq) GetName() string {
 	if x != nil {
-		return x.Command
+		return x.Name
 	}
-	return CommandNodeRes_REGISTER
+	return ""
 }
 
-func (x *CommandNodeRes) GetCreate() *CreateCommand {
-	if x != nil {
-		return x.Create
-	}
-	return nil
+type CreateSnapshotRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta    *ResponseMeta `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Lamport uint64        `protobuf:"varint,2,opt,name=lamport,proto3" json:"lamport,omitempty"` // lamport cut at which the snapshot was taken
 }
 
-func (x *CommandNodeRes) GetCommit() *CommitCommand {
-	if x != nil {
-		return x.Commit
-	}
-	return nil
+func (x *CreateSnapshotRes) Reset() {
+	*x = CreateSnapshotRes{}
+	mi := &file_namenode_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSnapshotRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSnapshotRes) ProtoMessage() {}
+
+func (x *CreateSnapshotRes) ProtoReflect() protoreflect.Message {
+	mi := &file_namenode_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSnapshotRes.ProtoReflect.Descriptor instead.
+func (*CreateSnapshotRes) Descriptor() ([]byte, []int) {
+	return file_namenode_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreateSnapshotRes) GetMeta() *ResponseMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *CreateSnapshotRes) GetLamport() uint64 {
+	if x != nil {
+		return x.Lamport
+	}
+	return 0
+}
+
+type DeleteSnapshotReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta *RequestMeta `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Name string       `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *DeleteSnapshotReq) Reset() {
+	*x = DeleteSnapshotReq{}
+	mi := &file_namenode_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSnapshotReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSnapshotReq) ProtoMessage() {}
+
+func (x *DeleteSnapshotReq) ProtoReflect() protoreflect.Message {
+	mi := &file_namenode_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSnapshotReq.ProtoReflect.Descriptor instead.
+func (*DeleteSnapshotReq) Descriptor() ([]byte, []int) {
+	return file_namenode_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DeleteSnapshotReq) GetMeta() *RequestMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *DeleteSnapshotReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeleteSnapshotRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta *ResponseMeta `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+}
+
+func (x *DeleteSnapshotRes) Reset() {
+	*x = DeleteSnapshotRes{}
+	mi := &file_namenode_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSnapshotRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSnapshotRes) ProtoMessage() {}
+
+func (x *DeleteSnapshotRes) ProtoReflect() protoreflect.Message {
+	mi := &file_namenode_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSnapshotRes.ProtoReflect.Descriptor instead.
+func (*DeleteSnapshotRes) Descriptor() ([]byte, []int) {
+	return file_namenode_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeleteSnapshotRes) GetMeta() *ResponseMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+// Object Replication Message Primitives ///////////
+type SetReplicationReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta        *RequestMeta `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Name        string       `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Replication uint32       `protobuf:"varint,3,opt,name=replication,proto3" json:"replication,omitempty"` // replicas the object keeps from now on, at least one
+}
+
+func (x *SetReplicationReq) Reset() {
+	*x = SetReplicationReq{}
+	mi := &file_namenode_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetReplicationReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetReplicationReq) ProtoMessage() {}
+
+func (x *SetReplicationReq) ProtoReflect() protoreflect.Message {
+	mi := &file_namenode_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetReplicationReq.ProtoReflect.Descriptor instead.
+func (*SetReplicationReq) Descriptor() ([]byte, []int) {
+	return file_namenode_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SetReplicationReq) GetMeta() *RequestMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *SetReplicationReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetReplicationReq) GetReplication() uint32 {
+	if x != nil {
+		return x.Replication
+	}
+	return 0
+}
+
+type SetReplicationRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta     *ResponseMeta `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Replicas uint32        `protobuf:"varint,2,opt,name=replicas,proto3" json:"replicas,omitempty"` // replicas the object has now, they are added or removed in the background
+}
+
+func (x *SetReplicationRes) Reset() {
+	*x = SetReplicationRes{}
+	mi := &file_namenode_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetReplicationRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetReplicationRes) ProtoMessage() {}
+
+func (x *SetReplicationRes) ProtoReflect() protoreflect.Message {
+	mi := &file_namenode_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetReplicationRes.ProtoReflect.Descriptor instead.
+func (*SetReplicationRes) Descriptor() ([]byte, []int) {
+	return file_namenode_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SetReplicationRes) GetMeta() *ResponseMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *SetReplicationRes) GetReplicas() uint32 {
+	if x != nil {
+		return x.Replicas
+	}
+	return 0
+}
+
+// Object Listing Message Primitives ///////////////
+type ListObjectsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta   *RequestMeta `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Prefix string       `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"` // only names starting with the prefix are listed, an empty prefix lists everything
+}
+
+func (x *ListObjectsReq) Reset() {
+	*x = ListObjectsReq{}
+	mi := &file_namenode_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListObjectsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListObjectsReq) ProtoMessage() {}
+
+func (x *ListObjectsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_namenode_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListObjectsReq.ProtoReflect.Descriptor instead.
+func (*ListObjectsReq) Descriptor() ([]byte, []int) {
+	return file_namenode_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListObjectsReq) GetMeta() *RequestMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *ListObjectsReq) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+type ListObjectsRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta  *ResponseMeta `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Names []string      `protobuf:"bytes,2,rep,name=names,proto3" json:"names,omitempty"` // sorted
+}
+
+func (x *ListObjectsRes) Reset() {
+	*x = ListObjectsRes{}
+	mi := &file_namenode_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListObjectsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListObjectsRes) ProtoMessage() {}
+
+func (x *ListObjectsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_namenode_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListObjectsRes.ProtoReflect.Descriptor instead.
+func (*ListObjectsRes) Descriptor() ([]byte, []int) {
+	return file_namenode_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListObjectsRes) GetMeta() *ResponseMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *ListObjectsRes) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+// Register Data Node Primitives //////////////////
+type NodeHeartBeat struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type          NodeHeartBeat_Type      `protobuf:"varint,7,opt,name=type,proto3,enum=proto.NodeHeartBeat_Type" json:"type,omitempty"`
+	Id            string                  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                       // data node name
+	Size          float32                 `protobuf:"fixed32,3,opt,name=size,proto3" json:"size,omitempty"`                 // data node store size
+	Objects       []string                `protobuf:"bytes,4,rep,name=objects,proto3" json:"objects,omitempty"`             // objects contained in this node's store
+	LeaserService string                  `protobuf:"bytes,5,opt,name=leaserService,proto3" json:"leaserService,omitempty"` // addr of lease pub service
+	ObjectData    []*NodeHeartBeat_Object `protobuf:"bytes,8,rep,name=objectData,proto3" json:"objectData,omitempty"`
+	Batch         []*NodeHeartBeat        `protobuf:"bytes,9,rep,name=batch,proto3" json:"batch,omitempty"`
+	RequestId     uint64                  `protobuf:"varint,10,opt,name=requestId,proto3" json:"requestId,omitempty"` // id of the command this acks or answers
+	// sent on the first heartbeat of a session only
+	NodeId    string             `protobuf:"bytes,11,opt,name=nodeId,proto3" json:"nodeId,omitempty"`       // uuid the datanode generated on its first start
+	ClusterId string             `protobuf:"bytes,12,opt,name=clusterId,proto3" json:"clusterId,omitempty"` // cluster the datanode joined, empty until its first join
+	JoinToken string             `protobuf:"bytes,13,opt,name=joinToken,proto3" json:"joinToken,omitempty"` // shared secret of the cluster
+	Lamport   uint64             `protobuf:"varint,14,opt,name=lamport,proto3" json:"lamport,omitempty"`    // lamport of the last command the datanode applied
+	Code      NodeHeartBeat_Code `protobuf:"varint,15,opt,name=code,proto3,enum=proto.NodeHeartBeat_Code" json:"code,omitempty"`
+	Error     string             `protobuf:"bytes,16,opt,name=error,proto3" json:"error,omitempty"`         // why the command failed, empty when code is OK
+	Capacity  float32            `protobuf:"fixed32,17,opt,name=capacity,proto3" json:"capacity,omitempty"` // bytes the store may hold, zero when unbounded. sent on the first heartbeat only
+	// failure domain of the datanode, replicas of an object are spread over distinct ones. sent on the first heartbeat only
+	Zone   string            `protobuf:"bytes,18,opt,name=zone,proto3" json:"zone,omitempty"`
+	Rack   string            `protobuf:"bytes,19,opt,name=rack,proto3" json:"rack,omitempty"`
+	Labels map[string]string `protobuf:"bytes,20,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // sent on the first heartbeat only
+	// token the lease service still publishes under, zero when fenced. sent on the first heartbeat only
+	Fence uint64 `protobuf:"varint,21,opt,name=fence,proto3" json:"fence,omitempty"`
+}
+
+func (x *NodeHeartBeat) Reset() {
+	*x = NodeHeartBeat{}
+	mi := &file_namenode_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeHeartBeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeHeartBeat) ProtoMessage() {}
+
+func (x *NodeHeartBeat) ProtoReflect() protoreflect.Message {
+	mi := &file_namenode_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeHeartBeat.ProtoReflect.Descriptor instead.
+func (*NodeHeartBeat) Descriptor() ([]byte, []int) {
+	return file_namenode_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *NodeHeartBeat) GetType() NodeHeartBeat_Type {
+	if x != nil {
+		return x.Type
+	}
+	return NodeHeartBeat_ACK
+}
+
+func (x *NodeHeartBeat) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *NodeHeartBeat) GetSize() float32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *NodeHeartBeat) GetObjects() []string {
+	if x != nil {
+		return x.Objects
+	}
+	return nil
+}
+
+func (x *NodeHeartBeat) GetLeaserService() string {
+	if x != nil {
+		return x.LeaserService
+	}
+	return ""
+}
+
+func (x *NodeHeartBeat) GetObjectData() []*NodeHeartBeat_Object {
+	if x != nil {
+		return x.ObjectData
+	}
+	return nil
+}
+
+func (x *NodeHeartBeat) GetBatch() []*NodeHeartBeat {
+	if x != nil {
+		return x.Batch
+	}
+	return nil
+}
+
+func (x *NodeHeartBeat) GetRequestId() uint64 {
+	if x != nil {
+		return x.RequestId
+	}
+	return 0
+}
+
+func (x *NodeHeartBeat) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *NodeHeartBeat) GetClusterId() string {
+	if x != nil {
+		return x.ClusterId
+	}
+	return ""
+}
+
+func (x *NodeHeartBeat) GetJoinToken() string {
+	if x != nil {
+		return x.JoinToken
+	}
+	return ""
+}
+
+func (x *NodeHeartBeat) GetLamport() uint64 {
+	if x != nil {
+		return x.Lamport
+	}
+	return 0
+}
+
+func (x *NodeHeartBeat) GetCode() NodeHeartBeat_Code {
+	if x != nil {
+		return x.Code
+	}
+	return NodeHeartBeat_OK
+}
+
+func (x *NodeHeartBeat) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *NodeHeartBeat) GetCapacity() float32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *NodeHeartBeat) GetZone() string {
+	if x != nil {
+		return x.Zone
+	}
+	return ""
+}
+
+func (x *NodeHeartBeat) GetRack() string {
+	if x != nil {
+		return x.Rack
+	}
+	return ""
+}
+
+func (x *NodeHeartBeat) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *NodeHeartBeat) GetFence() uint64 {
+	if x != nil {
+		return x.Fence
+	}
+	return 0
+}
+
+type CommandNodeRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta            *ResponseMeta           `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Command         CommandNodeRes_Command  `protobuf:"varint,2,opt,name=command,proto3,enum=proto.CommandNodeRes_Command" json:"command,omitempty"`
+	Create          *CreateCommand          `protobuf:"bytes,3,opt,name=create,proto3" json:"create,omitempty"`
+	Commit          *CommitCommand          `protobuf:"bytes,4,opt,name=commit,proto3" json:"commit,omitempty"`
+	Delete          *DeleteCommand          `protobuf:"bytes,5,opt,name=delete,proto3" json:"delete,omitempty"`
+	Update          *UpdateCommand          `protobuf:"bytes,6,opt,name=update,proto3" json:"update,omitempty"`
+	DistributedRead *DistributedReadCommand `protobuf:"bytes,8,opt,name=distributedRead,proto3" json:"distributedRead,omitempty"`
+	Snapshot        *SnapshotCommand        `protobuf:"bytes,9,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	Batch           []*CommandNodeRes       `protobuf:"bytes,10,rep,name=batch,proto3" json:"batch,omitempty"`
+	RequestId       uint64                  `protobuf:"varint,11,opt,name=requestId,proto3" json:"requestId,omitempty"` // unique for the lifetime of the namenode, assigned in send order
+	Epoch           uint32                  `protobuf:"varint,12,opt,name=epoch,proto3" json:"epoch,omitempty"`         // epoch of the namenode that sent the command
+	Register        *RegisterCommand        `protobuf:"bytes,13,opt,name=register,proto3" json:"register,omitempty"`
+	Fence           uint64                  `protobuf:"varint,14,opt,name=fence,proto3" json:"fence,omitempty"` // fencing token of the session, commands with any other token are refused
+}
+
+func (x *CommandNodeRes) Reset() {
+	*x = CommandNodeRes{}
+	mi := &file_namenode_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandNodeRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandNodeRes) ProtoMessage() {}
+
+func (x *CommandNodeRes) ProtoReflect() protoreflect.Message {
+	mi := &file_namenode_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandNodeRes.ProtoReflect.Descriptor instead.
+func (*CommandNodeRes) Descriptor() ([]byte, []int) {
+	return file_namenode_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CommandNodeRes) GetMeta() *ResponseMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *CommandNodeRes) GetCommand() CommandNodeRes_Command {
+	if x != nil {
+		return x.Command
+	}
+	return CommandNodeRes_REGISTER
+}
+
+func (x *CommandNodeRes) GetCreate() *CreateCommand {
+	if x != nil {
+		return x.Create
+	}
+	return nil
+}
+
+func (x *CommandNodeRes) GetCommit() *CommitCommand {
+	if x != nil {
+		return x.Commit
+	}
+	return nil
 }
 
 func (x *CommandNodeRes) GetDelete() *DeleteCommand {
@@ -878,20 +1692,55 @@ func (x *CommandNodeRes) GetUpdate() *UpdateCommand {
 	return nil
 }
 
-func (x *CommandNodeRes) GetMessageTag() string {
+func (x *CommandNodeRes) GetDistributedRead() *DistributedReadCommand {
 	if x != nil {
-		return x.MessageTag
+		return x.DistributedRead
 	}
-	return ""
+	return nil
 }
 
-func (x *CommandNodeRes) GetDistributedRead() *DistributedReadCommand {
+func (x *CommandNodeRes) GetSnapshot() *SnapshotCommand {
 	if x != nil {
-		return x.DistributedRead
+		return x.Snapshot
+	}
+	return nil
+}
+
+func (x *CommandNodeRes) GetBatch() []*CommandNodeRes {
+	if x != nil {
+		return x.Batch
+	}
+	return nil
+}
+
+func (x *CommandNodeRes) GetRequestId() uint64 {
+	if x != nil {
+		return x.RequestId
+	}
+	return 0
+}
+
+func (x *CommandNodeRes) GetEpoch() uint32 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *CommandNodeRes) GetRegister() *RegisterCommand {
+	if x != nil {
+		return x.Register
 	}
 	return nil
 }
 
+func (x *CommandNodeRes) GetFence() uint64 {
+	if x != nil {
+		return x.Fence
+	}
+	return 0
+}
+
 type CreateCommand struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -903,7 +1752,7 @@ type CreateCommand struct {
 
 func (x *CreateCommand) Reset() {
 	*x = CreateCommand{}
-	mi := &file_namenode_proto_msgTypes[12]
+	mi := &file_namenode_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -915,7 +1764,7 @@ func (x *CreateCommand) String() string {
 func (*CreateCommand) ProtoMessage() {}
 
 func (x *CreateCommand) ProtoReflect() protoreflect.Message {
-	mi := &file_namenode_proto_msgTypes[12]
+	mi := &file_namenode_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -928,7 +1777,7 @@ func (x *CreateCommand) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateCommand.ProtoReflect.Descriptor instead.
 func (*CreateCommand) Descriptor() ([]byte, []int) {
-	return file_namenode_proto_rawDescGZIP(), []int{12}
+	return file_namenode_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *CreateCommand) GetObjectName() string {
@@ -952,12 +1801,12 @@ type UpdateCommand struct {
 
 	ObjectName string `protobuf:"bytes,1,opt,name=objectName,proto3" json:"objectName,omitempty"`
 	ObjectData []byte `protobuf:"bytes,2,opt,name=objectData,proto3" json:"objectData,omitempty"`
-	Lamport    int32  `protobuf:"varint,3,opt,name=lamport,proto3" json:"lamport,omitempty"`
+	Lamport    uint64 `protobuf:"varint,3,opt,name=lamport,proto3" json:"lamport,omitempty"`
 }
 
 func (x *UpdateCommand) Reset() {
 	*x = UpdateCommand{}
-	mi := &file_namenode_proto_msgTypes[13]
+	mi := &file_namenode_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -969,7 +1818,7 @@ func (x *UpdateCommand) String() string {
 func (*UpdateCommand) ProtoMessage() {}
 
 func (x *UpdateCommand) ProtoReflect() protoreflect.Message {
-	mi := &file_namenode_proto_msgTypes[13]
+	mi := &file_namenode_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -982,7 +1831,7 @@ func (x *UpdateCommand) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateCommand.ProtoReflect.Descriptor instead.
 func (*UpdateCommand) Descriptor() ([]byte, []int) {
-	return file_namenode_proto_rawDescGZIP(), []int{13}
+	return file_namenode_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *UpdateCommand) GetObjectName() string {
@@ -999,7 +1848,7 @@ func (x *UpdateCommand) GetObjectData() []byte {
 	return nil
 }
 
-func (x *UpdateCommand) GetLamport() int32 {
+func (x *UpdateCommand) GetLamport() uint64 {
 	if x != nil {
 		return x.Lamport
 	}
@@ -1011,13 +1860,14 @@ type CommitCommand struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Lamport    int32  `protobuf:"varint,2,opt,name=lamport,proto3" json:"lamport,omitempty"`
-	ObjectName string `protobuf:"bytes,3,opt,name=objectName,proto3" json:"objectName,omitempty"`
+	Lamport    uint64   `protobuf:"varint,2,opt,name=lamport,proto3" json:"lamport,omitempty"`
+	ObjectName string   `protobuf:"bytes,3,opt,name=objectName,proto3" json:"objectName,omitempty"`
+	Nodes      []string `protobuf:"bytes,4,rep,name=nodes,proto3" json:"nodes,omitempty"` // only these datanodes commit their staged create, the others only record the lamport
 }
 
 func (x *CommitCommand) Reset() {
 	*x = CommitCommand{}
-	mi := &file_namenode_proto_msgTypes[14]
+	mi := &file_namenode_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1029,7 +1879,7 @@ func (x *CommitCommand) String() string {
 func (*CommitCommand) ProtoMessage() {}
 
 func (x *CommitCommand) ProtoReflect() protoreflect.Message {
-	mi := &file_namenode_proto_msgTypes[14]
+	mi := &file_namenode_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1042,10 +1892,10 @@ func (x *CommitCommand) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CommitCommand.ProtoReflect.Descriptor instead.
 func (*CommitCommand) Descriptor() ([]byte, []int) {
-	return file_namenode_proto_rawDescGZIP(), []int{14}
+	return file_namenode_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *CommitCommand) GetLamport() int32 {
+func (x *CommitCommand) GetLamport() uint64 {
 	if x != nil {
 		return x.Lamport
 	}
@@ -1059,18 +1909,26 @@ func (x *CommitCommand) GetObjectName() string {
 	return ""
 }
 
+func (x *CommitCommand) GetNodes() []string {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
 type DeleteCommand struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Lamport    int32  `protobuf:"varint,1,opt,name=lamport,proto3" json:"lamport,omitempty"`
+	Lamport    uint64 `protobuf:"varint,1,opt,name=lamport,proto3" json:"lamport,omitempty"`
 	ObjectName string `protobuf:"bytes,2,opt,name=objectName,proto3" json:"objectName,omitempty"`
+	Node       string `protobuf:"bytes,3,opt,name=node,proto3" json:"node,omitempty"` // when set only this datanode drops its replica, the object stays on the others
 }
 
 func (x *DeleteCommand) Reset() {
 	*x = DeleteCommand{}
-	mi := &file_namenode_proto_msgTypes[15]
+	mi := &file_namenode_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1082,7 +1940,7 @@ func (x *DeleteCommand) String() string {
 func (*DeleteCommand) ProtoMessage() {}
 
 func (x *DeleteCommand) ProtoReflect() protoreflect.Message {
-	mi := &file_namenode_proto_msgTypes[15]
+	mi := &file_namenode_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1095,10 +1953,10 @@ func (x *DeleteCommand) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteCommand.ProtoReflect.Descriptor instead.
 func (*DeleteCommand) Descriptor() ([]byte, []int) {
-	return file_namenode_proto_rawDescGZIP(), []int{15}
+	return file_namenode_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *DeleteCommand) GetLamport() int32 {
+func (x *DeleteCommand) GetLamport() uint64 {
 	if x != nil {
 		return x.Lamport
 	}
@@ -1112,18 +1970,26 @@ func (x *DeleteCommand) GetObjectName() string {
 	return ""
 }
 
+func (x *DeleteCommand) GetNode() string {
+	if x != nil {
+		return x.Node
+	}
+	return ""
+}
+
 type DistributedReadCommand struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Objects []string `protobuf:"bytes,1,rep,name=objects,proto3" json:"objects,omitempty"` // objecst to populate
-	Lamport int32    `protobuf:"varint,2,opt,name=lamport,proto3" json:"lamport,omitempty"`
+	Objects  []string `protobuf:"bytes,1,rep,name=objects,proto3" json:"objects,omitempty"` // objecst to populate
+	Lamport  uint64   `protobuf:"varint,2,opt,name=lamport,proto3" json:"lamport,omitempty"`
+	Snapshot string   `protobuf:"bytes,3,opt,name=snapshot,proto3" json:"snapshot,omitempty"` // read objects as of this snapshot instead of the live store
 }
 
 func (x *DistributedReadCommand) Reset() {
 	*x = DistributedReadCommand{}
-	mi := &file_namenode_proto_msgTypes[16]
+	mi := &file_namenode_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1135,7 +2001,7 @@ func (x *DistributedReadCommand) String() string {
 func (*DistributedReadCommand) ProtoMessage() {}
 
 func (x *DistributedReadCommand) ProtoReflect() protoreflect.Message {
-	mi := &file_namenode_proto_msgTypes[16]
+	mi := &file_namenode_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1148,7 +2014,7 @@ func (x *DistributedReadCommand) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DistributedReadCommand.ProtoReflect.Descriptor instead.
 func (*DistributedReadCommand) Descriptor() ([]byte, []int) {
-	return file_namenode_proto_rawDescGZIP(), []int{16}
+	return file_namenode_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *DistributedReadCommand) GetObjects() []string {
@@ -1158,13 +2024,136 @@ func (x *DistributedReadCommand) GetObjects() []string {
 	return nil
 }
 
-func (x *DistributedReadCommand) GetLamport() int32 {
+func (x *DistributedReadCommand) GetLamport() uint64 {
+	if x != nil {
+		return x.Lamport
+	}
+	return 0
+}
+
+func (x *DistributedReadCommand) GetSnapshot() string {
+	if x != nil {
+		return x.Snapshot
+	}
+	return ""
+}
+
+// the first command of every session. lamport is the namenode's cut when the datanode registered,
+// every later broadcast on the session has a greater lamport
+type RegisterCommand struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Lamport   uint64 `protobuf:"varint,1,opt,name=lamport,proto3" json:"lamport,omitempty"`
+	ClusterId string `protobuf:"bytes,2,opt,name=clusterId,proto3" json:"clusterId,omitempty"` // recorded by the datanode on its first join
+	Resync    bool   `protobuf:"varint,3,opt,name=resync,proto3" json:"resync,omitempty"`      // the datanode missed commands while it was fenced, its store must be wiped
+}
+
+func (x *RegisterCommand) Reset() {
+	*x = RegisterCommand{}
+	mi := &file_namenode_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterCommand) ProtoMessage() {}
+
+func (x *RegisterCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_namenode_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterCommand.ProtoReflect.Descriptor instead.
+func (*RegisterCommand) Descriptor() ([]byte, []int) {
+	return file_namenode_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RegisterCommand) GetLamport() uint64 {
+	if x != nil {
+		return x.Lamport
+	}
+	return 0
+}
+
+func (x *RegisterCommand) GetClusterId() string {
+	if x != nil {
+		return x.ClusterId
+	}
+	return ""
+}
+
+func (x *RegisterCommand) GetResync() bool {
+	if x != nil {
+		return x.Resync
+	}
+	return false
+}
+
+type SnapshotCommand struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Lamport uint64 `protobuf:"varint,1,opt,name=lamport,proto3" json:"lamport,omitempty"`
+	Name    string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *SnapshotCommand) Reset() {
+	*x = SnapshotCommand{}
+	mi := &file_namenode_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotCommand) ProtoMessage() {}
+
+func (x *SnapshotCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_namenode_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotCommand.ProtoReflect.Descriptor instead.
+func (*SnapshotCommand) Descriptor() ([]byte, []int) {
+	return file_namenode_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SnapshotCommand) GetLamport() uint64 {
 	if x != nil {
 		return x.Lamport
 	}
 	return 0
 }
 
+func (x *SnapshotCommand) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
 type NodeHeartBeat_Object struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1176,7 +2165,7 @@ type NodeHeartBeat_Object struct {
 
 func (x *NodeHeartBeat_Object) Reset() {
 	*x = NodeHeartBeat_Object{}
-	mi := &file_namenode_proto_msgTypes[17]
+	mi := &file_namenode_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1188,7 +2177,7 @@ func (x *NodeHeartBeat_Object) String() string {
 func (*NodeHeartBeat_Object) ProtoMessage() {}
 
 func (x *NodeHeartBeat_Object) ProtoReflect() protoreflect.Message {
-	mi := &file_namenode_proto_msgTypes[17]
+	mi := &file_namenode_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1201,7 +2190,7 @@ func (x *NodeHeartBeat_Object) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NodeHeartBeat_Object.ProtoReflect.Descriptor instead.
 func (*NodeHeartBeat_Object) Descriptor() ([]byte, []int) {
-	return file_namenode_proto_rawDescGZIP(), []int{10, 0}
+	return file_namenode_proto_rawDescGZIP(), []int{21, 0}
 }
 
 func (x *NodeHeartBeat_Object) GetName() string {
@@ -1224,165 +2213,334 @@ var file_namenode_proto_rawDesc = []byte{
 	0x0a, 0x0e, 0x6e, 0x61, 0x6d, 0x65, 0x6e, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 	0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
-	0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x39, 0x0a, 0x0b, 0x52, 0x65, 0x71, 0x75,
+	0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x61, 0x0a, 0x0b, 0x52, 0x65, 0x71, 0x75,
 	0x65, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x12, 0x2a, 0x0a, 0x02, 0x74, 0x73, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
-	0x02, 0x74, 0x73, 0x22, 0x9f, 0x01, 0x0a, 0x0c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x4d, 0x65, 0x74, 0x61, 0x12, 0x2a, 0x0a, 0x02, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x73,
-	0x12, 0x32, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
-	0x32, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x4d, 0x65, 0x74, 0x61, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x22, 0x2f, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b,
-	0x0a, 0x07, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x44,
-	0x45, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x50, 0x44, 0x41,
-	0x54, 0x45, 0x44, 0x10, 0x02, 0x22, 0x65, 0x0a, 0x13, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4f,
-	0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x04,
-	0x6d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04,
-	0x6d, 0x65, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x3f, 0x0a, 0x14,
-	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x22, 0x51, 0x0a,
-	0x13, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x22, 0x3f, 0x0a, 0x14, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74,
-	0x61, 0x22, 0x61, 0x0a, 0x0f, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63,
+	0x02, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e,
+	0x63, 0x79, 0x4b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x69, 0x64, 0x65,
+	0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x4b, 0x65, 0x79, 0x22, 0xc6, 0x01, 0x0a, 0x0c,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x12, 0x2a, 0x0a, 0x02,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x73, 0x12, 0x32, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x2e, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x56, 0x0a, 0x06,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45,
+	0x44, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x01,
+	0x12, 0x0b, 0x0a, 0x07, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x44, 0x10, 0x02, 0x12, 0x08, 0x0a,
+	0x04, 0x52, 0x45, 0x41, 0x44, 0x10, 0x03, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x4e, 0x41, 0x50, 0x53,
+	0x48, 0x4f, 0x54, 0x54, 0x45, 0x44, 0x10, 0x04, 0x12, 0x0a, 0x0a, 0x06, 0x4c, 0x49, 0x53, 0x54,
+	0x45, 0x44, 0x10, 0x05, 0x22, 0xa2, 0x02, 0x0a, 0x14, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x45, 0x0a,
+	0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x29, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e,
+	0x74, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x73, 0x2e, 0x52, 0x65, 0x71,
+	0x75, 0x69, 0x72, 0x65, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75,
+	0x69, 0x72, 0x65, 0x64, 0x12, 0x48, 0x0a, 0x09, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65,
+	0x64, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x50, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61,
+	0x69, 0x6e, 0x74, 0x73, 0x2e, 0x50, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x09, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x1a, 0x3b,
+	0x0a, 0x0d, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3c, 0x0a, 0x0e, 0x50,
+	0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xc6, 0x01, 0x0a, 0x13, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d,
+	0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74,
+	0x61, 0x12, 0x3d, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50,
+	0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69,
+	0x6e, 0x74, 0x73, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x73,
+	0x12, 0x20, 0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x22, 0x3f, 0x0a, 0x14, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x04, 0x6d, 0x65,
+	0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d,
+	0x65, 0x74, 0x61, 0x22, 0x51, 0x0a, 0x13, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65,
+	0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65,
+	0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x3f, 0x0a, 0x14, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27,
+	0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x74,
+	0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x22, 0x61, 0x0a, 0x0f, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65,
+	0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65,
+	0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x3a, 0x0a, 0x0f, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x12, 0x27, 0x0a,
+	0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x74, 0x61,
+	0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x22, 0x4c, 0x0a, 0x0e, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x4f,
+	0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x22, 0x6b, 0x0a, 0x0e, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x4f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12,
+	0x18, 0x0a, 0x07, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x07, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x72, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x65, 0x6e,
+	0x63, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x04, 0x52, 0x06, 0x66, 0x65, 0x6e, 0x63, 0x65,
+	0x73, 0x22, 0x66, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65,
+	0x71, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d,
+	0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x22, 0x4b, 0x0a, 0x0c, 0x47, 0x65, 0x74,
+	0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x04, 0x6d, 0x65, 0x74,
+	0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65,
+	0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x4f, 0x0a, 0x11, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x12, 0x26, 0x0a, 0x04, 0x6d,
+	0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d,
+	0x65, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x56, 0x0a, 0x11, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x04,
+	0x6d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x52,
+	0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x61, 0x6d, 0x70, 0x6f, 0x72, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6c, 0x61, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x22,
+	0x4f, 0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
 	0x74, 0x52, 0x65, 0x71, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65,
 	0x73, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04,
 	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
-	0x64, 0x61, 0x74, 0x61, 0x22, 0x3a, 0x0a, 0x0f, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4f, 0x62,
-	0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61,
-	0x22, 0x4c, 0x0a, 0x0e, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52,
-	0x65, 0x71, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x53,
-	0x0a, 0x0e, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73,
-	0x12, 0x27, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d,
-	0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x65, 0x61,
-	0x73, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x6c, 0x65, 0x61, 0x73,
-	0x65, 0x72, 0x73, 0x22, 0xe1, 0x02, 0x0a, 0x0d, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x65, 0x61, 0x72,
-	0x74, 0x42, 0x65, 0x61, 0x74, 0x12, 0x2d, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x07, 0x20,
-	0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x6f, 0x64, 0x65,
-	0x48, 0x65, 0x61, 0x72, 0x74, 0x42, 0x65, 0x61, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04,
-	0x74, 0x79, 0x70, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x02, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x62, 0x6a, 0x65,
-	0x63, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x62, 0x6a, 0x65, 0x63,
-	0x74, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6c, 0x65, 0x61, 0x73, 0x65,
-	0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x54, 0x61, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x61, 0x67, 0x12, 0x3b, 0x0a, 0x0a, 0x6f, 0x62, 0x6a, 0x65,
-	0x63, 0x74, 0x44, 0x61, 0x74, 0x61, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x65, 0x61, 0x72, 0x74, 0x42, 0x65,
-	0x61, 0x74, 0x2e, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63,
-	0x74, 0x44, 0x61, 0x74, 0x61, 0x1a, 0x30, 0x0a, 0x06, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12,
-	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
-	0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x2e, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12,
-	0x07, 0x0a, 0x03, 0x41, 0x43, 0x4b, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x42, 0x45, 0x41, 0x54,
-	0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x44, 0x49, 0x53, 0x54, 0x52, 0x49, 0x55, 0x54, 0x45, 0x44,
-	0x5f, 0x52, 0x45, 0x41, 0x44, 0x10, 0x02, 0x22, 0xf2, 0x03, 0x0a, 0x0e, 0x43, 0x6f, 0x6d, 0x6d,
-	0x61, 0x6e, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x04, 0x6d, 0x65,
-	0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d,
-	0x65, 0x74, 0x61, 0x12, 0x37, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x6d,
-	0x6d, 0x61, 0x6e, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x2e, 0x43, 0x6f, 0x6d, 0x6d,
-	0x61, 0x6e, 0x64, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x2c, 0x0a, 0x06,
-	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61,
-	0x6e, 0x64, 0x52, 0x06, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x63, 0x6f,
-	0x6d, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
-	0x52, 0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x12, 0x2c, 0x0a, 0x06, 0x64, 0x65, 0x6c, 0x65,
-	0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x06,
-	0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
-	0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x55,
-	0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x06, 0x75, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54,
-	0x61, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x54, 0x61, 0x67, 0x12, 0x47, 0x0a, 0x0f, 0x64, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75,
-	0x74, 0x65, 0x64, 0x52, 0x65, 0x61, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65,
-	0x64, 0x52, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x0f, 0x64, 0x69,
-	0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x64, 0x52, 0x65, 0x61, 0x64, 0x22, 0x5d, 0x0a,
-	0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x0c, 0x0a, 0x08, 0x52, 0x45, 0x47, 0x49,
-	0x53, 0x54, 0x45, 0x52, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45,
-	0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x4f, 0x4d, 0x4d, 0x49, 0x54, 0x10, 0x02, 0x12, 0x0a,
-	0x0a, 0x06, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x55, 0x50,
-	0x44, 0x41, 0x54, 0x45, 0x10, 0x04, 0x12, 0x14, 0x0a, 0x10, 0x44, 0x49, 0x53, 0x54, 0x52, 0x49,
-	0x42, 0x55, 0x54, 0x45, 0x44, 0x5f, 0x52, 0x45, 0x41, 0x44, 0x10, 0x05, 0x22, 0x55, 0x0a, 0x0d,
-	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x1e, 0x0a,
+	0x22, 0x3c, 0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x52, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x22, 0x71,
+	0x0a, 0x11, 0x53, 0x65, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x71, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x20, 0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x22, 0x58, 0x0a, 0x11, 0x53, 0x65, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12,
+	0x1a, 0x0a, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x73, 0x22, 0x50, 0x0a, 0x0e, 0x4c,
+	0x69, 0x73, 0x74, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x52, 0x65, 0x71, 0x12, 0x26, 0x0a,
+	0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x52,
+	0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x22, 0x4f, 0x0a,
+	0x0e, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x52, 0x65, 0x73, 0x12,
+	0x27, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65,
+	0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x61, 0x6d, 0x65,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x22, 0xe9,
+	0x06, 0x0a, 0x0d, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x65, 0x61, 0x72, 0x74, 0x42, 0x65, 0x61, 0x74,
+	0x12, 0x2d, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x65, 0x61, 0x72, 0x74,
+	0x42, 0x65, 0x61, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x02, 0x52, 0x04, 0x73,
+	0x69, 0x7a, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x12, 0x24, 0x0a,
+	0x0d, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x3b, 0x0a, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x44, 0x61, 0x74,
+	0x61, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x4e, 0x6f, 0x64, 0x65, 0x48, 0x65, 0x61, 0x72, 0x74, 0x42, 0x65, 0x61, 0x74, 0x2e, 0x4f, 0x62,
+	0x6a, 0x65, 0x63, 0x74, 0x52, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x44, 0x61, 0x74, 0x61,
+	0x12, 0x2a, 0x0a, 0x05, 0x62, 0x61, 0x74, 0x63, 0x68, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x65, 0x61, 0x72,
+	0x74, 0x42, 0x65, 0x61, 0x74, 0x52, 0x05, 0x62, 0x61, 0x74, 0x63, 0x68, 0x12, 0x1c, 0x0a, 0x09,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x6f,
+	0x64, 0x65, 0x49, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65,
+	0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x64, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x64,
+	0x12, 0x1c, 0x0a, 0x09, 0x6a, 0x6f, 0x69, 0x6e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x6a, 0x6f, 0x69, 0x6e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x18,
+	0x0a, 0x07, 0x6c, 0x61, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x07, 0x6c, 0x61, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x2d, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65,
+	0x18, 0x0f, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e,
+	0x6f, 0x64, 0x65, 0x48, 0x65, 0x61, 0x72, 0x74, 0x42, 0x65, 0x61, 0x74, 0x2e, 0x43, 0x6f, 0x64,
+	0x65, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1a, 0x0a,
+	0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x11, 0x20, 0x01, 0x28, 0x02, 0x52,
+	0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x7a, 0x6f, 0x6e,
+	0x65, 0x18, 0x12, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x7a, 0x6f, 0x6e, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x72, 0x61, 0x63, 0x6b, 0x18, 0x13, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x61, 0x63,
+	0x6b, 0x12, 0x38, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x14, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x65,
+	0x61, 0x72, 0x74, 0x42, 0x65, 0x61, 0x74, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x66,
+	0x65, 0x6e, 0x63, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x66, 0x65, 0x6e, 0x63,
+	0x65, 0x1a, 0x30, 0x0a, 0x06, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x39,
+	0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x43, 0x4b, 0x10, 0x00, 0x12,
+	0x08, 0x0a, 0x04, 0x42, 0x45, 0x41, 0x54, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x44, 0x49, 0x53,
+	0x54, 0x52, 0x49, 0x55, 0x54, 0x45, 0x44, 0x5f, 0x52, 0x45, 0x41, 0x44, 0x10, 0x02, 0x12, 0x09,
+	0x0a, 0x05, 0x42, 0x41, 0x54, 0x43, 0x48, 0x10, 0x03, 0x22, 0x49, 0x0a, 0x04, 0x43, 0x6f, 0x64,
+	0x65, 0x12, 0x06, 0x0a, 0x02, 0x4f, 0x4b, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x41, 0x49,
+	0x4c, 0x45, 0x44, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x44, 0x49, 0x53, 0x4b, 0x5f, 0x46, 0x55,
+	0x4c, 0x4c, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x43, 0x4f, 0x4e, 0x53, 0x54, 0x52, 0x41, 0x49,
+	0x4e, 0x54, 0x10, 0x03, 0x12, 0x0e, 0x0a, 0x0a, 0x43, 0x4f, 0x52, 0x52, 0x55, 0x50, 0x54, 0x49,
+	0x4f, 0x4e, 0x10, 0x04, 0x4a, 0x04, 0x08, 0x06, 0x10, 0x07, 0x22, 0xf8, 0x05, 0x0a, 0x0e, 0x43,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x12, 0x27, 0x0a,
+	0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x74, 0x61,
+	0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x37, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x2e, 0x43,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12,
+	0x2c, 0x0a, 0x06, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x06, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x2c, 0x0a,
+	0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x43, 0x6f, 0x6d, 0x6d,
+	0x61, 0x6e, 0x64, 0x52, 0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x12, 0x2c, 0x0a, 0x06, 0x64,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x52, 0x06, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52,
+	0x06, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x47, 0x0a, 0x0f, 0x64, 0x69, 0x73, 0x74, 0x72,
+	0x69, 0x62, 0x75, 0x74, 0x65, 0x64, 0x52, 0x65, 0x61, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62,
+	0x75, 0x74, 0x65, 0x64, 0x52, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52,
+	0x0f, 0x64, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x64, 0x52, 0x65, 0x61, 0x64,
+	0x12, 0x32, 0x0a, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x12, 0x2b, 0x0a, 0x05, 0x62, 0x61, 0x74, 0x63, 0x68, 0x18, 0x0a, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x6d, 0x6d,
+	0x61, 0x6e, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x52, 0x05, 0x62, 0x61, 0x74, 0x63,
+	0x68, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x12,
+	0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05,
+	0x65, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x32, 0x0a, 0x08, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65,
+	0x72, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52,
+	0x08, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x65, 0x6e,
+	0x63, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x66, 0x65, 0x6e, 0x63, 0x65, 0x22,
+	0x9d, 0x01, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x0c, 0x0a, 0x08, 0x52,
+	0x45, 0x47, 0x49, 0x53, 0x54, 0x45, 0x52, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x52, 0x45,
+	0x41, 0x54, 0x45, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x4f, 0x4d, 0x4d, 0x49, 0x54, 0x10,
+	0x02, 0x12, 0x0a, 0x0a, 0x06, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x10, 0x03, 0x12, 0x0a, 0x0a,
+	0x06, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x10, 0x04, 0x12, 0x14, 0x0a, 0x10, 0x44, 0x49, 0x53,
+	0x54, 0x52, 0x49, 0x42, 0x55, 0x54, 0x45, 0x44, 0x5f, 0x52, 0x45, 0x41, 0x44, 0x10, 0x05, 0x12,
+	0x0c, 0x0a, 0x08, 0x53, 0x4e, 0x41, 0x50, 0x53, 0x48, 0x4f, 0x54, 0x10, 0x06, 0x12, 0x13, 0x0a,
+	0x0f, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x5f, 0x53, 0x4e, 0x41, 0x50, 0x53, 0x48, 0x4f, 0x54,
+	0x10, 0x07, 0x12, 0x09, 0x0a, 0x05, 0x42, 0x41, 0x54, 0x43, 0x48, 0x10, 0x08, 0x12, 0x10, 0x0a,
+	0x0c, 0x44, 0x45, 0x43, 0x4f, 0x4d, 0x4d, 0x49, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x10, 0x09, 0x4a,
+	0x04, 0x08, 0x07, 0x10, 0x08, 0x22, 0x55, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x43,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x44, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6f, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x44, 0x61, 0x74, 0x61, 0x4a, 0x04, 0x08, 0x02, 0x10, 0x03, 0x22, 0x69, 0x0a, 0x0d,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x1e, 0x0a,
 	0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x09, 0x52, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a,
-	0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x44, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x44, 0x61, 0x74, 0x61, 0x4a, 0x04, 0x08,
-	0x02, 0x10, 0x03, 0x22, 0x69, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6d,
-	0x6d, 0x61, 0x6e, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61,
-	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74,
-	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x44, 0x61,
-	0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74,
-	0x44, 0x61, 0x74, 0x61, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x61, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x6c, 0x61, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x22, 0x4f,
-	0x0a, 0x0d, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12,
-	0x18, 0x0a, 0x07, 0x6c, 0x61, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x44, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x18, 0x0a,
+	0x07, 0x6c, 0x61, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07,
+	0x6c, 0x61, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x22, 0x65, 0x0a, 0x0d, 0x43, 0x6f, 0x6d, 0x6d, 0x69,
+	0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x61, 0x6d, 0x70,
+	0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6c, 0x61, 0x6d, 0x70, 0x6f,
+	0x72, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x4a, 0x04, 0x08, 0x01, 0x10, 0x02, 0x22, 0x5d,
+	0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12,
+	0x18, 0x0a, 0x07, 0x6c, 0x61, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
 	0x52, 0x07, 0x6c, 0x61, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x6f, 0x62, 0x6a,
-	0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f,
-	0x62, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x4a, 0x04, 0x08, 0x01, 0x10, 0x02, 0x22,
-	0x49, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
-	0x12, 0x18, 0x0a, 0x07, 0x6c, 0x61, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x07, 0x6c, 0x61, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x6f, 0x62,
-	0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
-	0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x4c, 0x0a, 0x16, 0x44, 0x69,
-	0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x64, 0x52, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x6d,
-	0x6d, 0x61, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x12, 0x18,
-	0x0a, 0x07, 0x6c, 0x61, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x07, 0x6c, 0x61, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x32, 0x9c, 0x02, 0x0a, 0x0b, 0x4e, 0x61, 0x6d,
-	0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x47, 0x0a, 0x0c, 0x43, 0x72, 0x65, 0x61,
-	0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65,
-	0x61, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x47, 0x0a, 0x0c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63,
-	0x74, 0x12, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
-	0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65,
-	0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x0c, 0x55, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52,
-	0x65, 0x71, 0x1a, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74,
-	0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x12, 0x3b, 0x0a, 0x0b, 0x4c, 0x65,
-	0x61, 0x73, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71,
-	0x1a, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x4f, 0x62,
-	0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x32, 0x4e, 0x0a, 0x0b, 0x44, 0x61, 0x74, 0x61, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3f, 0x0a, 0x0c, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
-	0x65, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e,
-	0x6f, 0x64, 0x65, 0x48, 0x65, 0x61, 0x72, 0x74, 0x42, 0x65, 0x61, 0x74, 0x1a, 0x15, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4e, 0x6f, 0x64, 0x65,
-	0x52, 0x65, 0x73, 0x28, 0x01, 0x30, 0x01, 0x42, 0x0c, 0x5a, 0x0a, 0x2e, 0x2e, 0x2f, 0x61, 0x70,
-	0x69, 0x3b, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f,
+	0x62, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x6f, 0x64,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x22, 0x68, 0x0a,
+	0x16, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x64, 0x52, 0x65, 0x61, 0x64,
+	0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x73, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x61, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x07, 0x6c, 0x61, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x22, 0x61, 0x0a, 0x0f, 0x52, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x65, 0x72, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x61,
+	0x6d, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6c, 0x61, 0x6d,
+	0x70, 0x6f, 0x72, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72,
+	0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x79, 0x6e, 0x63, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x06, 0x72, 0x65, 0x73, 0x79, 0x6e, 0x63, 0x22, 0x3f, 0x0a, 0x0f, 0x53, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x18, 0x0a,
+	0x07, 0x6c, 0x61, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07,
+	0x6c, 0x61, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x32, 0xe2, 0x04, 0x0a, 0x0b,
+	0x4e, 0x61, 0x6d, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x47, 0x0a, 0x0c, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x1a, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x0c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4f, 0x62,
+	0x6a, 0x65, 0x63, 0x74, 0x12, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4f,
+	0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a,
+	0x0c, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x16, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x12, 0x3b, 0x0a,
+	0x0b, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x15, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x52, 0x65, 0x71, 0x1a, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x65, 0x61, 0x73,
+	0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x12, 0x35, 0x0a, 0x09, 0x47, 0x65,
+	0x74, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x47, 0x65, 0x74, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65,
+	0x73, 0x12, 0x44, 0x0a, 0x0e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x18, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x12, 0x44, 0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x52, 0x65, 0x71, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x12, 0x3b, 0x0a,
+	0x0b, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x12, 0x15, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x1a, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x52, 0x65, 0x73, 0x12, 0x44, 0x0a, 0x0e, 0x53, 0x65,
+	0x74, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x65, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53,
+	0x65, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
+	0x32, 0x4e, 0x0a, 0x0b, 0x44, 0x61, 0x74, 0x61, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x3f, 0x0a, 0x0c, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x12,
+	0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x65, 0x61, 0x72,
+	0x74, 0x42, 0x65, 0x61, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x28, 0x01, 0x30, 0x01,
+	0x42, 0x0c, 0x5a, 0x0a, 0x2e, 0x2e, 0x2f, 0x61, 0x70, 0x69, 0x3b, 0x61, 0x70, 0x69, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1397,68 +2555,114 @@ func file_namenode_proto_rawDescGZIP() []byte {
 	return file_namenode_proto_rawDescData
 }
 
-var file_namenode_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_namenode_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_namenode_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_namenode_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
 var file_namenode_proto_goTypes = []any{
 	(ResponseMeta_Status)(0),       // 0: proto.ResponseMeta.Status
 	(NodeHeartBeat_Type)(0),        // 1: proto.NodeHeartBeat.Type
-	(CommandNodeRes_Command)(0),    // 2: proto.CommandNodeRes.Command
-	(*RequestMeta)(nil),            // 3: proto.RequestMeta
-	(*ResponseMeta)(nil),           // 4: proto.ResponseMeta
-	(*CreateObjectRequest)(nil),    // 5: proto.CreateObjectRequest
-	(*CreateObjectResponse)(nil),   // 6: proto.CreateObjectResponse
-	(*DeleteObjectRequest)(nil),    // 7: proto.DeleteObjectRequest
-	(*DeleteObjectResponse)(nil),   // 8: proto.DeleteObjectResponse
-	(*UpdateObjectReq)(nil),        // 9: proto.UpdateObjectReq
-	(*UpdateObjectRes)(nil),        // 10: proto.UpdateObjectRes
-	(*LeaseObjectReq)(nil),         // 11: proto.LeaseObjectReq
-	(*LeaseObjectRes)(nil),         // 12: proto.LeaseObjectRes
-	(*NodeHeartBeat)(nil),          // 13: proto.NodeHeartBeat
-	(*CommandNodeRes)(nil),         // 14: proto.CommandNodeRes
-	(*CreateCommand)(nil),          // 15: proto.CreateCommand
-	(*UpdateCommand)(nil),          // 16: proto.UpdateCommand
-	(*CommitCommand)(nil),          // 17: proto.CommitCommand
-	(*DeleteCommand)(nil),          // 18: proto.DeleteCommand
-	(*DistributedReadCommand)(nil), // 19: proto.DistributedReadCommand
-	(*NodeHeartBeat_Object)(nil),   // 20: proto.NodeHeartBeat.Object
-	(*timestamppb.Timestamp)(nil),  // 21: google.protobuf.Timestamp
+	(NodeHeartBeat_Code)(0),        // 2: proto.NodeHeartBeat.Code
+	(CommandNodeRes_Command)(0),    // 3: proto.CommandNodeRes.Command
+	(*RequestMeta)(nil),            // 4: proto.RequestMeta
+	(*ResponseMeta)(nil),           // 5: proto.ResponseMeta
+	(*PlacementConstraints)(nil),   // 6: proto.PlacementConstraints
+	(*CreateObjectRequest)(nil),    // 7: proto.CreateObjectRequest
+	(*CreateObjectResponse)(nil),   // 8: proto.CreateObjectResponse
+	(*DeleteObjectRequest)(nil),    // 9: proto.DeleteObjectRequest
+	(*DeleteObjectResponse)(nil),   // 10: proto.DeleteObjectResponse
+	(*UpdateObjectReq)(nil),        // 11: proto.UpdateObjectReq
+	(*UpdateObjectRes)(nil),        // 12: proto.UpdateObjectRes
+	(*LeaseObjectReq)(nil),         // 13: proto.LeaseObjectReq
+	(*LeaseObjectRes)(nil),         // 14: proto.LeaseObjectRes
+	(*GetObjectReq)(nil),           // 15: proto.GetObjectReq
+	(*GetObjectRes)(nil),           // 16: proto.GetObjectRes
+	(*CreateSnapshotReq)(nil),      // 17: proto.CreateSnapshotReq
+	(*CreateSnapshotRes)(nil),      // 18: proto.CreateSnapshotRes
+	(*DeleteSnapshotReq)(nil),      // 19: proto.DeleteSnapshotReq
+	(*DeleteSnapshotRes)(nil),      // 20: proto.DeleteSnapshotRes
+	(*SetReplicationReq)(nil),      // 21: proto.SetReplicationReq
+	(*SetReplicationRes)(nil),      // 22: proto.SetReplicationRes
+	(*ListObjectsReq)(nil),         // 23: proto.ListObjectsReq
+	(*ListObjectsRes)(nil),         // 24: proto.ListObjectsRes
+	(*NodeHeartBeat)(nil),          // 25: proto.NodeHeartBeat
+	(*CommandNodeRes)(nil),         // 26: proto.CommandNodeRes
+	(*CreateCommand)(nil),          // 27: proto.CreateCommand
+	(*UpdateCommand)(nil),          // 28: proto.UpdateCommand
+	(*CommitCommand)(nil),          // 29: proto.CommitCommand
+	(*DeleteCommand)(nil),          // 30: proto.DeleteCommand
+	(*DistributedReadCommand)(nil), // 31: proto.DistributedReadCommand
+	(*RegisterCommand)(nil),        // 32: proto.RegisterCommand
+	(*SnapshotCommand)(nil),        // 33: proto.SnapshotCommand
+	nil,                            // 34: proto.PlacementConstraints.RequiredEntry
+	nil,                            // 35: proto.PlacementConstraints.PreferredEntry
+	(*NodeHeartBeat_Object)(nil),   // 36: proto.NodeHeartBeat.Object
+	nil,                            // 37: proto.NodeHeartBeat.LabelsEntry
+	(*timestamppb.Timestamp)(nil),  // 38: google.protobuf.Timestamp
 }
 var file_namenode_proto_depIdxs = []int32{
-	21, // 0: proto.RequestMeta.ts:type_name -> google.protobuf.Timestamp
-	21, // 1: proto.ResponseMeta.ts:type_name -> google.protobuf.Timestamp
+	38, // 0: proto.RequestMeta.ts:type_name -> google.protobuf.Timestamp
+	38, // 1: proto.ResponseMeta.ts:type_name -> google.protobuf.Timestamp
 	0,  // 2: proto.ResponseMeta.status:type_name -> proto.ResponseMeta.Status
-	3,  // 3: proto.CreateObjectRequest.meta:type_name -> proto.RequestMeta
-	4,  // 4: proto.CreateObjectResponse.meta:type_name -> proto.ResponseMeta
-	3,  // 5: proto.DeleteObjectRequest.meta:type_name -> proto.RequestMeta
-	4,  // 6: proto.DeleteObjectResponse.meta:type_name -> proto.ResponseMeta
-	3,  // 7: proto.UpdateObjectReq.meta:type_name -> proto.RequestMeta
-	4,  // 8: proto.UpdateObjectRes.meta:type_name -> proto.ResponseMeta
-	3,  // 9: proto.LeaseObjectReq.meta:type_name -> proto.RequestMeta
-	4,  // 10: proto.LeaseObjectRes.meta:type_name -> proto.ResponseMeta
-	1,  // 11: proto.NodeHeartBeat.type:type_name -> proto.NodeHeartBeat.Type
-	20, // 12: proto.NodeHeartBeat.objectData:type_name -> proto.NodeHeartBeat.Object
-	4,  // 13: proto.CommandNodeRes.meta:type_name -> proto.ResponseMeta
-	2,  // 14: proto.CommandNodeRes.command:type_name -> proto.CommandNodeRes.Command
-	15, // 15: proto.CommandNodeRes.create:type_name -> proto.CreateCommand
-	17, // 16: proto.CommandNodeRes.commit:type_name -> proto.CommitCommand
-	18, // 17: proto.CommandNodeRes.delete:type_name -> proto.DeleteCommand
-	16, // 18: proto.CommandNodeRes.update:type_name -> proto.UpdateCommand
-	19, // 19: proto.CommandNodeRes.distributedRead:type_name -> proto.DistributedReadCommand
-	5,  // 20: proto.NameService.CreateObject:input_type -> proto.CreateObjectRequest
-	7,  // 21: proto.NameService.DeleteObject:input_type -> proto.DeleteObjectRequest
-	9,  // 22: proto.NameService.UpdateObject:input_type -> proto.UpdateObjectReq
-	11, // 23: proto.NameService.LeaseObject:input_type -> proto.LeaseObjectReq
-	13, // 24: proto.DataService.RegisterNode:input_type -> proto.NodeHeartBeat
-	6,  // 25: proto.NameService.CreateObject:output_type -> proto.CreateObjectResponse
-	8,  // 26: proto.NameService.DeleteObject:output_type -> proto.DeleteObjectResponse
-	10, // 27: proto.NameService.UpdateObject:output_type -> proto.UpdateObjectRes
-	12, // 28: proto.NameService.LeaseObject:output_type -> proto.LeaseObjectRes
-	14, // 29: proto.DataService.RegisterNode:output_type -> proto.CommandNodeRes
-	25, // [25:30] is the sub-list for method output_type
-	20, // [20:25] is the sub-list for method input_type
-	20, // [20:20] is the sub-list for extension type_name
-	20, // [20:20] is the sub-list for extension extendee
-	0,  // [0:20] is the sub-list for field type_name
+	34, // 3: proto.PlacementConstraints.required:type_name -> proto.PlacementConstraints.RequiredEntry
+	35, // 4: proto.PlacementConstraints.preferred:type_name -> proto.PlacementConstraints.PreferredEntry
+	4,  // 5: proto.CreateObjectRequest.meta:type_name -> proto.RequestMeta
+	6,  // 6: proto.CreateObjectRequest.constraints:type_name -> proto.PlacementConstraints
+	5,  // 7: proto.CreateObjectResponse.meta:type_name -> proto.ResponseMeta
+	4,  // 8: proto.DeleteObjectRequest.meta:type_name -> proto.RequestMeta
+	5,  // 9: proto.DeleteObjectResponse.meta:type_name -> proto.ResponseMeta
+	4,  // 10: proto.UpdateObjectReq.meta:type_name -> proto.RequestMeta
+	5,  // 11: proto.UpdateObjectRes.meta:type_name -> proto.ResponseMeta
+	4,  // 12: proto.LeaseObjectReq.meta:type_name -> proto.RequestMeta
+	5,  // 13: proto.LeaseObjectRes.meta:type_name -> proto.ResponseMeta
+	4,  // 14: proto.GetObjectReq.meta:type_name -> proto.RequestMeta
+	5,  // 15: proto.GetObjectRes.meta:type_name -> proto.ResponseMeta
+	4,  // 16: proto.CreateSnapshotReq.meta:type_name -> proto.RequestMeta
+	5,  // 17: proto.CreateSnapshotRes.meta:type_name -> proto.ResponseMeta
+	4,  // 18: proto.DeleteSnapshotReq.meta:type_name -> proto.RequestMeta
+	5,  // 19: proto.DeleteSnapshotRes.meta:type_name -> proto.ResponseMeta
+	4,  // 20: proto.SetReplicationReq.meta:type_name -> proto.RequestMeta
+	5,  // 21: proto.SetReplicationRes.meta:type_name -> proto.ResponseMeta
+	4,  // 22: proto.ListObjectsReq.meta:type_name -> proto.RequestMeta
+	5,  // 23: proto.ListObjectsRes.meta:type_name -> proto.ResponseMeta
+	1,  // 24: proto.NodeHeartBeat.type:type_name -> proto.NodeHeartBeat.Type
+	36, // 25: proto.NodeHeartBeat.objectData:type_name -> proto.NodeHeartBeat.Object
+	25, // 26: proto.NodeHeartBeat.batch:type_name -> proto.NodeHeartBeat
+	2,  // 27: proto.NodeHeartBeat.code:type_name -> proto.NodeHeartBeat.Code
+	37, // 28: proto.NodeHeartBeat.labels:type_name -> proto.NodeHeartBeat.LabelsEntry
+	5,  // 29: proto.CommandNodeRes.meta:type_name -> proto.ResponseMeta
+	3,  // 30: proto.CommandNodeRes.command:type_name -> proto.CommandNodeRes.Command
+	27, // 31: proto.CommandNodeRes.create:type_name -> proto.CreateCommand
+	29, // 32: proto.CommandNodeRes.commit:type_name -> proto.CommitCommand
+	30, // 33: proto.CommandNodeRes.delete:type_name -> proto.DeleteCommand
+	28, // 34: proto.CommandNodeRes.update:type_name -> proto.UpdateCommand
+	31, // 35: proto.CommandNodeRes.distributedRead:type_name -> proto.DistributedReadCommand
+	33, // 36: proto.CommandNodeRes.snapshot:type_name -> proto.SnapshotCommand
+	26, // 37: proto.CommandNodeRes.batch:type_name -> proto.CommandNodeRes
+	32, // 38: proto.CommandNodeRes.register:type_name -> proto.RegisterCommand
+	7,  // 39: proto.NameService.CreateObject:input_type -> proto.CreateObjectRequest
+	9,  // 40: proto.NameService.DeleteObject:input_type -> proto.DeleteObjectRequest
+	11, // 41: proto.NameService.UpdateObject:input_type -> proto.UpdateObjectReq
+	13, // 42: proto.NameService.LeaseObject:input_type -> proto.LeaseObjectReq
+	15, // 43: proto.NameService.GetObject:input_type -> proto.GetObjectReq
+	17, // 44: proto.NameService.CreateSnapshot:input_type -> proto.CreateSnapshotReq
+	19, // 45: proto.NameService.DeleteSnapshot:input_type -> proto.DeleteSnapshotReq
+	23, // 46: proto.NameService.ListObjects:input_type -> proto.ListObjectsReq
+	21, // 47: proto.NameService.SetReplication:input_type -> proto.SetReplicationReq
+	25, // 48: proto.DataService.RegisterNode:input_type -> proto.NodeHeartBeat
+	8,  // 49: proto.NameService.CreateObject:output_type -> proto.CreateObjectResponse
+	10, // 50: proto.NameService.DeleteObject:output_type -> proto.DeleteObjectResponse
+	12, // 51: proto.NameService.UpdateObject:output_type -> proto.UpdateObjectRes
+	14, // 52: proto.NameService.LeaseObject:output_type -> proto.LeaseObjectRes
+	16, // 53: proto.NameService.GetObject:output_type -> proto.GetObjectRes
+	18, // 54: proto.NameService.CreateSnapshot:output_type -> proto.CreateSnapshotRes
+	20, // 55: proto.NameService.DeleteSnapshot:output_type -> proto.DeleteSnapshotRes
+	24, // 56: proto.NameService.ListObjects:output_type -> proto.ListObjectsRes
+	22, // 57: proto.NameService.SetReplication:output_type -> proto.SetReplicationRes
+	26, // 58: proto.DataService.RegisterNode:output_type -> proto.CommandNodeRes
+	49, // [49:59] is the sub-list for method output_type
+	39, // [39:49] is the sub-list for method input_type
+	39, // [39:39] is the sub-list for extension type_name
+	39, // [39:39] is the sub-list for extension extendee
+	0,  // [0:39] is the sub-list for field type_name
 }
 
 func init() { file_namenode_proto_init() }
@@ -1471,8 +2675,8 @@ func file_namenode_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_namenode_proto_rawDesc,
-			NumEnums:      3,
-			NumMessages:   18,
+			NumEnums:      4,
+			NumMessages:   34,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
@@ -21,10 +21,15 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	NameService_CreateObject_FullMethodName = "/proto.NameService/CreateObject"
-	NameService_DeleteObject_FullMethodName = "/proto.NameService/DeleteObject"
-	NameService_UpdateObject_FullMethodName = "/proto.NameService/UpdateObject"
-	NameService_LeaseObject_FullMethodName  = "/proto.NameService/LeaseObject"
+	NameService_CreateObject_FullMethodName   = "/proto.NameService/CreateObject"
+	NameService_DeleteObject_FullMethodName   = "/proto.NameService/DeleteObject"
+	NameService_UpdateObject_FullMethodName   = "/proto.NameService/UpdateObject"
+	NameService_LeaseObject_FullMethodName    = "/proto.NameService/LeaseObject"
+	NameService_GetObject_FullMethodName      = "/proto.NameService/GetObject"
+	NameService_CreateSnapshot_FullMethodName = "/proto.NameService/CreateSnapshot"
+	NameService_DeleteSnapshot_FullMethodName = "/proto.NameService/DeleteSnapshot"
+	NameService_ListObjects_FullMethodName    = "/proto.NameService/ListObjects"
+	NameService_SetReplication_FullMethodName = "/proto.NameService/SetReplication"
 )
 
 // NameServiceClient is the client API for NameService service.
@@ -36,6 +41,11 @@ type NameServiceClient interface {
 	DeleteObject(ctx context.Context, in *DeleteObjectRequest, opts ...grpc.CallOption) (*DeleteObjectResponse, error)
 	UpdateObject(ctx context.Context, in *UpdateObjectReq, opts ...grpc.CallOption) (*UpdateObjectRes, error)
 	LeaseObject(ctx context.Context, in *LeaseObjectReq, opts ...grpc.CallOption) (*LeaseObjectRes, error)
+	GetObject(ctx context.Context, in *GetObjectReq, opts ...grpc.CallOption) (*GetObjectRes, error)
+	CreateSnapshot(ctx context.Context, in *CreateSnapshotReq, opts ...grpc.CallOption) (*CreateSnapshotRes, error)
+	DeleteSnapshot(ctx context.Context, in *DeleteSnapshotReq, opts ...grpc.CallOption) (*DeleteSnapshotRes, error)
+	ListObjects(ctx context.Context, in *ListObjectsReq, opts ...grpc.CallOption) (*ListObjectsRes, error)
+	SetReplication(ctx context.Context, in *SetReplicationReq, opts ...grpc.CallOption) (*SetReplicationRes, error)
 }
 
 type nameServiceClient struct {
@@ -86,6 +96,56 @@ func (c *nameServiceClient) LeaseObject(ctx context.Context, in *LeaseObjectReq,
 	return out, nil
 }
 
+func (c *nameServiceClient) GetObject(ctx context.Context, in *GetObjectReq, opts ...grpc.CallOption) (*GetObjectRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetObjectRes)
+	err := c.cc.Invoke(ctx, NameService_GetObject_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nameServiceClient) CreateSnapshot(ctx context.Context, in *CreateSnapshotReq, opts ...grpc.CallOption) (*CreateSnapshotRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateSnapshotRes)
+	err := c.cc.Invoke(ctx, NameService_CreateSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nameServiceClient) DeleteSnapshot(ctx context.Context, in *DeleteSnapshotReq, opts ...grpc.CallOption) (*DeleteSnapshotRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteSnapshotRes)
+	err := c.cc.Invoke(ctx, NameService_DeleteSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nameServiceClient) ListObjects(ctx context.Context, in *ListObjectsReq, opts ...grpc.CallOption) (*ListObjectsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListObjectsRes)
+	err := c.cc.Invoke(ctx, NameService_ListObjects_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nameServiceClient) SetReplication(ctx context.Context, in *SetReplicationReq, opts ...grpc.CallOption) (*SetReplicationRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetReplicationRes)
+	err := c.cc.Invoke(ctx, NameService_SetReplication_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NameServiceServer is the server API for NameService service.
 // All implementations must embed UnimplementedNameServiceServer
 // for forward compatibility.
@@ -95,6 +155,11 @@ type NameServiceServer interface {
 	DeleteObject(context.Context, *DeleteObjectRequest) (*DeleteObjectResponse, error)
 	UpdateObject(context.Context, *UpdateObjectReq) (*UpdateObjectRes, error)
 	LeaseObject(context.Context, *LeaseObjectReq) (*LeaseObjectRes, error)
+	GetObject(context.Context, *GetObjectReq) (*GetObjectRes, error)
+	CreateSnapshot(context.Context, *CreateSnapshotReq) (*CreateSnapshotRes, error)
+	DeleteSnapshot(context.Context, *DeleteSnapshotReq) (*DeleteSnapshotRes, error)
+	ListObjects(context.Context, *ListObjectsReq) (*ListObjectsRes, error)
+	SetReplication(context.Context, *SetReplicationReq) (*SetReplicationRes, error)
 	mustEmbedUnimplementedNameServiceServer()
 }
 
@@ -117,6 +182,21 @@ func (UnimplementedNameServiceServer) UpdateObject(context.Context, *UpdateObjec
 func (UnimplementedNameServiceServer) LeaseObject(context.Context, *LeaseObjectReq) (*LeaseObjectRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LeaseObject not implemented")
 }
+func (UnimplementedNameServiceServer) GetObject(context.Context, *GetObjectReq) (*GetObjectRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetObject not implemented")
+}
+func (UnimplementedNameServiceServer) CreateSnapshot(context.Context, *CreateSnapshotReq) (*CreateSnapshotRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSnapshot not implemented")
+}
+func (UnimplementedNameServiceServer) DeleteSnapshot(context.Context, *DeleteSnapshotReq) (*DeleteSnapshotRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSnapshot not implemented")
+}
+func (UnimplementedNameServiceServer) ListObjects(context.Context, *ListObjectsReq) (*ListObjectsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListObjects not implemented")
+}
+func (UnimplementedNameServiceServer) SetReplication(context.Context, *SetReplicationReq) (*SetReplicationRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetReplication not implemented")
+}
 func (UnimplementedNameServiceServer) mustEmbedUnimplementedNameServiceServer() {}
 func (UnimplementedNameServiceServer) testEmbeddedByValue()                     {}
 
@@ -210,6 +290,96 @@ func _NameService_LeaseObject_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NameService_GetObject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetObjectReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameServiceServer).GetObject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NameService_GetObject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameServiceServer).GetObject(ctx, req.(*GetObjectReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NameService_CreateSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSnapshotReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameServiceServer).CreateSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NameService_CreateSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameServiceServer).CreateSnapshot(ctx, req.(*CreateSnapshotReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NameService_DeleteSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSnapshotReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameServiceServer).DeleteSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NameService_DeleteSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameServiceServer).DeleteSnapshot(ctx, req.(*DeleteSnapshotReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NameService_ListObjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListObjectsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameServiceServer).ListObjects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NameService_ListObjects_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameServiceServer).ListObjects(ctx, req.(*ListObjectsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NameService_SetReplication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetReplicationReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameServiceServer).SetReplication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NameService_SetReplication_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameServiceServer).SetReplication(ctx, req.(*SetReplicationReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // NameService_ServiceDesc is the grpc.ServiceDesc for NameService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -233,6 +403,26 @@ var NameService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LeaseObject",
 			Handler:    _NameService_LeaseObject_Handler,
 		},
+		{
+			MethodName: "GetObject",
+			Handler:    _NameService_GetObject_Handler,
+		},
+		{
+			MethodName: "CreateSnapshot",
+			Handler:    _NameService_CreateSnapshot_Handler,
+		},
+		{
+			MethodName: "DeleteSnapshot",
+			Handler:    _NameService_DeleteSnapshot_Handler,
+		},
+		{
+			MethodName: "ListObjects",
+			Handler:    _NameService_ListObjects_Handler,
+		},
+		{
+			MethodName: "SetReplication",
+			Handler:    _NameService_SetReplication_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "namenode.proto",
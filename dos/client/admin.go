@@ -0,0 +1,325 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/mrowaha/dos/api"
+	"github.com/mrowaha/dos/namenode"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// AdminClient inspects and operates the cluster over the connections of the client it came from
+type AdminClient struct {
+	client *DosClient
+}
+
+func (c *DosClient) Admin() *AdminClient {
+	return &AdminClient{client: c}
+}
+
+type NodeStatus struct {
+	Name     string            `json:"name"`
+	NodeId   string            `json:"nodeId"`
+	Leaser   string            `json:"leaser"`
+	Size     float32           `json:"size"`
+	Capacity float32           `json:"capacity,omitempty"` // zero when the datanode did not say
+	Zone     string            `json:"zone,omitempty"`
+	Rack     string            `json:"rack,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Fence    uint64            `json:"fence"`
+	Objects  int               `json:"objects"`
+	Joined   time.Time         `json:"joined"`
+	State    string            `json:"state"` // active, decommissioning, decommissioned or maintenance
+	Drain    *DrainProgress    `json:"drain,omitempty"`
+
+	Maintenance *MaintenanceStatus `json:"maintenance,omitempty"`
+}
+
+// DrainProgress tells how far the objects of a decommissioning node were copied elsewhere
+type DrainProgress struct {
+	Total   int    `json:"total"`
+	Moved   int    `json:"moved"`
+	Failed  int    `json:"failed"`
+	Error   string `json:"error,omitempty"` // the last failure
+	Running bool   `json:"running"`
+}
+
+// MaintenanceStatus tells how long a node stays in maintenance and what it missed while away
+type MaintenanceStatus struct {
+	Until      time.Time `json:"until"`
+	Away       bool      `json:"away"`
+	Buffered   int       `json:"buffered"`   // commands kept for its catch up
+	Overflowed bool      `json:"overflowed"` // it missed too much to catch up, its store is resynced on return
+}
+
+type NodeDetail struct {
+	NodeStatus
+	ObjectNames []string `json:"objectNames"`
+	Queued      int      `json:"queued"`   // commands waiting to be sent to the datanode
+	Awaiting    int      `json:"awaiting"` // commands sent and not acked yet
+}
+
+type Ghost struct {
+	Name  string    `json:"name"`
+	Since time.Time `json:"since"`
+}
+
+type Replica struct {
+	Node   string `json:"node"`
+	Leaser string `json:"leaser,omitempty"` // empty for a ghost that did not register yet
+}
+
+type ObjectLocation struct {
+	Name        string    `json:"name"`
+	Replicas    []Replica `json:"replicas"`
+	Replication int       `json:"replication"`
+
+	Constraints *PlacementConstraints `json:"constraints,omitempty"`
+}
+
+type ClusterSummary struct {
+	ClusterId       string           `json:"clusterId"`
+	Lamport         namenode.Lamport `json:"lamport"`
+	Nodes           int              `json:"nodes"`
+	Ghosts          int              `json:"ghosts"`
+	Objects         int              `json:"objects"`
+	UnderReplicated int              `json:"underReplicated"`
+	Snapshots       int              `json:"snapshots"`
+	Replication     int              `json:"replication"`
+	Tolerance       int              `json:"tolerance"`
+	Decommissioning int              `json:"decommissioning"`
+	Maintenance     int              `json:"maintenance"`
+	Rebalancing     bool             `json:"rebalancing"`
+}
+
+type ReplicaMove struct {
+	Object string `json:"object"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Size   int    `json:"size"` // estimated
+}
+
+// RebalancePlan is the plan of a rebalance round
+type RebalancePlan struct {
+	Moves []ReplicaMove `json:"moves"`
+	Mean  float64       `json:"mean"` // mean datanode size the moves even out to
+}
+
+func nodeStatus(node *api.NodeSummary) NodeStatus {
+	status := NodeStatus{
+		Name:     node.Name,
+		NodeId:   node.NodeId,
+		Leaser:   node.Leaser,
+		Size:     node.Size,
+		Capacity: node.Capacity,
+		Zone:     node.Zone,
+		Rack:     node.Rack,
+		Labels:   node.Labels,
+		Fence:    node.Fence,
+		Objects:  int(node.Objects),
+		Joined:   node.Joined.AsTime(),
+		State:    strings.ToLower(node.State.String()),
+	}
+	if node.Drain != nil {
+		status.Drain = &DrainProgress{
+			Total:   int(node.Drain.Total),
+			Moved:   int(node.Drain.Moved),
+			Failed:  int(node.Drain.Failed),
+			Error:   node.Drain.Error,
+			Running: node.Drain.Running,
+		}
+	}
+	if node.Maintenance != nil {
+		status.Maintenance = &MaintenanceStatus{
+			Until:      node.Maintenance.Until.AsTime(),
+			Away:       node.Maintenance.Away,
+			Buffered:   int(node.Maintenance.Buffered),
+			Overflowed: node.Maintenance.Overflowed,
+		}
+	}
+	return status
+}
+
+// call runs an admin call under the client's retry policy
+// admin calls either change nothing or can be repeated without changing the outcome, so they are always safe to retry
+func (a *AdminClient) call(ctx context.Context, op string, call func(ctx context.Context, admin api.AdminServiceClient) error) error {
+	return a.client.retryConn(ctx, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+		if err := call(ctx, api.NewAdminServiceClient(conn)); err != nil {
+			a.client.logger.Printf("failed to %s...\n%s", op, err.Error())
+			return fromStatus(op, err)
+		}
+		return nil
+	})
+}
+
+func (a *AdminClient) ListNodes(ctx context.Context) ([]NodeStatus, error) {
+	var nodes []NodeStatus
+	err := a.call(ctx, "list nodes", func(ctx context.Context, admin api.AdminServiceClient) error {
+		res, err := admin.ListNodes(ctx, &api.ListNodesReq{})
+		if err != nil {
+			return err
+		}
+		nodes = make([]NodeStatus, 0, len(res.Nodes))
+		for _, node := range res.Nodes {
+			nodes = append(nodes, nodeStatus(node))
+		}
+		return nil
+	})
+	return nodes, err
+}
+
+func (a *AdminClient) NodeInfo(ctx context.Context, name string) (*NodeDetail, error) {
+	var detail *NodeDetail
+	err := a.call(ctx, "get node info", func(ctx context.Context, admin api.AdminServiceClient) error {
+		res, err := admin.NodeInfo(ctx, &api.NodeInfoReq{Name: name})
+		if err != nil {
+			return err
+		}
+		detail = &NodeDetail{
+			NodeStatus:  nodeStatus(res.Node),
+			ObjectNames: res.Objects,
+			Queued:      int(res.Queued),
+			Awaiting:    int(res.Awaiting),
+		}
+		return nil
+	})
+	return detail, err
+}
+
+func (a *AdminClient) ListGhosts(ctx context.Context) ([]Ghost, error) {
+	var ghosts []Ghost
+	err := a.call(ctx, "list ghosts", func(ctx context.Context, admin api.AdminServiceClient) error {
+		res, err := admin.ListGhosts(ctx, &api.ListGhostsReq{})
+		if err != nil {
+			return err
+		}
+		ghosts = make([]Ghost, 0, len(res.Ghosts))
+		for _, ghost := range res.Ghosts {
+			ghosts = append(ghosts, Ghost{Name: ghost.Name, Since: ghost.Since.AsTime()})
+		}
+		return nil
+	})
+	return ghosts, err
+}
+
+func (a *AdminClient) ObjectLocations(ctx context.Context, name string) (*ObjectLocation, error) {
+	var location *ObjectLocation
+	err := a.call(ctx, "locate object", func(ctx context.Context, admin api.AdminServiceClient) error {
+		res, err := admin.ObjectLocations(ctx, &api.ObjectLocationsReq{Name: name})
+		if err != nil {
+			return err
+		}
+		location = &ObjectLocation{
+			Name:        res.Name,
+			Replicas:    make([]Replica, 0, len(res.Replicas)),
+			Replication: int(res.Replication),
+		}
+		for _, replica := range res.Replicas {
+			location.Replicas = append(location.Replicas, Replica{Node: replica.Node, Leaser: replica.Leaser})
+		}
+		if res.Constraints != nil {
+			location.Constraints = &PlacementConstraints{Required: res.Constraints.Required, Preferred: res.Constraints.Preferred}
+		}
+		return nil
+	})
+	return location, err
+}
+
+func (a *AdminClient) ClusterSummary(ctx context.Context) (*ClusterSummary, error) {
+	var summary *ClusterSummary
+	err := a.call(ctx, "summarize cluster", func(ctx context.Context, admin api.AdminServiceClient) error {
+		res, err := admin.ClusterSummary(ctx, &api.ClusterSummaryReq{})
+		if err != nil {
+			return err
+		}
+		summary = &ClusterSummary{
+			ClusterId:       res.ClusterId,
+			Lamport:         namenode.Lamport(res.Lamport),
+			Nodes:           int(res.Nodes),
+			Ghosts:          int(res.Ghosts),
+			Objects:         int(res.Objects),
+			UnderReplicated: int(res.UnderReplicated),
+			Snapshots:       int(res.Snapshots),
+			Replication:     int(res.Replication),
+			Tolerance:       int(res.Tolerance),
+			Decommissioning: int(res.Decommissioning),
+			Maintenance:     int(res.Maintenance),
+			Rebalancing:     res.Rebalancing,
+		}
+		return nil
+	})
+	return summary, err
+}
+
+// Decommission stops placing objects on the node and starts copying its objects elsewhere
+// it returns right away, the node leaves the cluster once its drain completes. calling it for a node whose
+// drain left objects behind drains it again
+func (a *AdminClient) Decommission(ctx context.Context, name string) (NodeStatus, error) {
+	var node NodeStatus
+	err := a.call(ctx, "decommission node", func(ctx context.Context, admin api.AdminServiceClient) error {
+		res, err := admin.Decommission(ctx, &api.DecommissionReq{Name: name})
+		if err != nil {
+			return err
+		}
+		node = nodeStatus(res.Node)
+		return nil
+	})
+	return node, err
+}
+
+// StartMaintenance keeps objects off the node for window, during which it may go away without its objects
+// being re-replicated. a zero window uses the namenode's default, starting it again restarts the window
+func (a *AdminClient) StartMaintenance(ctx context.Context, name string, window time.Duration) (NodeStatus, error) {
+	var node NodeStatus
+	err := a.call(ctx, "start maintenance", func(ctx context.Context, admin api.AdminServiceClient) error {
+		req := &api.StartMaintenanceReq{Name: name}
+		if window > 0 {
+			req.Window = durationpb.New(window)
+		}
+		res, err := admin.StartMaintenance(ctx, req)
+		if err != nil {
+			return err
+		}
+		node = nodeStatus(res.Node)
+		return nil
+	})
+	return node, err
+}
+
+// EndMaintenance makes the node active again, a node that has not returned yet is treated as failed
+func (a *AdminClient) EndMaintenance(ctx context.Context, name string) (NodeStatus, error) {
+	var node NodeStatus
+	err := a.call(ctx, "end maintenance", func(ctx context.Context, admin api.AdminServiceClient) error {
+		res, err := admin.EndMaintenance(ctx, &api.EndMaintenanceReq{Name: name})
+		if err != nil {
+			return err
+		}
+		node = nodeStatus(res.Node)
+		return nil
+	})
+	return node, err
+}
+
+// Rebalance plans moves that even out the sizes of the datanodes and runs them in the background
+// with dryRun the plan is only returned
+func (a *AdminClient) Rebalance(ctx context.Context, dryRun bool) (*RebalancePlan, error) {
+	var plan *RebalancePlan
+	err := a.call(ctx, "rebalance", func(ctx context.Context, admin api.AdminServiceClient) error {
+		res, err := admin.Rebalance(ctx, &api.RebalanceReq{DryRun: dryRun})
+		if err != nil {
+			return err
+		}
+		plan = &RebalancePlan{
+			Moves: make([]ReplicaMove, 0, len(res.Moves)),
+			Mean:  res.Mean,
+		}
+		for _, move := range res.Moves {
+			plan.Moves = append(plan.Moves, ReplicaMove{Object: move.Object, From: move.From, To: move.To, Size: int(move.Size)})
+		}
+		return nil
+	})
+	return plan, err
+}
@@ -0,0 +1,237 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mrowaha/dos/api"
+	"github.com/mrowaha/dos/namenode"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeAdmin answers admin calls with the node it holds and records the maintenance windows asked for
+type fakeAdmin struct {
+	api.UnimplementedAdminServiceServer
+	lock    sync.Mutex
+	node    *api.NodeSummary
+	windows []time.Duration
+}
+
+func (f *fakeAdmin) ListNodes(ctx context.Context, req *api.ListNodesReq) (*api.ListNodesRes, error) {
+	return &api.ListNodesRes{Nodes: []*api.NodeSummary{f.node}}, nil
+}
+
+func (f *fakeAdmin) NodeInfo(ctx context.Context, req *api.NodeInfoReq) (*api.NodeInfoRes, error) {
+	return &api.NodeInfoRes{Node: f.node, Objects: []string{"a", "b"}, Queued: 2, Awaiting: 1}, nil
+}
+
+func (f *fakeAdmin) StartMaintenance(ctx context.Context, req *api.StartMaintenanceReq) (*api.StartMaintenanceRes, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.windows = append(f.windows, req.Window.AsDuration())
+	return &api.StartMaintenanceRes{Node: f.node}, nil
+}
+
+func (f *fakeAdmin) ObjectLocations(ctx context.Context, req *api.ObjectLocationsReq) (*api.ObjectLocationsRes, error) {
+	return &api.ObjectLocationsRes{
+		Name:        req.Name,
+		Replicas:    []*api.ObjectReplica{{Node: "ghost"}, {Node: "node-0", Leaser: "tcp://node-0:5555"}},
+		Replication: 2,
+		Constraints: &api.PlacementConstraints{Required: map[string]string{"disk": "ssd"}},
+	}, nil
+}
+
+// newFakeAdmin serves a fake admin service holding node and returns an admin client of it
+func newFakeAdmin(t *testing.T, node *api.NodeSummary) (*AdminClient, *fakeAdmin) {
+	t.Helper()
+	admin := &fakeAdmin{node: node}
+	server := grpc.NewServer()
+	api.RegisterAdminServiceServer(server, admin)
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///namenode",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewDosClient(conn, WithLogger(nil)).Admin(), admin
+}
+
+func TestNodeStatus(t *testing.T) {
+	joined := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		node *api.NodeSummary
+		want NodeStatus
+	}{
+		{
+			name: "active",
+			node: &api.NodeSummary{Name: "node-0", Size: 10, Objects: 3, Fence: 7, Joined: timestamppb.New(joined)},
+			want: NodeStatus{Name: "node-0", Size: 10, Objects: 3, Fence: 7, Joined: joined, State: "active"},
+		},
+		{
+			name: "draining",
+			node: &api.NodeSummary{
+				Name:   "node-0",
+				Zone:   "z1",
+				Labels: map[string]string{"disk": "ssd"},
+				Joined: timestamppb.New(joined),
+				State:  api.NodeSummary_DECOMMISSIONING,
+				Drain:  &api.DrainProgress{Total: 3, Moved: 1, Failed: 1, Error: "no target", Running: true},
+			},
+			want: NodeStatus{
+				Name:   "node-0",
+				Zone:   "z1",
+				Labels: map[string]string{"disk": "ssd"},
+				Joined: joined,
+				State:  "decommissioning",
+				Drain:  &DrainProgress{Total: 3, Moved: 1, Failed: 1, Error: "no target", Running: true},
+			},
+		},
+		{
+			name: "in maintenance",
+			node: &api.NodeSummary{
+				Name:        "node-0",
+				Joined:      timestamppb.New(joined),
+				State:       api.NodeSummary_MAINTENANCE,
+				Maintenance: &api.Maintenance{Until: timestamppb.New(joined.Add(time.Hour)), Away: true, Buffered: 4, Overflowed: true},
+			},
+			want: NodeStatus{
+				Name:        "node-0",
+				Joined:      joined,
+				State:       "maintenance",
+				Maintenance: &MaintenanceStatus{Until: joined.Add(time.Hour), Away: true, Buffered: 4, Overflowed: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nodeStatus(tt.node); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nodeStatus = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdminCalls(t *testing.T) {
+	node := &api.NodeSummary{Name: "node-0", Objects: 2, State: api.NodeSummary_MAINTENANCE}
+	admin, fake := newFakeAdmin(t, node)
+	ctx := context.Background()
+
+	nodes, err := admin.ListNodes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 || !reflect.DeepEqual(nodes[0], nodeStatus(node)) {
+		t.Errorf("nodes = %+v", nodes)
+	}
+
+	detail, err := admin.NodeInfo(ctx, "node-0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &NodeDetail{NodeStatus: nodeStatus(node), ObjectNames: []string{"a", "b"}, Queued: 2, Awaiting: 1}
+	if !reflect.DeepEqual(detail, want) {
+		t.Errorf("detail = %+v, want %+v", detail, want)
+	}
+
+	location, err := admin.ObjectLocations(ctx, "object")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantLocation := &ObjectLocation{
+		Name:        "object",
+		Replicas:    []Replica{{Node: "ghost"}, {Node: "node-0", Leaser: "tcp://node-0:5555"}},
+		Replication: 2,
+		Constraints: &PlacementConstraints{Required: map[string]string{"disk": "ssd"}},
+	}
+	if !reflect.DeepEqual(location, wantLocation) {
+		t.Errorf("location = %+v, want %+v", location, wantLocation)
+	}
+
+	// a zero window is left for the namenode to default
+	for _, window := range []time.Duration{0, time.Hour} {
+		if _, err := admin.StartMaintenance(ctx, "node-0", window); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !reflect.DeepEqual(fake.windows, []time.Duration{0, time.Hour}) {
+		t.Errorf("windows = %v", fake.windows)
+	}
+}
+
+func TestAdminErrors(t *testing.T) {
+	admin := newTestClient(t).Admin()
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		call   func() error
+		want   error
+		reason string
+	}{
+		{
+			name: "missing node",
+			call: func() error {
+				_, err := admin.NodeInfo(ctx, "missing")
+				return err
+			},
+			want:   ErrNotFound,
+			reason: namenode.ReasonNodeNotFound,
+		},
+		{
+			name: "missing object",
+			call: func() error {
+				_, err := admin.ObjectLocations(ctx, "missing")
+				return err
+			},
+			want:   ErrNotFound,
+			reason: namenode.ReasonObjectNotFound,
+		},
+		{
+			name: "decommissioning a missing node",
+			call: func() error {
+				_, err := admin.Decommission(ctx, "missing")
+				return err
+			},
+			want:   ErrNotFound,
+			reason: namenode.ReasonNodeNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			var e *Error
+			if !errors.Is(err, tt.want) || !errors.As(err, &e) || e.Reason != tt.reason {
+				t.Errorf("err = %v, want %v (%s)", err, tt.want, tt.reason)
+			}
+		})
+	}
+}
+
+func TestAdminClusterSummary(t *testing.T) {
+	c := newTestClient(t)
+	summary, err := c.Admin().ClusterSummary(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a cluster without datanodes or objects
+	if summary.ClusterId == "" || summary.Nodes != 0 || summary.Objects != 0 || summary.Replication == 0 {
+		t.Errorf("summary = %+v", summary)
+	}
+}
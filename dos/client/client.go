@@ -5,121 +5,306 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
-	"os"
-	"time"
 
 	api "github.com/mrowaha/dos/api"
-	"github.com/mrowaha/dos/datanode"
-	zmq "github.com/pebbe/zmq4"
+	"github.com/mrowaha/dos/namenode"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// DosClient is safe for concurrent use, every call is bounded by its context and the client's timeout
 type DosClient struct {
-	client api.NameServiceClient
-	logger *log.Logger
+	names   *namePool
+	leasers *leaserPool
+	logger  *log.Logger
+	config  *DosClientConfig
 }
 
-func NewDosClient(conn *grpc.ClientConn) *DosClient {
-	c := api.NewNameServiceClient(conn)
-	logger := log.New(os.Stdout, "[client]", log.Ltime)
+// NewDosClient makes calls over conn, which stays owned by the caller
+func NewDosClient(conn *grpc.ClientConn, opts ...ClientConfigFunc) *DosClient {
+	config := defaultDosClientConfig()
+	for _, fn := range opts {
+		fn(config)
+	}
+	return newDosClient(newNamePool([]*grpc.ClientConn{conn}, false), config)
+}
+
+// Dial opens a pool of connections to the namenode at target, Close closes them
+func Dial(target string, opts ...ClientConfigFunc) (*DosClient, error) {
+	config := defaultDosClientConfig()
+	for _, fn := range opts {
+		fn(config)
+	}
+	names, err := dialNamePool(target, config.poolSize, config.dialOptions)
+	if err != nil {
+		return nil, err
+	}
+	return newDosClient(names, config), nil
+}
+
+func newDosClient(names *namePool, config *DosClientConfig) *DosClient {
 	return &DosClient{
-		client: c,
-		logger: logger,
+		names:   names,
+		leasers: newLeaserPool(config),
+		logger:  config.logger,
+		config:  config,
 	}
 }
 
-func (c *DosClient) Create(name string, data []byte) error {
-	c.logger.Printf("creating object named %s", name)
+// Close ends every subscription and closes the connections the client dialed
+func (c *DosClient) Close() error {
+	c.leasers.close()
+	return c.names.close()
+}
 
-	ctx := context.Background()
-	_, err := c.client.CreateObject(ctx, &api.CreateObjectRequest{
-		Meta: &api.RequestMeta{Ts: timestamppb.Now()},
+// PlacementConstraints restrict the datanodes the replicas of an object are placed on by their labels
+type PlacementConstraints struct {
+	Required  map[string]string `json:"required,omitempty"`  // a node must carry every one of them
+	Preferred map[string]string `json:"preferred,omitempty"` // nodes carrying more of them are picked first
+}
+
+// CreateOptions are kept with an object by the namenode, the zero value keeps the cluster's defaults
+type CreateOptions struct {
+	Constraints PlacementConstraints
+	Replication int // zero for the cluster's replication
+}
+
+func (c *DosClient) Create(ctx context.Context, name string, data []byte) error {
+	return c.CreateWith(ctx, name, data, CreateOptions{})
+}
+
+// this function creates an object whose replicas are only placed as the constraints allow,
+// the namenode keeps them with the object for every later placement of its replicas
+func (c *DosClient) CreateWith(ctx context.Context, name string, data []byte, opts CreateOptions) error {
+	c.logger.Printf("creating object named %s", name)
+	if opts.Replication < 0 {
+		return &Error{Op: "create", Code: codes.InvalidArgument, Message: "replication must not be negative", kind: ErrInvalidArgument}
+	}
+	meta, err := newMeta("create")
+	if err != nil {
+		return err
+	}
+	req := &api.CreateObjectRequest{
+		Meta: meta,
 		Name: name,
 		Data: data,
-	})
-	if err != nil {
-		c.logger.Printf("failed to create object...\n %s", err.Error())
+		Constraints: &api.PlacementConstraints{
+			Required:  opts.Constraints.Required,
+			Preferred: opts.Constraints.Preferred,
+		},
+		Replication: uint32(opts.Replication),
 	}
-	return nil
+	return c.retry(ctx, func(ctx context.Context, names api.NameServiceClient) error {
+		_, err := names.CreateObject(ctx, req)
+		if err != nil {
+			c.logger.Printf("failed to create object...\n %s", err.Error())
+			return fromStatus("create", err)
+		}
+		return nil
+	})
 }
 
-func (c *DosClient) Delete(name string) error {
+func (c *DosClient) Delete(ctx context.Context, name string) error {
 	c.logger.Printf("deleting object named %s", name)
-	_, err := c.client.DeleteObject(context.TODO(), &api.DeleteObjectRequest{
+	meta, err := newMeta("delete")
+	if err != nil {
+		return err
+	}
+	req := &api.DeleteObjectRequest{
+		Meta: meta,
 		Name: name,
+	}
+	return c.retry(ctx, func(ctx context.Context, names api.NameServiceClient) error {
+		_, err := names.DeleteObject(ctx, req)
+		if err != nil {
+			c.logger.Printf("failed to delete object...\n%s", err.Error())
+			return fromStatus("delete", err)
+		}
+		return nil
 	})
+}
+
+// this function sets how many replicas the object keeps and returns how many it has now,
+// the namenode adds or removes replicas in the background until it has as many
+func (c *DosClient) SetReplication(ctx context.Context, name string, replication int) (int, error) {
+	c.logger.Printf("setting replication of %s to %d", name, replication)
+	if replication < 1 {
+		return 0, &Error{Op: "set replication", Code: codes.InvalidArgument, Message: "replication must be at least one", kind: ErrInvalidArgument}
+	}
+	meta, err := newMeta("set replication")
 	if err != nil {
-		c.logger.Printf("failed to delete object...\n%s", err.Error())
+		return 0, err
+	}
+	req := &api.SetReplicationReq{
+		Meta:        meta,
+		Name:        name,
+		Replication: uint32(replication),
 	}
-	return nil
+	var replicas int
+	err = c.retry(ctx, func(ctx context.Context, names api.NameServiceClient) error {
+		res, err := names.SetReplication(ctx, req)
+		if err != nil {
+			c.logger.Printf("failed to set replication...\n%s", err.Error())
+			return fromStatus("set replication", err)
+		}
+		replicas = int(res.Replicas)
+		return nil
+	})
+	return replicas, err
 }
 
-func (c *DosClient) Update(name string, data []byte) error {
+func (c *DosClient) Update(ctx context.Context, name string, data []byte) error {
 	c.logger.Printf("updating object named %s", name)
-	_, err := c.client.UpdateObject(context.TODO(), &api.UpdateObjectReq{
+	meta, err := newMeta("update")
+	if err != nil {
+		return err
+	}
+	req := &api.UpdateObjectReq{
+		Meta: meta,
 		Name: name,
 		Data: data,
-	})
-	if err != nil {
-		c.logger.Printf("failed to update object...\n%s", err.Error())
 	}
-	return nil
+	return c.retry(ctx, func(ctx context.Context, names api.NameServiceClient) error {
+		_, err := names.UpdateObject(ctx, req)
+		if err != nil {
+			c.logger.Printf("failed to update object...\n%s", err.Error())
+			return fromStatus("update", err)
+		}
+		return nil
+	})
 }
 
-// this function subscribes to an object name from a randomly selected datanode
-func (c *DosClient) Lease(name string) error {
-	c.logger.Printf("leasing object named %s", name)
-	res, err := c.client.LeaseObject(context.TODO(), &api.LeaseObjectReq{
-		Name: name,
+// this function reads an object. if snapshot is not empty the object is read as of that snapshot
+func (c *DosClient) Get(ctx context.Context, name string, snapshot string) ([]byte, error) {
+	c.logger.Printf("reading object named %s", name)
+	var data []byte
+	err := c.retry(ctx, func(ctx context.Context, names api.NameServiceClient) error {
+		res, err := names.GetObject(ctx, &api.GetObjectReq{
+			Meta:     &api.RequestMeta{Ts: timestamppb.Now()},
+			Name:     name,
+			Snapshot: snapshot,
+		})
+		if err != nil {
+			c.logger.Printf("failed to read object...\n%s", err.Error())
+			return fromStatus("get", err)
+		}
+		data = res.Data
+		return nil
 	})
-	if err != nil {
-		c.logger.Printf("failed to lease object...\n%s\n", err.Error())
-	}
-
-	c.logger.Printf("lease options: %v\n", res)
-
-	rand.Seed(time.Now().UnixNano())
-	randomIndex := rand.Intn(len(res.Leasers))
-	c.subscribe(name, res.Leasers[randomIndex])
-	return nil
+	return data, err
 }
 
-func (c *DosClient) subscribe(object string, leaseAddr string) error {
-	sub, err := zmq.NewSocket(zmq.SUB)
+// CreateSnapshot returns the lamport the snapshot was taken at
+func (c *DosClient) CreateSnapshot(ctx context.Context, name string) (namenode.Lamport, error) {
+	c.logger.Printf("creating snapshot named %s", name)
+	meta, err := newMeta("create snapshot")
 	if err != nil {
-		panic(err)
+		return 0, err
 	}
-	defer sub.Close()
-
-	fmt.Println(leaseAddr)
-	err = sub.Connect(leaseAddr)
-	if err != nil {
-		log.Fatalf("Failed to connect: %v", err)
+	req := &api.CreateSnapshotReq{
+		Meta: meta,
+		Name: name,
 	}
+	var lamport namenode.Lamport
+	err = c.retry(ctx, func(ctx context.Context, names api.NameServiceClient) error {
+		res, err := names.CreateSnapshot(ctx, req)
+		if err != nil {
+			c.logger.Printf("failed to create snapshot...\n%s", err.Error())
+			return fromStatus("create snapshot", err)
+		}
+		lamport = namenode.Lamport(res.Lamport)
+		c.logger.Printf("snapshot %s taken @lamport%s", name, lamport)
+		return nil
+	})
+	return lamport, err
+}
 
-	// topics for update and delete
-	updateTopic := fmt.Sprintf("%s%s", object, datanode.LEASE_UPDATE)
-	deleteTopic := fmt.Sprintf("%s%s", object, datanode.LEASE_DELETE)
-
-	err = sub.SetSubscribe(updateTopic)
+func (c *DosClient) DeleteSnapshot(ctx context.Context, name string) error {
+	c.logger.Printf("deleting snapshot named %s", name)
+	meta, err := newMeta("delete snapshot")
 	if err != nil {
-		log.Fatalf("failed to sub topic %s...\n%s\n", updateTopic, err.Error())
+		return err
 	}
-
-	err = sub.SetSubscribe(deleteTopic)
-	if err != nil {
-		log.Fatalf("failed to sub topic %s...\n%s\n", deleteTopic, err.Error())
+	req := &api.DeleteSnapshotReq{
+		Meta: meta,
+		Name: name,
 	}
+	return c.retry(ctx, func(ctx context.Context, names api.NameServiceClient) error {
+		_, err := names.DeleteSnapshot(ctx, req)
+		if err != nil {
+			c.logger.Printf("failed to delete snapshot...\n%s", err.Error())
+			return fromStatus("delete snapshot", err)
+		}
+		return nil
+	})
+}
 
-	c.logger.Printf("listening to %s and %s", updateTopic, deleteTopic)
+// List returns the sorted names of the objects starting with prefix
+func (c *DosClient) List(ctx context.Context, prefix string) ([]string, error) {
+	c.logger.Printf("listing objects with prefix %s", prefix)
+	var listed []string
+	err := c.retry(ctx, func(ctx context.Context, names api.NameServiceClient) error {
+		res, err := names.ListObjects(ctx, &api.ListObjectsReq{
+			Meta:   &api.RequestMeta{Ts: timestamppb.Now()},
+			Prefix: prefix,
+		})
+		if err != nil {
+			c.logger.Printf("failed to list objects...\n%s", err.Error())
+			return fromStatus("list", err)
+		}
+		listed = res.Names
+		return nil
+	})
+	return listed, err
+}
 
-	for {
-		msg, err := sub.RecvMessage(0)
+// Subscribe leases name from a randomly selected datanode and streams its publications without blocking
+// the channel is closed once ctx is done or cancel is called, a subscription that ends on its own
+// delivers an event carrying the reason first. cancel is safe to call more than once
+func (c *DosClient) Subscribe(ctx context.Context, name string) (<-chan Event, func(), error) {
+	c.logger.Printf("leasing object named %s", name)
+	var res *api.LeaseObjectRes
+	err := c.retry(ctx, func(ctx context.Context, names api.NameServiceClient) error {
+		var err error
+		res, err = names.LeaseObject(ctx, &api.LeaseObjectReq{
+			Name: name,
+		})
 		if err != nil {
-			c.logger.Fatalf("failed to recv from leaser...\n%s\n", err.Error())
+			c.logger.Printf("failed to lease object...\n%s\n", err.Error())
+			return fromStatus("lease", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.logger.Printf("lease options: %v\n", res)
+	if len(res.Leasers) == 0 || len(res.Fences) != len(res.Leasers) {
+		return nil, nil, &Error{
+			Op:      "lease",
+			Code:    codes.Unavailable,
+			Message: fmt.Sprintf("no datanode serves %s", name),
+			kind:    ErrUnavailable,
 		}
-		c.logger.Printf("recv message %s\n", msg)
 	}
 
+	randomIndex := rand.Intn(len(res.Leasers))
+	conn, err := c.leasers.acquire(res.Leasers[randomIndex])
+	if err != nil {
+		return nil, nil, err
+	}
+	sub := &subscription{
+		object:    name,
+		fence:     res.Fences[randomIndex],
+		events:    make(chan Event, c.config.eventBuffer),
+		conn:      conn,
+		ended:     make(chan struct{}),
+		cancelled: make(chan struct{}),
+	}
+	conn.add(sub)
+	go sub.watch(ctx)
+	c.logger.Printf("subscribed to %s on leaser %s", name, conn.addr)
+	return sub.events, sub.cancel, nil
 }
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// RetryPolicy retries calls that failed in a way another attempt may fix
+// mutations carry an idempotency key, so retrying them is safe as well
+type RetryPolicy struct {
+	Attempts   int // attempts in total, 1 never retries
+	BackoffMin time.Duration
+	BackoffMax time.Duration
+}
+
+type DosClientConfig struct {
+	retry       RetryPolicy
+	timeout     time.Duration // bounds every attempt of a namenode call, zero leaves it to the caller's context
+	logger      *log.Logger
+	poolSize    int // connections Dial opens to the namenode
+	dialOptions []grpc.DialOption
+	eventBuffer int // events a subscription holds before the subscriber counts as too slow
+}
+
+type ClientConfigFunc func(*DosClientConfig)
+
+func defaultDosClientConfig() *DosClientConfig {
+	return &DosClientConfig{
+		retry: RetryPolicy{
+			Attempts:   1,
+			BackoffMin: 100 * time.Millisecond,
+			BackoffMax: 5 * time.Second,
+		},
+		timeout:     10 * time.Second,
+		logger:      log.New(os.Stdout, "[client]", log.Ltime),
+		poolSize:    2,
+		dialOptions: []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())},
+		eventBuffer: 64,
+	}
+}
+
+func WithRetryPolicy(policy RetryPolicy) ClientConfigFunc {
+	return func(cfg *DosClientConfig) {
+		if policy.Attempts < 1 || policy.BackoffMin <= 0 || policy.BackoffMax < policy.BackoffMin {
+			log.Fatalln("client config error: retry policy requires attempts >= 1 and 0 < min <= max")
+		}
+		cfg.retry = policy
+	}
+}
+
+// WithTimeout bounds every attempt of a namenode call, a zero timeout only uses the caller's context
+func WithTimeout(timeout time.Duration) ClientConfigFunc {
+	return func(cfg *DosClientConfig) {
+		if timeout < 0 {
+			log.Fatalln("client config error: timeout can not be negative")
+		}
+		cfg.timeout = timeout
+	}
+}
+
+// WithLogger replaces the stdout logger, a nil logger silences the client
+func WithLogger(logger *log.Logger) ClientConfigFunc {
+	return func(cfg *DosClientConfig) {
+		if logger == nil {
+			logger = log.New(io.Discard, "", 0)
+		}
+		cfg.logger = logger
+	}
+}
+
+// WithPoolSize sets how many connections Dial opens to the namenode, calls are spread over them
+func WithPoolSize(size int) ClientConfigFunc {
+	return func(cfg *DosClientConfig) {
+		if size < 1 {
+			log.Fatalln("client config error: pool size must be at least 1")
+		}
+		cfg.poolSize = size
+	}
+}
+
+// WithDialOptions replaces the options Dial connects to the namenode with, by default insecure credentials
+func WithDialOptions(opts ...grpc.DialOption) ClientConfigFunc {
+	return func(cfg *DosClientConfig) {
+		cfg.dialOptions = opts
+	}
+}
+
+// WithEventBuffer sets how many events a subscription buffers for its reader
+func WithEventBuffer(size int) ClientConfigFunc {
+	return func(cfg *DosClientConfig) {
+		if size < 1 {
+			log.Fatalln("client config error: event buffer must be at least 1")
+		}
+		cfg.eventBuffer = size
+	}
+}
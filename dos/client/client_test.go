@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+func TestConcurrentCalls(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 40)
+	for range 20 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Get(ctx, "missing", ""); !errors.Is(err, ErrNotFound) {
+				errs <- err
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := c.List(ctx, ""); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestCallContext(t *testing.T) {
+	c := newTestClient(t, WithRetryPolicy(RetryPolicy{Attempts: 3, BackoffMin: time.Millisecond, BackoffMax: time.Millisecond}))
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{name: "cancelled", ctx: cancelled, want: context.Canceled},
+		{name: "past its deadline", ctx: expired, want: context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := c.Get(tt.ctx, "missing", ""); !errors.Is(err, tt.want) {
+				t.Errorf("get: %v, want %v", err, tt.want)
+			}
+			if err := c.Create(tt.ctx, "object", []byte("data")); !errors.Is(err, tt.want) {
+				t.Errorf("create: %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNamePoolSpreadsCalls(t *testing.T) {
+	conns := make([]*grpc.ClientConn, 0, 3)
+	for range 3 {
+		// nothing is dialed until a call is made
+		conn, err := grpc.NewClient("passthrough:///namenode", defaultDosClientConfig().dialOptions...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		conns = append(conns, conn)
+	}
+	pool := newNamePool(conns, false)
+
+	picked := make(map[grpc.ClientConnInterface]int)
+	for range 30 {
+		picked[pool.pick()]++
+	}
+	for i, conn := range conns {
+		if picked[conn] != 10 {
+			t.Errorf("connection %d picked %d times out of 30", i, picked[conn])
+		}
+	}
+	// the caller owns the connections it handed over
+	if err := pool.close(); err != nil {
+		t.Fatal(err)
+	}
+	if state := conns[0].GetState(); state == connectivity.Shutdown {
+		t.Error("connection was closed by the pool")
+	}
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mrowaha/dos/namenode"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+/**
+	calls to the namenode fail with an *Error, which matches one of the sentinels below with errors.Is
+	failures talking to a leaser match ErrUnavailable
+	the reason reported by the namenode tells failures of the same kind apart, for example whether
+	an object or a snapshot was not found
+**/
+
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrAlreadyExists   = errors.New("already exists")
+	ErrUnavailable     = errors.New("unavailable")
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrInternal        = errors.New("internal namenode error")
+)
+
+type Error struct {
+	Op       string // client call that failed
+	Code     codes.Code
+	Reason   string            // reason of the namenode's ErrorInfo, empty if it sent none
+	Metadata map[string]string // metadata of the namenode's ErrorInfo
+	Message  string
+	kind     error
+}
+
+func (e *Error) Error() string {
+	if len(e.Reason) != 0 {
+		return fmt.Sprintf("%s: %s (%s)", e.Op, e.Message, e.Reason)
+	}
+	return fmt.Sprintf("%s: %s", e.Op, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.kind
+}
+
+// fromStatus converts the error of a namenode call into an *Error
+func fromStatus(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	st := status.Convert(err)
+	e := &Error{
+		Op:      op,
+		Code:    st.Code(),
+		Message: st.Message(),
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.Domain == namenode.ErrorDomain {
+			e.Reason = info.Reason
+			e.Metadata = info.Metadata
+		}
+	}
+
+	switch st.Code() {
+	case codes.NotFound:
+		e.kind = ErrNotFound
+	case codes.AlreadyExists:
+		e.kind = ErrAlreadyExists
+	case codes.Unavailable, codes.ResourceExhausted:
+		e.kind = ErrUnavailable
+	case codes.InvalidArgument, codes.FailedPrecondition:
+		e.kind = ErrInvalidArgument
+	case codes.Canceled:
+		e.kind = context.Canceled
+	case codes.DeadlineExceeded:
+		e.kind = context.DeadlineExceeded
+	default:
+		e.kind = ErrInternal
+	}
+	return e
+}
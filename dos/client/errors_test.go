@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mrowaha/dos/namenode"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFromStatus(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		want error
+	}{
+		{code: codes.NotFound, want: ErrNotFound},
+		{code: codes.AlreadyExists, want: ErrAlreadyExists},
+		{code: codes.Unavailable, want: ErrUnavailable},
+		{code: codes.ResourceExhausted, want: ErrUnavailable},
+		{code: codes.InvalidArgument, want: ErrInvalidArgument},
+		{code: codes.FailedPrecondition, want: ErrInvalidArgument},
+		{code: codes.Canceled, want: context.Canceled},
+		{code: codes.DeadlineExceeded, want: context.DeadlineExceeded},
+		{code: codes.Internal, want: ErrInternal},
+		{code: codes.PermissionDenied, want: ErrInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			err := fromStatus("get", status.Error(tt.code, "failed"))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+			var e *Error
+			if !errors.As(err, &e) || e.Op != "get" || e.Code != tt.code || e.Message != "failed" || len(e.Reason) != 0 {
+				t.Errorf("error = %#v", e)
+			}
+		})
+	}
+	if err := fromStatus("get", nil); err != nil {
+		t.Errorf("fromStatus(nil) = %v", err)
+	}
+}
+
+func TestFromStatusDetail(t *testing.T) {
+	tests := []struct {
+		name   string
+		domain string
+		reason string // kept by the error
+	}{
+		{name: "namenode detail", domain: namenode.ErrorDomain, reason: namenode.ReasonSnapshotNotFound},
+		{name: "detail of another service", domain: "elsewhere"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, err := status.New(codes.NotFound, "missing").WithDetails(&errdetails.ErrorInfo{
+				Domain:   tt.domain,
+				Reason:   namenode.ReasonSnapshotNotFound,
+				Metadata: map[string]string{"name": "nightly"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var e *Error
+			if !errors.As(fromStatus("get", st.Err()), &e) {
+				t.Fatal("not an *Error")
+			}
+			if e.Reason != tt.reason || (len(tt.reason) != 0) != (e.Metadata["name"] == "nightly") {
+				t.Errorf("reason = %q, metadata = %v, want %q", e.Reason, e.Metadata, tt.reason)
+			}
+		})
+	}
+}
+
+// the errors of a namenode reach the caller with their kind and reason
+func TestNameNodeErrors(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		call   func() error
+		want   error
+		reason string
+	}{
+		{
+			name: "missing object",
+			call: func() error {
+				_, err := c.Get(ctx, "missing", "")
+				return err
+			},
+			want:   ErrNotFound,
+			reason: namenode.ReasonObjectNotFound,
+		},
+		{
+			name:   "missing snapshot",
+			call:   func() error { return c.DeleteSnapshot(ctx, "missing") },
+			want:   ErrNotFound,
+			reason: namenode.ReasonSnapshotNotFound,
+		},
+		{
+			name:   "no datanodes",
+			call:   func() error { return c.Create(ctx, "object", []byte("data")) },
+			want:   ErrUnavailable,
+			reason: namenode.ReasonNotEnoughDataNodes,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			var e *Error
+			if !errors.Is(err, tt.want) || !errors.As(err, &e) || e.Reason != tt.reason {
+				t.Errorf("err = %v, want %v (%s)", err, tt.want, tt.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mrowaha/dos/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeNames is a name service keeping objects in memory, without datanodes or a namespace log
+type fakeNames struct {
+	api.UnimplementedNameServiceServer
+	lock    sync.Mutex
+	objects map[string][]byte
+	lists   []string // prefixes of the listings asked for
+}
+
+func (f *fakeNames) CreateObject(ctx context.Context, req *api.CreateObjectRequest) (*api.CreateObjectResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if _, ok := f.objects[req.Name]; ok {
+		return nil, status.Error(codes.AlreadyExists, "object already exists")
+	}
+	f.objects[req.Name] = req.Data
+	return &api.CreateObjectResponse{Meta: &api.ResponseMeta{Status: api.ResponseMeta_CREATED}}, nil
+}
+
+func (f *fakeNames) GetObject(ctx context.Context, req *api.GetObjectReq) (*api.GetObjectRes, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	data, ok := f.objects[req.Name]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "object does not exist")
+	}
+	return &api.GetObjectRes{Meta: &api.ResponseMeta{Status: api.ResponseMeta_READ}, Data: data}, nil
+}
+
+func (f *fakeNames) ListObjects(ctx context.Context, req *api.ListObjectsReq) (*api.ListObjectsRes, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.lists = append(f.lists, req.Prefix)
+	names := make([]string, 0)
+	for name := range f.objects {
+		if strings.HasPrefix(name, req.Prefix) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return &api.ListObjectsRes{Meta: &api.ResponseMeta{Status: api.ResponseMeta_LISTED}, Names: names}, nil
+}
+
+// newFakeClient serves a fake name service holding objects and returns a client of it
+func newFakeClient(t *testing.T, objects ...string) (*DosClient, *fakeNames) {
+	t.Helper()
+	names := &fakeNames{objects: make(map[string][]byte)}
+	for _, object := range objects {
+		names.objects[object] = []byte(object)
+	}
+	server := grpc.NewServer()
+	api.RegisterNameServiceServer(server, names)
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///namenode",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewDosClient(conn, WithLogger(nil)), names
+}
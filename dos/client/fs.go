@@ -0,0 +1,256 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+/**
+	DosFS presents the store as a read only file system
+	object names are slash separated paths and a directory is every name under "<dir>/", so
+	directories exist as long as an object lives under them and are never created on their own
+	a name that is an object and also has objects under it is listed as a directory
+**/
+
+type DosFS struct {
+	client *DosClient
+	ctx    context.Context // bounds every call the file system makes
+}
+
+// FS returns the store as an fs.FS, it also implements fs.ReadDirFS, fs.ReadFileFS and fs.StatFS
+func (c *DosClient) FS(ctx context.Context) *DosFS {
+	return &DosFS{
+		client: c,
+		ctx:    ctx,
+	}
+}
+
+func (fsys *DosFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return &dosDir{fsys: fsys, name: name}, nil
+	}
+
+	data, err := fsys.client.Get(fsys.ctx, name, "")
+	if err == nil {
+		return &dosFile{
+			Reader: bytes.NewReader(data),
+			info:   fileInfo{name: path.Base(name), size: int64(len(data))},
+		}, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return nil, pathError("open", name, err)
+	}
+	isDir, err := fsys.isDir(name)
+	if err != nil {
+		return nil, pathError("open", name, err)
+	}
+	if !isDir {
+		return nil, pathError("open", name, ErrNotFound)
+	}
+	return &dosDir{fsys: fsys, name: name}, nil
+}
+
+func (fsys *DosFS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrInvalid}
+	}
+	data, err := fsys.client.Get(fsys.ctx, name, "")
+	if err != nil {
+		return nil, pathError("readfile", name, err)
+	}
+	return data, nil
+}
+
+func (fsys *DosFS) Stat(name string) (fs.FileInfo, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, pathError("stat", name, errors.Unwrap(err))
+	}
+	defer f.Close()
+	return f.Stat()
+}
+
+// ReadDir lists the objects and directories right under name, sorted by name
+func (fsys *DosFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	prefix := dirPrefix(name)
+	names, err := fsys.client.List(fsys.ctx, prefix)
+	if err != nil {
+		return nil, pathError("readdir", name, err)
+	}
+	if len(names) == 0 && name != "." {
+		return nil, pathError("readdir", name, ErrNotFound)
+	}
+
+	entries := make([]fs.DirEntry, 0, len(names))
+	seen := make(map[string]int)
+	for _, object := range names {
+		elem, _, nested := strings.Cut(strings.TrimPrefix(object, prefix), "/")
+		if !fs.ValidPath(prefix + elem) {
+			// empty elements, as in "a//b", have no path in the file system
+			continue
+		}
+		if i, ok := seen[elem]; ok {
+			if nested {
+				entries[i] = &dirEntry{fsys: fsys, path: prefix + elem, dir: true}
+			}
+			continue
+		}
+		seen[elem] = len(entries)
+		entries = append(entries, &dirEntry{fsys: fsys, path: prefix + elem, dir: nested})
+	}
+	// "a-b" sorts before "a/b", so the listing is sorted again by entry name
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries, nil
+}
+
+func (fsys *DosFS) isDir(name string) (bool, error) {
+	names, err := fsys.client.List(fsys.ctx, dirPrefix(name))
+	if err != nil {
+		return false, err
+	}
+	return len(names) != 0, nil
+}
+
+func dirPrefix(name string) string {
+	if name == "." {
+		return ""
+	}
+	return name + "/"
+}
+
+// pathError wraps err for the file system, a missing object is fs.ErrNotExist
+func pathError(op string, name string, err error) error {
+	if errors.Is(err, ErrNotFound) {
+		err = fs.ErrNotExist
+	}
+	return &fs.PathError{Op: op, Path: name, Err: err}
+}
+
+type fileInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (fi fileInfo) Name() string       { return fi.name }
+func (fi fileInfo) Size() int64        { return fi.size }
+func (fi fileInfo) ModTime() time.Time { return time.Time{} } // objects carry no modification time
+func (fi fileInfo) IsDir() bool        { return fi.dir }
+func (fi fileInfo) Sys() any           { return nil }
+
+func (fi fileInfo) Mode() fs.FileMode {
+	if fi.dir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+type dirEntry struct {
+	fsys *DosFS
+	path string
+	dir  bool
+}
+
+func (e *dirEntry) Name() string { return path.Base(e.path) }
+func (e *dirEntry) IsDir() bool  { return e.dir }
+
+func (e *dirEntry) Type() fs.FileMode {
+	if e.dir {
+		return fs.ModeDir
+	}
+	return 0
+}
+
+// Info of an object reads it, listings do not carry sizes
+func (e *dirEntry) Info() (fs.FileInfo, error) {
+	if e.dir {
+		return fileInfo{name: e.Name(), dir: true}, nil
+	}
+	return e.fsys.Stat(e.path)
+}
+
+type dosFile struct {
+	*bytes.Reader
+	info   fileInfo
+	closed bool
+}
+
+func (f *dosFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *dosFile) Close() error {
+	if f.closed {
+		return fs.ErrClosed
+	}
+	f.closed = true
+	f.Reader = bytes.NewReader(nil)
+	return nil
+}
+
+type dosDir struct {
+	fsys    *DosFS
+	name    string
+	entries []fs.DirEntry
+	loaded  bool
+	closed  bool
+}
+
+func (d *dosDir) Stat() (fs.FileInfo, error) {
+	return fileInfo{name: path.Base(d.name), dir: true}, nil
+}
+
+func (d *dosDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: fs.ErrInvalid}
+}
+
+func (d *dosDir) Close() error {
+	if d.closed {
+		return fs.ErrClosed
+	}
+	d.closed = true
+	return nil
+}
+
+// ReadDir lists the directory on the first call and hands out the listing over later calls
+func (d *dosDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if d.closed {
+		return nil, &fs.PathError{Op: "readdir", Path: d.name, Err: fs.ErrClosed}
+	}
+	if !d.loaded {
+		entries, err := d.fsys.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries = entries
+		d.loaded = true
+	}
+
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(d.entries))
+	entries := d.entries[:n:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFS(t *testing.T) {
+	c, _ := newFakeClient(t, "a.txt", "dir/b.txt", "dir/sub/c.txt", "dir/sub/d.txt", "e-f")
+	if err := fstest.TestFS(c.FS(context.Background()), "a.txt", "dir/b.txt", "dir/sub/c.txt", "dir/sub/d.txt", "e-f"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFSReadDir(t *testing.T) {
+	c, _ := newFakeClient(t, "a", "a/b", "a-b", "c//d", "dir/x", "dir/y/z")
+	fsys := c.FS(context.Background())
+	tests := []struct {
+		dir  string
+		want []string // directories end in a slash
+		err  error
+	}{
+		// an object that has objects under it is listed as a directory, "a-b" sorts before "a/b" in the store
+		{dir: ".", want: []string{"a/", "a-b", "c/", "dir/"}},
+		{dir: "dir", want: []string{"x", "y/"}},
+		{dir: "dir/y", want: []string{"z"}},
+		// empty path elements have no path in the file system
+		{dir: "c", want: []string{}},
+		{dir: "missing", err: fs.ErrNotExist},
+		{dir: "/abs", err: fs.ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			entries, err := fsys.ReadDir(tt.dir)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+			got := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				name := entry.Name()
+				if entry.IsDir() {
+					name += "/"
+				}
+				got = append(got, name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("entries = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFSOpen(t *testing.T) {
+	c, _ := newFakeClient(t, "a", "a/b", "dir/x")
+	fsys := c.FS(context.Background())
+	tests := []struct {
+		name string
+		dir  bool
+		size int64
+		err  error
+	}{
+		{name: "a", size: 1},
+		{name: "dir", dir: true},
+		{name: ".", dir: true},
+		{name: "missing", err: fs.ErrNotExist},
+		{name: "dir/", err: fs.ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := fs.Stat(fsys, tt.name)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+			if info.IsDir() != tt.dir || info.Size() != tt.size {
+				t.Errorf("dir = %v size = %d, want %v %d", info.IsDir(), info.Size(), tt.dir, tt.size)
+			}
+		})
+	}
+
+	f, err := fsys.Open("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); !errors.Is(err, fs.ErrClosed) {
+		t.Errorf("second close = %v, want %v", err, fs.ErrClosed)
+	}
+	if _, err := f.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("read after close = %v, want %v", err, io.EOF)
+	}
+}
+
+func TestFSDirPaging(t *testing.T) {
+	c, names := newFakeClient(t, "dir/a", "dir/b", "dir/c")
+	f, err := c.FS(context.Background()).Open("dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := f.(fs.ReadDirFile)
+	got := make([]string, 0)
+	for {
+		entries, err := dir.ReadDir(2)
+		for _, entry := range entries {
+			got = append(got, entry.Name())
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("entries = %v", got)
+	}
+	// opening checks the directory exists, the pages come from a single listing after that
+	if want := []string{"dir/", "dir/"}; !slices.Equal(names.lists, want) {
+		t.Errorf("listed %v, want %v", names.lists, want)
+	}
+	dir.Close()
+	if _, err := dir.ReadDir(1); !errors.Is(err, fs.ErrClosed) {
+		t.Errorf("read dir after close = %v, want %v", err, fs.ErrClosed)
+	}
+}
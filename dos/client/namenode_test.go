@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrowaha/dos/namenode"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient starts a namenode without datanodes in process and returns a client of it
+func newTestClient(t *testing.T, opts ...ClientConfigFunc) *DosClient {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ns"), nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	s, err := namenode.NewDosNameNodeServer(
+		filepath.Join(dir, "log"),
+		filepath.Join(dir, "ns"),
+		namenode.WithEpochFile(filepath.Join(dir, "epoch")),
+		namenode.WithClusterFile(filepath.Join(dir, "cluster")),
+		namenode.WithSnapshotFile(filepath.Join(dir, "snapshots")),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the listener is never closed, the namenode treats a failing listener as fatal
+	listener := net.Listener(bufconn.Listen(1 << 20))
+	go s.Serve(&listener)
+
+	conn, err := grpc.NewClient("passthrough:///namenode",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.(*bufconn.Listener).DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewDosClient(conn, append([]ClientConfigFunc{WithLogger(nil)}, opts...)...)
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"sync"
+)
+
+/**
+	readers and writers let code written against io handle objects
+	objects travel whole over the namenode, so a reader holds the object it read on open
+	and a writer holds what was written until Close creates the object from it
+**/
+
+type objectReader struct {
+	*bytes.Reader
+	closed bool
+}
+
+// OpenReader reads the object once and serves reads and seeks from that copy
+func (c *DosClient) OpenReader(ctx context.Context, name string) (io.ReadSeekCloser, error) {
+	data, err := c.Get(ctx, name, "")
+	if err != nil {
+		return nil, err
+	}
+	return &objectReader{Reader: bytes.NewReader(data)}, nil
+}
+
+func (r *objectReader) Close() error {
+	if r.closed {
+		return fs.ErrClosed
+	}
+	r.closed = true
+	r.Reader = bytes.NewReader(nil)
+	return nil
+}
+
+type objectWriter struct {
+	client *DosClient
+	ctx    context.Context
+	name   string
+
+	lock   sync.Mutex
+	buf    bytes.Buffer
+	closed bool
+}
+
+// OpenWriter buffers writes, Close creates the object from them under ctx
+// nothing is stored if Close is never called or fails
+func (c *DosClient) OpenWriter(ctx context.Context, name string) io.WriteCloser {
+	return &objectWriter{
+		client: c,
+		ctx:    ctx,
+		name:   name,
+	}
+}
+
+func (w *objectWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.closed {
+		return 0, fs.ErrClosed
+	}
+	return w.buf.Write(p)
+}
+
+func (w *objectWriter) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.closed {
+		return fs.ErrClosed
+	}
+	w.closed = true
+	data := w.buf.Bytes()
+	w.buf = bytes.Buffer{}
+	return w.client.Create(w.ctx, w.name, data)
+}
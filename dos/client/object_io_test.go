@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"testing"
+)
+
+func TestObjectReadWrite(t *testing.T) {
+	c, _ := newFakeClient(t)
+	ctx := context.Background()
+
+	w := c.OpenWriter(ctx, "object")
+	for _, part := range []string{"hello", " ", "world"} {
+		if _, err := io.WriteString(w, part); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// nothing is stored before the writer is closed
+	if _, err := c.Get(ctx, "object", ""); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("get before close = %v, want %v", err, ErrNotFound)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("more")); !errors.Is(err, fs.ErrClosed) {
+		t.Errorf("write after close = %v, want %v", err, fs.ErrClosed)
+	}
+	if err := w.Close(); !errors.Is(err, fs.ErrClosed) {
+		t.Errorf("second close = %v, want %v", err, fs.ErrClosed)
+	}
+
+	r, err := c.OpenReader(ctx, "object")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil || string(data) != "hello world" {
+		t.Fatalf("read %q, %v", data, err)
+	}
+	if _, err := r.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := io.ReadAll(r); string(data) != "world" {
+		t.Errorf("read %q after seeking", data)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Close(); !errors.Is(err, fs.ErrClosed) {
+		t.Errorf("second close = %v, want %v", err, fs.ErrClosed)
+	}
+}
+
+func TestObjectWriterFails(t *testing.T) {
+	c, _ := newFakeClient(t, "taken")
+	// the object is created on close, so that is where it fails
+	w := c.OpenWriter(context.Background(), "taken")
+	io.WriteString(w, "data")
+	if err := w.Close(); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("close = %v, want %v", err, ErrAlreadyExists)
+	}
+
+	if _, err := c.OpenReader(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("open reader = %v, want %v", err, ErrNotFound)
+	}
+}
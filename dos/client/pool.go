@@ -0,0 +1,137 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+)
+
+/**
+	the client keeps its connections in two pools
+	the name pool spreads namenode calls round robin over a fixed set of grpc connections
+	the leaser pool holds one sub socket per datanode, shared by every subscription to that datanode
+	and closed once the last of them ends
+**/
+
+type namePool struct {
+	conns []grpc.ClientConnInterface
+	owned []*grpc.ClientConn // connections the client dialed itself and closes on Close
+	next  atomic.Uint64
+}
+
+func newNamePool(conns []*grpc.ClientConn, owned bool) *namePool {
+	pool := &namePool{
+		conns: make([]grpc.ClientConnInterface, 0, len(conns)),
+	}
+	for _, conn := range conns {
+		pool.conns = append(pool.conns, conn)
+	}
+	if owned {
+		pool.owned = conns
+	}
+	return pool
+}
+
+// dialNamePool opens size connections to target
+func dialNamePool(target string, size int, opts []grpc.DialOption) (*namePool, error) {
+	conns := make([]*grpc.ClientConn, 0, size)
+	for range size {
+		conn, err := grpc.NewClient(target, opts...)
+		if err != nil {
+			for _, conn := range conns {
+				conn.Close()
+			}
+			return nil, fmt.Errorf("%w: failed to dial namenode %s: %v", ErrUnavailable, target, err)
+		}
+		conns = append(conns, conn)
+	}
+	return newNamePool(conns, true), nil
+}
+
+func (p *namePool) pick() grpc.ClientConnInterface {
+	return p.conns[p.next.Add(1)%uint64(len(p.conns))]
+}
+
+func (p *namePool) close() error {
+	var errs []error
+	for _, conn := range p.owned {
+		errs = append(errs, conn.Close())
+	}
+	return errors.Join(errs...)
+}
+
+type leaserPool struct {
+	lock   sync.Mutex
+	conns  map[string]*leaserConn
+	closed bool
+	config *DosClientConfig
+}
+
+func newLeaserPool(config *DosClientConfig) *leaserPool {
+	return &leaserPool{
+		conns:  make(map[string]*leaserConn),
+		config: config,
+	}
+}
+
+// acquire returns the connection to the leaser at addr, dialing it if no subscription uses it yet
+// every acquire is paired with a release
+func (p *leaserPool) acquire(addr string) (*leaserConn, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.closed {
+		return nil, ErrClientClosed
+	}
+	if lc, ok := p.conns[addr]; ok {
+		lc.refs++
+		return lc, nil
+	}
+	lc, err := dialLeaser(p, addr)
+	if err != nil {
+		return nil, err
+	}
+	lc.refs++
+	p.conns[addr] = lc
+	go lc.run()
+	return lc, nil
+}
+
+func (p *leaserPool) release(lc *leaserConn) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	lc.refs--
+	if lc.refs > 0 {
+		return
+	}
+	if p.conns[lc.addr] == lc {
+		delete(p.conns, lc.addr)
+	}
+	lc.close()
+}
+
+// forget drops a failed connection, the next subscription to its leaser dials a new one
+func (p *leaserPool) forget(lc *leaserConn) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.conns[lc.addr] == lc {
+		delete(p.conns, lc.addr)
+	}
+}
+
+// close ends every subscription, later acquires fail with ErrClientClosed
+func (p *leaserPool) close() {
+	p.lock.Lock()
+	p.closed = true
+	conns := make([]*leaserConn, 0, len(p.conns))
+	for _, lc := range p.conns {
+		conns = append(conns, lc)
+	}
+	p.lock.Unlock()
+
+	for _, lc := range conns {
+		lc.fail(ErrClientClosed)
+	}
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mrowaha/dos/api"
+	"github.com/mrowaha/dos/namenode"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// newMeta stamps a mutation of op with a fresh idempotency key, every attempt of it sends the same meta
+// without a key a retry could apply the mutation twice, so the mutation is not sent at all
+func newMeta(op string) (*api.RequestMeta, error) {
+	key, err := namenode.NewUUID()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to generate idempotency key: %w", op, err)
+	}
+	return &api.RequestMeta{
+		Ts:             timestamppb.Now(),
+		IdempotencyKey: key,
+	}, nil
+}
+
+// retry runs a name service call under the retry policy, call returns an error converted with fromStatus
+func (c *DosClient) retry(ctx context.Context, call func(ctx context.Context, names api.NameServiceClient) error) error {
+	return c.retryConn(ctx, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+		return call(ctx, api.NewNameServiceClient(conn))
+	})
+}
+
+// retryConn runs call under the retry policy
+// every attempt goes out over the next connection of the pool and is bounded by the client's timeout
+func (c *DosClient) retryConn(ctx context.Context, call func(ctx context.Context, conn grpc.ClientConnInterface) error) error {
+	policy := c.config.retry
+	var err error
+	for attempt := 0; attempt < policy.Attempts; attempt++ {
+		if attempt > 0 {
+			delay := namenode.Backoff(attempt-1, policy.BackoffMin, policy.BackoffMax)
+			c.logger.Printf("attempt %d failed (%v), retrying in %s", attempt, err, delay)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return err
+			}
+		}
+		err = c.attempt(ctx, call)
+		if !errors.Is(err, ErrUnavailable) && !errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+		if ctx.Err() != nil {
+			// the caller's own deadline passed, not just the attempt's
+			return err
+		}
+	}
+	return err
+}
+
+func (c *DosClient) attempt(ctx context.Context, call func(ctx context.Context, conn grpc.ClientConnInterface) error) error {
+	if c.config.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.timeout)
+		defer cancel()
+	}
+	return call(ctx, c.names.pick())
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mrowaha/dos/namenode"
+	"google.golang.org/grpc"
+)
+
+func TestRetry(t *testing.T) {
+	unavailable := &Error{Op: "get", kind: ErrUnavailable}
+	tests := []struct {
+		name     string
+		attempts int
+		errs     []error // outcome of each attempt, nil once they run out
+		calls    int
+		want     error
+	}{
+		{name: "succeeds", attempts: 3, calls: 1},
+		{name: "succeeds on a retry", attempts: 3, errs: []error{unavailable, unavailable}, calls: 3},
+		{name: "runs out of attempts", attempts: 2, errs: []error{unavailable, unavailable, unavailable}, calls: 2, want: ErrUnavailable},
+		{name: "attempt timed out", attempts: 2, errs: []error{context.DeadlineExceeded}, calls: 2},
+		{name: "not worth retrying", attempts: 3, errs: []error{&Error{Op: "get", kind: ErrNotFound}}, calls: 1, want: ErrNotFound},
+		{name: "retries off", attempts: 1, errs: []error{unavailable}, calls: 1, want: ErrUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := defaultDosClientConfig()
+			WithLogger(nil)(config)
+			WithRetryPolicy(RetryPolicy{Attempts: tt.attempts, BackoffMin: time.Millisecond, BackoffMax: 2 * time.Millisecond})(config)
+			c := newDosClient(newNamePool([]*grpc.ClientConn{nil}, false), config)
+
+			calls := 0
+			err := c.retryConn(context.Background(), func(ctx context.Context, conn grpc.ClientConnInterface) error {
+				calls++
+				if calls <= len(tt.errs) {
+					return tt.errs[calls-1]
+				}
+				return nil
+			})
+			if calls != tt.calls {
+				t.Errorf("calls = %d, want %d", calls, tt.calls)
+			}
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryStopsWithTheCaller(t *testing.T) {
+	config := defaultDosClientConfig()
+	WithLogger(nil)(config)
+	WithRetryPolicy(RetryPolicy{Attempts: 5, BackoffMin: time.Hour, BackoffMax: time.Hour})(config)
+	c := newDosClient(newNamePool([]*grpc.ClientConn{nil}, false), config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := c.retryConn(ctx, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+		calls++
+		// the caller gives up while the client waits to retry
+		cancel()
+		return &Error{Op: "get", kind: ErrUnavailable}
+	})
+	if calls != 1 || !errors.Is(err, ErrUnavailable) {
+		t.Errorf("calls = %d, err = %v", calls, err)
+	}
+}
+
+func TestNewMeta(t *testing.T) {
+	first, err := newMeta("create")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := newMeta("create")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !namenode.ValidUUID(first.IdempotencyKey) || first.IdempotencyKey == second.IdempotencyKey {
+		t.Errorf("keys %q and %q, want distinct uuids", first.IdempotencyKey, second.IdempotencyKey)
+	}
+}
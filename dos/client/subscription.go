@@ -0,0 +1,330 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/mrowaha/dos/datanode"
+	zmq "github.com/pebbe/zmq4"
+)
+
+/**
+	a subscription streams the publications of one object from the datanode it was leased from
+	the sub socket of a leaser is owned by the goroutine reading it, subscriptions only change the
+	topic table and leave the socket changes to that goroutine, which applies them between reads
+	publications are only trusted while they carry the fencing token the lease was granted under,
+	one under a newer token means the datanode rejoined and the subscription ends with ErrLeaseExpired
+**/
+
+var (
+	ErrClientClosed   = errors.New("client is closed")
+	ErrLeaseExpired   = errors.New("leaser rejoined under a new fencing token, lease again")
+	ErrSlowSubscriber = errors.New("subscriber fell behind its event buffer")
+)
+
+// how long the socket goroutine waits for a publication before looking at topic changes again
+const leaserPollInterval = 100 * time.Millisecond
+
+type EventKind int
+
+const (
+	EventCreate EventKind = iota + 1
+	EventUpdate
+	EventDelete
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventCreate:
+		return "create"
+	case EventUpdate:
+		return "update"
+	case EventDelete:
+		return "delete"
+	}
+	return "unknown"
+}
+
+type Event struct {
+	Object   string
+	Kind     EventKind
+	Sequence int
+	Data     []byte // content after a create or update
+	Err      error  // set on the last event of a subscription that ended on its own
+}
+
+type subscription struct {
+	object string
+	fence  uint64
+	events chan Event
+	conn   *leaserConn
+
+	ended     chan struct{} // closed once the subscription is off its leaser
+	cancelled chan struct{} // closed by the subscriber's cancel func
+	endOnce   sync.Once
+	cancelOne sync.Once
+}
+
+func (sub *subscription) topics() []string {
+	return []string{
+		sub.object + datanode.LEASE_CREATE,
+		sub.object + datanode.LEASE_UPDATE,
+		sub.object + datanode.LEASE_DELETE,
+	}
+}
+
+// end takes the subscription off its leaser and closes the event channel
+// err is handed to the subscriber first, unless it already cancelled
+func (sub *subscription) end(err error) {
+	sub.endOnce.Do(func() {
+		sub.conn.remove(sub)
+		sub.conn.pool.release(sub.conn)
+		close(sub.ended)
+		if err == nil {
+			close(sub.events)
+			return
+		}
+		go func() {
+			select {
+			case sub.events <- Event{Object: sub.object, Err: err}:
+			case <-sub.cancelled:
+			}
+			close(sub.events)
+		}()
+	})
+}
+
+func (sub *subscription) cancel() {
+	sub.cancelOne.Do(func() {
+		close(sub.cancelled)
+	})
+	sub.end(nil)
+}
+
+// watch ends the subscription with its context
+func (sub *subscription) watch(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		sub.end(nil)
+	case <-sub.ended:
+	}
+}
+
+type topicChange struct {
+	topic string
+	on    bool
+}
+
+type leaserConn struct {
+	addr   string
+	pool   *leaserPool
+	socket *zmq.Socket
+	refs   int // guarded by the pool's lock
+
+	lock    sync.Mutex
+	topics  map[string]map[*subscription]struct{}
+	changes []topicChange
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func dialLeaser(pool *leaserPool, addr string) (*leaserConn, error) {
+	socket, err := zmq.NewSocket(zmq.SUB)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to create sub socket: %v", ErrUnavailable, err)
+	}
+	if err := socket.SetRcvtimeo(leaserPollInterval); err != nil {
+		socket.Close()
+		return nil, fmt.Errorf("%w: failed to set sub socket timeout: %v", ErrUnavailable, err)
+	}
+	if err := socket.SetLinger(0); err != nil {
+		socket.Close()
+		return nil, fmt.Errorf("%w: failed to set sub socket linger: %v", ErrUnavailable, err)
+	}
+	if err := socket.Connect(addr); err != nil {
+		socket.Close()
+		return nil, fmt.Errorf("%w: failed to connect to leaser %s: %v", ErrUnavailable, addr, err)
+	}
+	pool.config.logger.Printf("connected to leaser %s", addr)
+	return &leaserConn{
+		addr:   addr,
+		pool:   pool,
+		socket: socket,
+		topics: make(map[string]map[*subscription]struct{}),
+		done:   make(chan struct{}),
+	}, nil
+}
+
+func (lc *leaserConn) add(sub *subscription) {
+	lc.lock.Lock()
+	defer lc.lock.Unlock()
+	for _, topic := range sub.topics() {
+		subs, ok := lc.topics[topic]
+		if !ok {
+			subs = make(map[*subscription]struct{})
+			lc.topics[topic] = subs
+			lc.changes = append(lc.changes, topicChange{topic: topic, on: true})
+		}
+		subs[sub] = struct{}{}
+	}
+}
+
+func (lc *leaserConn) remove(sub *subscription) {
+	lc.lock.Lock()
+	defer lc.lock.Unlock()
+	lc.removeLocked(sub)
+}
+
+func (lc *leaserConn) removeLocked(sub *subscription) {
+	for _, topic := range sub.topics() {
+		subs, ok := lc.topics[topic]
+		if !ok {
+			continue
+		}
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(lc.topics, topic)
+			lc.changes = append(lc.changes, topicChange{topic: topic, on: false})
+		}
+	}
+}
+
+func (lc *leaserConn) close() {
+	lc.closeOnce.Do(func() {
+		close(lc.done)
+	})
+}
+
+// fail ends every subscription of the connection with err
+func (lc *leaserConn) fail(err error) {
+	lc.pool.forget(lc)
+	lc.lock.Lock()
+	subs := make(map[*subscription]struct{})
+	for _, topicSubs := range lc.topics {
+		for sub := range topicSubs {
+			subs[sub] = struct{}{}
+		}
+	}
+	lc.lock.Unlock()
+
+	for sub := range subs {
+		sub.end(err)
+	}
+	// a connection nobody subscribed to yet is not released by any subscription
+	lc.close()
+}
+
+// run owns the socket, it reads publications and applies topic changes until the connection is closed
+func (lc *leaserConn) run() {
+	defer lc.socket.Close()
+	for {
+		select {
+		case <-lc.done:
+			return
+		default:
+		}
+
+		if err := lc.applyChanges(); err != nil {
+			lc.pool.config.logger.Printf("failed to change topics of leaser %s...\n%s", lc.addr, err.Error())
+			go lc.fail(fmt.Errorf("%w: leaser %s: %v", ErrUnavailable, lc.addr, err))
+			return
+		}
+
+		msg, err := lc.socket.RecvMessage(0)
+		if err != nil {
+			if zmq.AsErrno(err) == zmq.Errno(syscall.EAGAIN) {
+				continue
+			}
+			lc.pool.config.logger.Printf("failed to recv from leaser %s...\n%s", lc.addr, err.Error())
+			go lc.fail(fmt.Errorf("%w: lost leaser %s: %v", ErrUnavailable, lc.addr, err))
+			return
+		}
+		lc.dispatch(strings.Join(msg, ""))
+	}
+}
+
+func (lc *leaserConn) applyChanges() error {
+	lc.lock.Lock()
+	changes := lc.changes
+	lc.changes = nil
+	lc.lock.Unlock()
+
+	for _, change := range changes {
+		var err error
+		if change.on {
+			err = lc.socket.SetSubscribe(change.topic)
+		} else {
+			err = lc.socket.SetUnsubscribe(change.topic)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dispatch hands a publication "<topic> <fence> <sequence> [data]" to the subscriptions of its topic
+func (lc *leaserConn) dispatch(msg string) {
+	parts := strings.SplitN(msg, " ", 4)
+	if len(parts) < 3 {
+		lc.pool.config.logger.Printf("malformed publication %s", msg)
+		return
+	}
+	topic := parts[0]
+	token, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		lc.pool.config.logger.Printf("malformed fencing token in publication %s", msg)
+		return
+	}
+	sequence, err := strconv.Atoi(parts[2])
+	if err != nil {
+		lc.pool.config.logger.Printf("malformed sequence in publication %s", msg)
+		return
+	}
+
+	event := Event{Sequence: sequence}
+	switch {
+	case strings.HasSuffix(topic, datanode.LEASE_CREATE):
+		event.Kind = EventCreate
+		event.Object = strings.TrimSuffix(topic, datanode.LEASE_CREATE)
+	case strings.HasSuffix(topic, datanode.LEASE_UPDATE):
+		event.Kind = EventUpdate
+		event.Object = strings.TrimSuffix(topic, datanode.LEASE_UPDATE)
+	case strings.HasSuffix(topic, datanode.LEASE_DELETE):
+		event.Kind = EventDelete
+		event.Object = strings.TrimSuffix(topic, datanode.LEASE_DELETE)
+	default:
+		return
+	}
+	if len(parts) == 4 {
+		event.Data = []byte(parts[3])
+	}
+
+	lc.lock.Lock()
+	defer lc.lock.Unlock()
+	// topics are prefix matched by the socket, only exact matches belong to the subscription
+	for sub := range lc.topics[topic] {
+		switch {
+		case token < sub.fence:
+			// still in flight from before the lease was granted
+			continue
+		case token > sub.fence:
+			lc.removeLocked(sub)
+			go sub.end(fmt.Errorf("%w: %s leased under %d, published under %d", ErrLeaseExpired, sub.object, sub.fence, token))
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			lc.removeLocked(sub)
+			go sub.end(fmt.Errorf("%w: %s", ErrSlowSubscriber, sub.object))
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package datanode
+
+/*
+
+This file contains the acks a datanode answers commands with
+a command that failed is answered with the code of its error instead of taking the node down,
+so the namenode can retry it elsewhere or stop counting this node as a replica
+
+*/
+
+import (
+	"errors"
+	"syscall"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/mrowaha/dos/api"
+)
+
+var (
+	ErrCorruptStore = errors.New("store is corrupt")
+)
+
+// ackCode classifies a store error
+func ackCode(err error) api.NodeHeartBeat_Code {
+	var sqliteErr sqlite3.Error
+	switch {
+	case err == nil:
+		return api.NodeHeartBeat_OK
+	case errors.Is(err, syscall.ENOSPC), errors.Is(err, syscall.EDQUOT):
+		return api.NodeHeartBeat_DISK_FULL
+	case errors.Is(err, ErrCorruptStore):
+		return api.NodeHeartBeat_CORRUPTION
+	case errors.Is(err, ErrObjectAlreadyInStore):
+		return api.NodeHeartBeat_CONSTRAINT
+	case errors.As(err, &sqliteErr):
+		switch sqliteErr.Code {
+		case sqlite3.ErrFull:
+			return api.NodeHeartBeat_DISK_FULL
+		case sqlite3.ErrConstraint:
+			return api.NodeHeartBeat_CONSTRAINT
+		case sqlite3.ErrCorrupt, sqlite3.ErrNotADB:
+			return api.NodeHeartBeat_CORRUPTION
+		}
+	}
+	return api.NodeHeartBeat_FAILED
+}
+
+// ack answers the command with the given request id, err is the reason it failed
+func ack(requestId uint64, err error) *api.NodeHeartBeat {
+	message := &api.NodeHeartBeat{
+		Type:      api.NodeHeartBeat_ACK,
+		RequestId: requestId,
+		Code:      ackCode(err),
+	}
+	if err != nil {
+		message.Error = err.Error()
+	}
+	return message
+}
+
+// readAck answers a distributed read with its result, or with the reason it failed
+func readAck(requestId uint64, objects []*api.NodeHeartBeat_Object, err error) *api.NodeHeartBeat {
+	message := ack(requestId, err)
+	message.Type = api.NodeHeartBeat_DISTRIUTED_READ
+	message.ObjectData = objects
+	return message
+}
@@ -0,0 +1,102 @@
+package datanode
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/mrowaha/dos/api"
+	"github.com/mrowaha/dos/namenode"
+)
+
+func TestAckCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want api.NodeHeartBeat_Code
+	}{
+		{name: "no error", err: nil, want: api.NodeHeartBeat_OK},
+		{name: "no space", err: fmt.Errorf("write: %w", syscall.ENOSPC), want: api.NodeHeartBeat_DISK_FULL},
+		{name: "over quota", err: syscall.EDQUOT, want: api.NodeHeartBeat_DISK_FULL},
+		{name: "corrupt store", err: fmt.Errorf("%w: bad journal", ErrCorruptStore), want: api.NodeHeartBeat_CORRUPTION},
+		{name: "existing object", err: ErrObjectAlreadyInStore, want: api.NodeHeartBeat_CONSTRAINT},
+		{name: "sqlite full", err: sqlite3.Error{Code: sqlite3.ErrFull}, want: api.NodeHeartBeat_DISK_FULL},
+		{name: "sqlite constraint", err: fmt.Errorf("insert: %w", sqlite3.Error{Code: sqlite3.ErrConstraint}), want: api.NodeHeartBeat_CONSTRAINT},
+		{name: "sqlite corrupt", err: sqlite3.Error{Code: sqlite3.ErrCorrupt}, want: api.NodeHeartBeat_CORRUPTION},
+		{name: "not a database", err: sqlite3.Error{Code: sqlite3.ErrNotADB}, want: api.NodeHeartBeat_CORRUPTION},
+		{name: "anything else", err: errors.New("boom"), want: api.NodeHeartBeat_FAILED},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ackCode(tt.err); got != tt.want {
+				t.Errorf("ackCode(%v) = %s, want %s", tt.err, got, tt.want)
+			}
+			message := ack(7, tt.err)
+			if message.RequestId != 7 || message.Code != tt.want || (tt.err == nil) != (len(message.Error) == 0) {
+				t.Errorf("ack = %v", message)
+			}
+		})
+	}
+}
+
+// fullStore is a memory store on a full disk: recording a lamport and applying a batch fail
+type fullStore struct {
+	*DataNodeMemoryStore
+}
+
+func (s fullStore) SetLastLamport(lamport namenode.Lamport) error {
+	return syscall.ENOSPC
+}
+
+func (s fullStore) Begin() (DataNodeBatch, error) {
+	return fullBatch{passthroughBatch{s}}, nil
+}
+
+type fullBatch struct {
+	passthroughBatch
+}
+
+func (b fullBatch) Apply() error {
+	return fmt.Errorf("failed to commit batch: %w", syscall.ENOSPC)
+}
+
+func TestStoreFailuresAreNacked(t *testing.T) {
+	tests := []struct {
+		name   string
+		framed bool
+	}{
+		{name: "single command"},
+		{name: "batch frame", framed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDataNode(t, "")
+			d.store = fullStore{NewDataNodeMemoryStore()}
+
+			// nothing is staged, so the commit only records its lamport
+			d.batching = tt.framed
+			replies := send(t, d, commitCmd(1, "a", 1))
+			flushErr := d.flush()
+			d.batching = false
+			if tt.framed {
+				if flushErr == nil {
+					t.Fatal("batch applied on a full disk")
+				}
+				// the session answers the frame with the reason the batch failed
+				if err := d.fail(replies, flushErr); err == nil {
+					t.Fatal("lamport recorded on a full disk")
+				}
+			}
+
+			if len(replies) != 1 || replies[0].Code != api.NodeHeartBeat_DISK_FULL {
+				t.Fatalf("replies = %v, want a DISK_FULL nack", replies)
+			}
+			// the node keeps serving in order
+			if want := namenode.NewLamport(1, 1); d.lastLamport != want {
+				t.Errorf("lamport = %s, want %s", d.lastLamport, want)
+			}
+		})
+	}
+}
@@ -2,27 +2,52 @@ package datanode
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/mrowaha/dos/api"
 	"github.com/mrowaha/dos/namenode"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type DosDataNode struct {
 	me          string
 	client      api.DataServiceClient
 	logger      *log.Logger
-	store       *DataNodeSqlStore
+	store       DataNodeStore
 	config      *DataNodeConfig
 	queue       DataNodeQueue
 	leaser      *DataNodeLeaseService
-	initLamport int
+	identity    *DataNodeIdentity
+	lastLamport namenode.Lamport      // lamport of the last applied command, survives reconnects
+	fence       namenode.FencingToken // token of the current session, zero while fenced
+
+	// commands of a batch frame are applied through one store batch
+	// lease updates are held back until the batch is durable
+	batching     bool
+	batch        DataNodeBatch
+	publications []func()
 }
 
-func NewDosDataNode(conn *grpc.ClientConn, queue DataNodeQueue, opts ...DNodeConfigFunc) *DosDataNode {
+var (
+	ErrEmptyLeaserAddr = errors.New("node config error: leaserAddr cannot be empty")
+	ErrEmptyName       = errors.New("node config error: name cannot be empty")
+	ErrEpochMismatch   = errors.New("command is not from the epoch the node registered in")
+	ErrFenced          = errors.New("command carries a revoked fencing token")
+	ErrDecommissioned  = errors.New("node was decommissioned")
+)
+
+// how long a decommissioned node waits for the namenode to take its last acks
+const leaveTimeout = 5 * time.Second
+
+func NewDosDataNode(conn *grpc.ClientConn, queue DataNodeQueue, opts ...DNodeConfigFunc) (*DosDataNode, error) {
 	c := api.NewDataServiceClient(conn)
 	logger := log.New(os.Stdout, "[datanode]", log.Ltime)
 
@@ -32,15 +57,37 @@ func NewDosDataNode(conn *grpc.ClientConn, queue DataNodeQueue, opts ...DNodeCon
 	}
 
 	if len(cfg.leaserAddr) == 0 {
-		log.Fatalln("node config error: leaserAddr cannot be empty")
+		return nil, ErrEmptyLeaserAddr
 	}
 
 	if len(cfg.name) == 0 {
-		log.Fatalln("node config error: name cannot be empty")
+		return nil, ErrEmptyName
+	}
+
+	if len(cfg.identity) == 0 {
+		cfg.identity = fmt.Sprintf("%s.identity.json", cfg.name)
+	}
+	identity, err := loadIdentity(cfg.identity)
+	if err != nil {
+		return nil, err
+	}
+	logger.Printf("datanode %s has node id %s", cfg.name, identity.NodeId)
+
+	store, err := cfg.newStore()
+	if err != nil {
+		return nil, err
+	}
+
+	// the store records the lamport of every applied command, so a restarted node
+	// resumes where it stopped. the configured lamport only seeds stores that were
+	// populated out of band, such as a ghost node spawned by the daemon
+	lamport, err := store.LastLamport()
+	if err != nil {
+		return nil, err
 	}
+	initLamport := max(lamport, namenode.Lamport(cfg.lamport))
+	logger.Printf("resuming from lamport %s", initLamport)
 
-	store := NewDataNodeSqlStore(cfg.dbFile)
-	store.BootStrap()
 	leaser := NewDataNodeLeaseService(cfg.leaserAddr)
 
 	return &DosDataNode{
@@ -51,267 +98,874 @@ func NewDosDataNode(conn *grpc.ClientConn, queue DataNodeQueue, opts ...DNodeCon
 		me:          cfg.name,
 		queue:       queue,
 		leaser:      leaser,
-		initLamport: cfg.lamport,
-	}
+		identity:    identity,
+		lastLamport: initLamport,
+	}, nil
 }
 
-func (d *DosDataNode) HandleCommit(cmd *api.CommitCommand) {
-	commit := false
-	for create, _ := d.queue.PullCreateCmd(cmd.ObjectName); create != nil; create, _ = d.queue.PullCreateCmd(cmd.ObjectName) {
-		commit = true
-		sequence, err := d.store.Write(create.Name, create.Data)
+// tx is the store commands are applied to, the open batch while a batch frame is applied
+func (d *DosDataNode) tx() DataNodeStore {
+	if !d.batching {
+		return d.store
+	}
+	if d.batch == nil {
+		batch, err := d.store.Begin()
 		if err != nil {
-			log.Fatalf("failed to flush create requests %v", err)
+			// each command is then durable on its own before it is acknowledged
+			log.Printf("failed to begin store batch, applying commands one by one...\n%s\n", err.Error())
+			return d.store
 		}
-		log.Printf("flushed create requests")
-		d.leaser.PushObjectCreate(create.Name, create.Data, sequence)
+		d.batch = batch
+	}
+	return d.batch
+}
+
+// publish pushes a lease update once what it announces is durable
+func (d *DosDataNode) publish(push func()) {
+	if d.batch != nil {
+		d.publications = append(d.publications, push)
+		return
+	}
+	push()
+}
+
+// flush applies the open batch and pushes the lease updates it held back
+// a batch that fails to apply leaves nothing behind and publishes nothing
+func (d *DosDataNode) flush() error {
+	if d.batch == nil {
+		return nil
+	}
+	batch, publications := d.batch, d.publications
+	d.batch, d.publications = nil, nil
+	if err := batch.Apply(); err != nil {
+		batch.Discard()
+		log.Printf("failed to apply store batch...\n%s\n", err.Error())
+		return err
+	}
+	for _, push := range publications {
+		push()
 	}
-	if !commit {
-		log.Printf("nothing to commit")
+	return nil
+}
+
+// fail answers the commands of a batch that failed to apply with its error
+// like any failed command they are delivered, so the lamport they reached is recorded without them
+// it is called with no batch open
+func (d *DosDataNode) fail(replies []*api.NodeHeartBeat, err error) error {
+	for _, reply := range replies {
+		reply.Code = ackCode(err)
+		reply.Error = err.Error()
+		reply.ObjectData = nil
+	}
+	if err := d.store.SetLastLamport(d.lastLamport); err != nil {
+		log.Printf("failed to record lamport %s...\n%s\n", d.lastLamport, err.Error())
+		return err
+	}
+	return nil
+}
+
+// applied records a command that did not change the store, so that its lamport is not delivered again
+// a node that fails to record it resumes from an earlier lamport if it restarts before its next write
+func (d *DosDataNode) applied(lamport namenode.Lamport) error {
+	if err := d.tx().SetLastLamport(lamport); err != nil {
+		log.Printf("failed to record lamport %s...\n%s\n", lamport, err.Error())
+		return err
+	}
+	return nil
+}
+
+// discard drops the copy of an object that a failed command left stale, along with recording the command
+// the namenode stops counting this node as a replica of the object once it sees the failure
+func (d *DosDataNode) discard(object string, lamport namenode.Lamport) {
+	if _, err := d.tx().Delete(object, lamport); err != nil {
+		log.Printf("failed to discard stale copy of %s: %v\n", object, err)
+		d.applied(lamport)
+	}
+}
+
+func (d *DosDataNode) HandleCreate(cmd *api.CreateCommand) error {
+	// the create is staged until its commit arrives
+	if err := d.tx().Stage(cmd.ObjectName, cmd.ObjectData); err != nil {
+		log.Printf("failed to stage create request...\n%s\n", err.Error())
+		return err
 	}
+	return nil
 }
 
-func (d *DosDataNode) HandleDelete(cmd *api.DeleteCommand) {
+func (d *DosDataNode) HandleCommit(cmd *api.CommitCommand) error {
+	if len(cmd.Nodes) != 0 && !slices.Contains(cmd.Nodes, d.me) {
+		// not a replica, whatever is staged here belongs to a create that failed
+		return d.applied(namenode.Lamport(cmd.Lamport))
+	}
+	data, sequence, err := d.tx().Commit(cmd.ObjectName, namenode.Lamport(cmd.Lamport))
+	if err != nil {
+		if err == ErrNothingStaged {
+			log.Printf("nothing to commit")
+			return d.applied(namenode.Lamport(cmd.Lamport))
+		}
+		log.Printf("failed to commit create request %v", err)
+		d.applied(namenode.Lamport(cmd.Lamport))
+		return err
+	}
+	log.Printf("committed create request for %s", cmd.ObjectName)
+	d.publish(func() { d.leaser.PushObjectCreate(cmd.ObjectName, data, sequence) })
+	return nil
+}
+
+func (d *DosDataNode) HandleDelete(cmd *api.DeleteCommand) error {
+	if len(cmd.Node) != 0 {
+		return d.drop(cmd)
+	}
 	log.Printf("executing delete command for %s\n", cmd.ObjectName)
-	sequence, err := d.store.Delete(cmd.ObjectName)
+	sequence, err := d.tx().Delete(cmd.ObjectName, namenode.Lamport(cmd.Lamport))
 	if err != nil {
 		if err == ErrObjectNotInStore {
-			return
+			return d.applied(namenode.Lamport(cmd.Lamport))
+		}
+		log.Printf("failed to handle delete object...\n%s\n", err.Error())
+		d.applied(namenode.Lamport(cmd.Lamport))
+		return err
+	}
+	d.publish(func() { d.leaser.PushObjectDelete(cmd.ObjectName, sequence+1) })
+	return nil
+}
+
+// drop removes the replica of an object that was moved to another datanode
+// the object still exists, so its subscribers are not told it was deleted
+func (d *DosDataNode) drop(cmd *api.DeleteCommand) error {
+	if cmd.Node != d.me {
+		return d.applied(namenode.Lamport(cmd.Lamport))
+	}
+	log.Printf("dropping replica of %s\n", cmd.ObjectName)
+	if _, err := d.tx().Delete(cmd.ObjectName, namenode.Lamport(cmd.Lamport)); err != nil {
+		if err == ErrObjectNotInStore {
+			return d.applied(namenode.Lamport(cmd.Lamport))
 		}
-		log.Fatalf("failed to handle delete object...\n%s\n", err.Error())
+		d.applied(namenode.Lamport(cmd.Lamport))
+		log.Printf("failed to drop replica...\n%s\n", err.Error())
+		return err
 	}
-	d.leaser.PushObjectDelete(cmd.ObjectName, sequence+1)
+	return nil
 }
 
-func (d *DosDataNode) HandleUpdate(cmd *api.UpdateCommand) {
+func (d *DosDataNode) HandleUpdate(cmd *api.UpdateCommand) error {
 	log.Printf("update object request %s\n", cmd.ObjectName)
-	sequence, err := d.store.Update(cmd.ObjectName, cmd.ObjectData)
+	sequence, err := d.tx().Update(cmd.ObjectName, cmd.ObjectData, namenode.Lamport(cmd.Lamport))
 	if err != nil {
 		if err == ErrObjectNotInStore {
-			return
+			return d.applied(namenode.Lamport(cmd.Lamport))
 		}
-		log.Fatalf("failed to handle update object...\n%s\n", err.Error())
+		log.Printf("failed to handle update object...\n%s\n", err.Error())
+		d.discard(cmd.ObjectName, namenode.Lamport(cmd.Lamport))
+		return err
 	}
-	d.leaser.PushObjectUpdate(cmd.ObjectName, cmd.ObjectData, sequence)
+	d.publish(func() { d.leaser.PushObjectUpdate(cmd.ObjectName, cmd.ObjectData, sequence) })
+	return nil
 }
 
-func (d *DosDataNode) HandleDistributedRead(cmd *api.DistributedReadCommand) []*api.NodeHeartBeat_Object {
+func (d *DosDataNode) HandleDistributedRead(cmd *api.DistributedReadCommand) ([]*api.NodeHeartBeat_Object, error) {
 	log.Printf("distributed read object handler\n")
-	result, err := d.store.ObjectsWithData(cmd.Objects)
+	result, err := d.read(cmd)
+	if err := d.applied(namenode.Lamport(cmd.Lamport)); err != nil {
+		return nil, err
+	}
+	return result, err
+}
+
+func (d *DosDataNode) read(cmd *api.DistributedReadCommand) ([]*api.NodeHeartBeat_Object, error) {
+	if len(cmd.Snapshot) != 0 {
+		result, err := d.tx().SnapshotObjectsWithData(cmd.Snapshot, cmd.Objects)
+		if err != nil {
+			if err == ErrSnapshotNotInStore {
+				// this node joined after the snapshot was taken
+				return make([]*api.NodeHeartBeat_Object, 0), nil
+			}
+			log.Printf("failed to handle snapshot read...\n%s\n", err.Error())
+			return nil, err
+		}
+		return result, nil
+	}
+	result, err := d.tx().ObjectsWithData(cmd.Objects)
 	if err != nil {
-		log.Fatalf("failed to handle distributed read...\n%s\n", err.Error())
+		log.Printf("failed to handle distributed read...\n%s\n", err.Error())
+		return nil, err
+	}
+	return result, nil
+}
+
+func (d *DosDataNode) HandleSnapshot(cmd *api.SnapshotCommand) error {
+	log.Printf("executing snapshot command for %s @lamport%s\n", cmd.Name, namenode.Lamport(cmd.Lamport))
+	if err := d.tx().CreateSnapshot(cmd.Name, namenode.Lamport(cmd.Lamport)); err != nil {
+		log.Printf("failed to handle snapshot...\n%s\n", err.Error())
+		d.applied(namenode.Lamport(cmd.Lamport))
+		return err
+	}
+	return nil
+}
+
+func (d *DosDataNode) HandleDeleteSnapshot(cmd *api.SnapshotCommand) error {
+	log.Printf("executing delete snapshot command for %s\n", cmd.Name)
+	if err := d.tx().DeleteSnapshot(cmd.Name, namenode.Lamport(cmd.Lamport)); err != nil {
+		if err == ErrSnapshotNotInStore {
+			return d.applied(namenode.Lamport(cmd.Lamport))
+		}
+		log.Printf("failed to handle delete snapshot...\n%s\n", err.Error())
+		d.applied(namenode.Lamport(cmd.Lamport))
+		return err
+	}
+	return nil
+}
+
+// join records the cluster on the first join and refuses any other cluster afterwards
+func (d *DosDataNode) join(clusterId string) error {
+	if d.identity.ClusterId == clusterId {
+		return nil
+	}
+	if len(d.identity.ClusterId) != 0 {
+		return fmt.Errorf("%w: joined %s, namenode is in %s", ErrForeignCluster, d.identity.ClusterId, clusterId)
+	}
+	d.identity.ClusterId = clusterId
+	if err := d.identity.save(d.config.identity); err != nil {
+		// joining again records it once the node can
+		d.identity.ClusterId = ""
+		return fmt.Errorf("failed to record cluster id: %w", err)
+	}
+	d.logger.Printf("joined cluster %s", clusterId)
+	return nil
+}
+
+// resync adopts the namenode's register cut when the node can not continue from its own lamport
+// a new epoch means the namenode restarted and its counter started over, so the blocked commands can
+// never be delivered. a node that is behind gets what it missed replayed after the cut, and is flagged
+// suspect when the namenode no longer has those commands, so its store is wiped rather than resumed
+// a failure ends the session, registering again resyncs the node from where its store is
+func (d *DosDataNode) resync(cut namenode.Lamport, suspect bool) error {
+	switch {
+	case suspect:
+		d.logger.Printf("store is suspect, it missed the commands from lamport %s up to %s while fenced. wiping it", d.lastLamport+1, cut)
+	case cut.Epoch() != d.lastLamport.Epoch():
+		d.logger.Printf("namenode is in epoch %d, was %d. resuming from lamport %s", cut.Epoch(), d.lastLamport.Epoch(), cut)
+	case cut > d.lastLamport:
+		d.logger.Printf("missed the commands from lamport %s up to %s while disconnected", d.lastLamport+1, cut)
+	default:
+		// in step with the namenode, everything after the cut is sent on this session
+		return nil
+	}
+
+	// the queue may share the store's database, so the open batch goes first
+	if err := d.flush(); err != nil {
+		return err
+	}
+	if err := d.queue.Reset(); err != nil {
+		return fmt.Errorf("failed to reset the command queue: %w", err)
+	}
+	if suspect {
+		if err := d.tx().Wipe(cut); err != nil {
+			return fmt.Errorf("failed to wipe suspect store: %w", err)
+		}
+	} else if err := d.applied(cut); err != nil {
+		return err
+	}
+	d.lastLamport = cut
+	return nil
+}
+
+// block queues a command that arrived ahead of its lamport
+// the queue may share the store's database, so the open batch goes first
+func (d *DosDataNode) block(lamport namenode.Lamport, cmd interface{}) error {
+	if err := d.flush(); err != nil {
+		return err
+	}
+	if err := d.queue.BlockCommand(lamport, cmd); err != nil {
+		return fmt.Errorf("failed to block command @lamport%s: %w", lamport, err)
+	}
+	return nil
+}
+
+type delivery int
+
+const (
+	deliverNow delivery = iota
+	deliverLater
+	alreadyDelivered
+)
+
+// order decides what to do with a command given the lamport of the last applied one
+func order(lamport namenode.Lamport, lastLamport namenode.Lamport) delivery {
+	switch {
+	case lamport == lastLamport+1:
+		return deliverNow
+	case lamport <= lastLamport:
+		// resent after a crash between applying and acknowledging
+		return alreadyDelivered
+	default:
+		return deliverLater
 	}
-	return result
 }
 
+// Register keeps the datanode registered with the namenode for the lifetime of the process
+// a lost stream is re-established with jittered exponential backoff under the same id,
+// and ordering resumes from the last applied lamport. the lease service keeps its token
+// across reconnects and the namenode hands it back unless the store is resynced, so
+// subscribers are only told to lease again when what they leased is gone
+// it returns once the node is decommissioned
 func (d *DosDataNode) Register() {
-	bistream, err := d.client.RegisterNode(context.Background())
+	attempt := 0
+	for {
+		started := time.Now()
+		err := d.session()
+		// commands of the next session carry the token it registers under
+		// nothing is published meanwhile, only applied commands are
+		d.fence = 0
+		if errors.Is(err, ErrDecommissioned) {
+			// the namenode moved every object elsewhere, registering again would only bring them back
+			d.logger.Printf("decommissioned, leaving the cluster")
+			return
+		}
+		if errors.Is(err, ErrJoinRejected) || errors.Is(err, ErrForeignCluster) {
+			// retrying does not change the answer
+			d.logger.Fatalf("can not register with the namenode: %v", err)
+		}
+		if ackCode(err) == api.NodeHeartBeat_CORRUPTION {
+			// a corrupt store would only be resynced into the same failure
+			d.logger.Fatalf("store is corrupt: %v", err)
+		}
+		if time.Since(started) > d.config.backoffMax {
+			// the session was healthy for a while, start over with short delays
+			attempt = 0
+		}
+		delay := namenode.Backoff(attempt, d.config.backoffMin, d.config.backoffMax)
+		attempt++
+		d.logger.Printf("lost namenode stream (%v), reconnecting in %s", err, delay)
+		time.Sleep(delay)
+	}
+}
+
+// session registers with the namenode and serves commands until the stream fails
+func (d *DosDataNode) session() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bistream, err := d.client.RegisterNode(ctx)
+	if err != nil {
+		return err
+	}
+	// the first heartbeat identifies the node, the namenode answers with a register command or rejects it
+	size, _ := d.store.Size()
+	objects, _ := d.store.Objects()
+	err = bistream.Send(&api.NodeHeartBeat{
+		Id:            d.me,
+		Size:          size,
+		Objects:       objects,
+		LeaserService: d.config.leaserAddr,
+		Type:          api.NodeHeartBeat_BEAT,
+		NodeId:        d.identity.NodeId,
+		ClusterId:     d.identity.ClusterId,
+		JoinToken:     d.config.joinToken,
+		Capacity:      d.config.capacity,
+		Zone:          d.config.zone,
+		Rack:          d.config.rack,
+		Labels:        d.config.labels,
+		Lamport:       uint64(d.lastLamport),
+		Fence:         uint64(d.leaser.Token()),
+	})
 	if err != nil {
-		log.Fatalf("failed to start register node\n%s", err.Error())
+		return err
 	}
+	d.logger.Printf("registering with namenode, resuming from lamport %s", d.lastLamport)
 
 	messageChan := make(chan *api.NodeHeartBeat, 5)
+	// queues a message for the sender, dropped once the session is over
+	send := func(message *api.NodeHeartBeat) {
+		select {
+		case messageChan <- message:
+		case <-ctx.Done():
+		}
+	}
 
 	go func() {
 		for {
 			// heartbeat
 			size, _ := d.store.Size()
 			objects, _ := d.store.Objects()
-			messageChan <- &api.NodeHeartBeat{
+			select {
+			case messageChan <- &api.NodeHeartBeat{
 				Id:            d.me,
 				Size:          size,
 				Objects:       objects,
 				LeaserService: d.config.leaserAddr,
 				Type:          api.NodeHeartBeat_BEAT,
+			}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case <-time.After(3 * time.Second):
+			case <-ctx.Done():
+				return
 			}
-			time.Sleep(3 * time.Second)
 		}
 	}()
 
+	// closing leave has the sender write out what is queued and half close the stream
+	leave := make(chan struct{})
+	sent := make(chan struct{})
 	go func() {
-		for message := range messageChan {
-			bistream.Send(message)
+		defer close(sent)
+		for {
+			select {
+			case message := <-messageChan:
+				if err := bistream.Send(message); err != nil {
+					// nothing is sent anymore, Recv fails once the stream is cancelled and ends the session
+					cancel()
+					return
+				}
+			case <-leave:
+				for {
+					select {
+					case message := <-messageChan:
+						if err := bistream.Send(message); err != nil {
+							return
+						}
+					default:
+						bistream.CloseSend()
+						return
+					}
+				}
+			case <-ctx.Done():
+				return
+			}
 		}
 	}()
 
-	var lastLamport int32
-	var blocked bool
-	var skip bool
-	lastLamport = int32(d.initLamport) // have not yet received any lamport
 	for {
 		log.Printf("awaiting")
-		blocked = false
-		skip = false
 		resp, err := bistream.Recv()
 		if err != nil {
-			if err.Error() == "EOF" {
-				log.Fatalf("stream closed by server")
+			if err == io.EOF {
+				return errors.New("stream closed by server")
+			}
+			switch status.Code(err) {
+			case codes.PermissionDenied, codes.InvalidArgument, codes.FailedPrecondition:
+				return fmt.Errorf("%w: %s", ErrJoinRejected, status.Convert(err).Message())
+			}
+			return err
+		}
+
+		if resp.Command != api.CommandNodeRes_BATCH {
+			err := d.handle(resp, send)
+			if errors.Is(err, ErrDecommissioned) {
+				// the ack is queued, the namenode is told before the stream goes away
+				close(leave)
+				d.leave(bistream, sent)
+			}
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		replies, err := d.applyBatch(resp.Batch)
+		if err != nil && !errors.Is(err, ErrDecommissioned) {
+			// the namenode gives up on the unanswered commands of the batch when the session ends
+			return err
+		}
+		send(&api.NodeHeartBeat{
+			Type:  api.NodeHeartBeat_BATCH,
+			Batch: replies,
+		})
+		if err != nil {
+			// decommissioned by the last command of the batch
+			close(leave)
+			d.leave(bistream, sent)
+			return err
+		}
+	}
+}
+
+// applyBatch applies the commands of a batch frame in one store transaction and returns their replies,
+// which only go out once the whole batch is durable. the commands before one that ends the session are applied,
+// a batch that fails to apply leaves none of its commands applied and answers every one with the reason
+func (d *DosDataNode) applyBatch(batch []*api.CommandNodeRes) ([]*api.NodeHeartBeat, error) {
+	log.Printf("received a batch of %d commands\n", len(batch))
+	replies := make([]*api.NodeHeartBeat, 0, len(batch))
+	d.batching = true
+	var err error
+	for _, cmd := range batch {
+		err = d.handle(cmd, func(message *api.NodeHeartBeat) {
+			replies = append(replies, message)
+		})
+		if err != nil {
+			break
+		}
+	}
+	flushErr := d.flush()
+	d.batching = false
+	if err != nil && !errors.Is(err, ErrDecommissioned) {
+		return replies, err
+	}
+	if flushErr != nil {
+		if err := d.fail(replies, flushErr); err != nil {
+			return replies, err
+		}
+	}
+	return replies, err
+}
+
+// leave waits until the sender wrote out the last acks, then until the namenode ends the stream
+// the namenode reads everything sent before the half close first, so the acks are not cut off
+// by the session cancelling the stream. a namenode that does not answer is given up on
+func (d *DosDataNode) leave(bistream api.DataService_RegisterNodeClient, sent <-chan struct{}) {
+	timeout := time.After(leaveTimeout)
+	select {
+	case <-sent:
+	case <-timeout:
+		return
+	}
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, err := bistream.Recv(); err != nil {
+				return
 			}
-			log.Fatalf("error receiving from stream: %v", err)
 		}
+	}()
+	select {
+	case <-closed:
+	case <-timeout:
+		// cancelling the session unblocks the receiver
+	}
+}
 
-		switch resp.Command {
-		case api.CommandNodeRes_CREATE:
-			log.Printf("received create command %s\n", resp.Create.ObjectName)
-			// we are going to wait for commit
-			_ = d.queue.PushCreateCmd(namenode.CreateCommand{
-				Name: resp.Create.ObjectName,
-				Data: resp.Create.ObjectData,
-			})
+// handle applies a single command, reply receives its ack or read result
+// commands that fail are answered with a failed ack, a command that arrived ahead of its lamport
+// is answered once it is delivered. an error ends the session, registering again resynchronizes the node
+// from where its store is. a store or queue that fails outside of a command, like a command that can not
+// be blocked, ends the session too
+func (d *DosDataNode) handle(resp *api.CommandNodeRes, reply func(message *api.NodeHeartBeat)) error {
+	if resp.Command == api.CommandNodeRes_REGISTER {
+		if err := d.join(resp.Register.ClusterId); err != nil {
+			return err
+		}
+		if err := d.resync(namenode.Lamport(resp.Register.Lamport), resp.Register.Resync); err != nil {
+			return err
+		}
+		d.fence = namenode.FencingToken(resp.Fence)
+		if d.leaser.Token() != d.fence {
+			// the namenode revoked the old token, subscribers of it lease again
+			d.leaser.Unfence(d.fence)
+		}
+		d.logger.Printf("serving under fencing token %s", d.fence)
+		reply(ack(resp.RequestId, nil))
+		return nil
+	}
+	if d.fence == 0 || namenode.FencingToken(resp.Fence) != d.fence {
+		return fmt.Errorf("%w: %s, serving under %s", ErrFenced, namenode.FencingToken(resp.Fence), d.fence)
+	}
+	if resp.Epoch != d.lastLamport.Epoch() {
+		return fmt.Errorf("%w: epoch %d, registered in %d", ErrEpochMismatch, resp.Epoch, d.lastLamport.Epoch())
+	}
+
+	if resp.Command == api.CommandNodeRes_DECOMMISSION {
+		// nothing is ordered after it, the session ends here
+		reply(ack(resp.RequestId, nil))
+		return ErrDecommissioned
+	}
+
+	var blocked bool
+	var skip bool
+	switch resp.Command {
+	case api.CommandNodeRes_CREATE:
+		log.Printf("received create command %s\n", resp.Create.ObjectName)
+		// we are going to wait for commit
+		err := d.HandleCreate(resp.Create)
+		skip = true
+		reply(ack(resp.RequestId, err))
+	case api.CommandNodeRes_COMMIT:
+		// flush the create queue if commit contains this datanode
+		log.Printf("received commit command, lamport %s\n", namenode.Lamport(resp.Commit.Lamport))
+		switch order(namenode.Lamport(resp.Commit.Lamport), d.lastLamport) {
+		case deliverNow:
+			err := d.HandleCommit(resp.Commit)
+			d.lastLamport = namenode.Lamport(resp.Commit.Lamport)
+			reply(ack(resp.RequestId, err))
+		case alreadyDelivered:
+			log.Printf("commit command was already delivered")
 			skip = true
-			messageChan <- &api.NodeHeartBeat{
-				Type:       api.NodeHeartBeat_ACK,
-				MessageTag: resp.MessageTag,
+			reply(ack(resp.RequestId, nil))
+		case deliverLater:
+			log.Printf("commit command was blocked")
+			blocked = true
+			err := d.block(
+				namenode.Lamport(resp.Commit.Lamport),
+				namenode.CommitCommand{
+					Name:      resp.Commit.ObjectName,
+					Type:      namenode.COMMIT,
+					RequestId: resp.RequestId,
+					Nodes:     resp.Commit.Nodes,
+				},
+			)
+			if err != nil {
+				return err
 			}
-		case api.CommandNodeRes_COMMIT:
-			// flush the create queue if commit contains this datanode
-			log.Printf("received commit command, lamport %d\n", resp.Commit.Lamport)
-			if resp.Commit.Lamport-lastLamport == 1 {
-				lastLamport = resp.Commit.Lamport
-				d.HandleCommit(resp.Commit)
-			} else {
-				log.Printf("commit command was blocked")
-				blocked = true
-				err := d.queue.BlockCommand(
-					float64(resp.Commit.Lamport),
-					namenode.CommitCommand{
-						Type: namenode.COMMIT,
-					},
-				)
-				if err != nil {
-					log.Fatalf("failed to block command... %v\n", err.Error())
-				}
+		}
+	case api.CommandNodeRes_DELETE:
+		log.Printf("delete object request, lamport %s\n", namenode.Lamport(resp.Delete.Lamport))
+		switch order(namenode.Lamport(resp.Delete.Lamport), d.lastLamport) {
+		case deliverNow:
+			err := d.HandleDelete(resp.Delete)
+			d.lastLamport = namenode.Lamport(resp.Delete.Lamport)
+			reply(ack(resp.RequestId, err))
+		case alreadyDelivered:
+			log.Printf("delete command was already delivered")
+			skip = true
+			reply(ack(resp.RequestId, nil))
+		case deliverLater:
+			log.Printf("delete command was blocked")
+			blocked = true
+			err := d.block(
+				namenode.Lamport(resp.Delete.Lamport),
+				namenode.DeleteCommand{
+					Name:      resp.Delete.ObjectName,
+					Type:      namenode.DELETE,
+					RequestId: resp.RequestId,
+					Node:      resp.Delete.Node,
+				},
+			)
+			if err != nil {
+				return err
+			}
+		}
+	case api.CommandNodeRes_UPDATE:
+		log.Printf("update object request %s, @lamport%s\n", resp.Update.ObjectName, namenode.Lamport(resp.Update.Lamport))
+		switch order(namenode.Lamport(resp.Update.Lamport), d.lastLamport) {
+		case deliverNow:
+			err := d.HandleUpdate(resp.Update)
+			d.lastLamport = namenode.Lamport(resp.Update.Lamport)
+			reply(ack(resp.RequestId, err))
+		case alreadyDelivered:
+			log.Printf("update command was already delivered")
+			skip = true
+			reply(ack(resp.RequestId, nil))
+		case deliverLater:
+			log.Printf("update command was blocked")
+			blocked = true
+			err := d.block(
+				namenode.Lamport(resp.Update.Lamport),
+				namenode.UpdateCommand{
+					Name:      resp.Update.ObjectName,
+					Data:      resp.Update.ObjectData,
+					Type:      namenode.UPDATE,
+					RequestId: resp.RequestId,
+				},
+			)
+			if err != nil {
+				return err
 			}
-			messageChan <- &api.NodeHeartBeat{
-				Type:       api.NodeHeartBeat_ACK,
-				MessageTag: resp.MessageTag,
+		}
+	case api.CommandNodeRes_DISTRIBUTED_READ:
+		log.Printf("distributed read request %v, @lamport%s\n", resp.DistributedRead.Objects, namenode.Lamport(resp.DistributedRead.Lamport))
+		switch order(namenode.Lamport(resp.DistributedRead.Lamport), d.lastLamport) {
+		case deliverNow:
+			result, err := d.HandleDistributedRead(resp.DistributedRead)
+			d.lastLamport = namenode.Lamport(resp.DistributedRead.Lamport)
+			reply(readAck(resp.RequestId, result, err))
+		case alreadyDelivered:
+			// reads do not change the store, so a resent read is simply answered again
+			skip = true
+			result, err := d.read(resp.DistributedRead)
+			reply(readAck(resp.RequestId, result, err))
+		case deliverLater:
+			log.Printf("distribted read was blocked")
+			blocked = true
+			err := d.block(
+				namenode.Lamport(resp.DistributedRead.Lamport),
+				namenode.DistributedReadCommand{
+					Objects:   resp.DistributedRead.Objects,
+					Snapshot:  resp.DistributedRead.Snapshot,
+					Type:      namenode.DISTRIBUTEDREAD,
+					RequestId: resp.RequestId,
+				},
+			)
+			if err != nil {
+				return err
 			}
-		case api.CommandNodeRes_DELETE:
-			log.Printf("delete object request, lamport %d\n", resp.Delete.Lamport)
-			if resp.Delete.Lamport-lastLamport == 1 {
-				lastLamport = resp.Delete.Lamport
-				d.HandleDelete(resp.Delete)
+		}
+	case api.CommandNodeRes_SNAPSHOT, api.CommandNodeRes_DELETE_SNAPSHOT:
+		event := namenode.SNAPSHOT
+		if resp.Command == api.CommandNodeRes_DELETE_SNAPSHOT {
+			event = namenode.DELETESNAPSHOT
+		}
+		log.Printf("%s request %s, @lamport%s\n", event, resp.Snapshot.Name, namenode.Lamport(resp.Snapshot.Lamport))
+		switch order(namenode.Lamport(resp.Snapshot.Lamport), d.lastLamport) {
+		case deliverNow:
+			var err error
+			if event == namenode.SNAPSHOT {
+				err = d.HandleSnapshot(resp.Snapshot)
 			} else {
-				log.Printf("delete command was blocked")
-				blocked = true
-				err := d.queue.BlockCommand(
-					float64(resp.Delete.Lamport),
-					namenode.DeleteCommand{
-						Name: resp.Delete.ObjectName,
-						Type: namenode.DELETE,
-					},
-				)
-				if err != nil {
-					log.Fatalf("failed to block delete command... %v\n", err.Error())
-				}
+				err = d.HandleDeleteSnapshot(resp.Snapshot)
 			}
-			messageChan <- &api.NodeHeartBeat{
-				Type:       api.NodeHeartBeat_ACK,
-				MessageTag: resp.MessageTag,
+			d.lastLamport = namenode.Lamport(resp.Snapshot.Lamport)
+			reply(ack(resp.RequestId, err))
+		case alreadyDelivered:
+			log.Printf("%s command was already delivered", event)
+			skip = true
+			reply(ack(resp.RequestId, nil))
+		case deliverLater:
+			log.Printf("%s command was blocked", event)
+			blocked = true
+			err := d.block(
+				namenode.Lamport(resp.Snapshot.Lamport),
+				namenode.SnapshotCommand{
+					Name:      resp.Snapshot.Name,
+					Type:      event,
+					RequestId: resp.RequestId,
+				},
+			)
+			if err != nil {
+				return err
 			}
-		case api.CommandNodeRes_UPDATE:
-			log.Printf("update object request %s, @lamport%d\n", resp.Update.ObjectName, resp.Update.Lamport)
-			if resp.Update.Lamport-lastLamport == 1 {
-				lastLamport = resp.Update.Lamport
-				d.HandleUpdate(resp.Update)
-			} else {
-				log.Printf("update command was blocked")
-				blocked = true
-				err := d.queue.BlockCommand(
-					float64(resp.Update.Lamport),
-					namenode.UpdateCommand{
-						Name: resp.Update.ObjectName,
-						Data: resp.Update.ObjectData,
-						Type: namenode.UPDATE,
-					},
-				)
-				if err != nil {
-					log.Fatalf("failed to block update command... %v\n", err.Error())
-				}
+		}
+	}
+
+	// the blocked commands that are next in order are delivered now
+	if !skip && !blocked {
+		for {
+			_, lamport, err := d.queue.RetrieveCommand()
+			if err == ErrNoCommandToRetrieve {
+				break
 			}
-			messageChan <- &api.NodeHeartBeat{
-				Type:       api.NodeHeartBeat_ACK,
-				MessageTag: resp.MessageTag,
+			if err != nil {
+				return fmt.Errorf("failed to retrieve blocked command: %w", err)
 			}
-		case api.CommandNodeRes_DISTRIBUTED_READ:
-			log.Printf("distributed read request %v, @lamport%d\n", resp.DistributedRead.Objects, resp.DistributedRead.Lamport)
-			if resp.DistributedRead.Lamport-lastLamport == 1 {
-				lastLamport = resp.DistributedRead.Lamport
-				result := d.HandleDistributedRead(resp.DistributedRead)
-				messageChan <- &api.NodeHeartBeat{
-					Type:       api.NodeHeartBeat_DISTRIUTED_READ,
-					MessageTag: resp.MessageTag,
-					ObjectData: result,
+			if lamport <= d.lastLamport {
+				// answered before it could leave the queue, by a crash or a batch that failed to apply
+				log.Printf("dropping blocked command @lamport%s, it was already delivered\n", lamport)
+				if err := d.flush(); err != nil {
+					return err
 				}
-			} else {
-				log.Printf("distribted read was blocked")
-				blocked = true
-				err := d.queue.BlockCommand(
-					float64(resp.DistributedRead.Lamport),
-					namenode.DistributedReadCommand{
-						Objects: resp.DistributedRead.Objects,
-						Type:    namenode.DISTRIBUTEDREAD,
-					},
-				)
-				if err != nil {
-					log.Fatalf("failed to block distributed read command... %v\n", err.Error())
+				if _, _, err := d.queue.DeliverCommand(); err != nil {
+					return fmt.Errorf("failed to drop blocked command: %w", err)
 				}
+				continue
+			}
+			if lamport != d.lastLamport+1 {
+				break
+			}
+			if err := d.deliver(lamport, reply); err != nil {
+				return err
 			}
 		}
+	}
+	return nil
+}
 
-		// now we begin delivering all the messages and update the lastlamport accordingly
-		// first we retrieve the command that had the smallest lamport value in the queue
-		// if the diff between that lamport and current lamport is 1, deliver and update current
-		// lamport
-		if !skip && !blocked {
+// deliver applies the blocked command at the head of the queue, which is next in order
+// a queue in the store's database gives the command up in the store batch that applies it, so a crash
+// never leaves it applied and still queued or dequeued and never applied. any other queue gives it up once
+// the batch is durable, and a crash in between leaves it queued behind the store's lamport, where it is dropped
+// the lamport advances and the command is acknowledged once it is applied, a batch that fails to apply
+// answers it with the reason and leaves it queued behind the lamport as well
+func (d *DosDataNode) deliver(lamport namenode.Lamport, reply func(message *api.NodeHeartBeat)) error {
+	// the command is applied in a batch of its own
+	if err := d.flush(); err != nil {
+		return err
+	}
+	framed := d.batching
+	d.batching = true
+	defer func() { d.batching = framed }()
 
-			for {
-				next, lamport, err := d.queue.RetrieveCommand()
-				if err == ErrNoCommandToRetrieve {
-					break
-				}
-				if err != nil {
-					log.Fatalf("%v\n", err)
-				}
-				if lamport-float64(lastLamport) != 1 {
-					break
-				}
+	d.tx()
+	var next interface{}
+	var err error
+	shared := false
+	if queue, ok := d.queue.(TransactionalQueue); ok {
+		next, _, err = queue.DeliverCommandIn(d.batch)
+		shared = err == nil
+	}
+	if !shared && (err == nil || errors.Is(err, ErrQueueNotInBatch)) {
+		next, _, err = d.queue.RetrieveCommand()
+	}
+	if err != nil {
+		d.flush()
+		return fmt.Errorf("failed to deliver command @lamport%s: %w", lamport, err)
+	}
 
-				// now we know that this event is a valid blocked next
-				lastLamport = int32(lamport)
-				log.Printf("going to deliver command %s @lamport%.0f\n", next, lamport)
-				next, _, err = d.queue.DeliverCommand()
-				if err != nil {
-					log.Fatalf("%s\n", err.Error())
-				}
-				switch v := next.(type) {
-				case namenode.CommitCommand:
-					d.HandleCommit(&api.CommitCommand{})
-					log.Printf("delievered commit")
-				case namenode.DeleteCommand:
-					d.HandleDelete(&api.DeleteCommand{
-						ObjectName: v.Name,
-					})
-					log.Printf("delievered delete")
-				case namenode.UpdateCommand:
-					d.HandleUpdate(&api.UpdateCommand{
-						ObjectName: v.Name,
-						ObjectData: v.Data,
-					})
-					log.Printf("delievered update")
-				case namenode.DistributedReadCommand:
-					result := d.HandleDistributedRead(&api.DistributedReadCommand{
-						Objects: v.Objects,
-					})
-					messageChan <- &api.NodeHeartBeat{
-						Type:       api.NodeHeartBeat_DISTRIUTED_READ,
-						ObjectData: result,
-						MessageTag: namenode.DistributedReadTag(v.Objects, d.me),
-					}
-					log.Printf("delievered distributed read")
-				}
+	log.Printf("going to deliver command %s @lamport%s\n", next, lamport)
+	message := d.applyBlocked(next, lamport)
+	d.lastLamport = lamport
+	// the queue reads outside the batch, it must not see the command still queued
+	if err := d.flush(); err != nil {
+		if message != nil {
+			if err := d.fail([]*api.NodeHeartBeat{message}, err); err != nil {
+				return err
 			}
+			reply(message)
+		}
+		return nil
+	}
+	if !shared {
+		if _, _, err := d.queue.DeliverCommand(); err != nil {
+			return fmt.Errorf("failed to dequeue command @lamport%s: %w", lamport, err)
+		}
+	}
+	if message != nil {
+		reply(message)
+	}
+	return nil
+}
 
+// applyBlocked applies a command that was blocked until lamport and returns its ack
+func (d *DosDataNode) applyBlocked(next interface{}, lamport namenode.Lamport) *api.NodeHeartBeat {
+	switch v := next.(type) {
+	case namenode.CommitCommand:
+		err := d.HandleCommit(&api.CommitCommand{
+			ObjectName: v.Name,
+			Lamport:    uint64(lamport),
+			Nodes:      v.Nodes,
+		})
+		log.Printf("delievered commit")
+		return ack(v.RequestId, err)
+	case namenode.DeleteCommand:
+		err := d.HandleDelete(&api.DeleteCommand{
+			ObjectName: v.Name,
+			Lamport:    uint64(lamport),
+			Node:       v.Node,
+		})
+		log.Printf("delievered delete")
+		return ack(v.RequestId, err)
+	case namenode.UpdateCommand:
+		err := d.HandleUpdate(&api.UpdateCommand{
+			ObjectName: v.Name,
+			ObjectData: v.Data,
+			Lamport:    uint64(lamport),
+		})
+		log.Printf("delievered update")
+		return ack(v.RequestId, err)
+	case namenode.DistributedReadCommand:
+		result, err := d.HandleDistributedRead(&api.DistributedReadCommand{
+			Objects:  v.Objects,
+			Snapshot: v.Snapshot,
+			Lamport:  uint64(lamport),
+		})
+		log.Printf("delievered distributed read")
+		return readAck(v.RequestId, result, err)
+	case namenode.SnapshotCommand:
+		snapshot := &api.SnapshotCommand{
+			Name:    v.Name,
+			Lamport: uint64(lamport),
+		}
+		var err error
+		if v.Type == namenode.DELETESNAPSHOT {
+			err = d.HandleDeleteSnapshot(snapshot)
+		} else {
+			err = d.HandleSnapshot(snapshot)
 		}
+		log.Printf("delievered %s", v.Type)
+		return ack(v.RequestId, err)
 	}
+	// the queue only decodes the commands above
+	d.applied(lamport)
+	return nil
 }
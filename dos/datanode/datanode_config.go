@@ -1,20 +1,66 @@
 package datanode
 
-import "log"
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+type StoreBackend string
+
+// these are the storage backends a datanode can run on
+const (
+	SqlBackend    StoreBackend = "sql"
+	FileBackend   StoreBackend = "file"
+	MemoryBackend StoreBackend = "memory"
+)
 
 type DataNodeConfig struct {
 	dbFile     string
+	storeDir   string
+	backend    StoreBackend
 	leaserAddr string
 	name       string
-	lamport    int
+	lamport    uint64
+	identity   string
+	joinToken  string
+	capacity   float32
+	zone       string
+	rack       string
+	labels     map[string]string
+	backoffMin time.Duration
+	backoffMax time.Duration
 }
 
 func defaultDataNodeConfig() *DataNodeConfig {
 	return &DataNodeConfig{
 		dbFile:     "data.db",
+		storeDir:   "data",
+		backend:    SqlBackend,
 		leaserAddr: "",
 		name:       "",
 		lamport:    0,
+		identity:   "",
+		joinToken:  "",
+		capacity:   0,
+		zone:       "",
+		rack:       "",
+		labels:     nil,
+		backoffMin: 500 * time.Millisecond,
+		backoffMax: 30 * time.Second,
+	}
+}
+
+func (cfg *DataNodeConfig) newStore() (DataNodeStore, error) {
+	switch cfg.backend {
+	case SqlBackend:
+		return NewDataNodeSqlStore(cfg.dbFile)
+	case FileBackend:
+		return NewDataNodeFileStore(cfg.storeDir)
+	case MemoryBackend:
+		return NewDataNodeMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("node config error: unknown store backend %s", cfg.backend)
 	}
 }
 
@@ -28,10 +74,26 @@ func WithName(name string) DNodeConfigFunc {
 
 func WithDBFile(dbFile string) DNodeConfigFunc {
 	return func(node *DataNodeConfig) {
+		node.backend = SqlBackend
 		node.dbFile = dbFile
 	}
 }
 
+// the file store keeps one file per object under dir
+func WithFileStore(dir string) DNodeConfigFunc {
+	return func(node *DataNodeConfig) {
+		node.backend = FileBackend
+		node.storeDir = dir
+	}
+}
+
+// the memory store does not survive restarts, use it for tests only
+func WithMemoryStore() DNodeConfigFunc {
+	return func(node *DataNodeConfig) {
+		node.backend = MemoryBackend
+	}
+}
+
 func WithLeaser(leaserAddr string) DNodeConfigFunc {
 	return func(node *DataNodeConfig) {
 		if len(leaserAddr) == 0 {
@@ -41,8 +103,55 @@ func WithLeaser(leaserAddr string) DNodeConfigFunc {
 	}
 }
 
-func WithLamport(n int) DNodeConfigFunc {
+func WithLamport(n uint64) DNodeConfigFunc {
 	return func(node *DataNodeConfig) {
 		node.lamport = n
 	}
 }
+
+// the identity file keeps the node id and cluster id, it defaults to <name>.identity.json
+func WithIdentityFile(path string) DNodeConfigFunc {
+	return func(node *DataNodeConfig) {
+		node.identity = path
+	}
+}
+
+// the join token is presented to the namenode when registering
+func WithJoinToken(token string) DNodeConfigFunc {
+	return func(node *DataNodeConfig) {
+		node.joinToken = token
+	}
+}
+
+// the capacity in bytes is reported to the namenode for placement, zero leaves it unbounded
+func WithCapacity(bytes float32) DNodeConfigFunc {
+	return func(node *DataNodeConfig) {
+		node.capacity = bytes
+	}
+}
+
+// the zone and rack are the failure domain of the node, the namenode spreads the replicas of an object over them
+func WithTopology(zone string, rack string) DNodeConfigFunc {
+	return func(node *DataNodeConfig) {
+		node.zone = zone
+		node.rack = rack
+	}
+}
+
+// labels are matched by the placement constraints of objects, like disk=ssd
+func WithLabels(labels map[string]string) DNodeConfigFunc {
+	return func(node *DataNodeConfig) {
+		node.labels = labels
+	}
+}
+
+// the datanode waits between min and max before reconnecting to the namenode
+func WithBackoff(min time.Duration, max time.Duration) DNodeConfigFunc {
+	return func(node *DataNodeConfig) {
+		if min <= 0 || max < min {
+			log.Fatalln("node config error: backoff requires 0 < min <= max")
+		}
+		node.backoffMin = min
+		node.backoffMax = max
+	}
+}
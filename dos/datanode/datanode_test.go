@@ -0,0 +1,499 @@
+package datanode
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"testing"
+
+	"github.com/mrowaha/dos/api"
+	"github.com/mrowaha/dos/namenode"
+)
+
+const testFence = namenode.FencingToken(1)
+
+// newTestDataNode serves from a sqlite store, with its queue in the same file unless queueFile is set
+// the lease service is fenced, so nothing is published
+func newTestDataNode(t *testing.T, queueFile string) *DosDataNode {
+	t.Helper()
+	dir := t.TempDir()
+	dbFile := filepath.Join(dir, "data.db")
+	if len(queueFile) == 0 {
+		queueFile = dbFile
+	} else {
+		queueFile = filepath.Join(dir, queueFile)
+	}
+	store, err := NewDataNodeSqlStore(dbFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	queue, err := NewDataNodeSqlQueue(queueFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { queue.Close() })
+	return &DosDataNode{
+		me:          "test",
+		logger:      log.New(io.Discard, "", 0),
+		store:       store,
+		queue:       queue,
+		leaser:      &DataNodeLeaseService{},
+		config:      defaultDataNodeConfig(),
+		identity:    &DataNodeIdentity{},
+		lastLamport: namenode.NewLamport(1, 0),
+		fence:       testFence,
+	}
+}
+
+// stamp addresses the command to the node's session
+func stamp(d *DosDataNode, cmd *api.CommandNodeRes) *api.CommandNodeRes {
+	cmd.Fence = uint64(testFence)
+	cmd.Epoch = d.lastLamport.Epoch()
+	return cmd
+}
+
+// send hands the command to the node as if it arrived on its session and returns the replies
+func send(t *testing.T, d *DosDataNode, cmd *api.CommandNodeRes) []*api.NodeHeartBeat {
+	t.Helper()
+	stamp(d, cmd)
+	replies := make([]*api.NodeHeartBeat, 0)
+	if err := d.handle(cmd, func(message *api.NodeHeartBeat) {
+		replies = append(replies, message)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return replies
+}
+
+func createCmd(requestId uint64, object string) *api.CommandNodeRes {
+	return &api.CommandNodeRes{
+		RequestId: requestId,
+		Command:   api.CommandNodeRes_CREATE,
+		Create:    &api.CreateCommand{ObjectName: object, ObjectData: []byte(object)},
+	}
+}
+
+func commitCmd(requestId uint64, object string, counter uint32) *api.CommandNodeRes {
+	return &api.CommandNodeRes{
+		RequestId: requestId,
+		Command:   api.CommandNodeRes_COMMIT,
+		Commit:    &api.CommitCommand{ObjectName: object, Lamport: uint64(namenode.NewLamport(1, counter))},
+	}
+}
+
+func TestBlockedCommandsAreDelivered(t *testing.T) {
+	tests := []struct {
+		name      string
+		queueFile string
+		framed    bool
+	}{
+		{name: "queue in the store's file", queueFile: ""},
+		{name: "queue in a file of its own", queueFile: "queue.db"},
+		{name: "unblocked by a batch frame", queueFile: "", framed: true},
+		{name: "unblocked by a batch frame, queue in a file of its own", queueFile: "queue.db", framed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDataNode(t, tt.queueFile)
+			send(t, d, createCmd(1, "a"))
+			send(t, d, createCmd(2, "b"))
+
+			// b's commit arrives ahead of a's and waits for it
+			if replies := send(t, d, commitCmd(3, "b", 2)); len(replies) != 0 {
+				t.Fatalf("blocked commit was answered: %v", replies)
+			}
+			var replies []*api.NodeHeartBeat
+			if tt.framed {
+				var err error
+				if replies, err = d.applyBatch([]*api.CommandNodeRes{stamp(d, commitCmd(4, "a", 1))}); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				replies = send(t, d, commitCmd(4, "a", 1))
+			}
+			if len(replies) != 2 || replies[0].RequestId != 4 || replies[1].RequestId != 3 {
+				t.Fatalf("replies = %v, want the acks of 4 then 3", replies)
+			}
+			for _, reply := range replies {
+				if reply.Code != api.NodeHeartBeat_OK {
+					t.Errorf("request %d failed: %s", reply.RequestId, reply.Error)
+				}
+			}
+
+			if _, _, err := d.queue.RetrieveCommand(); err != ErrNoCommandToRetrieve {
+				t.Errorf("queue still holds a command: %v", err)
+			}
+			stored, err := d.store.LastLamport()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := namenode.NewLamport(1, 2); stored != want || d.lastLamport != want {
+				t.Errorf("lamport = %s, stored %s, want %s", d.lastLamport, stored, want)
+			}
+			objects, _ := d.store.Objects()
+			if len(objects) != 2 {
+				t.Errorf("objects = %v, want a and b", objects)
+			}
+		})
+	}
+}
+
+func TestAppliedBlockedCommandIsDropped(t *testing.T) {
+	d := newTestDataNode(t, "queue.db")
+	// a crash after the command was applied and before it left the queue
+	stale := namenode.NewLamport(1, 1)
+	if err := d.queue.BlockCommand(stale, namenode.CommitCommand{Name: "a", Type: namenode.COMMIT, RequestId: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.store.SetLastLamport(stale); err != nil {
+		t.Fatal(err)
+	}
+	d.lastLamport = stale
+
+	send(t, d, createCmd(2, "b"))
+	replies := send(t, d, commitCmd(3, "b", 2))
+	if len(replies) != 1 || replies[0].RequestId != 3 {
+		t.Fatalf("replies = %v, want the ack of 3 only", replies)
+	}
+	if _, _, err := d.queue.RetrieveCommand(); err != ErrNoCommandToRetrieve {
+		t.Errorf("stale command is still queued: %v", err)
+	}
+}
+
+func TestResync(t *testing.T) {
+	tests := []struct {
+		name    string
+		cut     namenode.Lamport
+		suspect bool
+		want    namenode.Lamport
+		blocked bool // the blocked command is still queued afterwards
+	}{
+		{name: "in step", cut: namenode.NewLamport(1, 1), want: namenode.NewLamport(1, 1), blocked: true},
+		{name: "namenode restarted", cut: namenode.NewLamport(2, 0), want: namenode.NewLamport(2, 0)},
+		{name: "namenode restarted and sent commands", cut: namenode.NewLamport(2, 7), want: namenode.NewLamport(2, 7)},
+		{name: "missed commands nobody can replay", cut: namenode.NewLamport(1, 5), suspect: true, want: namenode.NewLamport(1, 5)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDataNode(t, "")
+			send(t, d, createCmd(1, "a"))
+			send(t, d, commitCmd(2, "a", 1))
+			// waits on a command of the old epoch that is never sent
+			send(t, d, commitCmd(3, "a", 3))
+
+			if err := d.resync(tt.cut, tt.suspect); err != nil {
+				t.Fatal(err)
+			}
+			stored, err := d.store.LastLamport()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.lastLamport != tt.want || stored != tt.want {
+				t.Errorf("lamport = %s, stored %s, want %s", d.lastLamport, stored, tt.want)
+			}
+			_, _, err = d.queue.RetrieveCommand()
+			if blocked := err != ErrNoCommandToRetrieve; blocked != tt.blocked {
+				t.Errorf("blocked command queued = %v, want %v", blocked, tt.blocked)
+			}
+			// a suspect store is wiped, any other is resumed
+			if objects, _ := d.store.Objects(); len(objects) == 0 != tt.suspect {
+				t.Errorf("objects = %v after a resync that wiped it: %v", objects, tt.suspect)
+			}
+		})
+	}
+}
+
+func TestRefusedCommands(t *testing.T) {
+	tests := []struct {
+		name  string
+		fence namenode.FencingToken // of the node
+		cmd   func(cmd *api.CommandNodeRes)
+		want  error
+	}{
+		{name: "node is fenced", fence: 0, cmd: func(cmd *api.CommandNodeRes) {}, want: ErrFenced},
+		{name: "token of a revoked session", fence: testFence + 1, cmd: func(cmd *api.CommandNodeRes) {}, want: ErrFenced},
+		{name: "no token", fence: testFence, cmd: func(cmd *api.CommandNodeRes) { cmd.Fence = 0 }, want: ErrFenced},
+		{name: "another epoch", fence: testFence, cmd: func(cmd *api.CommandNodeRes) { cmd.Epoch = 2 }, want: ErrEpochMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDataNode(t, "")
+			d.fence = tt.fence
+			cmd := createCmd(1, "a")
+			cmd.Fence = uint64(testFence)
+			cmd.Epoch = d.lastLamport.Epoch()
+			tt.cmd(cmd)
+
+			replied := false
+			err := d.handle(cmd, func(message *api.NodeHeartBeat) { replied = true })
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if replied {
+				t.Error("a refused command was answered")
+			}
+			if objects, _ := d.store.Objects(); len(objects) != 0 {
+				t.Errorf("a refused command was applied: %v", objects)
+			}
+		})
+	}
+}
+func TestCommitOnItsNodes(t *testing.T) {
+	tests := []struct {
+		name      string
+		nodes     []string
+		blocked   bool // arrives ahead of the command before it
+		committed bool
+	}{
+		{name: "every node", committed: true},
+		{name: "this node", nodes: []string{"other", "test"}, committed: true},
+		{name: "other nodes", nodes: []string{"other"}},
+		{name: "other nodes, blocked", nodes: []string{"other"}, blocked: true},
+		{name: "this node, blocked", nodes: []string{"test"}, blocked: true, committed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDataNode(t, "")
+			// staged by a create that may have failed
+			send(t, d, createCmd(1, "a"))
+			commit := commitCmd(2, "a", 1)
+			commit.Commit.Nodes = tt.nodes
+			if tt.blocked {
+				commit.Commit.Lamport = uint64(namenode.NewLamport(1, 2))
+				send(t, d, commit)
+				send(t, d, &api.CommandNodeRes{
+					RequestId: 3,
+					Command:   api.CommandNodeRes_DELETE,
+					Delete:    &api.DeleteCommand{ObjectName: "b", Lamport: uint64(namenode.NewLamport(1, 1))},
+				})
+			} else {
+				send(t, d, commit)
+			}
+
+			if _, _, err := d.store.Get("a"); (err == nil) != tt.committed {
+				t.Errorf("get = %v, want committed %v", err, tt.committed)
+			}
+			if lamport, _ := d.store.LastLamport(); lamport != namenode.Lamport(commit.Commit.Lamport) {
+				t.Errorf("lamport = %s, want the commit's recorded", lamport)
+			}
+		})
+	}
+}
+
+func TestRegisterKeepsLeaseToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		registered namenode.FencingToken // handed out by the namenode
+	}{
+		{name: "token resumed", registered: testFence},
+		{name: "token revoked", registered: testFence + 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDataNode(t, "")
+			d.leaser.Unfence(testFence)
+			// the stream was lost, the lease service went on publishing under its token
+			d.fence = 0
+
+			err := d.handle(&api.CommandNodeRes{
+				Command:  api.CommandNodeRes_REGISTER,
+				Fence:    uint64(tt.registered),
+				Register: &api.RegisterCommand{Lamport: uint64(d.lastLamport)},
+			}, func(message *api.NodeHeartBeat) {})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.fence != tt.registered || d.leaser.Token() != tt.registered {
+				t.Errorf("serving under %s, publishing under %s, want %s", d.fence, d.leaser.Token(), tt.registered)
+			}
+		})
+	}
+}
+
+// failingStore hands out batches that fail to stage one object, or to apply
+type failingStore struct {
+	DataNodeStore
+	stage string
+	apply bool
+}
+
+func (s failingStore) Begin() (DataNodeBatch, error) {
+	batch, err := s.DataNodeStore.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return failingBatch{batch, s}, nil
+}
+
+type failingBatch struct {
+	DataNodeBatch
+	store failingStore
+}
+
+func (b failingBatch) Stage(object string, data []byte) error {
+	if object == b.store.stage {
+		return fmt.Errorf("failed to stage %s: %w", object, syscall.ENOSPC)
+	}
+	return b.DataNodeBatch.Stage(object, data)
+}
+
+func (b failingBatch) Apply() error {
+	if b.store.apply {
+		return fmt.Errorf("failed to apply batch: %w", syscall.ENOSPC)
+	}
+	return b.DataNodeBatch.Apply()
+}
+
+func TestBatchIsAppliedTogether(t *testing.T) {
+	tests := []struct {
+		name  string
+		fails bool
+		want  api.NodeHeartBeat_Code
+	}{
+		{name: "applied", want: api.NodeHeartBeat_OK},
+		{name: "failing to apply", fails: true, want: api.NodeHeartBeat_DISK_FULL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDataNode(t, "")
+			send(t, d, createCmd(1, "a"))
+			if tt.fails {
+				d.store = failingStore{DataNodeStore: d.store, apply: true}
+			}
+
+			replies, err := d.applyBatch([]*api.CommandNodeRes{
+				stamp(d, commitCmd(2, "a", 1)),
+				stamp(d, createCmd(3, "b")),
+				stamp(d, commitCmd(4, "b", 2)),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(replies) != 3 {
+				t.Fatalf("replies = %v, want one per command", replies)
+			}
+			for _, reply := range replies {
+				if reply.Code != tt.want || (len(reply.Error) != 0) != tt.fails {
+					t.Errorf("request %d answered %s (%s), want %s", reply.RequestId, reply.Code, reply.Error, tt.want)
+				}
+			}
+			objects, _ := d.store.Objects()
+			if tt.fails && len(objects) != 0 || !tt.fails && len(objects) != 2 {
+				t.Errorf("objects = %v", objects)
+			}
+			// a failed batch is delivered like any failed command
+			if lamport, _ := d.store.LastLamport(); lamport != namenode.NewLamport(1, 2) {
+				t.Errorf("lamport = %s, want the batch's last", lamport)
+			}
+		})
+	}
+}
+
+func TestBatchIsAckedPartially(t *testing.T) {
+	tests := []struct {
+		name    string
+		batch   []*api.CommandNodeRes
+		want    []api.NodeHeartBeat_Code
+		err     error
+		objects int
+	}{
+		{
+			name:    "a failing command",
+			batch:   []*api.CommandNodeRes{createCmd(1, "a"), createCmd(2, "full"), commitCmd(3, "a", 1)},
+			want:    []api.NodeHeartBeat_Code{api.NodeHeartBeat_OK, api.NodeHeartBeat_DISK_FULL, api.NodeHeartBeat_OK},
+			objects: 1,
+		},
+		{
+			name:    "a command of another epoch",
+			batch:   []*api.CommandNodeRes{createCmd(1, "a"), commitCmd(2, "a", 1), {RequestId: 3, Command: api.CommandNodeRes_DECOMMISSION, Epoch: 2}},
+			want:    []api.NodeHeartBeat_Code{api.NodeHeartBeat_OK, api.NodeHeartBeat_OK},
+			err:     ErrEpochMismatch,
+			objects: 1,
+		},
+		{
+			name:    "decommissioned",
+			batch:   []*api.CommandNodeRes{createCmd(1, "a"), commitCmd(2, "a", 1), {RequestId: 3, Command: api.CommandNodeRes_DECOMMISSION}},
+			want:    []api.NodeHeartBeat_Code{api.NodeHeartBeat_OK, api.NodeHeartBeat_OK, api.NodeHeartBeat_OK},
+			err:     ErrDecommissioned,
+			objects: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDataNode(t, "")
+			d.store = failingStore{DataNodeStore: d.store, stage: "full"}
+			// a command given an epoch keeps it
+			for _, cmd := range tt.batch {
+				epoch := cmd.Epoch
+				stamp(d, cmd)
+				if epoch != 0 {
+					cmd.Epoch = epoch
+				}
+			}
+
+			replies, err := d.applyBatch(tt.batch)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			codes := make([]api.NodeHeartBeat_Code, 0, len(replies))
+			for _, reply := range replies {
+				codes = append(codes, reply.Code)
+			}
+			if !slices.Equal(codes, tt.want) {
+				t.Errorf("codes = %v, want %v", codes, tt.want)
+			}
+			// the commands answered are applied even when the session ends
+			if objects, _ := d.store.Objects(); len(objects) != tt.objects {
+				t.Errorf("objects = %v, want %d", objects, tt.objects)
+			}
+		})
+	}
+}
+
+func TestRepliesCarryRequestIds(t *testing.T) {
+	d := newTestDataNode(t, "")
+	// ids are unique per command, not per object, so two commands of a share nothing but the object
+	replies, err := d.applyBatch([]*api.CommandNodeRes{
+		stamp(d, createCmd(10, "a")),
+		stamp(d, createCmd(11, "b")),
+		// b's commit arrives ahead of a's and is answered once it is delivered
+		stamp(d, commitCmd(13, "b", 2)),
+		stamp(d, commitCmd(12, "a", 1)),
+		// a resent commit is answered under the id it was resent with
+		stamp(d, commitCmd(14, "a", 1)),
+		stamp(d, &api.CommandNodeRes{
+			RequestId:       15,
+			Command:         api.CommandNodeRes_DISTRIBUTED_READ,
+			DistributedRead: &api.DistributedReadCommand{Objects: []string{"a"}, Lamport: uint64(namenode.NewLamport(1, 3))},
+		}),
+		// and so is a resent read
+		stamp(d, &api.CommandNodeRes{
+			RequestId:       16,
+			Command:         api.CommandNodeRes_DISTRIBUTED_READ,
+			DistributedRead: &api.DistributedReadCommand{Objects: []string{"a"}, Lamport: uint64(namenode.NewLamport(1, 3))},
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]uint64, 0, len(replies))
+	for _, reply := range replies {
+		ids = append(ids, reply.RequestId)
+		if reply.Code != api.NodeHeartBeat_OK {
+			t.Errorf("request %d failed: %s", reply.RequestId, reply.Error)
+		}
+	}
+	if want := []uint64{10, 11, 12, 13, 14, 15, 16}; !slices.Equal(ids, want) {
+		t.Errorf("replies answer %v, want %v", ids, want)
+	}
+	for _, reply := range replies[5:] {
+		if reply.Type != api.NodeHeartBeat_DISTRIUTED_READ || len(reply.ObjectData) != 1 {
+			t.Errorf("read %d answered %v", reply.RequestId, reply)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package datanode
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/mrowaha/dos/api"
+	"github.com/mrowaha/dos/namenode"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const decommissionRequest = 3
+
+// leavingNameNode registers a datanode and decommissions it, then records every ack until the node ends the stream
+type leavingNameNode struct {
+	api.UnimplementedDataServiceServer
+	framed bool
+	acked  chan []uint64
+}
+
+func (n *leavingNameNode) RegisterNode(stream api.DataService_RegisterNodeServer) error {
+	if _, err := stream.Recv(); err != nil {
+		return err
+	}
+	err := stream.Send(&api.CommandNodeRes{
+		RequestId: 1,
+		Command:   api.CommandNodeRes_REGISTER,
+		Register:  &api.RegisterCommand{Lamport: uint64(namenode.NewLamport(1, 0))},
+		Fence:     uint64(testFence),
+	})
+	if err != nil {
+		return err
+	}
+
+	decommission := &api.CommandNodeRes{RequestId: decommissionRequest, Command: api.CommandNodeRes_DECOMMISSION}
+	frame := decommission
+	if n.framed {
+		// a commit of nothing staged, queued on the session ahead of the decommission
+		commit := commitCmd(2, "a", 1)
+		commit.Epoch, commit.Fence = 1, uint64(testFence)
+		frame = &api.CommandNodeRes{Command: api.CommandNodeRes_BATCH, Batch: []*api.CommandNodeRes{commit, decommission}}
+	}
+	decommission.Epoch, decommission.Fence = 1, uint64(testFence)
+	frame.Epoch, frame.Fence = 1, uint64(testFence)
+	if err := stream.Send(frame); err != nil {
+		return err
+	}
+
+	acked := make([]uint64, 0)
+	defer func() { n.acked <- acked }()
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			// the datanode half closed the stream, ending it answers its last receive
+			return nil
+		}
+		if req.Type == api.NodeHeartBeat_ACK {
+			acked = append(acked, req.RequestId)
+		}
+		for _, res := range req.Batch {
+			if res.Type == api.NodeHeartBeat_ACK {
+				acked = append(acked, res.RequestId)
+			}
+		}
+	}
+}
+
+func TestDecommissionIsAcknowledged(t *testing.T) {
+	tests := []struct {
+		name   string
+		framed bool
+	}{
+		{name: "single command"},
+		{name: "last of a batch frame", framed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namenode := &leavingNameNode{framed: tt.framed, acked: make(chan []uint64, 1)}
+			listener := bufconn.Listen(1 << 16)
+			server := grpc.NewServer()
+			api.RegisterDataServiceServer(server, namenode)
+			go server.Serve(listener)
+			t.Cleanup(server.Stop)
+
+			conn, err := grpc.NewClient("passthrough:///namenode",
+				grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+					return listener.DialContext(ctx)
+				}),
+				grpc.WithTransportCredentials(insecure.NewCredentials()),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { conn.Close() })
+
+			d := newTestDataNode(t, "")
+			d.client = api.NewDataServiceClient(conn)
+			d.fence = 0
+
+			if err := d.session(); !errors.Is(err, ErrDecommissioned) {
+				t.Fatalf("session ended with %v, want %v", err, ErrDecommissioned)
+			}
+			if acked := <-namenode.acked; !slices.Contains(acked, decommissionRequest) {
+				t.Errorf("namenode got acks for %v, the decommission is not among them", acked)
+			}
+		})
+	}
+}
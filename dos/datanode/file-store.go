@@ -0,0 +1,568 @@
+package datanode
+
+/*
+
+This file contains a plain filesystem backend for the datanode store
+every object is a single file holding its sequence on the first line followed by its data
+files are replaced through a temp file and an atomic rename, so a crash never leaves a torn object
+
+commands touch more than one file (the object, snapshot copies and the last applied lamport),
+so each command first writes an intent to the journal. the journal is replayed on startup,
+which makes a command either fully applied together with its lamport or not applied at all
+
+layout of the store directory
+	lamport                                       last applied lamport
+	journal                                       intent of the command being applied
+	staged/<escaped name>.obj                     creates waiting for their commit
+	objects/<escaped name>.obj
+	snapshots/s-<snapshot>/lamport
+	snapshots/s-<snapshot>/<escaped name>.obj     copy of the object when the snapshot was taken
+	snapshots/s-<snapshot>/<escaped name>.absent  object did not exist when the snapshot was taken
+
+snapshot names are escaped and prefixed, so no name, not even an empty or dot-only one, resolves outside its own dir
+*/
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mrowaha/dos/api"
+	"github.com/mrowaha/dos/namenode"
+)
+
+const (
+	objectSuffix   = ".obj"
+	absentSuffix   = ".absent"
+	snapshotPrefix = "s-"
+)
+
+type DataNodeFileStore struct {
+	dir  string
+	lock sync.Mutex
+}
+
+func NewDataNodeFileStore(dir string) (*DataNodeFileStore, error) {
+	for _, sub := range []string{"objects", "snapshots", "staged"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return nil, fmt.Errorf("failed to bootstrap file store: %w", err)
+		}
+	}
+	store := &DataNodeFileStore{dir: dir}
+	if err := store.recover(); err != nil {
+		return nil, fmt.Errorf("failed to recover file store journal: %w", err)
+	}
+	return store, nil
+}
+
+func objectFileName(object string) string {
+	return url.PathEscape(object) + objectSuffix
+}
+
+func (s *DataNodeFileStore) objectPath(object string) string {
+	return filepath.Join(s.dir, "objects", objectFileName(object))
+}
+
+func (s *DataNodeFileStore) snapshotPath(snapshot string) string {
+	return filepath.Join(s.dir, "snapshots", snapshotPrefix+url.PathEscape(snapshot))
+}
+
+// writeAtomic writes the file next to its destination and renames it into place
+func writeAtomic(path string, content []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func encodeObject(sequence int, data []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(strconv.Itoa(sequence))
+	buf.WriteByte('\n')
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+func readObjectFile(path string) ([]byte, int, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, 0, ErrObjectNotInStore
+		}
+		return nil, 0, err
+	}
+	header, data, ok := bytes.Cut(content, []byte{'\n'})
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: object file %s", ErrCorruptStore, path)
+	}
+	sequence, err := strconv.Atoi(string(header))
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: object file %s: %v", ErrCorruptStore, path, err)
+	}
+	return data, sequence, nil
+}
+
+func (s *DataNodeFileStore) snapshots() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dir, "snapshots"))
+	if err != nil {
+		return nil, err
+	}
+	snapshots := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), snapshotPrefix) {
+			snapshots = append(snapshots, filepath.Join(s.dir, "snapshots", entry.Name()))
+		}
+	}
+	return snapshots, nil
+}
+
+// preserve copies the current file of object into every snapshot that does not hold it yet
+// if the object does not exist, the snapshot records it as absent
+func (s *DataNodeFileStore) preserve(object string) error {
+	snapshots, err := s.snapshots()
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(s.objectPath(object))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	absent := err != nil
+
+	name := url.PathEscape(object)
+	for _, snapshot := range snapshots {
+		copied := filepath.Join(snapshot, name+objectSuffix)
+		marker := filepath.Join(snapshot, name+absentSuffix)
+		if fileExists(copied) || fileExists(marker) {
+			continue
+		}
+		if absent {
+			err = writeAtomic(marker, nil)
+		} else {
+			err = writeAtomic(copied, content)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to preserve object %s for snapshots: %w", object, err)
+		}
+	}
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func (s *DataNodeFileStore) Write(object string, data []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if fileExists(s.objectPath(object)) {
+		return 0, ErrObjectAlreadyInStore
+	}
+	if err := s.preserve(object); err != nil {
+		return 0, err
+	}
+	if err := writeAtomic(s.objectPath(object), encodeObject(1, data)); err != nil {
+		return 0, fmt.Errorf("failed to write object file: %w", err)
+	}
+	return 1, nil
+}
+
+func (s *DataNodeFileStore) stagedPath(object string) string {
+	return filepath.Join(s.dir, "staged", objectFileName(object))
+}
+
+// a later stage of the same object replaces the earlier one, the latest create wins
+func (s *DataNodeFileStore) Stage(object string, data []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := writeAtomic(s.stagedPath(object), encodeObject(1, data)); err != nil {
+		return fmt.Errorf("failed to stage object %s: %w", object, err)
+	}
+	return nil
+}
+
+func (s *DataNodeFileStore) Commit(object string, lamport namenode.Lamport) ([]byte, int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	data, _, err := readObjectFile(s.stagedPath(object))
+	if err == ErrObjectNotInStore {
+		return nil, 0, ErrNothingStaged
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	if fileExists(s.objectPath(object)) {
+		return nil, 0, ErrObjectAlreadyInStore
+	}
+
+	err = s.apply(&fileStoreIntent{Lamport: lamport, Op: intentCommit, Object: object, Data: data, Sequence: 1})
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, 1, nil
+}
+
+func (s *DataNodeFileStore) Update(object string, data []byte, lamport namenode.Lamport) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, sequence, err := readObjectFile(s.objectPath(object))
+	if err != nil {
+		return 0, err
+	}
+	sequence++
+	err = s.apply(&fileStoreIntent{Lamport: lamport, Op: intentUpdate, Object: object, Data: data, Sequence: sequence})
+	if err != nil {
+		return 0, err
+	}
+	return sequence, nil
+}
+
+func (s *DataNodeFileStore) Delete(object string, lamport namenode.Lamport) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, sequence, err := readObjectFile(s.objectPath(object))
+	if err != nil {
+		return 0, err
+	}
+	err = s.apply(&fileStoreIntent{Lamport: lamport, Op: intentDelete, Object: object})
+	if err != nil {
+		return 0, err
+	}
+	return sequence, nil
+}
+
+func (s *DataNodeFileStore) LastLamport() (namenode.Lamport, error) {
+	content, err := os.ReadFile(filepath.Join(s.dir, "lamport"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read last lamport: %w", err)
+	}
+	lamport, err := strconv.ParseUint(string(content), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: lamport file: %v", ErrCorruptStore, err)
+	}
+	return namenode.Lamport(lamport), nil
+}
+
+func (s *DataNodeFileStore) SetLastLamport(lamport namenode.Lamport) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.setLamport(lamport)
+}
+
+func (s *DataNodeFileStore) setLamport(lamport namenode.Lamport) error {
+	if err := writeAtomic(filepath.Join(s.dir, "lamport"), []byte(strconv.FormatUint(uint64(lamport), 10))); err != nil {
+		return fmt.Errorf("failed to record lamport %s: %w", lamport, err)
+	}
+	return nil
+}
+
+func (s *DataNodeFileStore) Wipe(lamport namenode.Lamport) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.apply(&fileStoreIntent{Lamport: lamport, Op: intentWipe})
+}
+
+const (
+	intentCommit         = "commit"
+	intentUpdate         = "update"
+	intentDelete         = "delete"
+	intentSnapshot       = "snapshot"
+	intentDeleteSnapshot = "delete-snapshot"
+	intentWipe           = "wipe"
+)
+
+type fileStoreIntent struct {
+	Lamport  namenode.Lamport `json:"lamport"`
+	Op       string           `json:"op"`
+	Object   string           `json:"object,omitempty"`
+	Snapshot string           `json:"snapshot,omitempty"`
+	Data     []byte           `json:"data,omitempty"`
+	Sequence int              `json:"sequence,omitempty"`
+}
+
+func (s *DataNodeFileStore) journalPath() string {
+	return filepath.Join(s.dir, "journal")
+}
+
+// apply journals the intent, performs it and records its lamport
+// it must be called with the lock held
+func (s *DataNodeFileStore) apply(intent *fileStoreIntent) error {
+	content, err := json.Marshal(intent)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s intent: %w", intent.Op, err)
+	}
+	if err := writeAtomic(s.journalPath(), content); err != nil {
+		return fmt.Errorf("failed to journal %s intent: %w", intent.Op, err)
+	}
+	return s.redo(intent)
+}
+
+// redo performs a journaled intent. every step is idempotent so an intent
+// interrupted by a crash can be performed again from the start
+func (s *DataNodeFileStore) redo(intent *fileStoreIntent) error {
+	var err error
+	switch intent.Op {
+	case intentCommit:
+		if err = s.preserve(intent.Object); err == nil {
+			err = writeAtomic(s.objectPath(intent.Object), encodeObject(intent.Sequence, intent.Data))
+		}
+		if err == nil {
+			err = os.Remove(s.stagedPath(intent.Object))
+		}
+	case intentUpdate:
+		if err = s.preserve(intent.Object); err == nil {
+			err = writeAtomic(s.objectPath(intent.Object), encodeObject(intent.Sequence, intent.Data))
+		}
+	case intentDelete:
+		if err = s.preserve(intent.Object); err == nil {
+			err = os.Remove(s.objectPath(intent.Object))
+		}
+	case intentSnapshot:
+		err = s.createSnapshot(intent.Snapshot, intent.Lamport)
+	case intentDeleteSnapshot:
+		err = os.RemoveAll(s.snapshotPath(intent.Snapshot))
+	case intentWipe:
+		for _, sub := range []string{"objects", "snapshots", "staged"} {
+			if err = os.RemoveAll(filepath.Join(s.dir, sub)); err != nil {
+				break
+			}
+			if err = os.MkdirAll(filepath.Join(s.dir, sub), 0755); err != nil {
+				break
+			}
+		}
+	default:
+		err = fmt.Errorf("unknown intent %s", intent.Op)
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to apply %s @lamport%s: %w", intent.Op, intent.Lamport, err)
+	}
+
+	if err := s.setLamport(intent.Lamport); err != nil {
+		return err
+	}
+	if err := os.Remove(s.journalPath()); err != nil {
+		return fmt.Errorf("failed to clear journal: %w", err)
+	}
+	return nil
+}
+
+func (s *DataNodeFileStore) recover() error {
+	content, err := os.ReadFile(s.journalPath())
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var intent fileStoreIntent
+	if err := json.Unmarshal(content, &intent); err != nil {
+		return fmt.Errorf("%w: journal: %v", ErrCorruptStore, err)
+	}
+	return s.redo(&intent)
+}
+
+func (s *DataNodeFileStore) Get(object string) ([]byte, int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return readObjectFile(s.objectPath(object))
+}
+
+func (s *DataNodeFileStore) Objects() ([]string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.objects()
+}
+
+func (s *DataNodeFileStore) objects() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dir, "objects"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %w", err)
+	}
+	var objects []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), objectSuffix)
+		if !ok {
+			continue
+		}
+		object, err := url.PathUnescape(name)
+		if err != nil {
+			continue
+		}
+		objects = append(objects, object)
+	}
+	slices.Sort(objects)
+	return objects, nil
+}
+
+func (s *DataNodeFileStore) ObjectsWithData(objects []string) ([]*api.NodeHeartBeat_Object, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	result := make([]*api.NodeHeartBeat_Object, 0, len(objects))
+	for _, object := range objects {
+		data, _, err := readObjectFile(s.objectPath(object))
+		if err == ErrObjectNotInStore {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &api.NodeHeartBeat_Object{Name: object, Data: data})
+	}
+	return result, nil
+}
+
+func (s *DataNodeFileStore) Size() (float32, error) {
+	var size int64
+	err := filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return 0, errors.New("error getting store size info")
+	}
+	return float32(size), nil
+}
+
+func (s *DataNodeFileStore) Iterate(fn func(object string, data []byte, sequence int) error) error {
+	s.lock.Lock()
+	objects, err := s.objects()
+	s.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, object := range objects {
+		data, sequence, err := s.Get(object)
+		if err == ErrObjectNotInStore {
+			// deleted while iterating
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(object, data, sequence); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *DataNodeFileStore) CreateSnapshot(name string, lamport namenode.Lamport) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.apply(&fileStoreIntent{Lamport: lamport, Op: intentSnapshot, Snapshot: name})
+}
+
+func (s *DataNodeFileStore) createSnapshot(name string, lamport namenode.Lamport) error {
+	path := s.snapshotPath(name)
+	if fileExists(path) {
+		return nil
+	}
+	// the snapshot is built in a temp dir so it only becomes visible once complete
+	tmp, err := os.MkdirTemp(filepath.Join(s.dir, "snapshots"), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot %s: %w", name, err)
+	}
+	if err := writeAtomic(filepath.Join(tmp, "lamport"), []byte(strconv.FormatUint(uint64(lamport), 10))); err != nil {
+		os.RemoveAll(tmp)
+		return fmt.Errorf("failed to create snapshot %s: %w", name, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.RemoveAll(tmp)
+		return fmt.Errorf("failed to create snapshot %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *DataNodeFileStore) DeleteSnapshot(name string, lamport namenode.Lamport) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !fileExists(s.snapshotPath(name)) {
+		return ErrSnapshotNotInStore
+	}
+	return s.apply(&fileStoreIntent{Lamport: lamport, Op: intentDeleteSnapshot, Snapshot: name})
+}
+
+func (s *DataNodeFileStore) SnapshotObjectsWithData(snapshot string, objects []string) ([]*api.NodeHeartBeat_Object, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	path := s.snapshotPath(snapshot)
+	if !fileExists(path) {
+		return nil, ErrSnapshotNotInStore
+	}
+
+	result := make([]*api.NodeHeartBeat_Object, 0, len(objects))
+	for _, object := range objects {
+		name := url.PathEscape(object)
+		if fileExists(filepath.Join(path, name+absentSuffix)) {
+			continue
+		}
+		data, _, err := readObjectFile(filepath.Join(path, name+objectSuffix))
+		if err == ErrObjectNotInStore {
+			// not mutated since the snapshot
+			data, _, err = readObjectFile(s.objectPath(object))
+		}
+		if err == ErrObjectNotInStore {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &api.NodeHeartBeat_Object{Name: object, Data: data})
+	}
+	return result, nil
+}
+
+func (s *DataNodeFileStore) Close() error {
+	return nil
+}
+
+// every mutation of the file store is durable on its own
+func (s *DataNodeFileStore) Begin() (DataNodeBatch, error) {
+	return passthroughBatch{s}, nil
+}
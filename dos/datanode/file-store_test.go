@@ -0,0 +1,177 @@
+package datanode
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/mrowaha/dos/namenode"
+)
+
+// journal leaves intent in the journal of the store in dir, like a crash before the intent was cleared
+func journal(t *testing.T, dir string, intent fileStoreIntent) {
+	t.Helper()
+	content, err := json.Marshal(intent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile((&DataNodeFileStore{dir: dir}).journalPath(), content, 0666); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileStoreJournalRedo(t *testing.T) {
+	tests := []struct {
+		name string
+		// crash runs commands on the store and leaves an intent in the journal
+		crash    func(t *testing.T, dir string, store *DataNodeFileStore)
+		lamport  namenode.Lamport
+		objects  map[string]string
+		snapshot map[string]string // what snap reads, nil when there is no snapshot
+	}{
+		{
+			name: "commit journaled, nothing applied",
+			crash: func(t *testing.T, dir string, store *DataNodeFileStore) {
+				store.Stage("a", []byte("one"))
+				journal(t, dir, fileStoreIntent{Lamport: namenode.NewLamport(1, 1), Op: intentCommit, Object: "a", Data: []byte("one"), Sequence: 1})
+			},
+			lamport: namenode.NewLamport(1, 1),
+			objects: map[string]string{"a": "one"},
+		},
+		{
+			name: "commit applied, lamport not recorded",
+			crash: func(t *testing.T, dir string, store *DataNodeFileStore) {
+				store.Stage("a", []byte("one"))
+				store.Commit("a", namenode.NewLamport(1, 1))
+				store.SetLastLamport(0)
+				journal(t, dir, fileStoreIntent{Lamport: namenode.NewLamport(1, 1), Op: intentCommit, Object: "a", Data: []byte("one"), Sequence: 1})
+			},
+			lamport: namenode.NewLamport(1, 1),
+			objects: map[string]string{"a": "one"},
+		},
+		{
+			name: "update applied after its snapshot copy",
+			crash: func(t *testing.T, dir string, store *DataNodeFileStore) {
+				store.Write("a", []byte("one"))
+				store.CreateSnapshot("snap", namenode.NewLamport(1, 1))
+				store.Update("a", []byte("two"), namenode.NewLamport(1, 2))
+				journal(t, dir, fileStoreIntent{Lamport: namenode.NewLamport(1, 2), Op: intentUpdate, Object: "a", Data: []byte("two"), Sequence: 2})
+			},
+			lamport:  namenode.NewLamport(1, 2),
+			objects:  map[string]string{"a": "two"},
+			snapshot: map[string]string{"a": "one"},
+		},
+		{
+			name: "delete of an object already gone",
+			crash: func(t *testing.T, dir string, store *DataNodeFileStore) {
+				store.Write("a", []byte("one"))
+				store.Write("b", []byte("bee"))
+				store.Delete("a", namenode.NewLamport(1, 1))
+				journal(t, dir, fileStoreIntent{Lamport: namenode.NewLamport(1, 1), Op: intentDelete, Object: "a"})
+			},
+			lamport: namenode.NewLamport(1, 1),
+			objects: map[string]string{"b": "bee"},
+		},
+		{
+			name: "wipe journaled",
+			crash: func(t *testing.T, dir string, store *DataNodeFileStore) {
+				store.Write("a", []byte("one"))
+				store.CreateSnapshot("snap", namenode.NewLamport(1, 1))
+				journal(t, dir, fileStoreIntent{Lamport: namenode.NewLamport(2, 3), Op: intentWipe})
+			},
+			lamport: namenode.NewLamport(2, 3),
+			objects: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			store, err := NewDataNodeFileStore(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.crash(t, dir, store)
+
+			store, err = NewDataNodeFileStore(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(store.journalPath()); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("journal was not cleared: %v", err)
+			}
+			if lamport, _ := store.LastLamport(); lamport != tt.lamport {
+				t.Errorf("lamport = %s, want %s", lamport, tt.lamport)
+			}
+			objects, _ := store.Objects()
+			if len(objects) != len(tt.objects) {
+				t.Errorf("objects = %v, want %v", objects, tt.objects)
+			}
+			for object, want := range tt.objects {
+				if data, _, err := store.Get(object); err != nil || string(data) != want {
+					t.Errorf("%s = %q, %v, want %q", object, data, err, want)
+				}
+			}
+			if tt.snapshot != nil {
+				read, err := store.SnapshotObjectsWithData("snap", objects)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for object, want := range tt.snapshot {
+					if got := objectData(read)[object]; got != want {
+						t.Errorf("snapshot %s = %q, want %q", object, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFileStoreCorruptJournal(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewDataNodeFileStore(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile((&DataNodeFileStore{dir: dir}).journalPath(), []byte("{torn"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewDataNodeFileStore(dir); !errors.Is(err, ErrCorruptStore) {
+		t.Errorf("err = %v, want %v", err, ErrCorruptStore)
+	}
+}
+
+func TestFileStoreSnapshotNames(t *testing.T) {
+	for _, name := range []string{"..", ".", "", "../objects", "nested/name"} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			store, err := NewDataNodeFileStore(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			store.Write("a", []byte("one"))
+			if err := store.CreateSnapshot("kept", namenode.NewLamport(1, 1)); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.CreateSnapshot(name, namenode.NewLamport(1, 2)); err != nil {
+				t.Fatal(err)
+			}
+			store.Update("a", []byte("two"), namenode.NewLamport(1, 3))
+			if err := store.DeleteSnapshot(name, namenode.NewLamport(1, 4)); err != nil {
+				t.Fatal(err)
+			}
+			// replaying the delete after a crash
+			journal(t, dir, fileStoreIntent{Lamport: namenode.NewLamport(1, 4), Op: intentDeleteSnapshot, Snapshot: name})
+			if store, err = NewDataNodeFileStore(dir); err != nil {
+				t.Fatal(err)
+			}
+
+			if data, _, err := store.Get("a"); err != nil || string(data) != "two" {
+				t.Errorf("a = %q, %v after deleting snapshot %q", data, err, name)
+			}
+			read, err := store.SnapshotObjectsWithData("kept", []string{"a"})
+			if got := objectData(read); err != nil || got["a"] != "one" {
+				t.Errorf("kept snapshot = %v, %v after deleting snapshot %q", got, err, name)
+			}
+		})
+	}
+}
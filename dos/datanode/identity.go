@@ -0,0 +1,70 @@
+package datanode
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/mrowaha/dos/namenode"
+)
+
+/**
+	the identity file keeps who this datanode is across restarts
+	the node id is generated on the first start, the cluster id is recorded on the first join
+	and from then on the node only registers with namenodes of that cluster
+**/
+
+var (
+	ErrLoadIdentity   = errors.New("failed to load datanode identity")
+	ErrForeignCluster = errors.New("namenode belongs to another cluster")
+	ErrJoinRejected   = errors.New("namenode rejected the datanode")
+)
+
+type DataNodeIdentity struct {
+	NodeId    string `json:"nodeId"`
+	ClusterId string `json:"clusterId"`
+}
+
+// loadIdentity reads the identity from path, a missing file gets a new node id
+func loadIdentity(path string) (*DataNodeIdentity, error) {
+	identity := &DataNodeIdentity{}
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if err := json.Unmarshal(data, identity); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrLoadIdentity, err)
+		}
+		if !namenode.ValidUUID(identity.NodeId) {
+			return nil, fmt.Errorf("%w: %s is not a uuid", ErrLoadIdentity, identity.NodeId)
+		}
+		return identity, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %v", ErrLoadIdentity, err)
+	}
+
+	identity.NodeId, err = namenode.NewUUID()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrLoadIdentity, err)
+	}
+	if err := identity.save(path); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// save replaces the identity file, a crash leaves either the old or the new identity
+func (id *DataNodeIdentity) save(path string) error {
+	data, err := json.Marshal(id)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrLoadIdentity, err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0666); err != nil {
+		return fmt.Errorf("%w: %v", ErrLoadIdentity, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("%w: %v", ErrLoadIdentity, err)
+	}
+	return nil
+}
@@ -11,12 +11,16 @@ contais a ZMQ pub socket for publishing object updates as topics
 import (
 	"fmt"
 	"log"
+	"sync/atomic"
 
+	"github.com/mrowaha/dos/namenode"
 	zmq "github.com/pebbe/zmq4"
 )
 
 type DataNodeLeaseService struct {
 	publisher *zmq.Socket
+	// fencing token stamped on publications, zero while the node is fenced and publishes nothing
+	fence atomic.Uint64
 }
 
 func NewDataNodeLeaseService(binding string) *DataNodeLeaseService {
@@ -31,10 +35,40 @@ func NewDataNodeLeaseService(binding string) *DataNodeLeaseService {
 
 	log.Printf("datanode leaser listening on addr %s\n", binding)
 	return &DataNodeLeaseService{
-		publisher,
+		publisher: publisher,
 	}
 }
 
+// Fence stops all publications, subscribers can not tell stale content of a removed node from current content
+func (leaser *DataNodeLeaseService) Fence() {
+	leaser.fence.Store(0)
+}
+
+// Unfence resumes publications under the token of the node's new session
+func (leaser *DataNodeLeaseService) Unfence(fence namenode.FencingToken) {
+	leaser.fence.Store(uint64(fence))
+}
+
+// Token returns the token publications are stamped with, zero while fenced
+func (leaser *DataNodeLeaseService) Token() namenode.FencingToken {
+	return namenode.FencingToken(leaser.fence.Load())
+}
+
+// publish sends the message unless the node is fenced
+// publications are "<topic> <fence> <sequence> [data]", subscribers drop the ones of a token they did not lease from
+func (leaser *DataNodeLeaseService) publish(topic string, sequence int, rest ...string) {
+	fence := leaser.fence.Load()
+	if fence == 0 {
+		log.Printf("fenced, dropping publication %s %d", topic, sequence)
+		return
+	}
+	message := fmt.Sprintf("%s %d %d", topic, fence, sequence)
+	for _, part := range rest {
+		message += " " + part
+	}
+	leaser.publisher.Send(message, 0)
+}
+
 var (
 	LEASE_DELETE = ":delete"
 	LEASE_UPDATE = ":update"
@@ -44,19 +78,16 @@ var (
 func (leaser *DataNodeLeaseService) PushObjectCreate(objectName string, data []byte, sequence int) {
 	topic := objectName
 	stringified := string(data)
-	message := fmt.Sprintf("%s%s %d %s", topic, LEASE_CREATE, sequence, stringified)
-	leaser.publisher.Send(message, 0)
+	leaser.publish(topic+LEASE_CREATE, sequence, stringified)
 }
 
 func (leaser *DataNodeLeaseService) PushObjectUpdate(objectName string, data []byte, sequence int) {
 	topic := objectName
 	stringified := string(data)
-	message := fmt.Sprintf("%s%s %d %s", topic, LEASE_UPDATE, sequence, stringified)
-	leaser.publisher.Send(message, 0)
+	leaser.publish(topic+LEASE_UPDATE, sequence, stringified)
 }
 
 func (leaser *DataNodeLeaseService) PushObjectDelete(objectName string, sequence int) {
 	topic := objectName
-	message := fmt.Sprintf("%s%s %d", topic, LEASE_DELETE, sequence)
-	leaser.publisher.Send(message, 0)
+	leaser.publish(topic+LEASE_DELETE, sequence)
 }
@@ -0,0 +1,261 @@
+package datanode
+
+/*
+
+This file contains an in-memory backend for the datanode store
+nothing survives a restart, so it is meant for tests and throwaway nodes
+*/
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/mrowaha/dos/api"
+	"github.com/mrowaha/dos/namenode"
+)
+
+type memoryObject struct {
+	data     []byte
+	sequence int
+}
+
+type memorySnapshot struct {
+	lamport namenode.Lamport
+	// a nil entry means the object did not exist when the snapshot was taken
+	objects map[string]*memoryObject
+}
+
+type DataNodeMemoryStore struct {
+	lock      sync.RWMutex
+	objects   map[string]*memoryObject
+	staged    map[string][]byte
+	snapshots map[string]*memorySnapshot
+	lamport   namenode.Lamport
+}
+
+func NewDataNodeMemoryStore() *DataNodeMemoryStore {
+	return &DataNodeMemoryStore{
+		objects:   make(map[string]*memoryObject),
+		staged:    make(map[string][]byte),
+		snapshots: make(map[string]*memorySnapshot),
+	}
+}
+
+// preserve must be called with the write lock held
+func (s *DataNodeMemoryStore) preserve(object string) {
+	current := s.objects[object]
+	for _, snapshot := range s.snapshots {
+		if _, ok := snapshot.objects[object]; !ok {
+			snapshot.objects[object] = current
+		}
+	}
+}
+
+func (s *DataNodeMemoryStore) Write(object string, data []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.objects[object]; ok {
+		return 0, ErrObjectAlreadyInStore
+	}
+	s.preserve(object)
+	s.objects[object] = &memoryObject{data: slices.Clone(data), sequence: 1}
+	return 1, nil
+}
+
+func (s *DataNodeMemoryStore) Stage(object string, data []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.staged[object] = slices.Clone(data)
+	return nil
+}
+
+func (s *DataNodeMemoryStore) Commit(object string, lamport namenode.Lamport) ([]byte, int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	data, ok := s.staged[object]
+	if !ok {
+		return nil, 0, ErrNothingStaged
+	}
+	if _, ok := s.objects[object]; ok {
+		return nil, 0, ErrObjectAlreadyInStore
+	}
+	s.preserve(object)
+	s.objects[object] = &memoryObject{data: data, sequence: 1}
+	delete(s.staged, object)
+	s.lamport = lamport
+	return slices.Clone(data), 1, nil
+}
+
+func (s *DataNodeMemoryStore) Update(object string, data []byte, lamport namenode.Lamport) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	current, ok := s.objects[object]
+	if !ok {
+		return 0, ErrObjectNotInStore
+	}
+	s.preserve(object)
+	// objects are replaced rather than mutated since snapshots may share them
+	s.objects[object] = &memoryObject{data: slices.Clone(data), sequence: current.sequence + 1}
+	s.lamport = lamport
+	return current.sequence + 1, nil
+}
+
+func (s *DataNodeMemoryStore) Delete(object string, lamport namenode.Lamport) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	current, ok := s.objects[object]
+	if !ok {
+		return 0, ErrObjectNotInStore
+	}
+	s.preserve(object)
+	delete(s.objects, object)
+	s.lamport = lamport
+	return current.sequence, nil
+}
+
+func (s *DataNodeMemoryStore) LastLamport() (namenode.Lamport, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.lamport, nil
+}
+
+func (s *DataNodeMemoryStore) SetLastLamport(lamport namenode.Lamport) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.lamport = lamport
+	return nil
+}
+
+func (s *DataNodeMemoryStore) Wipe(lamport namenode.Lamport) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.objects = make(map[string]*memoryObject)
+	s.staged = make(map[string][]byte)
+	s.snapshots = make(map[string]*memorySnapshot)
+	s.lamport = lamport
+	return nil
+}
+
+func (s *DataNodeMemoryStore) Get(object string) ([]byte, int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	current, ok := s.objects[object]
+	if !ok {
+		return nil, 0, ErrObjectNotInStore
+	}
+	return slices.Clone(current.data), current.sequence, nil
+}
+
+func (s *DataNodeMemoryStore) Objects() ([]string, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	objects := make([]string, 0, len(s.objects))
+	for object := range s.objects {
+		objects = append(objects, object)
+	}
+	slices.Sort(objects)
+	return objects, nil
+}
+
+func (s *DataNodeMemoryStore) ObjectsWithData(objects []string) ([]*api.NodeHeartBeat_Object, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	result := make([]*api.NodeHeartBeat_Object, 0, len(objects))
+	for _, object := range objects {
+		if current, ok := s.objects[object]; ok {
+			result = append(result, &api.NodeHeartBeat_Object{Name: object, Data: slices.Clone(current.data)})
+		}
+	}
+	return result, nil
+}
+
+func (s *DataNodeMemoryStore) Size() (float32, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var size int
+	for object, current := range s.objects {
+		size += len(object) + len(current.data)
+	}
+	return float32(size), nil
+}
+
+func (s *DataNodeMemoryStore) Iterate(fn func(object string, data []byte, sequence int) error) error {
+	objects, _ := s.Objects()
+	for _, object := range objects {
+		data, sequence, err := s.Get(object)
+		if err == ErrObjectNotInStore {
+			continue
+		}
+		if err := fn(object, data, sequence); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *DataNodeMemoryStore) CreateSnapshot(name string, lamport namenode.Lamport) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.snapshots[name]; !ok {
+		s.snapshots[name] = &memorySnapshot{
+			lamport: lamport,
+			objects: make(map[string]*memoryObject),
+		}
+	}
+	s.lamport = lamport
+	return nil
+}
+
+func (s *DataNodeMemoryStore) DeleteSnapshot(name string, lamport namenode.Lamport) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.snapshots[name]; !ok {
+		return ErrSnapshotNotInStore
+	}
+	delete(s.snapshots, name)
+	s.lamport = lamport
+	return nil
+}
+
+func (s *DataNodeMemoryStore) SnapshotObjectsWithData(snapshot string, objects []string) ([]*api.NodeHeartBeat_Object, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	frozen, ok := s.snapshots[snapshot]
+	if !ok {
+		return nil, ErrSnapshotNotInStore
+	}
+
+	result := make([]*api.NodeHeartBeat_Object, 0, len(objects))
+	for _, object := range objects {
+		current, preserved := frozen.objects[object]
+		if !preserved {
+			current = s.objects[object]
+		}
+		if current == nil {
+			continue
+		}
+		result = append(result, &api.NodeHeartBeat_Object{Name: object, Data: slices.Clone(current.data)})
+	}
+	return result, nil
+}
+
+func (s *DataNodeMemoryStore) Close() error {
+	return nil
+}
+
+// the memory store has nothing to make durable, a batch applies straight to it
+func (s *DataNodeMemoryStore) Begin() (DataNodeBatch, error) {
+	return passthroughBatch{s}, nil
+}
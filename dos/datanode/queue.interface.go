@@ -1,19 +1,89 @@
 package datanode
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/mrowaha/dos/namenode"
 )
 
 var (
 	ErrNoCommandToRetrieve = errors.New("no command is blocked")
+	ErrUnknownCommandType  = errors.New("unknown blocked command type")
+	ErrQueueNotInBatch     = errors.New("queue does not share the database of the store batch")
 )
 
+// DataNodeQueue holds the commands that arrived ahead of their lamport
+// staged creates are kept by the DataNodeStore so that their commit is transactional
 type DataNodeQueue interface {
-	PushCreateCmd(cmd namenode.CreateCommand) error
-	PullCreateCmd(string) (*namenode.CreateCommand, error)
-	BlockCommand(float64, interface{}) error
-	DeliverCommand() (interface{}, float64, error)
-	RetrieveCommand() (interface{}, float64, error)
+	BlockCommand(namenode.Lamport, interface{}) error
+	DeliverCommand() (interface{}, namenode.Lamport, error)
+	RetrieveCommand() (interface{}, namenode.Lamport, error)
+	// Reset drops every blocked command, used when the ordering state is resynchronized
+	Reset() error
+}
+
+// TransactionalQueue is a queue that can live in the database of the store
+// a command it delivers in a store batch leaves the queue exactly when the batch applies it
+type TransactionalQueue interface {
+	// DeliverCommandIn is DeliverCommand in the transaction of batch, ErrQueueNotInBatch when it is another database
+	DeliverCommandIn(batch DataNodeBatch) (interface{}, namenode.Lamport, error)
+}
+
+// EventOf returns the broadcast event of a blocked command
+func EventOf(cmd interface{}) (namenode.BroadcastEvent, error) {
+	switch v := cmd.(type) {
+	case namenode.CommitCommand:
+		return namenode.COMMIT, nil
+	case namenode.DeleteCommand:
+		return namenode.DELETE, nil
+	case namenode.UpdateCommand:
+		return namenode.UPDATE, nil
+	case namenode.DistributedReadCommand:
+		return namenode.DISTRIBUTEDREAD, nil
+	case namenode.SnapshotCommand:
+		return v.Type, nil
+	default:
+		return "", fmt.Errorf("%w: %T", ErrUnknownCommandType, cmd)
+	}
+}
+
+// DecodeBlockedCommand restores a blocked command that a queue stored as json
+func DecodeBlockedCommand(event namenode.BroadcastEvent, data []byte) (interface{}, error) {
+	var cmd interface{}
+	var err error
+	switch event {
+	case namenode.COMMIT:
+		var v namenode.CommitCommand
+		err = json.Unmarshal(data, &v)
+		v.Type = event
+		cmd = v
+	case namenode.DELETE:
+		var v namenode.DeleteCommand
+		err = json.Unmarshal(data, &v)
+		v.Type = event
+		cmd = v
+	case namenode.UPDATE:
+		var v namenode.UpdateCommand
+		err = json.Unmarshal(data, &v)
+		v.Type = event
+		cmd = v
+	case namenode.DISTRIBUTEDREAD:
+		var v namenode.DistributedReadCommand
+		err = json.Unmarshal(data, &v)
+		v.Type = event
+		cmd = v
+	case namenode.SNAPSHOT, namenode.DELETESNAPSHOT:
+		var v namenode.SnapshotCommand
+		err = json.Unmarshal(data, &v)
+		v.Type = event
+		cmd = v
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCommandType, event)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s command: %w", event, err)
+	}
+	return cmd, nil
 }
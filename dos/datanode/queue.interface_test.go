@@ -0,0 +1,56 @@
+package datanode
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mrowaha/dos/namenode"
+)
+
+func TestBlockedCommandRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		cmd   interface{}
+		event namenode.BroadcastEvent
+	}{
+		{name: "commit", cmd: namenode.CommitCommand{Name: "a", Type: namenode.COMMIT, RequestId: 1}, event: namenode.COMMIT},
+		{name: "commit on some nodes", cmd: namenode.CommitCommand{Name: "a", Type: namenode.COMMIT, RequestId: 1, Nodes: []string{"n"}}, event: namenode.COMMIT},
+		{name: "delete", cmd: namenode.DeleteCommand{Name: "a", Type: namenode.DELETE, Node: "n", RequestId: 2}, event: namenode.DELETE},
+		{name: "update", cmd: namenode.UpdateCommand{Name: "a", Data: []byte("x"), Type: namenode.UPDATE, RequestId: 3}, event: namenode.UPDATE},
+		{
+			name:  "distributed read",
+			cmd:   namenode.DistributedReadCommand{Objects: []string{"a", "b"}, Snapshot: "s", Type: namenode.DISTRIBUTEDREAD, RequestId: 4},
+			event: namenode.DISTRIBUTEDREAD,
+		},
+		{name: "snapshot", cmd: namenode.SnapshotCommand{Name: "s", Type: namenode.SNAPSHOT, RequestId: 5}, event: namenode.SNAPSHOT},
+		{name: "delete snapshot", cmd: namenode.SnapshotCommand{Name: "s", Type: namenode.DELETESNAPSHOT, RequestId: 6}, event: namenode.DELETESNAPSHOT},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := EventOf(tt.cmd)
+			if err != nil || event != tt.event {
+				t.Fatalf("EventOf = %s, %v, want %s", event, err, tt.event)
+			}
+			data, err := json.Marshal(tt.cmd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := DecodeBlockedCommand(event, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decoded, tt.cmd) {
+				t.Errorf("decoded %#v, want %#v", decoded, tt.cmd)
+			}
+		})
+	}
+
+	if _, err := EventOf(namenode.CreateCommand{}); !errors.Is(err, ErrUnknownCommandType) {
+		t.Errorf("EventOf a create: %v, want %v", err, ErrUnknownCommandType)
+	}
+	if _, err := DecodeBlockedCommand("CREATE", []byte("{}")); !errors.Is(err, ErrUnknownCommandType) {
+		t.Errorf("decoded a create: %v, want %v", err, ErrUnknownCommandType)
+	}
+}
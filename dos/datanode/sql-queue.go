@@ -0,0 +1,142 @@
+package datanode
+
+/*
+
+This file contains a durable DataNodeQueue that lives in a sqlite file
+lamport-blocked commands survive a datanode restart,
+so a datanode does not need any external service to order its commands
+*/
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mrowaha/dos/namenode"
+)
+
+type DataNodeSqlQueue struct {
+	db     *sql.DB
+	dbFile string
+}
+
+// dbFile may be the file of the sqlite store, the queue keeps to its own tables
+func NewDataNodeSqlQueue(dbFile string) (*DataNodeSqlQueue, error) {
+	db, err := sql.Open("sqlite3", dbFile+"?_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("data node queue could not establish db connection: %w", err)
+	}
+	// a single connection serializes the queue and keeps :memory: databases alive
+	db.SetMaxOpenConns(1)
+
+	query := `
+	CREATE TABLE IF NOT EXISTS delivery_queue (
+		lamport INTEGER PRIMARY KEY,
+		type TEXT NOT NULL,
+		command BLOB NOT NULL
+	);
+	`
+	if _, err := db.Exec(query); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to bootstrap sqlite queue: %w", err)
+	}
+
+	return &DataNodeSqlQueue{db: db, dbFile: dbFile}, nil
+}
+
+func (q *DataNodeSqlQueue) BlockCommand(lamport namenode.Lamport, cmd interface{}) error {
+	event, err := EventOf(cmd)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to marshal blocked command: %w", err)
+	}
+
+	query := `
+		INSERT OR REPLACE INTO delivery_queue (lamport, type, command)
+		VALUES (?, ?, ?);
+	`
+
+	if _, err := q.db.Exec(query, int64(lamport), string(event), data); err != nil {
+		return fmt.Errorf("failed to insert to delivery queue: %w", err)
+	}
+	return nil
+}
+
+func (q *DataNodeSqlQueue) RetrieveCommand() (interface{}, namenode.Lamport, error) {
+	query := `
+		SELECT lamport, type, command
+		FROM delivery_queue
+		ORDER BY lamport
+		LIMIT 1;
+	`
+
+	var lamport int64
+	var event string
+	var data []byte
+	err := q.db.QueryRow(query).Scan(&lamport, &event, &data)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, 0, ErrNoCommandToRetrieve
+		}
+		return nil, 0, fmt.Errorf("failed to get min from delivery queue: %w", err)
+	}
+
+	cmd, err := DecodeBlockedCommand(namenode.BroadcastEvent(event), data)
+	if err != nil {
+		return nil, 0, err
+	}
+	return cmd, namenode.Lamport(lamport), nil
+}
+
+func (q *DataNodeSqlQueue) DeliverCommand() (interface{}, namenode.Lamport, error) {
+	return deliverFrom(q.db)
+}
+
+// DeliverCommandIn dequeues in the transaction of a batch of the sqlite store the queue shares its file with
+// every :memory: database is a database of its own
+func (q *DataNodeSqlQueue) DeliverCommandIn(batch DataNodeBatch) (interface{}, namenode.Lamport, error) {
+	b, ok := batch.(*dataNodeSqlBatch)
+	if !ok || b.dbFile != q.dbFile || q.dbFile == ":memory:" {
+		return nil, 0, ErrQueueNotInBatch
+	}
+	return deliverFrom(b.tx)
+}
+
+func deliverFrom(ex execer) (interface{}, namenode.Lamport, error) {
+	query := `
+		DELETE FROM delivery_queue
+		WHERE lamport = (SELECT MIN(lamport) FROM delivery_queue)
+		RETURNING lamport, type, command;
+	`
+
+	var lamport int64
+	var event string
+	var data []byte
+	err := ex.QueryRow(query).Scan(&lamport, &event, &data)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, 0, ErrNoCommandToRetrieve
+		}
+		return nil, 0, fmt.Errorf("failed to remove min from delivery queue: %w", err)
+	}
+
+	cmd, err := DecodeBlockedCommand(namenode.BroadcastEvent(event), data)
+	if err != nil {
+		return nil, 0, err
+	}
+	return cmd, namenode.Lamport(lamport), nil
+}
+
+func (q *DataNodeSqlQueue) Reset() error {
+	if _, err := q.db.Exec(`DELETE FROM delivery_queue;`); err != nil {
+		return fmt.Errorf("failed to reset delivery queue: %w", err)
+	}
+	return nil
+}
+
+func (q *DataNodeSqlQueue) Close() error {
+	return q.db.Close()
+}
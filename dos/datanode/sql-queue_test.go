@@ -0,0 +1,168 @@
+package datanode
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mrowaha/dos/namenode"
+)
+
+func TestSqlQueueOrder(t *testing.T) {
+	queue, err := NewDataNodeSqlQueue(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer queue.Close()
+
+	blocked := []struct {
+		counter uint32
+		cmd     interface{}
+	}{
+		{counter: 3, cmd: namenode.DeleteCommand{Name: "c", Type: namenode.DELETE, RequestId: 3, Node: "n"}},
+		{counter: 1, cmd: namenode.CommitCommand{Name: "a", Type: namenode.COMMIT, RequestId: 1}},
+		{counter: 2, cmd: namenode.SnapshotCommand{Name: "s", Type: namenode.DELETESNAPSHOT, RequestId: 2}},
+	}
+	for _, b := range blocked {
+		if err := queue.BlockCommand(namenode.NewLamport(1, b.counter), b.cmd); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, want := range []uint32{1, 2, 3} {
+		_, head, err := queue.RetrieveCommand()
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmd, lamport, err := queue.DeliverCommand()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lamport != namenode.NewLamport(1, want) || head != lamport {
+			t.Fatalf("delivered %s after retrieving %s, want 1.%d", lamport, head, want)
+		}
+		for _, b := range blocked {
+			if b.counter == want && !reflect.DeepEqual(cmd, b.cmd) {
+				t.Errorf("delivered %#v, want %#v", cmd, b.cmd)
+			}
+		}
+	}
+	if _, _, err := queue.DeliverCommand(); err != ErrNoCommandToRetrieve {
+		t.Errorf("empty queue delivered: %v", err)
+	}
+}
+
+func TestSqlQueueDeliverInBatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		shared  bool
+		apply   bool
+		queued  bool
+		wantErr error
+	}{
+		{name: "applied batch dequeues", shared: true, apply: true, queued: false},
+		{name: "discarded batch keeps the command", shared: true, apply: false, queued: true},
+		{name: "queue in another file", shared: false, queued: true, wantErr: ErrQueueNotInBatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			dbFile := filepath.Join(dir, "data.db")
+			queueFile := dbFile
+			if !tt.shared {
+				queueFile = filepath.Join(dir, "queue.db")
+			}
+			store, err := NewDataNodeSqlStore(dbFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer store.Close()
+			queue, err := NewDataNodeSqlQueue(queueFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer queue.Close()
+			lamport := namenode.NewLamport(1, 1)
+			if err := queue.BlockCommand(lamport, namenode.CommitCommand{Name: "a", Type: namenode.COMMIT, RequestId: 1}); err != nil {
+				t.Fatal(err)
+			}
+
+			batch, err := store.Begin()
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, delivered, err := queue.DeliverCommandIn(batch)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && delivered != lamport {
+				t.Fatalf("delivered %s, want %s", delivered, lamport)
+			}
+			if tt.apply {
+				err = batch.Apply()
+			} else {
+				err = batch.Discard()
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, _, err = queue.RetrieveCommand()
+			if queued := err == nil; queued != tt.queued {
+				t.Errorf("queued = %v, want %v (%v)", queued, tt.queued, err)
+			}
+		})
+	}
+}
+
+func TestSqlQueueSurvivesRestart(t *testing.T) {
+	queueFile := filepath.Join(t.TempDir(), "queue.db")
+	queue, err := NewDataNodeSqlQueue(queueFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := namenode.CommitCommand{Name: "a", Type: namenode.COMMIT, RequestId: 1}
+	queue.BlockCommand(namenode.NewLamport(1, 2), first)
+	// a command resent after a reconnect is queued once
+	queue.BlockCommand(namenode.NewLamport(1, 2), first)
+	queue.BlockCommand(namenode.NewLamport(1, 4), namenode.UpdateCommand{Name: "a", Data: []byte("x"), Type: namenode.UPDATE, RequestId: 2})
+	if err := queue.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	queue, err = NewDataNodeSqlQueue(queueFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer queue.Close()
+	var delivered []namenode.Lamport
+	for {
+		_, lamport, err := queue.DeliverCommand()
+		if err == ErrNoCommandToRetrieve {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		delivered = append(delivered, lamport)
+	}
+	if len(delivered) != 2 || delivered[0] != namenode.NewLamport(1, 2) || delivered[1] != namenode.NewLamport(1, 4) {
+		t.Errorf("delivered %v after a restart, want 1.2 and 1.4", delivered)
+	}
+}
+
+func TestSqlQueueReset(t *testing.T) {
+	queue, err := NewDataNodeSqlQueue(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer queue.Close()
+	queue.BlockCommand(namenode.NewLamport(1, 2), namenode.CommitCommand{Name: "a", Type: namenode.COMMIT})
+	if err := queue.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := queue.RetrieveCommand(); err != ErrNoCommandToRetrieve {
+		t.Errorf("queue holds a command after a reset: %v", err)
+	}
+}
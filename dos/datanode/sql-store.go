@@ -4,45 +4,177 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"log"
-	"os"
 	"strings"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 	"github.com/mrowaha/dos/api"
+	"github.com/mrowaha/dos/namenode"
 )
 
 type DataNodeSqlStore struct {
 	db     *sql.DB
 	dbFile string
+	tx     *sql.Tx // set on the store of a batch
 }
 
-func NewDataNodeSqlStore(dbFile string) *DataNodeSqlStore {
-	db, err := sql.Open("sqlite3", dbFile)
+func NewDataNodeSqlStore(dbFile string) (*DataNodeSqlStore, error) {
+	db, err := sql.Open("sqlite3", dbFile+"?_busy_timeout=5000")
 	if err != nil {
-		log.Fatalf("data node could not establish db connection: %v", err)
+		return nil, fmt.Errorf("data node could not establish db connection: %w", err)
 	}
-	return &DataNodeSqlStore{
+	store := &DataNodeSqlStore{
 		db:     db,
 		dbFile: dbFile,
 	}
+	if err := store.BootStrap(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
 }
 
-func (s *DataNodeSqlStore) BootStrap() {
+func (s *DataNodeSqlStore) BootStrap() error {
 	query := `
 	CREATE TABLE IF NOT EXISTS datanode (
 		object TEXT PRIMARY KEY,
 		data BLOB NOT NULL,
 		sequence INTEGER DEFAULT 1
 	);	
+	CREATE TABLE IF NOT EXISTS snapshots (
+		name TEXT PRIMARY KEY,
+		lamport INTEGER NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS snapshot_objects (
+		snapshot TEXT NOT NULL,
+		object TEXT NOT NULL,
+		data BLOB,
+		sequence INTEGER,
+		PRIMARY KEY (snapshot, object)
+	);
+	CREATE TABLE IF NOT EXISTS staged (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		object TEXT NOT NULL,
+		data BLOB
+	);
+	CREATE TABLE IF NOT EXISTS meta (
+		key TEXT PRIMARY KEY,
+		value INTEGER NOT NULL
+	);
 	`
 
-	if _, err := s.db.Exec(query); err != nil {
-		log.Fatalf("failed to bootstrap sqlite store: %v", err)
+	if _, err := s.conn().Exec(query); err != nil {
+		return fmt.Errorf("failed to bootstrap sqlite store: %w", err)
 	}
+	return nil
 }
 
-func (s *DataNodeSqlStore) Write(object string, data []byte) (int, error) {
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// conn is the batch transaction if there is one, the database otherwise
+func (s *DataNodeSqlStore) conn() execer {
+	if s.tx != nil {
+		return s.tx
+	}
+	return s.db
+}
+
+// transact runs fn in a single sqlite transaction, rolling back if fn fails
+// inside a batch fn runs in a savepoint of the batch transaction instead
+func (s *DataNodeSqlStore) transact(fn func(tx *sql.Tx) error) error {
+	if s.tx != nil {
+		return s.savepoint(fn)
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (s *DataNodeSqlStore) savepoint(fn func(tx *sql.Tx) error) error {
+	if _, err := s.tx.Exec(`SAVEPOINT command;`); err != nil {
+		return fmt.Errorf("failed to begin savepoint: %w", err)
+	}
+	if err := fn(s.tx); err != nil {
+		// the failed command leaves nothing behind, the rest of the batch is kept
+		s.tx.Exec(`ROLLBACK TO command;`)
+		s.tx.Exec(`RELEASE command;`)
+		return err
+	}
+	if _, err := s.tx.Exec(`RELEASE command;`); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+	return nil
+}
+
+// Begin opens a transaction that every command of the batch is applied in
+func (s *DataNodeSqlStore) Begin() (DataNodeBatch, error) {
+	if s.tx != nil {
+		return nil, ErrBatchInProgress
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin batch: %w", err)
+	}
+	return &dataNodeSqlBatch{
+		DataNodeSqlStore: &DataNodeSqlStore{
+			db:     s.db,
+			dbFile: s.dbFile,
+			tx:     tx,
+		},
+	}, nil
+}
+
+type dataNodeSqlBatch struct {
+	*DataNodeSqlStore
+}
+
+func (b *dataNodeSqlBatch) Apply() error {
+	if err := b.tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit batch: %w", err)
+	}
+	return nil
+}
+
+func (b *dataNodeSqlBatch) Discard() error {
+	return b.tx.Rollback()
+}
+
+// closing a batch drops it, the database stays open
+func (b *dataNodeSqlBatch) Close() error {
+	return b.Discard()
+}
+
+func setLamport(ex execer, lamport namenode.Lamport) error {
+	query := `
+		INSERT INTO meta (key, value)
+		VALUES ('lamport', ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value;
+	`
+
+	if _, err := ex.Exec(query, lamport); err != nil {
+		return fmt.Errorf("failed to record lamport %s: %w", lamport, err)
+	}
+	return nil
+}
+
+func insertObject(ex execer, object string, data []byte) (int, error) {
+	if err := preserveAbsent(ex, object); err != nil {
+		return 0, err
+	}
+
 	query := `
 		INSERT INTO datanode (object, data, sequence)
 		VALUES (?, ?, 1)
@@ -50,7 +182,11 @@ func (s *DataNodeSqlStore) Write(object string, data []byte) (int, error) {
 	`
 
 	var sequence int
-	err := s.db.QueryRow(query, object, data).Scan(&sequence)
+	err := ex.QueryRow(query, object, data).Scan(&sequence)
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+		return 0, ErrObjectAlreadyInStore
+	}
 	if err != nil {
 		return 0, fmt.Errorf("failed to write data to datanode table: %w", err)
 	}
@@ -58,76 +194,228 @@ func (s *DataNodeSqlStore) Write(object string, data []byte) (int, error) {
 	return sequence, nil
 }
 
-var ErrObjectNotInStore = errors.New("object not found in the store")
-
-func (s *DataNodeSqlStore) Delete(object string) (int, error) {
+func (s *DataNodeSqlStore) Write(object string, data []byte) (int, error) {
 	var sequence int
+	err := s.transact(func(tx *sql.Tx) error {
+		var err error
+		sequence, err = insertObject(tx, object, data)
+		return err
+	})
+	return sequence, err
+}
 
-	// Query to retrieve the sequence of the object
-	querySelect := `
-		SELECT sequence
-		FROM datanode
-		WHERE object = ?;
+func (s *DataNodeSqlStore) Stage(object string, data []byte) error {
+	query := `
+		INSERT INTO staged (object, data)
+		VALUES (?, ?);
 	`
 
-	err := s.db.QueryRow(querySelect, object).Scan(&sequence)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return 0, ErrObjectNotInStore
-		}
-		return 0, fmt.Errorf("failed to retrieve sequence for object %s: %w", object, err)
+	if _, err := s.conn().Exec(query, object, data); err != nil {
+		return fmt.Errorf("failed to stage object %s: %w", object, err)
 	}
+	return nil
+}
 
-	// Query to delete the object
-	queryDelete := `
-		DELETE FROM datanode
-		WHERE object = ?;
-	`
+func (s *DataNodeSqlStore) Commit(object string, lamport namenode.Lamport) ([]byte, int, error) {
+	var data []byte
+	var sequence int
+	err := s.transact(func(tx *sql.Tx) error {
+		// the latest staged create wins, older ones belong to creates that never committed
+		querySelect := `
+			SELECT data
+			FROM staged
+			WHERE object = ?
+			ORDER BY id DESC
+			LIMIT 1;
+		`
+
+		err := tx.QueryRow(querySelect, object).Scan(&data)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrNothingStaged
+			}
+			return fmt.Errorf("failed to read staged object %s: %w", object, err)
+		}
+
+		if sequence, err = insertObject(tx, object, data); err != nil {
+			return err
+		}
 
-	result, err := s.db.Exec(queryDelete, object)
+		if _, err := tx.Exec(`DELETE FROM staged WHERE object = ?;`, object); err != nil {
+			return fmt.Errorf("failed to remove staged object %s: %w", object, err)
+		}
+
+		return setLamport(tx, lamport)
+	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to delete object from datanode table: %w", err)
+		return nil, 0, err
 	}
+	return data, sequence, nil
+}
+
+func (s *DataNodeSqlStore) Delete(object string, lamport namenode.Lamport) (int, error) {
+	var sequence int
+
+	err := s.transact(func(tx *sql.Tx) error {
+		// Query to retrieve the sequence of the object
+		querySelect := `
+			SELECT sequence
+			FROM datanode
+			WHERE object = ?;
+		`
+
+		err := tx.QueryRow(querySelect, object).Scan(&sequence)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrObjectNotInStore
+			}
+			return fmt.Errorf("failed to retrieve sequence for object %s: %w", object, err)
+		}
+
+		if err := preserve(tx, object); err != nil {
+			return err
+		}
+
+		// Query to delete the object
+		queryDelete := `
+			DELETE FROM datanode
+			WHERE object = ?;
+		`
+
+		if _, err := tx.Exec(queryDelete, object); err != nil {
+			return fmt.Errorf("failed to delete object from datanode table: %w", err)
+		}
 
-	rowsAffected, err := result.RowsAffected()
+		return setLamport(tx, lamport)
+	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+		return 0, err
 	}
 
-	if rowsAffected == 0 {
-		return 0, ErrObjectNotInStore
+	// Return the sequence of the deleted object
+	return sequence, nil
+}
+
+func (s *DataNodeSqlStore) Update(object string, data []byte, lamport namenode.Lamport) (int, error) {
+	var sequence int
+
+	err := s.transact(func(tx *sql.Tx) error {
+		if err := preserve(tx, object); err != nil {
+			return err
+		}
+
+		query := `
+			UPDATE datanode
+			SET data = ?, sequence = sequence + 1
+			WHERE object = ?
+			RETURNING sequence;
+		`
+
+		err := tx.QueryRow(query, data, object).Scan(&sequence)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrObjectNotInStore
+			}
+			return fmt.Errorf("failed to update object in datanode table: %w", err)
+		}
+
+		return setLamport(tx, lamport)
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	// Return the sequence of the deleted object
 	return sequence, nil
 }
 
-func (s *DataNodeSqlStore) Update(object string, data []byte) (int, error) {
+func (s *DataNodeSqlStore) LastLamport() (namenode.Lamport, error) {
+	var lamport namenode.Lamport
+	err := s.conn().QueryRow(`SELECT value FROM meta WHERE key = 'lamport';`).Scan(&lamport)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read last lamport: %w", err)
+	}
+	return lamport, nil
+}
+
+func (s *DataNodeSqlStore) SetLastLamport(lamport namenode.Lamport) error {
+	return setLamport(s.conn(), lamport)
+}
+
+func (s *DataNodeSqlStore) Wipe(lamport namenode.Lamport) error {
+	return s.transact(func(tx *sql.Tx) error {
+		for _, table := range []string{"datanode", "staged", "snapshot_objects", "snapshots"} {
+			if _, err := tx.Exec(fmt.Sprintf(`DELETE FROM %s;`, table)); err != nil {
+				return fmt.Errorf("failed to wipe %s table: %w", table, err)
+			}
+		}
+		return setLamport(tx, lamport)
+	})
+}
+
+func (s *DataNodeSqlStore) Get(object string) ([]byte, int, error) {
 	query := `
-		UPDATE datanode
-		SET data = ?, sequence = sequence + 1
-		WHERE object = ?
-		RETURNING sequence;
+		SELECT data, sequence
+		FROM datanode
+		WHERE object = ?;
 	`
 
+	var data []byte
 	var sequence int
-	err := s.db.QueryRow(query, data, object).Scan(&sequence)
+	err := s.conn().QueryRow(query, object).Scan(&data, &sequence)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return 0, ErrObjectNotInStore
+			return nil, 0, ErrObjectNotInStore
 		}
-		return 0, fmt.Errorf("failed to update object in datanode table: %w", err)
+		return nil, 0, fmt.Errorf("failed to read object from datanode table: %w", err)
 	}
 
-	return sequence, nil
+	return data, sequence, nil
+}
+
+func (s *DataNodeSqlStore) Iterate(fn func(object string, data []byte, sequence int) error) error {
+	query := `
+		SELECT object, data, sequence
+		FROM datanode
+		ORDER BY object;
+	`
+
+	rows, err := s.conn().Query(query)
+	if err != nil {
+		return fmt.Errorf("failed to iterate datanode table: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var object string
+		var data []byte
+		var sequence int
+		if err := rows.Scan(&object, &data, &sequence); err != nil {
+			return fmt.Errorf("failed to scan object: %w", err)
+		}
+		if err := fn(object, data, sequence); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (s *DataNodeSqlStore) Close() error {
+	return s.db.Close()
 }
 
+// Size sums the bytes of the names and data of the objects the store holds. the database file does not
+// shrink when objects are deleted, so its size would keep a node looking full after its objects were moved away
 func (s *DataNodeSqlStore) Size() (float32, error) {
-	fileInfo, err := os.Stat(s.dbFile)
+	var size int64
+	err := s.db.QueryRow(`SELECT COALESCE(SUM(LENGTH(object) + LENGTH(data)), 0) FROM datanode;`).Scan(&size)
 	if err != nil {
 		return 0, errors.New("error getting db size info")
 	}
-	return float32(fileInfo.Size()), nil
+	return float32(size), nil
 }
 
 func (s *DataNodeSqlStore) Objects() ([]string, error) {
@@ -136,7 +424,7 @@ func (s *DataNodeSqlStore) Objects() ([]string, error) {
 		FROM datanode;
 	`
 
-	rows, err := s.db.Query(query)
+	rows, err := s.conn().Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve objects from datanode table: %w", err)
 	}
@@ -174,7 +462,7 @@ func (s *DataNodeSqlStore) ObjectsWithData(objects []string) ([]*api.NodeHeartBe
 		args[i] = obj
 	}
 
-	rows, err := s.db.Query(query, args...)
+	rows, err := s.conn().Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve object-data pairs from datanode table: %w", err)
 	}
@@ -199,3 +487,140 @@ func (s *DataNodeSqlStore) ObjectsWithData(objects []string) ([]*api.NodeHeartBe
 
 	return result, nil
 }
+
+/**
+	snapshots are copy-on-write. taking a snapshot only records its name, and the first
+	mutation of an object after the snapshot copies the old row into snapshot_objects.
+	a copied row with a NULL data column means the object did not exist at the snapshot
+**/
+
+// preserve copies the current row of object into every snapshot that does not hold a copy yet
+func preserve(ex execer, object string) error {
+	query := `
+		INSERT OR IGNORE INTO snapshot_objects (snapshot, object, data, sequence)
+		SELECT snapshots.name, datanode.object, datanode.data, datanode.sequence
+		FROM snapshots, datanode
+		WHERE datanode.object = ?;
+	`
+
+	if _, err := ex.Exec(query, object); err != nil {
+		return fmt.Errorf("failed to preserve object %s for snapshots: %w", object, err)
+	}
+	return nil
+}
+
+// preserveAbsent records in every snapshot that object did not exist when the snapshot was taken
+func preserveAbsent(ex execer, object string) error {
+	query := `
+		INSERT OR IGNORE INTO snapshot_objects (snapshot, object, data, sequence)
+		SELECT name, ?, NULL, NULL
+		FROM snapshots;
+	`
+
+	if _, err := ex.Exec(query, object); err != nil {
+		return fmt.Errorf("failed to preserve absent object %s for snapshots: %w", object, err)
+	}
+	return nil
+}
+
+func (s *DataNodeSqlStore) CreateSnapshot(name string, lamport namenode.Lamport) error {
+	return s.transact(func(tx *sql.Tx) error {
+		query := `
+			INSERT OR IGNORE INTO snapshots (name, lamport)
+			VALUES (?, ?);
+		`
+
+		if _, err := tx.Exec(query, name, lamport); err != nil {
+			return fmt.Errorf("failed to create snapshot %s: %w", name, err)
+		}
+		return setLamport(tx, lamport)
+	})
+}
+
+func (s *DataNodeSqlStore) DeleteSnapshot(name string, lamport namenode.Lamport) error {
+	return s.transact(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM snapshot_objects WHERE snapshot = ?;`, name); err != nil {
+			return fmt.Errorf("failed to delete objects of snapshot %s: %w", name, err)
+		}
+
+		result, err := tx.Exec(`DELETE FROM snapshots WHERE name = ?;`, name)
+		if err != nil {
+			return fmt.Errorf("failed to delete snapshot %s: %w", name, err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return ErrSnapshotNotInStore
+		}
+		return setLamport(tx, lamport)
+	})
+}
+
+// SnapshotObjectsWithData reads the objects as they were when the snapshot was taken
+// objects that have not been mutated since are read from the live table
+func (s *DataNodeSqlStore) SnapshotObjectsWithData(snapshot string, objects []string) ([]*api.NodeHeartBeat_Object, error) {
+	var exists int
+	err := s.conn().QueryRow(`SELECT COUNT(*) FROM snapshots WHERE name = ?;`, snapshot).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up snapshot %s: %w", snapshot, err)
+	}
+	if exists == 0 {
+		return nil, ErrSnapshotNotInStore
+	}
+
+	if len(objects) == 0 {
+		return make([]*api.NodeHeartBeat_Object, 0), nil
+	}
+
+	in := `(?` + strings.Repeat(",?", len(objects)-1) + `)`
+	query := `
+		SELECT object, data
+		FROM snapshot_objects
+		WHERE snapshot = ? AND data IS NOT NULL AND object IN ` + in + `
+		UNION ALL
+		SELECT object, data
+		FROM datanode
+		WHERE object IN ` + in + ` AND object NOT IN (
+			SELECT object FROM snapshot_objects WHERE snapshot = ?
+		);
+	`
+
+	args := make([]interface{}, 0, 2*len(objects)+2)
+	args = append(args, snapshot)
+	for _, obj := range objects {
+		args = append(args, obj)
+	}
+	for _, obj := range objects {
+		args = append(args, obj)
+	}
+	args = append(args, snapshot)
+
+	rows, err := s.conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve object-data pairs from snapshot %s: %w", snapshot, err)
+	}
+	defer rows.Close()
+
+	var result []*api.NodeHeartBeat_Object
+	for rows.Next() {
+		var obj string
+		var data []byte
+		if err := rows.Scan(&obj, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan object-data pair: %w", err)
+		}
+		result = append(result, &api.NodeHeartBeat_Object{
+			Name: obj,
+			Data: data,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,81 @@
+package datanode
+
+import (
+	"errors"
+
+	"github.com/mrowaha/dos/api"
+	"github.com/mrowaha/dos/namenode"
+)
+
+var (
+	ErrObjectNotInStore     = errors.New("object not found in the store")
+	ErrObjectAlreadyInStore = errors.New("object already exists in the store")
+	ErrSnapshotNotInStore   = errors.New("snapshot not found in the store")
+	ErrNothingStaged        = errors.New("no staged create for object")
+	ErrBatchInProgress      = errors.New("store batch already in progress")
+)
+
+// DataNodeStore is the storage backend of a datanode
+// every mutation returns the new sequence of the object, which is published by the lease service
+// methods taking a lamport apply a broadcast command and record the lamport atomically with it,
+// so the store always knows exactly which commands it has applied
+type DataNodeStore interface {
+	Write(object string, data []byte) (int, error)
+	// Stage holds a create until its commit arrives
+	Stage(object string, data []byte) error
+	// Commit moves the staged create of object into the store
+	Commit(object string, lamport namenode.Lamport) ([]byte, int, error)
+	Update(object string, data []byte, lamport namenode.Lamport) (int, error)
+	Delete(object string, lamport namenode.Lamport) (int, error)
+	Get(object string) ([]byte, int, error)
+	Objects() ([]string, error)
+	ObjectsWithData(objects []string) ([]*api.NodeHeartBeat_Object, error)
+	Size() (float32, error)
+	// Iterate calls fn for every object in the store, stopping at the first error
+	Iterate(fn func(object string, data []byte, sequence int) error) error
+
+	CreateSnapshot(name string, lamport namenode.Lamport) error
+	DeleteSnapshot(name string, lamport namenode.Lamport) error
+	SnapshotObjectsWithData(snapshot string, objects []string) ([]*api.NodeHeartBeat_Object, error)
+
+	// LastLamport is the lamport of the last applied command, zero for a fresh store
+	LastLamport() (namenode.Lamport, error)
+	// SetLastLamport records commands that did not change the store
+	SetLastLamport(lamport namenode.Lamport) error
+	// Wipe drops every object, staged create and snapshot of a suspect store and records lamport
+	Wipe(lamport namenode.Lamport) error
+	// Begin starts a batch, the commands of one frame are applied through it together
+	Begin() (DataNodeBatch, error)
+	Close() error
+}
+
+// DataNodeBatch is a store whose mutations become durable together on Apply
+// reads through the batch see its own mutations
+type DataNodeBatch interface {
+	DataNodeStore
+	Apply() error
+	Discard() error
+}
+
+// passthroughBatch is the batch of backends that make every mutation durable on its own
+// Discard cannot undo anything there, which is fine since commands are never rolled back on purpose
+type passthroughBatch struct {
+	DataNodeStore
+}
+
+func (b passthroughBatch) Begin() (DataNodeBatch, error) {
+	return nil, ErrBatchInProgress
+}
+
+func (b passthroughBatch) Apply() error {
+	return nil
+}
+
+func (b passthroughBatch) Discard() error {
+	return nil
+}
+
+// closing a batch must not close the store behind it
+func (b passthroughBatch) Close() error {
+	return nil
+}
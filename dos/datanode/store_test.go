@@ -0,0 +1,231 @@
+package datanode
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mrowaha/dos/api"
+	"github.com/mrowaha/dos/namenode"
+)
+
+type storeBackend struct {
+	name string
+	// open opens the store kept in dir, a durable backend finds what it stored there before
+	open    func(t *testing.T, dir string) DataNodeStore
+	durable bool
+}
+
+var storeBackends = []storeBackend{
+	{
+		name: "memory",
+		open: func(t *testing.T, dir string) DataNodeStore {
+			return NewDataNodeMemoryStore()
+		},
+	},
+	{
+		name: "sqlite",
+		open: func(t *testing.T, dir string) DataNodeStore {
+			store, err := NewDataNodeSqlStore(filepath.Join(dir, "data.db"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return store
+		},
+		durable: true,
+	},
+	{
+		name: "file",
+		open: func(t *testing.T, dir string) DataNodeStore {
+			store, err := NewDataNodeFileStore(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return store
+		},
+		durable: true,
+	},
+}
+
+// objectData returns the data of the objects in the order they are asked for
+func objectData(objects []*api.NodeHeartBeat_Object) map[string]string {
+	data := make(map[string]string)
+	for _, object := range objects {
+		data[object.Name] = string(object.Data)
+	}
+	return data
+}
+
+func TestStoreObjects(t *testing.T) {
+	for _, backend := range storeBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			store := backend.open(t, t.TempDir())
+			defer store.Close()
+
+			if _, _, err := store.Commit("a", namenode.NewLamport(1, 1)); !errors.Is(err, ErrNothingStaged) {
+				t.Fatalf("commit of nothing staged: %v, want %v", err, ErrNothingStaged)
+			}
+			if err := store.Stage("a", []byte("one")); err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := store.Get("a"); !errors.Is(err, ErrObjectNotInStore) {
+				t.Fatalf("a staged create is visible: %v", err)
+			}
+			data, sequence, err := store.Commit("a", namenode.NewLamport(1, 1))
+			if err != nil || string(data) != "one" || sequence != 1 {
+				t.Fatalf("commit = %q, %d, %v", data, sequence, err)
+			}
+			if err := store.Stage("a", []byte("again")); err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := store.Commit("a", namenode.NewLamport(1, 2)); !errors.Is(err, ErrObjectAlreadyInStore) {
+				t.Fatalf("second commit: %v, want %v", err, ErrObjectAlreadyInStore)
+			}
+
+			if sequence, err := store.Update("a", []byte("two"), namenode.NewLamport(1, 3)); err != nil || sequence != 2 {
+				t.Fatalf("update = %d, %v", sequence, err)
+			}
+			if _, err := store.Update("b", []byte("two"), namenode.NewLamport(1, 4)); !errors.Is(err, ErrObjectNotInStore) {
+				t.Fatalf("update of a missing object: %v, want %v", err, ErrObjectNotInStore)
+			}
+			if sequence, err := store.Write("b", []byte("bee")); err != nil || sequence != 1 {
+				t.Fatalf("write = %d, %v", sequence, err)
+			}
+
+			if data, sequence, err := store.Get("a"); err != nil || string(data) != "two" || sequence != 2 {
+				t.Errorf("get = %q, %d, %v", data, sequence, err)
+			}
+			if objects, _ := store.Objects(); !slices.Equal(objects, []string{"a", "b"}) {
+				t.Errorf("objects = %v", objects)
+			}
+			read, err := store.ObjectsWithData([]string{"a", "missing"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := objectData(read); len(got) != 1 || got["a"] != "two" {
+				t.Errorf("objects with data = %v", got)
+			}
+			seen := make([]string, 0)
+			store.Iterate(func(object string, data []byte, sequence int) error {
+				seen = append(seen, object)
+				return nil
+			})
+			if !slices.Equal(seen, []string{"a", "b"}) {
+				t.Errorf("iterated %v", seen)
+			}
+
+			if sequence, err := store.Delete("a", namenode.NewLamport(1, 5)); err != nil || sequence != 2 {
+				t.Fatalf("delete = %d, %v", sequence, err)
+			}
+			if _, err := store.Delete("a", namenode.NewLamport(1, 6)); !errors.Is(err, ErrObjectNotInStore) {
+				t.Fatalf("second delete: %v, want %v", err, ErrObjectNotInStore)
+			}
+			if lamport, err := store.LastLamport(); err != nil || lamport != namenode.NewLamport(1, 5) {
+				t.Errorf("lamport = %s, %v, want the one of the delete", lamport, err)
+			}
+		})
+	}
+}
+
+func TestStoreSnapshots(t *testing.T) {
+	for _, backend := range storeBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			store := backend.open(t, t.TempDir())
+			defer store.Close()
+
+			store.Write("kept", []byte("before"))
+			store.Write("deleted", []byte("before"))
+			if err := store.CreateSnapshot("snap", namenode.NewLamport(1, 1)); err != nil {
+				t.Fatal(err)
+			}
+			store.Update("kept", []byte("after"), namenode.NewLamport(1, 2))
+			store.Delete("deleted", namenode.NewLamport(1, 3))
+			store.Write("created", []byte("after"))
+
+			read, err := store.SnapshotObjectsWithData("snap", []string{"kept", "deleted", "created"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := objectData(read)
+			if len(got) != 2 || got["kept"] != "before" || got["deleted"] != "before" {
+				t.Errorf("snapshot = %v, want kept and deleted as they were", got)
+			}
+
+			if err := store.DeleteSnapshot("snap", namenode.NewLamport(1, 4)); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := store.SnapshotObjectsWithData("snap", []string{"kept"}); !errors.Is(err, ErrSnapshotNotInStore) {
+				t.Errorf("read of a deleted snapshot: %v, want %v", err, ErrSnapshotNotInStore)
+			}
+			if err := store.DeleteSnapshot("snap", namenode.NewLamport(1, 5)); !errors.Is(err, ErrSnapshotNotInStore) {
+				t.Errorf("second delete: %v, want %v", err, ErrSnapshotNotInStore)
+			}
+		})
+	}
+}
+
+func TestStoreSurvivesRestart(t *testing.T) {
+	for _, backend := range storeBackends {
+		if !backend.durable {
+			continue
+		}
+		t.Run(backend.name, func(t *testing.T) {
+			dir := t.TempDir()
+			store := backend.open(t, dir)
+			store.Stage("a", []byte("one"))
+			store.Commit("a", namenode.NewLamport(1, 1))
+			store.Stage("b", []byte("staged"))
+			store.CreateSnapshot("snap", namenode.NewLamport(1, 2))
+			store.Update("a", []byte("two"), namenode.NewLamport(1, 3))
+			if err := store.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			store = backend.open(t, dir)
+			defer store.Close()
+			if data, sequence, err := store.Get("a"); err != nil || string(data) != "two" || sequence != 2 {
+				t.Errorf("get = %q, %d, %v", data, sequence, err)
+			}
+			if lamport, _ := store.LastLamport(); lamport != namenode.NewLamport(1, 3) {
+				t.Errorf("lamport = %s, want 1.3", lamport)
+			}
+			read, err := store.SnapshotObjectsWithData("snap", []string{"a"})
+			if got := objectData(read); err != nil || got["a"] != "one" {
+				t.Errorf("snapshot = %v, %v", got, err)
+			}
+			// the staged create is still waiting for its commit
+			if _, _, err := store.Commit("b", namenode.NewLamport(1, 4)); err != nil {
+				t.Errorf("commit of a create staged before the restart: %v", err)
+			}
+		})
+	}
+}
+
+func TestStoreWipe(t *testing.T) {
+	for _, backend := range storeBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			store := backend.open(t, t.TempDir())
+			defer store.Close()
+			store.Write("a", []byte("one"))
+			store.Stage("b", []byte("staged"))
+			store.CreateSnapshot("snap", namenode.NewLamport(1, 1))
+
+			if err := store.Wipe(namenode.NewLamport(2, 4)); err != nil {
+				t.Fatal(err)
+			}
+			if objects, _ := store.Objects(); len(objects) != 0 {
+				t.Errorf("objects = %v after a wipe", objects)
+			}
+			if _, _, err := store.Commit("b", namenode.NewLamport(2, 5)); !errors.Is(err, ErrNothingStaged) {
+				t.Errorf("staged create survived the wipe: %v", err)
+			}
+			if _, err := store.SnapshotObjectsWithData("snap", nil); !errors.Is(err, ErrSnapshotNotInStore) {
+				t.Errorf("snapshot survived the wipe: %v", err)
+			}
+			if lamport, _ := store.LastLamport(); lamport != namenode.NewLamport(2, 4) {
+				t.Errorf("lamport = %s, want the one of the wipe", lamport)
+			}
+		})
+	}
+}
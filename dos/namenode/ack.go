@@ -0,0 +1,31 @@
+package namenode
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mrowaha/dos/api"
+)
+
+var (
+	ErrCommandFailed = errors.New("datanode failed to apply command")
+)
+
+// Ack is a datanode's answer to a command
+// a failed command is answered as well, with the code of what went wrong
+type Ack struct {
+	Code    api.NodeHeartBeat_Code
+	Error   string
+	Objects []*api.NodeHeartBeat_Object // result of a distributed read
+}
+
+func (a Ack) Ok() bool {
+	return a.Code == api.NodeHeartBeat_OK
+}
+
+func (a Ack) Err() error {
+	if a.Ok() {
+		return nil
+	}
+	return fmt.Errorf("%w: %s: %s", ErrCommandFailed, a.Code, a.Error)
+}
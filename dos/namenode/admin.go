@@ -0,0 +1,183 @@
+package namenode
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/mrowaha/dos/api"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+/**
+	the admin service lets operators look into the cluster without reading the namenode log
+	every call reads the datanode meta, the ghosts and the namespace under the global lock,
+	so a response is one consistent view of the cluster
+**/
+
+var (
+	ErrDataNodeNotFound = errors.New("datanode is not registered")
+)
+
+// nodeSummary describes a registered datanode, called under the global lock
+func (s *DosNameNodeServer) nodeSummary(entry *MetaHeapEntry) *api.NodeSummary {
+	return &api.NodeSummary{
+		Name:    entry.Id,
+		NodeId:  entry.NodeId,
+		Leaser:  entry.Lease,
+		Size:    entry.Size,
+		Fence:   uint64(entry.Fence),
+		Objects: uint32(len(s.flatNS.Objects(entry.Id))),
+		Joined:  timestamppb.New(entry.Joined),
+		State:   api.NodeSummary_State(entry.State),
+		Drain:   drainProgress(entry.Drain),
+
+		Maintenance: maintenance(entry),
+		Capacity:    entry.Capacity,
+		Zone:        entry.Zone,
+		Rack:        entry.Rack,
+		Labels:      entry.Labels,
+	}
+}
+
+func maintenance(entry *MetaHeapEntry) *api.Maintenance {
+	if entry.Maintenance == nil {
+		return nil
+	}
+	return &api.Maintenance{
+		Until:      timestamppb.New(entry.Maintenance.Until),
+		Away:       entry.Session.Closed(),
+		Buffered:   uint32(len(entry.Maintenance.Commands)),
+		Overflowed: entry.Maintenance.Overflowed,
+	}
+}
+
+func drainProgress(drain *DrainProgress) *api.DrainProgress {
+	if drain == nil {
+		return nil
+	}
+	progress := &api.DrainProgress{
+		Total:   uint32(drain.Total),
+		Moved:   uint32(drain.Moved),
+		Failed:  uint32(drain.Failed),
+		Running: drain.Running,
+	}
+	if drain.Err != nil {
+		progress.Error = drain.Err.Error()
+	}
+	return progress
+}
+
+func (s *DosNameNodeServer) ListNodes(ctx context.Context, req *api.ListNodesReq) (*api.ListNodesRes, error) {
+	res := &api.ListNodesRes{}
+	s.Transactional(func() {
+		s.meta.ForEach(func(entry *MetaHeapEntry) {
+			res.Nodes = append(res.Nodes, s.nodeSummary(entry))
+		})
+	})
+	slices.SortFunc(res.Nodes, func(a, b *api.NodeSummary) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return res, nil
+}
+
+func (s *DosNameNodeServer) NodeInfo(ctx context.Context, req *api.NodeInfoReq) (*api.NodeInfoRes, error) {
+	var res *api.NodeInfoRes
+	s.Transactional(func() {
+		entry := s.meta.Get(req.Name)
+		if entry == nil {
+			return
+		}
+		objects := s.flatNS.Objects(entry.Id)
+		slices.Sort(objects)
+		res = &api.NodeInfoRes{
+			Node:    s.nodeSummary(entry),
+			Objects: objects,
+		}
+		if entry.Session != nil {
+			queued, awaiting := entry.Session.Backlog()
+			res.Queued, res.Awaiting = uint32(queued), uint32(awaiting)
+		}
+	})
+	if res == nil {
+		return nil, ErrDataNodeNotFound
+	}
+	return res, nil
+}
+
+func (s *DosNameNodeServer) ListGhosts(ctx context.Context, req *api.ListGhostsReq) (*api.ListGhostsRes, error) {
+	res := &api.ListGhostsRes{}
+	s.Transactional(func() {
+		for _, ghost := range s.ghosts {
+			res.Ghosts = append(res.Ghosts, &api.GhostSummary{
+				Name:  ghost.Id,
+				Since: timestamppb.New(ghost.Since),
+			})
+		}
+	})
+	slices.SortFunc(res.Ghosts, func(a, b *api.GhostSummary) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return res, nil
+}
+
+func (s *DosNameNodeServer) ObjectLocations(ctx context.Context, req *api.ObjectLocationsReq) (*api.ObjectLocationsRes, error) {
+	var res *api.ObjectLocationsRes
+	var transactionErr error
+	s.Transactional(func() {
+		nodes, err := s.flatNS.Nodes(req.Name)
+		if err != nil {
+			transactionErr = err
+			return
+		}
+		slices.Sort(nodes)
+		res = &api.ObjectLocationsRes{
+			Name:        req.Name,
+			Replication: uint32(s.flatNS.Replication(req.Name)),
+			Constraints: s.flatNS.Constraints(req.Name).proto(),
+		}
+		for _, node := range nodes {
+			replica := &api.ObjectReplica{Node: node}
+			// a ghost that took over the node's objects is not a registered datanode yet
+			if entry := s.meta.Get(node); entry != nil {
+				replica.Leaser = entry.Lease
+			}
+			res.Replicas = append(res.Replicas, replica)
+		}
+	})
+	if transactionErr != nil {
+		return nil, transactionErr
+	}
+	return res, nil
+}
+
+func (s *DosNameNodeServer) ClusterSummary(ctx context.Context, req *api.ClusterSummaryReq) (*api.ClusterSummaryRes, error) {
+	var res *api.ClusterSummaryRes
+	s.Transactional(func() {
+		decommissioning, inMaintenance := 0, 0
+		s.meta.ForEach(func(entry *MetaHeapEntry) {
+			switch entry.State {
+			case NodeDecommissioning:
+				decommissioning++
+			case NodeMaintenance:
+				inMaintenance++
+			}
+		})
+		res = &api.ClusterSummaryRes{
+			ClusterId:       s.clusterId,
+			Lamport:         uint64(s.lamport),
+			Nodes:           uint32(s.meta.Count()),
+			Ghosts:          uint32(len(s.ghosts)),
+			Objects:         uint32(s.flatNS.Len()),
+			UnderReplicated: uint32(s.flatNS.UnderReplicated()),
+			Snapshots:       uint32(len(s.snapshots)),
+			Replication:     uint32(s.config.Replication),
+			Tolerance:       uint32(max(s.config.Tolerance, 0)),
+			Decommissioning: uint32(decommissioning),
+			Maintenance:     uint32(inMaintenance),
+			Rebalancing:     s.rebalancing,
+		}
+	})
+	return res, nil
+}
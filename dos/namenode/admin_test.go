@@ -0,0 +1,218 @@
+package namenode
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mrowaha/dos/api"
+)
+
+func TestListNodes(t *testing.T) {
+	s := newTestNameNode(t, 2, "o1", "o2", "pinned\t{\"replication\":1,\"constraints\":{\"required\":{\"disk\":\"ssd\"}}}")
+	// registered out of name order, listed in it
+	registerFake(t, s, 1, "o1")
+	registerFake(t, s, 0, "o1", "o2", "pinned")
+	s.Transactional(func() {
+		entry := s.meta.Get("node-1")
+		entry.Zone, entry.Rack, entry.Labels = "z1", "r1", labels("disk", "hdd")
+		entry.State = NodeDecommissioning
+		entry.Drain = &DrainProgress{Total: 1, Failed: 1, Err: errors.New("no target"), Running: true}
+	})
+
+	res, err := s.ListNodes(context.Background(), &api.ListNodesReq{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		objects uint32
+		state   api.NodeSummary_State
+		zone    string
+		drain   *api.DrainProgress
+	}{
+		{name: "node-0", objects: 3, state: api.NodeSummary_ACTIVE},
+		{name: "node-1", objects: 1, state: api.NodeSummary_DECOMMISSIONING, zone: "z1", drain: &api.DrainProgress{Total: 1, Failed: 1, Error: "no target", Running: true}},
+	}
+	if len(res.Nodes) != len(tests) {
+		t.Fatalf("nodes = %v", res.Nodes)
+	}
+	for i, tt := range tests {
+		node := res.Nodes[i]
+		if node.Name != tt.name || node.Objects != tt.objects || node.State != tt.state || node.Zone != tt.zone {
+			t.Errorf("node %d = %s with %d objects %s in %q, want %s with %d %s in %q",
+				i, node.Name, node.Objects, node.State, node.Zone, tt.name, tt.objects, tt.state, tt.zone)
+		}
+		if node.Fence == 0 || node.Joined.AsTime().IsZero() {
+			t.Errorf("%s has fence %d, joined %s", node.Name, node.Fence, node.Joined.AsTime())
+		}
+		if (node.Drain == nil) != (tt.drain == nil) || node.Drain != nil && node.Drain.String() != tt.drain.String() {
+			t.Errorf("%s drain = %v, want %v", node.Name, node.Drain, tt.drain)
+		}
+	}
+}
+
+func TestNodeInfo(t *testing.T) {
+	s := newTestNameNode(t, 1, "b", "a", "c")
+	registerFake(t, s, 0, "b", "a")
+	registerFake(t, s, 1, "c")
+	if _, err := s.StartMaintenance(context.Background(), &api.StartMaintenanceReq{Name: "node-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		objects     []string
+		maintenance bool
+		err         error
+	}{
+		{name: "node-0", objects: []string{"a", "b"}},
+		{name: "node-1", objects: []string{"c"}, maintenance: true},
+		{name: "node-2", err: ErrDataNodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := s.NodeInfo(context.Background(), &api.NodeInfoReq{Name: tt.name})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+			if !slices.Equal(res.Objects, tt.objects) {
+				t.Errorf("objects = %v, want %v", res.Objects, tt.objects)
+			}
+			if (res.Node.Maintenance != nil) != tt.maintenance {
+				t.Errorf("maintenance = %v, want %v", res.Node.Maintenance, tt.maintenance)
+			}
+			if tt.maintenance && (res.Node.Maintenance.Away || !res.Node.Maintenance.Until.AsTime().After(time.Now())) {
+				t.Errorf("maintenance = %v, want a window ahead of a node that is here", res.Node.Maintenance)
+			}
+			// every command the fake got was acked
+			if res.Awaiting != 0 {
+				t.Errorf("%d commands awaiting their ack", res.Awaiting)
+			}
+		})
+	}
+}
+
+func TestListGhosts(t *testing.T) {
+	s := newTestNameNode(t, 1)
+	since := time.Now().Add(-time.Minute)
+	s.Transactional(func() {
+		for _, name := range []string{"g2", "g1"} {
+			s.ghosts[name] = &GhostNodeEntry{Id: name, Since: since}
+		}
+	})
+
+	res, err := s.ListGhosts(context.Background(), &api.ListGhostsReq{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(res.Ghosts))
+	for _, ghost := range res.Ghosts {
+		names = append(names, ghost.Name)
+		if !ghost.Since.AsTime().Equal(since) {
+			t.Errorf("%s since %s, want %s", ghost.Name, ghost.Since.AsTime(), since)
+		}
+	}
+	if !slices.Equal(names, []string{"g1", "g2"}) {
+		t.Errorf("ghosts = %v", names)
+	}
+}
+
+func TestObjectLocations(t *testing.T) {
+	s := newTestNameNode(t, 2, "object", "pinned\t{\"replication\":1,\"constraints\":{\"required\":{\"disk\":\"ssd\"}}}")
+	registerFake(t, s, 1, "object", "pinned")
+	s.Transactional(func() {
+		s.meta.Get("node-1").Lease = "tcp://node-1:5555"
+		// the objects of a failed node went to a ghost that did not register yet
+		s.flatNS.AddNode("object", "ghost")
+	})
+
+	tests := []struct {
+		name        string
+		replicas    []*api.ObjectReplica
+		replication uint32
+		constraints *api.PlacementConstraints
+		err         error
+	}{
+		{
+			name:        "object",
+			replicas:    []*api.ObjectReplica{{Node: "ghost"}, {Node: "node-1", Leaser: "tcp://node-1:5555"}},
+			replication: 2,
+		},
+		{
+			name:        "pinned",
+			replicas:    []*api.ObjectReplica{{Node: "node-1", Leaser: "tcp://node-1:5555"}},
+			replication: 1,
+			constraints: &api.PlacementConstraints{Required: labels("disk", "ssd")},
+		},
+		{name: "missing", err: ErrObjectDoestNotExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := s.ObjectLocations(context.Background(), &api.ObjectLocationsReq{Name: tt.name})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+			if !slices.EqualFunc(res.Replicas, tt.replicas, func(a, b *api.ObjectReplica) bool {
+				return a.Node == b.Node && a.Leaser == b.Leaser
+			}) {
+				t.Errorf("replicas = %v, want %v", res.Replicas, tt.replicas)
+			}
+			if res.Replication != tt.replication {
+				t.Errorf("replication = %d, want %d", res.Replication, tt.replication)
+			}
+			if res.Constraints.String() != tt.constraints.String() {
+				t.Errorf("constraints = %v, want %v", res.Constraints, tt.constraints)
+			}
+		})
+	}
+}
+
+func TestClusterSummary(t *testing.T) {
+	s := newTestNameNode(t, 2, "o1", "o2", "o3")
+	registerFake(t, s, 0, "o1", "o2")
+	registerFake(t, s, 1, "o1")
+	registerFake(t, s, 2)
+	s.Transactional(func() {
+		s.meta.Get("node-1").State = NodeDecommissioning
+		s.ghosts["g1"] = &GhostNodeEntry{Id: "g1"}
+	})
+	if _, err := s.StartMaintenance(context.Background(), &api.StartMaintenanceReq{Name: "node-2"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateSnapshot(context.Background(), &api.CreateSnapshotReq{Name: "s"}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := s.ClusterSummary(context.Background(), &api.ClusterSummaryReq{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &api.ClusterSummaryRes{
+		ClusterId:       s.clusterId,
+		Lamport:         res.Lamport,
+		Nodes:           3,
+		Ghosts:          1,
+		Objects:         3,
+		UnderReplicated: 2,
+		Snapshots:       1,
+		Replication:     2,
+		Decommissioning: 1,
+		Maintenance:     1,
+	}
+	if res.String() != want.String() {
+		t.Errorf("summary = %v, want %v", res, want)
+	}
+	// the snapshot took a lamport
+	if Lamport(res.Lamport) == NewLamport(s.lamport.Epoch(), 0) {
+		t.Errorf("lamport = %s, want the snapshot's", Lamport(res.Lamport))
+	}
+}
@@ -0,0 +1,22 @@
+package namenode
+
+import (
+	"math/rand"
+	"time"
+)
+
+/**
+	datanodes reconnecting to the namenode and clients retrying their calls back off the same way,
+	exponentially with equal jitter, so a namenode coming back is not hit by every one of them at once
+**/
+
+// Backoff returns the delay before the given attempt, counted from zero
+// it doubles from minDelay up to maxDelay, and half of it is jitter
+func Backoff(attempt int, minDelay time.Duration, maxDelay time.Duration) time.Duration {
+	delay := maxDelay
+	if attempt < 32 && minDelay<<attempt < maxDelay {
+		delay = minDelay << attempt
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
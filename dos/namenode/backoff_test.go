@@ -0,0 +1,29 @@
+package namenode
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	minDelay, maxDelay := 100*time.Millisecond, 5*time.Second
+	tests := []struct {
+		attempt int
+		delay   time.Duration // before jitter
+	}{
+		{attempt: 0, delay: minDelay},
+		{attempt: 1, delay: 2 * minDelay},
+		{attempt: 5, delay: 32 * minDelay},
+		{attempt: 6, delay: maxDelay},
+		{attempt: 40, delay: maxDelay},
+		{attempt: 1000, delay: maxDelay},
+	}
+	for _, tt := range tests {
+		for range 20 {
+			// equal jitter keeps at least half of the delay
+			if got := Backoff(tt.attempt, minDelay, maxDelay); got < tt.delay/2 || got > tt.delay {
+				t.Fatalf("Backoff(%d) = %s, want within [%s, %s]", tt.attempt, got, tt.delay/2, tt.delay)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package namenode
+
+/**
+	a datanode that registers reports the lamport of the last command it applied, and continues from there
+	when the namenode can send it every command after it. the commands it missed come from its maintenance
+	buffer if it was parked, otherwise from the most recent broadcasts the namenode keeps for everyone.
+	a node whose gap is no longer kept missed commands nobody can replay, so its store is wiped and resynced
+
+	a node from a previous epoch was registered with a namenode that has since restarted, and the commands of
+	that namenode after the node's lamport were lost with it. a node behind another one that registered in this
+	epoch missed some of them for sure and is resynced, any other is trusted with what it applied, as there is
+	no telling whether the previous namenode sent anything after it. it then catches up on this epoch like
+	any node. a node from an epoch this namenode never had, like one of a namenode whose epoch file was lost,
+	can not be placed in the order at all and is resynced
+**/
+
+// recentBroadcasts keeps the last broadcasts, in lamport order
+type recentBroadcasts struct {
+	commands []CommandNode
+	limit    int
+}
+
+func (r *recentBroadcasts) record(cmd CommandNode) {
+	if r.limit <= 0 {
+		return
+	}
+	if len(r.commands) == r.limit {
+		r.commands = r.commands[1:]
+	}
+	r.commands = append(r.commands, cmd)
+}
+
+// since returns the commands after lamport up to now
+// it returns false when some of them are no longer kept
+func (r *recentBroadcasts) since(lamport Lamport, now Lamport) ([]CommandNode, bool) {
+	if lamport == now {
+		return nil, true
+	}
+	// broadcasts take every lamport in turn, so the oldest kept one tells what is covered
+	if len(r.commands) == 0 || lamport+1 < r.commands[0].stamp() {
+		return nil, false
+	}
+	missed := make([]CommandNode, 0, len(r.commands))
+	for _, cmd := range r.commands {
+		if cmd.stamp() > lamport {
+			missed = append(missed, cmd)
+		}
+	}
+	return missed, true
+}
+
+// catchUp decides how a registering datanode at lamport reported continues, called under the global lock
+// it returns the register cut, the commands to send after it, and whether the node's store is resynced
+// parked is the node's entry when it comes back from maintenance
+func (s *DosNameNodeServer) catchUp(reported Lamport, parked *MetaHeapEntry) (Lamport, []CommandNode, bool) {
+	epoch := s.lamport.Epoch()
+	from := reported
+	switch {
+	case reported.Epoch() == epoch && reported > s.lamport:
+		// it applied commands this namenode never sent
+		return s.lamport, nil, true
+	case reported.Epoch() > epoch:
+		return s.lamport, nil, true
+	case reported.Epoch() < epoch:
+		if reported < s.previous {
+			return s.lamport, nil, true
+		}
+		s.previous = reported
+		from = NewLamport(epoch, 0)
+	}
+
+	if parked != nil {
+		if buffered, ok := parked.Maintenance.missed(from); ok {
+			return from, buffered, false
+		}
+	}
+	if missed, ok := s.recent.since(from, s.lamport); ok {
+		return from, missed, false
+	}
+	return s.lamport, nil, true
+}
@@ -0,0 +1,123 @@
+package namenode
+
+import (
+	"testing"
+
+	"github.com/mrowaha/dos/api"
+)
+
+func deleteAt(lamport Lamport) CommandNode {
+	return CommandNode{
+		command: api.CommandNodeRes_DELETE,
+		delete:  DeleteCommand{Lamport: lamport, Type: DELETE, Name: "object"},
+	}
+}
+
+func stamps(cmds []CommandNode) []Lamport {
+	lamports := make([]Lamport, 0, len(cmds))
+	for _, cmd := range cmds {
+		lamports = append(lamports, cmd.stamp())
+	}
+	return lamports
+}
+
+func TestRecentBroadcasts(t *testing.T) {
+	recent := &recentBroadcasts{limit: 3}
+	for counter := uint32(1); counter <= 5; counter++ {
+		recent.record(deleteAt(NewLamport(1, counter)))
+	}
+	now := NewLamport(1, 5)
+
+	tests := []struct {
+		name    string
+		lamport Lamport
+		want    []Lamport
+		ok      bool
+	}{
+		{name: "in step", lamport: now, want: nil, ok: true},
+		{name: "one behind", lamport: NewLamport(1, 4), want: []Lamport{now}, ok: true},
+		{name: "as far back as kept", lamport: NewLamport(1, 2), want: []Lamport{NewLamport(1, 3), NewLamport(1, 4), now}, ok: true},
+		{name: "further back than kept", lamport: NewLamport(1, 1), ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			missed, ok := recent.since(tt.lamport, now)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			got := stamps(missed)
+			if len(got) != len(tt.want) {
+				t.Fatalf("missed = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("missed = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	if _, ok := (&recentBroadcasts{}).since(NewLamport(1, 4), now); ok {
+		t.Error("caught up without keeping any broadcast")
+	}
+}
+
+func TestCatchUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		sent     uint32 // broadcasts in this epoch so far, the namenode keeps the last three
+		reported Lamport
+		previous Lamport // highest lamport of the last epoch seen so far
+		parked   *Maintenance
+		cut      Lamport
+		missed   int
+		resync   bool
+	}{
+		{name: "in step", sent: 5, reported: NewLamport(2, 5), cut: NewLamport(2, 5)},
+		{name: "one behind", sent: 5, reported: NewLamport(2, 4), cut: NewLamport(2, 4), missed: 1},
+		{name: "behind what is kept", sent: 5, reported: NewLamport(2, 1), cut: NewLamport(2, 5), resync: true},
+		{
+			name:     "behind what is kept, back from maintenance",
+			sent:     5,
+			reported: NewLamport(2, 1),
+			parked:   &Maintenance{Since: NewLamport(2, 1), Commands: []CommandNode{deleteAt(NewLamport(2, 2))}},
+			cut:      NewLamport(2, 1),
+			missed:   1,
+		},
+		{
+			name:     "maintenance buffer overflowed",
+			sent:     5,
+			reported: NewLamport(2, 1),
+			parked:   &Maintenance{Since: NewLamport(2, 1), Overflowed: true},
+			cut:      NewLamport(2, 5),
+			resync:   true,
+		},
+		{name: "ahead of the namenode", sent: 5, reported: NewLamport(2, 6), cut: NewLamport(2, 5), resync: true},
+		{name: "previous epoch", sent: 3, reported: NewLamport(1, 9), previous: NewLamport(1, 9), cut: NewLamport(2, 0), missed: 3},
+		{name: "first of the previous epoch", sent: 3, reported: NewLamport(1, 9), previous: NewLamport(1, 2), cut: NewLamport(2, 0), missed: 3},
+		{name: "previous epoch, behind another node", sent: 3, reported: NewLamport(1, 8), previous: NewLamport(1, 9), cut: NewLamport(2, 3), resync: true},
+		{name: "previous epoch, this one no longer kept", sent: 5, reported: NewLamport(1, 9), cut: NewLamport(2, 5), resync: true},
+		{name: "epoch never had", sent: 5, reported: NewLamport(3, 1), cut: NewLamport(2, 5), resync: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestNameNode(t, 1)
+			// the namenode is in its second epoch
+			s.lamport = NewLamport(2, tt.sent)
+			s.previous = tt.previous
+			s.recent = &recentBroadcasts{limit: 3}
+			for counter := uint32(1); counter <= tt.sent; counter++ {
+				s.recent.record(deleteAt(NewLamport(2, counter)))
+			}
+			var parked *MetaHeapEntry
+			if tt.parked != nil {
+				parked = &MetaHeapEntry{Id: "parked", State: NodeMaintenance, Maintenance: tt.parked}
+			}
+
+			cut, missed, resync := s.catchUp(tt.reported, parked)
+			if cut != tt.cut || len(missed) != tt.missed || resync != tt.resync {
+				t.Errorf("catchUp = %s, %v, %v, want %s, %d missed, %v", cut, stamps(missed), resync, tt.cut, tt.missed, tt.resync)
+			}
+		})
+	}
+}
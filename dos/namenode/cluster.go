@@ -0,0 +1,77 @@
+package namenode
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+/**
+	the cluster id names the cluster a namenode serves, it is generated on the first start and kept
+	in the cluster file. datanodes record it on their first join and are rejected by any other cluster
+	datanodes are told apart by the uuid they generate on their first start, not by their process name
+**/
+
+var (
+	ErrLoadClusterId     = errors.New("failed to load cluster id")
+	ErrInvalidJoinToken  = errors.New("data node presented an invalid join token")
+	ErrForeignDataNode   = errors.New("data node belongs to another cluster")
+	ErrDuplicateDataNode = errors.New("a data node with the same name or uuid is already registered")
+)
+
+// NewUUID returns a random (version 4) uuid
+func NewUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // rfc 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// ValidUUID reports whether id is a uuid in its canonical textual form
+func ValidUUID(id string) bool {
+	if len(id) != 36 {
+		return false
+	}
+	for i, c := range id {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdef", c) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// loadClusterId reads the cluster id from clusterFile, a missing file is a fresh cluster and gets a new id
+func loadClusterId(clusterFile string) (string, error) {
+	data, err := os.ReadFile(clusterFile)
+	if err == nil {
+		id := strings.TrimSpace(string(data))
+		if !ValidUUID(id) {
+			return "", fmt.Errorf("%w: %s is not a uuid", ErrLoadClusterId, id)
+		}
+		return id, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: %v", ErrLoadClusterId, err)
+	}
+
+	id, err := NewUUID()
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrLoadClusterId, err)
+	}
+	if err := writeFileAtomic(clusterFile, []byte(id+"\n")); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrLoadClusterId, err)
+	}
+	return id, nil
+}
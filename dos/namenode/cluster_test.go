@@ -0,0 +1,78 @@
+package namenode
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mrowaha/dos/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewUUID(t *testing.T) {
+	id, err := NewUUID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ValidUUID(id) {
+		t.Errorf("%s is not a valid uuid", id)
+	}
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{id: "00000000-0000-0000-0000-000000000001", want: true},
+		{id: "00000000-0000-0000-0000-00000000000", want: false},
+		{id: "00000000-0000-0000-0000-00000000000G", want: false},
+		{id: "00000000-0000-0000-0000-00000000000A", want: false},
+		{id: "000000000000-0000-0000-000000000001", want: false},
+		{id: "", want: false},
+	}
+	for _, tt := range tests {
+		if got := ValidUUID(tt.id); got != tt.want {
+			t.Errorf("ValidUUID(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterRejects(t *testing.T) {
+	s := newTestNameNode(t, 1)
+	registerFake(t, s, 0)
+	s.config.JoinToken = "secret"
+
+	tests := []struct {
+		name   string
+		change func(beat *api.NodeHeartBeat)
+		want   codes.Code
+	}{
+		{name: "no join token", change: func(beat *api.NodeHeartBeat) { beat.JoinToken = "" }, want: codes.PermissionDenied},
+		{name: "wrong join token", change: func(beat *api.NodeHeartBeat) { beat.JoinToken = "guess" }, want: codes.PermissionDenied},
+		{name: "no uuid", change: func(beat *api.NodeHeartBeat) { beat.NodeId = "" }, want: codes.InvalidArgument},
+		{
+			name:   "another cluster",
+			change: func(beat *api.NodeHeartBeat) { beat.ClusterId = "00000000-0000-0000-0000-0000000000ff" },
+			want:   codes.FailedPrecondition,
+		},
+		{name: "name taken", change: func(beat *api.NodeHeartBeat) { beat.NodeId = "00000000-0000-0000-0000-000000000001" }, want: codes.AlreadyExists},
+		{name: "uuid taken", change: func(beat *api.NodeHeartBeat) { beat.Id = "node-1" }, want: codes.AlreadyExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// node-0 with the token, as registered
+			beat := &api.NodeHeartBeat{
+				Id:        "node-0",
+				NodeId:    "00000000-0000-0000-0000-000000000000",
+				JoinToken: "secret",
+				Type:      api.NodeHeartBeat_BEAT,
+			}
+			tt.change(beat)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			node := &fakeDataNode{ctx: ctx, in: make(chan *api.NodeHeartBeat, 1)}
+			node.in <- beat
+			if err := s.RegisterNode(node); status.Code(err) != tt.want {
+				t.Errorf("err = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
@@ -4,99 +4,136 @@ import (
 	"log"
 
 	"github.com/mrowaha/dos/api"
-	"google.golang.org/grpc"
 )
 
+// the mux turns queued commands into their wire form
+// it does not send anything, the datanode session is the only writer of a stream
 type DataNodeCommandMux struct {
-	stream grpc.BidiStreamingServer[api.NodeHeartBeat, api.CommandNodeRes]
 	logger *log.Logger
 }
 
-func NewDataNodeCommandMux(stream grpc.BidiStreamingServer[api.NodeHeartBeat, api.CommandNodeRes], logger *log.Logger) *DataNodeCommandMux {
+func NewDataNodeCommandMux(logger *log.Logger) *DataNodeCommandMux {
 	return &DataNodeCommandMux{
-		stream: stream,
 		logger: logger,
 	}
 }
 
-func (mux *DataNodeCommandMux) Handle(cmd *CommandNode) {
+func (mux *DataNodeCommandMux) Encode(cmd *CommandNode) *api.CommandNodeRes {
 	switch cmd.command {
+	case api.CommandNodeRes_REGISTER:
+		return mux.register(cmd.command, cmd.id, &cmd.register)
 	case api.CommandNodeRes_CREATE:
-		mux.create(cmd.command, cmd.tag, &cmd.create)
+		return mux.create(cmd.command, cmd.id, &cmd.create)
 	case api.CommandNodeRes_COMMIT:
-		mux.commit(cmd.command, cmd.tag, &cmd.commit)
+		return mux.commit(cmd.command, cmd.id, &cmd.commit)
 	case api.CommandNodeRes_DELETE:
-		mux.delete(cmd.command, cmd.tag, &cmd.delete)
+		return mux.delete(cmd.command, cmd.id, &cmd.delete)
 	case api.CommandNodeRes_UPDATE:
-		mux.update(cmd.command, cmd.tag, &cmd.update)
+		return mux.update(cmd.command, cmd.id, &cmd.update)
 	case api.CommandNodeRes_DISTRIBUTED_READ:
-		mux.distributedRead(cmd.command, cmd.tag, &cmd.distributedRead)
+		return mux.distributedRead(cmd.command, cmd.id, &cmd.distributedRead)
+	case api.CommandNodeRes_SNAPSHOT, api.CommandNodeRes_DELETE_SNAPSHOT:
+		return mux.snapshot(cmd.command, cmd.id, &cmd.snapshot)
+	case api.CommandNodeRes_DECOMMISSION:
+		mux.logger.Printf("sending decommission request %d\n", cmd.id)
+		return &api.CommandNodeRes{Command: cmd.command, RequestId: cmd.id}
 	}
+	return nil
 }
 
-func (mux *DataNodeCommandMux) commit(cmd api.CommandNodeRes_Command, tag string, req *CommitCommand) {
-	mux.logger.Printf("sending commit tagged %s @lamport%d\n", tag, req.Lamport)
+func (mux *DataNodeCommandMux) commit(cmd api.CommandNodeRes_Command, id uint64, req *CommitCommand) *api.CommandNodeRes {
+	mux.logger.Printf("sending commit request %d @lamport%s\n", id, req.Lamport)
 	// time.Sleep(5 * time.Second)
 	apicmd := &api.CommandNodeRes{
-		Command:    cmd,
-		MessageTag: tag,
+		Command:   cmd,
+		RequestId: id,
 		Commit: &api.CommitCommand{
-			Lamport:    req.Lamport,
+			Lamport:    uint64(req.Lamport),
 			ObjectName: req.Name,
+			Nodes:      req.Nodes,
 		},
 	}
-	mux.stream.Send(apicmd)
+	return apicmd
 }
 
-func (mux *DataNodeCommandMux) create(cmd api.CommandNodeRes_Command, tag string, req *CreateCommand) {
-	mux.logger.Printf("sending create tagged %s\n", tag)
+func (mux *DataNodeCommandMux) create(cmd api.CommandNodeRes_Command, id uint64, req *CreateCommand) *api.CommandNodeRes {
+	mux.logger.Printf("sending create request %d\n", id)
 	apicmd := &api.CommandNodeRes{
-		Command:    cmd,
-		MessageTag: tag,
+		Command:   cmd,
+		RequestId: id,
 		Create: &api.CreateCommand{
 			ObjectName: req.Name,
 			ObjectData: req.Data,
 		},
 	}
-	mux.stream.Send(apicmd)
+	return apicmd
 }
 
-func (mux *DataNodeCommandMux) delete(cmd api.CommandNodeRes_Command, tag string, req *DeleteCommand) {
-	mux.logger.Printf("sending delete tagged %s @lamport%d\n", tag, req.Lamport)
+func (mux *DataNodeCommandMux) delete(cmd api.CommandNodeRes_Command, id uint64, req *DeleteCommand) *api.CommandNodeRes {
+	mux.logger.Printf("sending delete request %d @lamport%s\n", id, req.Lamport)
 	apicmd := &api.CommandNodeRes{
-		Command:    cmd,
-		MessageTag: tag,
+		Command:   cmd,
+		RequestId: id,
 		Delete: &api.DeleteCommand{
-			Lamport:    req.Lamport,
+			Lamport:    uint64(req.Lamport),
 			ObjectName: req.Name,
+			Node:       req.Node,
 		},
 	}
-	mux.stream.Send(apicmd)
+	return apicmd
 }
 
-func (mux *DataNodeCommandMux) update(cmd api.CommandNodeRes_Command, tag string, req *UpdateCommand) {
-	mux.logger.Printf("sending update tagged %s @lamport%d\n", tag, req.Lamport)
+func (mux *DataNodeCommandMux) update(cmd api.CommandNodeRes_Command, id uint64, req *UpdateCommand) *api.CommandNodeRes {
+	mux.logger.Printf("sending update request %d @lamport%s\n", id, req.Lamport)
 	apicmd := &api.CommandNodeRes{
-		Command:    cmd,
-		MessageTag: tag,
+		Command:   cmd,
+		RequestId: id,
 		Update: &api.UpdateCommand{
-			Lamport:    req.Lamport,
+			Lamport:    uint64(req.Lamport),
 			ObjectName: req.Name,
 			ObjectData: req.Data,
 		},
 	}
-	mux.stream.Send(apicmd)
+	return apicmd
 }
 
-func (mux *DataNodeCommandMux) distributedRead(cmd api.CommandNodeRes_Command, tag string, req *DistributedReadCommand) {
-	mux.logger.Printf("sending distributed read tagged %s @lamport%d\n", tag, req.Lamport)
+func (mux *DataNodeCommandMux) distributedRead(cmd api.CommandNodeRes_Command, id uint64, req *DistributedReadCommand) *api.CommandNodeRes {
+	mux.logger.Printf("sending distributed read request %d @lamport%s\n", id, req.Lamport)
 	apicmd := &api.CommandNodeRes{
-		Command:    cmd,
-		MessageTag: tag,
+		Command:   cmd,
+		RequestId: id,
 		DistributedRead: &api.DistributedReadCommand{
-			Objects: req.Objects,
-			Lamport: req.Lamport,
+			Objects:  req.Objects,
+			Lamport:  uint64(req.Lamport),
+			Snapshot: req.Snapshot,
 		},
 	}
-	mux.stream.Send(apicmd)
+	return apicmd
+}
+
+func (mux *DataNodeCommandMux) snapshot(cmd api.CommandNodeRes_Command, id uint64, req *SnapshotCommand) *api.CommandNodeRes {
+	mux.logger.Printf("sending %s request %d @lamport%s\n", req.Type, id, req.Lamport)
+	apicmd := &api.CommandNodeRes{
+		Command:   cmd,
+		RequestId: id,
+		Snapshot: &api.SnapshotCommand{
+			Lamport: uint64(req.Lamport),
+			Name:    req.Name,
+		},
+	}
+	return apicmd
+}
+
+func (mux *DataNodeCommandMux) register(cmd api.CommandNodeRes_Command, id uint64, req *RegisterCommand) *api.CommandNodeRes {
+	mux.logger.Printf("sending register request %d @lamport%s\n", id, req.Lamport)
+	apicmd := &api.CommandNodeRes{
+		Command:   cmd,
+		RequestId: id,
+		Register: &api.RegisterCommand{
+			Lamport:   uint64(req.Lamport),
+			ClusterId: req.ClusterId,
+			Resync:    req.Resync,
+		},
+	}
+	return apicmd
 }
@@ -0,0 +1,89 @@
+package namenode
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/mrowaha/dos/api"
+)
+
+/**
+	datanodes register with arbitrary key/value labels, like disk=ssd or compliance=pci, and an object can be
+	created with placement constraints over them. required labels are hard: only nodes carrying every one of them
+	ever hold a replica, and an object no node matches is not created. preferred labels are soft: among the nodes
+	allowed, the ones matching the most of them are picked first, after spreading over failure domains
+
+	the constraints are kept with the object in the namespace, so re-replication and rebalancing honour them too,
+	and a rebalance round moves replicas off nodes that no longer match, like a node that registered with new labels
+**/
+
+type PlacementConstraints struct {
+	Required  map[string]string `json:"required,omitempty"`
+	Preferred map[string]string `json:"preferred,omitempty"`
+}
+
+// constraintsFrom reads the constraints of a request, nil when there are none
+func constraintsFrom(req *api.PlacementConstraints) *PlacementConstraints {
+	if len(req.GetRequired()) == 0 && len(req.GetPreferred()) == 0 {
+		return nil
+	}
+	return &PlacementConstraints{
+		Required:  maps.Clone(req.Required),
+		Preferred: maps.Clone(req.Preferred),
+	}
+}
+
+func (c *PlacementConstraints) proto() *api.PlacementConstraints {
+	if c == nil {
+		return nil
+	}
+	return &api.PlacementConstraints{Required: c.Required, Preferred: c.Preferred}
+}
+
+// matching counts the labels of selector the node carries
+func matching(entry *MetaHeapEntry, selector map[string]string) int {
+	count := 0
+	for key, value := range selector {
+		if label, ok := entry.Labels[key]; ok && label == value {
+			count++
+		}
+	}
+	return count
+}
+
+// allows tells whether the node may hold a replica, every node may when there are no constraints
+func (c *PlacementConstraints) allows(entry *MetaHeapEntry) bool {
+	return c == nil || matching(entry, c.Required) == len(c.Required)
+}
+
+// allowed keeps the candidates with every required label
+func (c *PlacementConstraints) allowed(candidates []*MetaHeapEntry) []*MetaHeapEntry {
+	if c == nil || len(c.Required) == 0 {
+		return candidates
+	}
+	return slices.DeleteFunc(slices.Clone(candidates), func(entry *MetaHeapEntry) bool {
+		return !c.allows(entry)
+	})
+}
+
+// preference is how many preferred labels the node carries
+func (c *PlacementConstraints) preference(entry *MetaHeapEntry) int {
+	if c == nil {
+		return 0
+	}
+	return matching(entry, c.Preferred)
+}
+
+// preferred keeps the candidates carrying the most preferred labels
+func (c *PlacementConstraints) preferred(candidates []*MetaHeapEntry) []*MetaHeapEntry {
+	if c == nil || len(c.Preferred) == 0 {
+		return candidates
+	}
+	most := 0
+	for _, entry := range candidates {
+		most = max(most, c.preference(entry))
+	}
+	return slices.DeleteFunc(slices.Clone(candidates), func(entry *MetaHeapEntry) bool {
+		return c.preference(entry) != most
+	})
+}
@@ -0,0 +1,146 @@
+package namenode
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mrowaha/dos/api"
+)
+
+func TestConstraintsFrom(t *testing.T) {
+	if c := constraintsFrom(nil); c != nil {
+		t.Errorf("constraints of no request = %v, want nil", c)
+	}
+	if c := constraintsFrom(&api.PlacementConstraints{Required: map[string]string{}}); c != nil {
+		t.Errorf("constraints of empty selectors = %v, want nil", c)
+	}
+	req := &api.PlacementConstraints{Required: labels("disk", "ssd"), Preferred: labels("tier", "fast")}
+	c := constraintsFrom(req)
+	req.Required["disk"] = "hdd"
+	if c.Required["disk"] != "ssd" || c.Preferred["tier"] != "fast" {
+		t.Errorf("constraints = %v, want them apart from the request", c)
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	candidates := []*MetaHeapEntry{
+		{Id: "a", Labels: labels("disk", "ssd", "region", "eu")},
+		{Id: "b", Labels: labels("disk", "ssd")},
+		{Id: "c", Labels: labels("disk", "hdd", "region", "eu")},
+		{Id: "d"},
+	}
+	tests := []struct {
+		name        string
+		constraints *PlacementConstraints
+		want        []string
+	}{
+		{name: "no constraints", want: []string{"a", "b", "c", "d"}},
+		{name: "only preferred", constraints: &PlacementConstraints{Preferred: labels("disk", "ssd")}, want: []string{"a", "b", "c", "d"}},
+		{name: "one label", constraints: &PlacementConstraints{Required: labels("disk", "ssd")}, want: []string{"a", "b"}},
+		{name: "every label", constraints: &PlacementConstraints{Required: labels("disk", "ssd", "region", "eu")}, want: []string{"a"}},
+		{name: "no node matches", constraints: &PlacementConstraints{Required: labels("disk", "nvme")}, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ids(tt.constraints.allowed(candidates)); !slices.Equal(got, tt.want) {
+				t.Errorf("allowed = %v, want %v", got, tt.want)
+			}
+			for _, entry := range candidates {
+				if allows := tt.constraints.allows(entry); allows != slices.Contains(tt.want, entry.Id) {
+					t.Errorf("allows(%s) = %v", entry.Id, allows)
+				}
+			}
+		})
+	}
+}
+
+func TestPreferred(t *testing.T) {
+	candidates := []*MetaHeapEntry{
+		{Id: "a", Labels: labels("disk", "ssd", "region", "eu")},
+		{Id: "b", Labels: labels("disk", "ssd")},
+		{Id: "c", Labels: labels("region", "eu")},
+		{Id: "d"},
+	}
+	tests := []struct {
+		name        string
+		constraints *PlacementConstraints
+		want        []string
+	}{
+		{name: "no constraints", want: []string{"a", "b", "c", "d"}},
+		{name: "one label", constraints: &PlacementConstraints{Preferred: labels("disk", "ssd")}, want: []string{"a", "b"}},
+		{name: "most labels", constraints: &PlacementConstraints{Preferred: labels("disk", "ssd", "region", "eu")}, want: []string{"a"}},
+		{name: "no node matches", constraints: &PlacementConstraints{Preferred: labels("disk", "nvme")}, want: []string{"a", "b", "c", "d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ids(tt.constraints.preferred(candidates)); !slices.Equal(got, tt.want) {
+				t.Errorf("preferred = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPickHonoursConstraints(t *testing.T) {
+	tests := []struct {
+		name        string
+		n           int
+		constraints *PlacementConstraints
+		want        []string
+	}{
+		{name: "required label", n: 3, constraints: &PlacementConstraints{Required: labels("disk", "ssd")}, want: []string{"b", "d"}},
+		// spreading over the zones comes before the preferred labels
+		{name: "preferred label", n: 2, constraints: &PlacementConstraints{Preferred: labels("tier", "fast")}, want: []string{"c", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestNameNode(t, 1)
+			layout(t, s, []*MetaHeapEntry{
+				{Id: "a", Size: 1, Zone: "z1"},
+				{Id: "b", Size: 2, Zone: "z2", Labels: labels("disk", "ssd")},
+				{Id: "c", Size: 3, Zone: "z1", Labels: labels("tier", "fast")},
+				{Id: "d", Size: 4, Zone: "z1", Labels: labels("disk", "ssd", "tier", "fast")},
+			}, nil)
+
+			var picked []*MetaHeapEntry
+			s.Transactional(func() {
+				picked = s.pick(tt.n, tt.constraints, nil, nil)
+			})
+			if got := ids(picked); !slices.Equal(got, tt.want) {
+				t.Errorf("picked %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateWithConstraints(t *testing.T) {
+	s := newTestNameNode(t, 1)
+	registerFake(t, s, 0)
+	ssd := registerFake(t, s, 1)
+	s.Transactional(func() {
+		s.meta.Get("node-1").Labels = labels("disk", "ssd")
+	})
+
+	_, err := s.CreateObject(context.Background(), &api.CreateObjectRequest{
+		Name:        "fast",
+		Data:        []byte("data"),
+		Constraints: &api.PlacementConstraints{Required: labels("disk", "ssd")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := replicas(s, "fast"); !slices.Equal(got, []string{"node-1"}) {
+		t.Errorf("replicas = %v, want node-1", got)
+	}
+	eventually(t, func() bool { return ssd.holds("fast") })
+
+	_, err = s.CreateObject(context.Background(), &api.CreateObjectRequest{
+		Name:        "nowhere",
+		Data:        []byte("data"),
+		Constraints: &api.PlacementConstraints{Required: labels("disk", "nvme")},
+	})
+	if !errors.Is(err, ErrNotEnoughDataNodes) {
+		t.Errorf("err = %v, want %v", err, ErrNotEnoughDataNodes)
+	}
+}
@@ -1,10 +1,13 @@
 package namenode
 
 import (
+	"crypto/subtle"
 	"errors"
-	"sync/atomic"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/mrowaha/dos/api"
 )
@@ -21,6 +24,8 @@ const (
 	DELETE          BroadcastEvent = "delete"
 	UPDATE          BroadcastEvent = "update"
 	DISTRIBUTEDREAD BroadcastEvent = "distributed-read"
+	SNAPSHOT        BroadcastEvent = "snapshot"
+	DELETESNAPSHOT  BroadcastEvent = "delete-snapshot"
 )
 
 type CreateCommand struct {
@@ -29,247 +34,496 @@ type CreateCommand struct {
 }
 
 type CommitCommand struct {
-	Lamport int32          `json:"-"`
-	Type    BroadcastEvent `json:"type"`
-	Name    string         `json:"name"`
+	Lamport   Lamport        `json:"-"`
+	Type      BroadcastEvent `json:"type"`
+	Name      string         `json:"name"`
+	RequestId uint64         `json:"requestId"`
+	Nodes     []string       `json:"nodes,omitempty"` // only these datanodes commit, a create that failed leaves stages elsewhere
 }
 
 type DeleteCommand struct {
-	Lamport int32          `json:"-"`
-	Name    string         `json:"name"`
-	Type    BroadcastEvent `json:"type"`
+	Lamport   Lamport        `json:"-"`
+	Name      string         `json:"name"`
+	Type      BroadcastEvent `json:"type"`
+	RequestId uint64         `json:"requestId"`
+	Node      string         `json:"node,omitempty"` // only this datanode drops its replica when set
 }
 
 type UpdateCommand struct {
-	Lamport int32          `json:"-"`
-	Name    string         `json:"name"`
-	Data    []byte         `json:"data"`
-	Type    BroadcastEvent `json:"type"`
+	Lamport   Lamport        `json:"-"`
+	Name      string         `json:"name"`
+	Data      []byte         `json:"data"`
+	Type      BroadcastEvent `json:"type"`
+	RequestId uint64         `json:"requestId"`
 }
 
 type DistributedReadCommand struct {
-	Lamport int32          `json:"-"`
-	Objects []string       `json:"objects"`
-	Type    BroadcastEvent `json:"type"`
+	Lamport  Lamport        `json:"-"`
+	Objects  []string       `json:"objects"`
+	Snapshot string         `json:"snapshot"`
+	Type     BroadcastEvent `json:"type"`
+	// a blocked command is answered when it is delivered, the answer carries the id of the request
+	RequestId uint64 `json:"requestId"`
+}
+
+// snapshot commands are used both to take and to drop a snapshot
+// the event type tells which one it is
+type SnapshotCommand struct {
+	Lamport   Lamport        `json:"-"`
+	Name      string         `json:"name"`
+	Type      BroadcastEvent `json:"type"`
+	RequestId uint64         `json:"requestId"`
+}
+
+// register commands hand a datanode the namenode's lamport cut and cluster id when it registers
+type RegisterCommand struct {
+	Lamport   Lamport
+	ClusterId string
+	Resync    bool
 }
 
 type CommandNode struct {
-	tag             string
+	id              uint64 // assigned by the session when the command is queued
+	register        RegisterCommand
 	command         api.CommandNodeRes_Command
 	commit          CommitCommand
 	create          CreateCommand
 	delete          DeleteCommand
 	update          UpdateCommand
 	distributedRead DistributedReadCommand
+	snapshot        SnapshotCommand
 }
 
 // this file contains definitions for the datanode service
 func (s *DosNameNodeServer) RegisterNode(stream grpc.BidiStreamingServer[api.NodeHeartBeat, api.CommandNodeRes]) error {
 	var dataNodeID string
 	// block for first heartbeat
-	req, _ := stream.Recv()
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
 	dataNodeID = req.Id
-	s.logger.Printf("registering data node %s", dataNodeID)
+	s.logger.Printf("registering data node %s (%s)", dataNodeID, req.NodeId)
 
-	reqChan := make(chan CommandNode)
-	resChans := make(map[string]chan interface{})
-	closedCh := make(chan struct{}, 1) // size of the channel is one
-	// because we do not want to block when the stream is closed
+	if subtle.ConstantTimeCompare([]byte(req.JoinToken), []byte(s.config.JoinToken)) != 1 {
+		s.logger.Printf("[datanode %s] rejected: %v", dataNodeID, ErrInvalidJoinToken)
+		return status.Error(codes.PermissionDenied, ErrInvalidJoinToken.Error())
+	}
+	if !ValidUUID(req.NodeId) {
+		s.logger.Printf("[datanode %s] rejected: %v", dataNodeID, ErrInvalidDataNodeUUID)
+		return status.Error(codes.InvalidArgument, ErrInvalidDataNodeUUID.Error())
+	}
+	if len(req.ClusterId) != 0 && req.ClusterId != s.clusterId {
+		s.logger.Printf("[datanode %s] rejected: %v (%s)", dataNodeID, ErrForeignDataNode, req.ClusterId)
+		return status.Error(codes.FailedPrecondition, ErrForeignDataNode.Error())
+	}
 
+	var session *DataNodeSession
+	var entry *MetaHeapEntry
 	s.Transactional(func() {
-		s.meta.RegisterNode(&MetaHeapEntry{
-			Id:        dataNodeID,
-			CommandCh: reqChan,
-			ResChs:    resChans,
-			ClosedCh:  closedCh,
-			Size:      req.Size,
-			Lease:     req.LeaserService,
+		// a datanode parked for maintenance takes its place back
+		parked := s.meta.Get(dataNodeID)
+		if parked != nil && (parked.State != NodeMaintenance || !parked.Session.Closed() || parked.NodeId != req.NodeId) {
+			parked = nil
+		}
+		// a name or uuid that is taken would make removing the node ambiguous
+		// the datanode retries, a stale session of its own is gone once its stream is noticed broken
+		if parked == nil && (s.meta.Exists(dataNodeID) || s.meta.ExistsNodeId(req.NodeId)) {
+			err = ErrDuplicateDataNode
+			return
+		}
+		// the node was fenced since it left. it is sent the broadcasts it missed meanwhile,
+		// or wiped and resynced when they can not be replayed
+		reported := Lamport(req.Lamport)
+		cut, missed, resync := s.catchUp(reported, parked)
+		fence := s.resumeFence(dataNodeID, FencingToken(req.Fence), resync)
+		session = NewDataNodeSession(dataNodeID, stream, &s.requests, fence, s.logger)
+		if fence == FencingToken(req.Fence) {
+			s.logger.Printf("[datanode %s] resumed fencing token %s", dataNodeID, fence)
+		} else {
+			s.logger.Printf("[datanode %s] issued fencing token %s", dataNodeID, fence)
+		}
+
+		// the register cut is queued before the node can be part of any broadcast,
+		// broadcasts take their lamport under the same lock, so the node gets exactly those after the cut
+		session.Request(CommandNode{
+			command:  api.CommandNodeRes_REGISTER,
+			register: RegisterCommand{Lamport: cut, ClusterId: s.clusterId, Resync: resync},
 		})
+		for _, cmd := range missed {
+			// nobody waits on the acks of the catch up
+			session.Request(cmd)
+		}
+		if len(missed) != 0 {
+			s.logger.Printf("[datanode %s] catching up from lamport %s with %d commands", dataNodeID, cut, len(missed))
+		}
+
+		if parked != nil {
+			entry = parked
+			entry.Fence = fence
+			entry.Session = session
+			entry.Lease = req.LeaserService
+			entry.Capacity = req.Capacity
+			entry.Zone = req.Zone
+			entry.Rack = req.Rack
+			entry.Labels = req.Labels
+			s.meta.UpdateSize(dataNodeID, req.Size)
+		} else {
+			entry = &MetaHeapEntry{
+				Id:       dataNodeID,
+				NodeId:   req.NodeId,
+				Fence:    fence,
+				Session:  session,
+				Size:     req.Size,
+				Capacity: req.Capacity,
+				Zone:     req.Zone,
+				Rack:     req.Rack,
+				Labels:   req.Labels,
+				Lease:    req.LeaserService,
+				Joined:   time.Now(),
+			}
+			s.meta.RegisterNode(entry)
+		}
+		if resync {
+			s.logger.Printf("[datanode %s] is at lamport %s and can not catch up to %s. its store is resynced", dataNodeID, reported, s.lamport)
+			if parked != nil {
+				// the wipe drops the replicas it kept through its maintenance
+				s.flatNS.RemoveNode(dataNodeID)
+			}
+			return
+		}
+		// a reconnecting datanode reports what its store still holds
+		// objects that are still in the namespace are served by it again
+		for _, object := range req.Objects {
+			if s.flatNS.Exists(object) {
+				s.flatNS.AddNode(object, dataNodeID)
+			}
+		}
 	})
+	if err != nil {
+		s.logger.Printf("[datanode %s] rejected: %v", dataNodeID, err)
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
 
 	defer func() {
-		closedCh <- struct{}{}
-		for tag, ch := range resChans {
-			s.logger.Printf("closing ch for tag %s\n", tag)
-			close(ch)
-		}
+		session.Close()
 
+		parked, decommissioned := false, false
 		s.Transactional(func() {
+			if entry.Session != session {
+				// the node is back on a new stream, registered again while parked
+				parked = true
+				return
+			}
+			s.logger.Printf("[datanode %s] session under fencing token %s ended", dataNodeID, session.fence)
+			if entry.State == NodeMaintenance {
+				// it keeps its place in the heap and its replicas, broadcasts are buffered until it returns
+				s.logger.Printf("[datanode %s] away for maintenance until %s", dataNodeID, entry.Maintenance.Until.Format(time.TimeOnly))
+				parked = true
+				return
+			}
 			s.meta.DeleteNode(dataNodeID)
+			decommissioned = entry.State == NodeDecommissioned
 			s.logger.Printf("[datanode %s] removed from heap", dataNodeID)
 		})
-
-		// begin protocol for fault tolerance via ghost nodes
-		err := s.InitiateSpawn(dataNodeID)
-		if err != nil {
-			if err == ErrNoGhostNode {
-				// s.logger.Fatalf("likely bug. execution should not reach")
-			}
+		if parked {
+			return
 		}
-
-		s.Transactional(func() {
-			s.flatNS.RemoveNode(dataNodeID)
-		})
-
+		if decommissioned {
+			// every object it held has its replicas elsewhere, there is nothing to take over
+			s.logger.Printf("[datanode %s] decommissioned and deregistered", dataNodeID)
+			return
+		}
+		s.lose(dataNodeID)
 	}()
 
+	// sizes are applied apart from the receiving loop. the receiving loop must never wait on the
+	// global lock, a create holds it while it waits for this datanode's ack
+	sizes := make(chan float32, 1)
 	go func() {
-		// heart beat
-		for {
+		for size := range sizes {
+			s.Transactional(func() {
+				s.meta.UpdateSize(dataNodeID, size)
+			})
+		}
+	}()
+
+	var handle func(req *api.NodeHeartBeat)
+	handle = func(req *api.NodeHeartBeat) {
+		if req.Type == api.NodeHeartBeat_ACK {
+			if session.Resolve(req.RequestId, Ack{Code: req.Code, Error: req.Error}) {
+				if req.Code == api.NodeHeartBeat_OK {
+					s.logger.Printf("ack for request %d", req.RequestId)
+				} else {
+					s.logger.Printf("nack for request %d: %s %s", req.RequestId, req.Code, req.Error)
+				}
+			}
+		} else if req.Type == api.NodeHeartBeat_BEAT {
 			select {
-			case <-closedCh:
-				return
+			case sizes <- req.Size:
 			default:
-				req, err := stream.Recv()
-				if err != nil {
-					return
-				}
+				// an update is still pending, the next beat catches up
+			}
+		} else if req.Type == api.NodeHeartBeat_DISTRIUTED_READ {
+			if session.Resolve(req.RequestId, Ack{Code: req.Code, Error: req.Error, Objects: req.ObjectData}) {
+				s.logger.Printf("distributed read result for request %d", req.RequestId)
+			}
+		} else if req.Type == api.NodeHeartBeat_BATCH {
+			for _, res := range req.Batch {
+				handle(res)
+			}
+		}
+	}
 
-				if req.Type == api.NodeHeartBeat_ACK {
-					if ch, ok := resChans[req.MessageTag]; ok {
-						s.logger.Printf("ack message tagged %s", req.MessageTag)
-						ch <- true
-					} else {
-						s.logger.Printf("ack error, message tag %s channel does not exist", req.MessageTag)
-					}
-				} else if req.Type == api.NodeHeartBeat_BEAT {
-					s.Transactional(func() {
-						s.meta.UpdateSize(req.Id, req.Size)
-					})
-				} else if req.Type == api.NodeHeartBeat_DISTRIUTED_READ {
-					if ch, ok := resChans[req.MessageTag]; ok {
-						s.logger.Printf("distributed read tagged %s result", req.MessageTag)
-						ch <- req.ObjectData
-					} else {
-						s.logger.Printf("distributed read error, message tag %s channel does not exist", req.MessageTag)
-					}
-				}
+	go func() {
+		defer close(sizes)
+		// a broken stream ends the session, which stops the writer as well
+		defer session.Close()
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				return
 			}
+			handle(req)
 		}
 	}()
 
-	mux := NewDataNodeCommandMux(stream, s.logger)
+	err = session.Run()
+	if err == ErrSessionClosed {
+		s.logger.Printf("[datanode %s] closed connection", dataNodeID)
+		return nil
+	}
+	s.logger.Printf("[datanode %s] session ended: %v", dataNodeID, err)
+	return err
+}
 
-	for {
-		select {
-		case req, ok := <-reqChan:
-			if !ok {
-				s.logger.Printf("[datanode %s] closed request ch", dataNodeID)
-				return nil
-			}
-			go mux.Handle(&req)
-		case <-stream.Context().Done():
-			s.logger.Printf("[datanode %s] closed connection", dataNodeID)
-			return stream.Context().Err()
+// lose hands the objects of a failed datanode to a ghost and drops the node from their replicas
+func (s *DosNameNodeServer) lose(dataNodeID string) {
+	// begin protocol for fault tolerance via ghost nodes
+	err := s.InitiateSpawn(dataNodeID)
+	if err != nil {
+		if err == ErrNoGhostNode {
+			// s.logger.Fatalf("likely bug. execution should not reach")
 		}
 	}
+
+	s.Transactional(func() {
+		s.flatNS.RemoveNode(dataNodeID)
+	})
 }
 
-func (s *DosNameNodeServer) BroadcastCommit(name string) {
-	atomic.AddInt32(&s.lamport, 1)
-	command := CommandNode{
-		command: api.CommandNodeRes_COMMIT,
-		commit:  CommitCommand{Lamport: s.lamport, Type: COMMIT, Name: name},
-	}
+type broadcastAck struct {
+	node string
+	res  Ack
+}
+
+// broadcast stamps the command with the next lamport and queues it on every connected datanode
+// both happen under the global lock, so every datanode receives broadcasts in lamport order
+// it must not be called inside a transaction
+func (s *DosNameNodeServer) broadcast(build func(lamport Lamport) CommandNode) (Lamport, []broadcastAck) {
+	var lamport Lamport
+	var calls []pendingCall
+	s.Transactional(func() {
+		s.lamport++
+		lamport = s.lamport
+		calls = s.queueBroadcast(build(lamport))
+	})
+	return lamport, await(calls)
+}
+
+// queueBroadcast queues a stamped command on every connected datanode and buffers it for the ones in maintenance
+// and for the ones that register after missing it. it is called under the global lock
+func (s *DosNameNodeServer) queueBroadcast(command CommandNode) []pendingCall {
+	s.recent.record(command)
 	s.meta.ForEach(func(entry *MetaHeapEntry) {
-		messageTag := CommitMessageTag(name, entry.Id)
-		command.tag = messageTag
-		ackChan := make(chan interface{})
-		entry.ResChs[messageTag] = ackChan
-		entry.CommandCh <- command
-		success, ok := <-ackChan
-		if ok {
-			if !success.(bool) {
-				s.logger.Printf("failed to broadcast commit to %s\n", entry.Id)
-			} else {
-				s.logger.Printf("broadcasted commit to %s\n", entry.Id)
-			}
+		if entry.Maintenance != nil {
+			entry.Maintenance.record(command, s.config.MaintenanceBacklog)
 		}
-		delete(entry.ResChs, messageTag)
 	})
+	return request(s.sessions(), command)
 }
 
-func (s *DosNameNodeServer) BroadcastDelete(name string) {
-	atomic.AddInt32(&s.lamport, 1)
-	command := CommandNode{
-		command: api.CommandNodeRes_DELETE,
-		delete: DeleteCommand{
-			Lamport: s.lamport,
-			Type:    DELETE,
-			Name:    name,
-		},
-	}
+// sessions of every connected datanode, called under the global lock
+func (s *DosNameNodeServer) sessions() []*DataNodeSession {
+	sessions := make([]*DataNodeSession, 0, s.meta.Count())
 	s.meta.ForEach(func(entry *MetaHeapEntry) {
-		messageTag := DeleteMessageTag(name, entry.Id)
-		command.tag = messageTag
-		ackChan := make(chan interface{})
-		entry.ResChs[messageTag] = ackChan
-		entry.CommandCh <- command
-		success, ok := <-ackChan
-		if ok {
-			if !success.(bool) {
-				s.logger.Printf("failed to broadcast delete %s\n", entry.Id)
-			} else {
-				s.logger.Printf("broadcasted delete to %s\n", entry.Id)
-			}
+		sessions = append(sessions, entry.Session)
+	})
+	return sessions
+}
+
+type pendingCall struct {
+	session *DataNodeSession
+	id      uint64
+	ackCh   <-chan Ack
+}
+
+// request queues the command on every session without waiting on any of them
+func request(sessions []*DataNodeSession, command CommandNode) []pendingCall {
+	calls := make([]pendingCall, 0, len(sessions))
+	for _, session := range sessions {
+		id, ackCh, err := session.Request(command)
+		if err != nil {
+			continue
+		}
+		calls = append(calls, pendingCall{session: session, id: id, ackCh: ackCh})
+	}
+	return calls
+}
+
+// await collects the acks of queued commands, so one slow datanode does not hold back the rest
+// datanodes that leave before answering are left out
+func await(calls []pendingCall) []broadcastAck {
+	acks := make([]broadcastAck, 0, len(calls))
+	for _, c := range calls {
+		select {
+		case res := <-c.ackCh:
+			acks = append(acks, broadcastAck{node: c.session.Id(), res: res})
+		case <-c.session.Done():
+			c.session.forget(c.id)
+		}
+	}
+	return acks
+}
+
+// BroadcastCommit commits the create of the object on its replicas
+// every datanode records the lamport, the others keep whatever an earlier failed create staged there
+func (s *DosNameNodeServer) BroadcastCommit(name string, replicas []string) {
+	_, acks := s.broadcast(func(lamport Lamport) CommandNode {
+		return CommandNode{
+			command: api.CommandNodeRes_COMMIT,
+			commit:  CommitCommand{Lamport: lamport, Type: COMMIT, Name: name, Nodes: replicas},
 		}
-		delete(entry.ResChs, messageTag)
 	})
+	failed := make([]string, 0)
+	for _, ack := range acks {
+		if !ack.res.Ok() {
+			s.logger.Printf("failed to commit %s on %s: %v\n", name, ack.node, ack.res.Err())
+			failed = append(failed, ack.node)
+		} else {
+			s.logger.Printf("broadcasted commit to %s\n", ack.node)
+		}
+	}
+	s.failReplicas(name, failed)
 }
 
-func (s *DosNameNodeServer) BroadcastUpdate(name string, data []byte) {
-	atomic.AddInt32(&s.lamport, 1)
-	command := CommandNode{
-		command: api.CommandNodeRes_UPDATE,
-		update: UpdateCommand{
-			Lamport: s.lamport,
-			Type:    UPDATE,
-			Name:    name,
-			Data:    data,
-		},
+// failReplicas drops the nodes that failed to apply a command to the object from its replicas
+// their copy is no longer current, the node discards it
+func (s *DosNameNodeServer) failReplicas(name string, nodes []string) {
+	if len(nodes) == 0 {
+		return
 	}
-	s.meta.ForEach(func(entry *MetaHeapEntry) {
-		messageTag := UpdateMessageTag(name, entry.Id)
-		command.tag = messageTag
-		ackChan := make(chan interface{})
-		entry.ResChs[messageTag] = ackChan
-		entry.CommandCh <- command
-		success, ok := <-ackChan
-		if ok {
-			if !success.(bool) {
-				s.logger.Printf("failed to broadcast update %s\n", entry.Id)
-			} else {
-				s.logger.Printf("broadcasted update to %s\n", entry.Id)
-			}
+	s.Transactional(func() {
+		for _, node := range nodes {
+			s.flatNS.FailReplica(name, node)
+			s.logger.Printf("replica of %s on %s marked failed\n", name, node)
 		}
-		delete(entry.ResChs, messageTag)
 	})
 }
 
-func (s *DosNameNodeServer) BroadcastDistributedRead(objects []string) <-chan interface{} {
-	atomic.AddInt32(&s.lamport, 1)
-	command := CommandNode{
-		command: api.CommandNodeRes_DISTRIBUTED_READ,
-		distributedRead: DistributedReadCommand{
-			Objects: objects,
-			Lamport: s.lamport,
-		},
+func (s *DosNameNodeServer) BroadcastDelete(name string) {
+	_, acks := s.broadcast(func(lamport Lamport) CommandNode {
+		s.written(name)
+		return CommandNode{
+			command: api.CommandNodeRes_DELETE,
+			delete: DeleteCommand{
+				Lamport: lamport,
+				Type:    DELETE,
+				Name:    name,
+			},
+		}
+	})
+	for _, ack := range acks {
+		if !ack.res.Ok() {
+			// the object is out of the namespace already, the leftover copy is never served
+			s.logger.Printf("failed to delete %s on %s: %v\n", name, ack.node, ack.res.Err())
+		} else {
+			s.logger.Printf("broadcasted delete to %s\n", ack.node)
+		}
 	}
+}
 
-	resultsCh := make(chan interface{}, 5)
-	defer close(resultsCh)
-	s.meta.ForEach(func(entry *MetaHeapEntry) {
-		messageTag := DistributedReadTag(objects, entry.Id)
-		command.tag = messageTag
-		ackChan := make(chan interface{})
-		entry.ResChs[messageTag] = ackChan
-		entry.CommandCh <- command
-		reads, ok := <-ackChan
-		if ok {
-			resultsCh <- reads
+func (s *DosNameNodeServer) BroadcastUpdate(name string, data []byte) {
+	_, acks := s.broadcast(func(lamport Lamport) CommandNode {
+		s.written(name)
+		return CommandNode{
+			command: api.CommandNodeRes_UPDATE,
+			update: UpdateCommand{
+				Lamport: lamport,
+				Type:    UPDATE,
+				Name:    name,
+				Data:    data,
+			},
 		}
-		delete(entry.ResChs, messageTag)
 	})
+	failed := make([]string, 0)
+	for _, ack := range acks {
+		if !ack.res.Ok() {
+			s.logger.Printf("failed to update %s on %s: %v\n", name, ack.node, ack.res.Err())
+			failed = append(failed, ack.node)
+		} else {
+			s.logger.Printf("broadcasted update to %s\n", ack.node)
+		}
+	}
+	s.failReplicas(name, failed)
+}
+
+// DistributedReadResult is what one datanode read of the objects
+type DistributedReadResult struct {
+	Node    string
+	Objects []*api.NodeHeartBeat_Object
+}
+
+// only the results of datanodes that could read their store are passed on
+// every datanode answers with what it holds, callers only take the copies of the replicas they know of
+func (s *DosNameNodeServer) BroadcastDistributedRead(objects []string, snapshot string) <-chan DistributedReadResult {
+	_, acks := s.broadcast(func(lamport Lamport) CommandNode {
+		return CommandNode{
+			command: api.CommandNodeRes_DISTRIBUTED_READ,
+			distributedRead: DistributedReadCommand{
+				Objects:  objects,
+				Lamport:  lamport,
+				Snapshot: snapshot,
+			},
+		}
+	})
+
+	resultsCh := make(chan DistributedReadResult, len(acks))
+	defer close(resultsCh)
+	for _, ack := range acks {
+		if !ack.res.Ok() {
+			s.logger.Printf("failed distributed read on %s: %v\n", ack.node, ack.res.Err())
+			continue
+		}
+		resultsCh <- DistributedReadResult{Node: ack.node, Objects: ack.res.Objects}
+	}
 
 	return resultsCh
 }
+
+// the snapshot broadcast is the lamport cut of the snapshot
+// every datanode freezes its store at exactly this point in the command order
+func (s *DosNameNodeServer) BroadcastSnapshot(name string, event BroadcastEvent) Lamport {
+	command := api.CommandNodeRes_SNAPSHOT
+	if event == DELETESNAPSHOT {
+		command = api.CommandNodeRes_DELETE_SNAPSHOT
+	}
+	lamport, acks := s.broadcast(func(lamport Lamport) CommandNode {
+		return CommandNode{
+			command: command,
+			snapshot: SnapshotCommand{
+				Lamport: lamport,
+				Name:    name,
+				Type:    event,
+			},
+		}
+	})
+	for _, ack := range acks {
+		if !ack.res.Ok() {
+			s.logger.Printf("failed to broadcast %s %s to %s: %v\n", event, name, ack.node, ack.res.Err())
+		} else {
+			s.logger.Printf("broadcasted %s %s to %s\n", event, name, ack.node)
+		}
+	}
+	return lamport
+}
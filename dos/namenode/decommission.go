@@ -0,0 +1,303 @@
+package namenode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/mrowaha/dos/api"
+)
+
+/**
+	decommissioning retires a datanode without losing replicas
+	the node stops receiving new objects, then each of its objects is copied to another node until it
+	is fully replicated without it. once every object is, the node is dropped from the namespace and
+	told to leave, and its stream closing is a clean deregistration rather than a failure
+
+	a replica is copied by reading the object, staging it on the new node and committing it there with a
+	broadcast like any create. writes to the object between the read and the commit would be missed by
+	the copy, so the object is marked while it is moved and a copy that saw a write is thrown away and retried
+**/
+
+var (
+	ErrObjectWrittenDuringMove = errors.New("object was written while its replica was copied")
+	ErrNoReplicaTarget         = errors.New("no datanode can take another replica of the object")
+)
+
+// attempts at copying one replica before the object counts as failed
+const maxMoveAttempts = 3
+
+type DrainProgress struct {
+	Total   int
+	Moved   int
+	Failed  int
+	Err     error // last failure
+	Running bool
+}
+
+func (s *DosNameNodeServer) Decommission(ctx context.Context, req *api.DecommissionReq) (*api.DecommissionRes, error) {
+	s.logger.Printf("attempting request [decommission %s]\n", req.Name)
+
+	var res *api.DecommissionRes
+	var transactionErr error
+	var entry *MetaHeapEntry
+	start := false
+	s.Transactional(func() {
+		entry = s.meta.Get(req.Name)
+		if entry == nil {
+			transactionErr = ErrDataNodeNotFound
+			return
+		}
+		switch {
+		case entry.State == NodeMaintenance:
+			// it would be drained while it is down, end its maintenance first
+			transactionErr = ErrNodeInMaintenance
+			return
+		case entry.State == NodeActive:
+			entry.State = NodeDecommissioning
+			start = true
+		case entry.State == NodeDecommissioning && !entry.Drain.Running:
+			// the last drain left objects behind, they are tried again
+			start = true
+		}
+		if start {
+			entry.Drain = &DrainProgress{Running: true}
+		}
+		res = &api.DecommissionRes{Node: s.nodeSummary(entry)}
+	})
+	if transactionErr != nil {
+		return nil, transactionErr
+	}
+
+	if start {
+		s.logger.Printf("[datanode %s] decommissioning, no new objects are placed on it", req.Name)
+		go s.drain(entry)
+	}
+	return res, nil
+}
+
+// drain copies the objects of a decommissioning node elsewhere and lets it leave once none depends on it
+func (s *DosNameNodeServer) drain(entry *MetaHeapEntry) {
+	var objects []string
+	s.Transactional(func() {
+		objects = s.flatNS.Objects(entry.Id)
+		entry.Drain.Total = len(objects)
+	})
+
+	for _, object := range objects {
+		err := s.replicateWithout(object, entry.Id)
+		s.Transactional(func() {
+			if err != nil {
+				entry.Drain.Failed++
+				entry.Drain.Err = err
+				return
+			}
+			entry.Drain.Moved++
+		})
+		if err != nil {
+			s.logger.Printf("[datanode %s] failed to drain %s: %v", entry.Id, object, err)
+		}
+	}
+
+	var session *DataNodeSession
+	var progress DrainProgress
+	s.Transactional(func() {
+		entry.Drain.Running = false
+		progress = *entry.Drain
+		if entry.Drain.Failed != 0 {
+			return
+		}
+		entry.State = NodeDecommissioned
+		s.flatNS.RemoveNode(entry.Id)
+		session = entry.Session
+	})
+	if session == nil {
+		s.logger.Printf("[datanode %s] drain left %d objects behind, decommission again to retry", entry.Id, progress.Failed)
+		return
+	}
+
+	s.logger.Printf("[datanode %s] drained %d objects, telling it to leave", entry.Id, progress.Moved)
+	calls := request([]*DataNodeSession{session}, CommandNode{command: api.CommandNodeRes_DECOMMISSION})
+	if len(calls) == 0 {
+		// it is decommissioned all the same, nothing depends on it anymore
+		s.logger.Printf("[datanode %s] left before it was told to", entry.Id)
+		return
+	}
+	acks := await(calls)
+	switch {
+	case len(acks) == 0:
+		// the node ends its stream once it takes the command, the ack can be lost with it
+		s.logger.Printf("[datanode %s] closed its stream after being told to leave", entry.Id)
+	case !acks[0].res.Ok():
+		s.logger.Printf("[datanode %s] failed to leave: %v", entry.Id, acks[0].res.Err())
+	default:
+		s.logger.Printf("[datanode %s] acknowledged leaving", entry.Id)
+	}
+}
+
+// replicateWithout copies the object until it has as many replicas as its replication besides node
+// the copies go to the domains the other replicas do not cover, so the object keeps its spread without the node
+// an object deleted meanwhile needs no copies
+func (s *DosNameNodeServer) replicateWithout(object string, node string) error {
+	tried := map[string]bool{node: true}
+	failures := 0
+	for {
+		var missing int
+		others := make([]string, 0)
+		s.Transactional(func() {
+			nodes, err := s.flatNS.Nodes(object)
+			if err != nil {
+				return
+			}
+			missing = s.flatNS.Replication(object)
+			for _, replica := range nodes {
+				tried[replica] = true
+				if replica != node {
+					others = append(others, replica)
+					missing--
+				}
+			}
+		})
+		if missing <= 0 {
+			return nil
+		}
+
+		_, err := s.copyReplica(object, others, tried)
+		if errors.Is(err, ErrObjectDoestNotExist) {
+			return nil
+		}
+		if err != nil {
+			failures++
+			if failures == maxMoveAttempts || errors.Is(err, ErrNoReplicaTarget) {
+				return err
+			}
+		}
+	}
+}
+
+// copyReplica adds a replica of the object held by replicas on an active node that is not in skip
+// the node that was tried is added to skip
+func (s *DosNameNodeServer) copyReplica(object string, replicas []string, skip map[string]bool) (string, error) {
+	var target *MetaHeapEntry
+	s.Transactional(func() {
+		picked := s.pick(1, s.flatNS.Constraints(object), replicas, skip)
+		if len(picked) == 0 {
+			return
+		}
+		target = picked[0]
+		skip[target.Id] = true
+	})
+	if target == nil {
+		return "", ErrNoReplicaTarget
+	}
+	_, err := s.copyReplicaTo(object, target)
+	return target.Id, err
+}
+
+// copyReplicaTo adds a replica of the object on target and returns the size of the copied data
+// copies are made one at a time
+func (s *DosNameNodeServer) copyReplicaTo(object string, target *MetaHeapEntry) (int, error) {
+	s.moves.Lock()
+	defer s.moves.Unlock()
+
+	var err error
+	s.Transactional(func() {
+		if target.State != NodeActive || target.Session.Closed() {
+			err = fmt.Errorf("%w: %s can not take new objects", ErrFailedObjectReplication, target.Id)
+			return
+		}
+		// every write to the object from here on spoils the copy
+		s.moving[object] = false
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer s.Transactional(func() {
+		delete(s.moving, object)
+	})
+
+	data, err := s.readObject(object)
+	if err != nil {
+		return 0, err
+	}
+	staged := await(request([]*DataNodeSession{target.Session}, CommandNode{
+		command: api.CommandNodeRes_CREATE,
+		create:  CreateCommand{Name: object, Data: data},
+	}))
+	if len(staged) == 0 {
+		return 0, fmt.Errorf("%w: %s left", ErrFailedObjectReplication, target.Id)
+	}
+	if !staged[0].res.Ok() {
+		return 0, fmt.Errorf("%w: %v", ErrFailedObjectReplication, staged[0].res.Err())
+	}
+
+	// the check and the commit happen under one lock, no write can come between them
+	// the replica is counted right away, so a write after the commit that fails on it drops it again
+	var calls []pendingCall
+	s.Transactional(func() {
+		if !s.flatNS.Exists(object) {
+			err = ErrObjectDoestNotExist
+			return
+		}
+		if s.moving[object] {
+			err = ErrObjectWrittenDuringMove
+			return
+		}
+		s.lamport++
+		calls = s.queueBroadcast(CommandNode{
+			command: api.CommandNodeRes_COMMIT,
+			commit:  CommitCommand{Lamport: s.lamport, Type: COMMIT, Name: object, Nodes: []string{target.Id}},
+		})
+		s.flatNS.AddNode(object, target.Id)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, ack := range await(calls) {
+		if ack.node != target.Id {
+			// the other nodes have nothing staged
+			continue
+		}
+		if !ack.res.Ok() {
+			s.failReplicas(object, []string{target.Id})
+			return 0, fmt.Errorf("%w: %v", ErrFailedObjectReplication, ack.res.Err())
+		}
+		s.logger.Printf("copied a replica of %s to %s", object, target.Id)
+		return len(data), nil
+	}
+	s.failReplicas(object, []string{target.Id})
+	return 0, fmt.Errorf("%w: %s left", ErrFailedObjectReplication, target.Id)
+}
+
+// readObject reads the object's current data from whichever of its replicas answers first
+func (s *DosNameNodeServer) readObject(object string) ([]byte, error) {
+	var replicas []string
+	var err error
+	s.Transactional(func() {
+		replicas, err = s.flatNS.Nodes(object)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for result := range s.BroadcastDistributedRead([]string{object}, "") {
+		if !slices.Contains(replicas, result.Node) {
+			// a copy left behind by a failed drop
+			continue
+		}
+		for _, ob := range result.Objects {
+			if ob.Name == object {
+				return ob.Data, nil
+			}
+		}
+	}
+	return nil, ErrObjectDoestNotExist
+}
+
+// written marks a write to the object for a copy in progress, it is called under the global lock
+func (s *DosNameNodeServer) written(object string) {
+	if _, ok := s.moving[object]; ok {
+		s.moving[object] = true
+	}
+}
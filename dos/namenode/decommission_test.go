@@ -0,0 +1,110 @@
+package namenode
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/mrowaha/dos/api"
+)
+
+func TestDecommissionAfterLoad(t *testing.T) {
+	// objects loaded from a namespace written before replication was recorded
+	s := newTestNameNode(t, 2, "legacy", "single\t{\"replication\":1}")
+	leaving := registerFake(t, s, 0, "legacy", "single")
+	staying := []*fakeDataNode{registerFake(t, s, 1), registerFake(t, s, 2)}
+
+	if _, err := s.Decommission(context.Background(), &api.DecommissionReq{Name: leaving.id}); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool {
+		state := NodeDecommissioning
+		s.Transactional(func() {
+			if entry := s.meta.Get(leaving.id); entry != nil {
+				state = entry.State
+			}
+		})
+		return state == NodeDecommissioned
+	})
+
+	tests := []struct {
+		object   string
+		replicas int
+	}{
+		{object: "legacy", replicas: 2},
+		{object: "single", replicas: 1},
+	}
+	for _, tt := range tests {
+		got := replicas(s, tt.object)
+		if len(got) != tt.replicas {
+			t.Errorf("%s replicas = %v, want %d of them", tt.object, got, tt.replicas)
+		}
+		if slices.Contains(got, leaving.id) {
+			t.Errorf("%s is still counted on the decommissioned node", tt.object)
+		}
+		for _, node := range staying {
+			if slices.Contains(got, node.id) != node.holds(tt.object) {
+				t.Errorf("%s replicas = %v, but %s holding it is %v", tt.object, got, node.id, node.holds(tt.object))
+			}
+		}
+	}
+}
+
+// told reports whether the fake received a decommission
+func (f *fakeDataNode) told() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Contains(f.seen, api.CommandNodeRes_DECOMMISSION)
+}
+
+func TestDecommissionedNodeLeaves(t *testing.T) {
+	tests := []struct {
+		name   string
+		leaves bool // closes its stream without acking the decommission
+	}{
+		{name: "acknowledges"},
+		{name: "closes its stream", leaves: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestNameNode(t, 1, "object")
+			leaving := registerFakeWith(t, s, 0, tt.leaves, "object")
+			registerFake(t, s, 1)
+
+			if _, err := s.Decommission(context.Background(), &api.DecommissionReq{Name: leaving.id}); err != nil {
+				t.Fatal(err)
+			}
+			eventually(t, leaving.told)
+			eventually(t, func() bool {
+				done := false
+				s.Transactional(func() {
+					entry := s.meta.Get(leaving.id)
+					// a node that left is deregistered, one that stays connected waits to be shut down
+					done = (entry == nil) == tt.leaves && (entry == nil || entry.State == NodeDecommissioned)
+				})
+				return done
+			})
+			if got := replicas(s, "object"); !slices.Equal(got, []string{"node-1"}) {
+				t.Errorf("object replicas = %v, want [node-1]", got)
+			}
+		})
+	}
+}
+
+func TestReadObjectReadsReplicas(t *testing.T) {
+	s := newTestNameNode(t, 1, "object")
+	// the leftover copy answers first
+	registerFake(t, s, 0).leaveCopy("object", "stale")
+	replica := registerFake(t, s, 1, "object")
+
+	if data, err := s.readObject("object"); err != nil || string(data) != "object" {
+		t.Errorf("read = %q, %v, want the replica's copy", data, err)
+	}
+	replica.cancel()
+	s.Transactional(func() {
+		s.flatNS.FailReplica("object", replica.id)
+	})
+	if data, err := s.readObject("object"); err == nil {
+		t.Errorf("read %q with no replica left", data)
+	}
+}
@@ -0,0 +1,82 @@
+package namenode
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+/**
+	the cluster epoch counts namenode starts and is persisted in the epoch file
+	it is the high half of every lamport, so the commands of a restarted namenode order after
+	everything the previous one sent, and datanodes can tell that the counter started over
+**/
+
+var (
+	ErrLoadEpoch = errors.New("failed to load cluster epoch")
+)
+
+// Lamport is an (epoch, counter) pair, ordered by epoch first
+type Lamport uint64
+
+func NewLamport(epoch uint32, counter uint32) Lamport {
+	return Lamport(uint64(epoch)<<32 | uint64(counter))
+}
+
+func (l Lamport) Epoch() uint32 {
+	return uint32(l >> 32)
+}
+
+func (l Lamport) Counter() uint32 {
+	return uint32(l)
+}
+
+func (l Lamport) String() string {
+	return fmt.Sprintf("%d.%d", l.Epoch(), l.Counter())
+}
+
+// nextEpoch bumps the epoch recorded in epochFile and returns the new epoch
+// a missing file is a fresh cluster, which starts at epoch 1
+func nextEpoch(epochFile string) (uint32, error) {
+	var epoch uint32
+	data, err := os.ReadFile(epochFile)
+	if err == nil {
+		v, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrLoadEpoch, err)
+		}
+		epoch = uint32(v)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return 0, fmt.Errorf("%w: %v", ErrLoadEpoch, err)
+	}
+	epoch++
+
+	// the new epoch must be on disk before any command of it is sent
+	if err := writeFileAtomic(epochFile, []byte(fmt.Sprintf("%d\n", epoch))); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrLoadEpoch, err)
+	}
+	return epoch, nil
+}
+
+// writeFileAtomic replaces path with data, a crash leaves either the old or the new content
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
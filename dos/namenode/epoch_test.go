@@ -0,0 +1,75 @@
+package namenode
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLamport(t *testing.T) {
+	tests := []struct {
+		epoch   uint32
+		counter uint32
+		str     string
+	}{
+		{epoch: 0, counter: 0, str: "0.0"},
+		{epoch: 1, counter: 0, str: "1.0"},
+		{epoch: 1, counter: 42, str: "1.42"},
+		{epoch: 7, counter: math.MaxUint32, str: "7.4294967295"},
+		{epoch: math.MaxUint32, counter: 1, str: "4294967295.1"},
+	}
+	for _, tt := range tests {
+		l := NewLamport(tt.epoch, tt.counter)
+		if l.Epoch() != tt.epoch || l.Counter() != tt.counter || l.String() != tt.str {
+			t.Errorf("NewLamport(%d, %d) = %s, epoch %d, counter %d", tt.epoch, tt.counter, l, l.Epoch(), l.Counter())
+		}
+		// a counter can never reach into the next epoch
+		if next := NewLamport(tt.epoch+1, 0); tt.epoch != math.MaxUint32 && l >= next {
+			t.Errorf("%s does not order before %s", l, next)
+		}
+	}
+}
+
+func TestNextEpoch(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    *string // nil for a missing file
+		want    uint32
+		wantErr bool
+	}{
+		{name: "fresh cluster", data: nil, want: 1},
+		{name: "restart", data: ptr("4\n"), want: 5},
+		{name: "no newline", data: ptr("4"), want: 5},
+		{name: "garbage", data: ptr("four\n"), wantErr: true},
+		{name: "out of range", data: ptr("4294967296\n"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "epoch")
+			if tt.data != nil {
+				if err := os.WriteFile(path, []byte(*tt.data), 0666); err != nil {
+					t.Fatal(err)
+				}
+			}
+			epoch, err := nextEpoch(path)
+			if tt.wantErr {
+				if !errors.Is(err, ErrLoadEpoch) {
+					t.Fatalf("err = %v, want %v", err, ErrLoadEpoch)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if epoch != tt.want {
+				t.Errorf("epoch = %d, want %d", epoch, tt.want)
+			}
+			// the next start is in the epoch after
+			if again, err := nextEpoch(path); err != nil || again != tt.want+1 {
+				t.Errorf("next epoch = %d, %v, want %d", again, err, tt.want+1)
+			}
+		})
+	}
+}
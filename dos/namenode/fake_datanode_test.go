@@ -0,0 +1,203 @@
+package namenode
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mrowaha/dos/api"
+	"google.golang.org/grpc"
+)
+
+// fakeDataNode is the stream of a datanode that applies commands to an in memory store and acks them
+type fakeDataNode struct {
+	grpc.ServerStream
+	id    string
+	ctx   context.Context
+	in    chan *api.NodeHeartBeat
+	mu    sync.Mutex
+	store map[string][]byte
+	stage map[string][]byte
+	seen  []api.CommandNodeRes_Command
+
+	// leaves closes the stream on a decommission instead of acking it
+	leaves bool
+	cancel context.CancelFunc
+}
+
+func (f *fakeDataNode) Context() context.Context { return f.ctx }
+
+func (f *fakeDataNode) Recv() (*api.NodeHeartBeat, error) {
+	select {
+	case req := <-f.in:
+		return req, nil
+	case <-f.ctx.Done():
+		return nil, io.EOF
+	}
+}
+
+func (f *fakeDataNode) Send(cmd *api.CommandNodeRes) error {
+	f.mu.Lock()
+	decommission := func(cmd *api.CommandNodeRes) bool {
+		return cmd.Command == api.CommandNodeRes_DECOMMISSION
+	}
+	if f.leaves && (decommission(cmd) || slices.ContainsFunc(cmd.Batch, decommission)) {
+		f.seen = append(f.seen, api.CommandNodeRes_DECOMMISSION)
+		f.mu.Unlock()
+		f.cancel()
+		return nil
+	}
+	var res *api.NodeHeartBeat
+	if cmd.Command == api.CommandNodeRes_BATCH {
+		res = &api.NodeHeartBeat{Type: api.NodeHeartBeat_BATCH}
+		for _, batched := range cmd.Batch {
+			res.Batch = append(res.Batch, f.apply(batched))
+		}
+	} else {
+		res = f.apply(cmd)
+	}
+	f.mu.Unlock()
+	go func() {
+		select {
+		case f.in <- res:
+		case <-f.ctx.Done():
+		}
+	}()
+	return nil
+}
+
+func (f *fakeDataNode) apply(cmd *api.CommandNodeRes) *api.NodeHeartBeat {
+	f.seen = append(f.seen, cmd.Command)
+	res := &api.NodeHeartBeat{Type: api.NodeHeartBeat_ACK, RequestId: cmd.RequestId}
+	switch cmd.Command {
+	case api.CommandNodeRes_CREATE:
+		f.stage[cmd.Create.ObjectName] = cmd.Create.ObjectData
+	case api.CommandNodeRes_COMMIT:
+		if len(cmd.Commit.Nodes) != 0 && !slices.Contains(cmd.Commit.Nodes, f.id) {
+			break
+		}
+		if data, ok := f.stage[cmd.Commit.ObjectName]; ok {
+			f.store[cmd.Commit.ObjectName] = data
+			delete(f.stage, cmd.Commit.ObjectName)
+		}
+	case api.CommandNodeRes_DELETE:
+		if len(cmd.Delete.Node) == 0 || cmd.Delete.Node == f.id {
+			delete(f.store, cmd.Delete.ObjectName)
+		}
+	case api.CommandNodeRes_DISTRIBUTED_READ:
+		res.Type = api.NodeHeartBeat_DISTRIUTED_READ
+		for _, object := range cmd.DistributedRead.Objects {
+			if data, ok := f.store[object]; ok {
+				res.ObjectData = append(res.ObjectData, &api.NodeHeartBeat_Object{Name: object, Data: data})
+			}
+		}
+	}
+	return res
+}
+
+// holds tells whether the object is committed in the fake's store
+func (f *fakeDataNode) holds(object string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.store[object]
+	return ok
+}
+
+// newTestNameNode starts a namenode over a namespace file holding lines
+func newTestNameNode(t *testing.T, replication int, lines ...string) *DosNameNodeServer {
+	t.Helper()
+	dir := t.TempDir()
+	data := ""
+	for _, line := range lines {
+		data += line + "\n"
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ns"), []byte(data), 0666); err != nil {
+		t.Fatal(err)
+	}
+	return openTestNameNode(t, dir, replication)
+}
+
+// openTestNameNode starts a namenode over the files in dir, like a namenode restarting
+func openTestNameNode(t *testing.T, dir string, replication int) *DosNameNodeServer {
+	t.Helper()
+	s, err := NewDosNameNodeServer(
+		filepath.Join(dir, "log"),
+		filepath.Join(dir, "ns"),
+		WithEpochFile(filepath.Join(dir, "epoch")),
+		WithClusterFile(filepath.Join(dir, "cluster")),
+		WithSnapshotFile(filepath.Join(dir, "snapshots")),
+		WithReplication(replication),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// registerFake connects the i-th fake datanode holding objects and waits until it is registered
+func registerFake(t *testing.T, s *DosNameNodeServer, i int, objects ...string) *fakeDataNode {
+	t.Helper()
+	return registerFakeWith(t, s, i, false, objects...)
+}
+
+// registerFakeWith connects a fake datanode that closes its stream when it is decommissioned when leaves is set
+func registerFakeWith(t *testing.T, s *DosNameNodeServer, i int, leaves bool, objects ...string) *fakeDataNode {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	node := &fakeDataNode{
+		id:     fmt.Sprintf("node-%d", i),
+		ctx:    ctx,
+		leaves: leaves,
+		cancel: cancel,
+		in:     make(chan *api.NodeHeartBeat, 1),
+		store:  make(map[string][]byte),
+		stage:  make(map[string][]byte),
+	}
+	for _, object := range objects {
+		node.store[object] = []byte(object)
+	}
+	node.in <- &api.NodeHeartBeat{
+		Id:      node.id,
+		NodeId:  fmt.Sprintf("00000000-0000-0000-0000-%012d", i),
+		Type:    api.NodeHeartBeat_BEAT,
+		Objects: objects,
+	}
+	go s.RegisterNode(node)
+	eventually(t, func() bool {
+		registered := false
+		s.Transactional(func() {
+			registered = s.meta.Exists(node.id)
+		})
+		return registered
+	})
+	return node
+}
+
+// eventually fails the test when cond does not hold within a few seconds
+func eventually(t *testing.T, cond func() bool) {
+	t.Helper()
+	for range 500 {
+		if cond() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("condition not met in time")
+}
+
+// replicas returns the sorted nodes the namespace counts as replicas of the object
+func replicas(s *DosNameNodeServer, object string) []string {
+	var nodes []string
+	s.Transactional(func() {
+		nodes, _ = s.flatNS.Nodes(object)
+	})
+	slices.Sort(nodes)
+	return nodes
+}
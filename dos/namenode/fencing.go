@@ -0,0 +1,50 @@
+package namenode
+
+import "fmt"
+
+/**
+	a fencing token is issued to a datanode when it registers and is revoked when its store is resynced
+	every command of a session carries the token and the lease service stamps it on its publications,
+	so a datanode whose store was wiped can not pass off old state as current. a datanode that resumes
+	its store keeps its token, its subscribers are not told to lease again
+	tokens are (epoch, counter) pairs like lamports, so no token is ever issued twice
+**/
+
+type FencingToken uint64
+
+func (f FencingToken) Epoch() uint32 {
+	return uint32(f >> 32)
+}
+
+func (f FencingToken) String() string {
+	return fmt.Sprintf("%d.%d", f.Epoch(), uint32(f))
+}
+
+// issueFence must be called under the global lock
+func (s *DosNameNodeServer) issueFence() FencingToken {
+	s.fences++
+	return FencingToken(NewLamport(s.lamport.Epoch(), s.fences))
+}
+
+// resumeFence hands a registering datanode back the token its lease service still publishes under,
+// so subscriptions survive a lost stream or a namenode restart. the token is only revoked when the
+// node's store is resynced, its content then no longer is what was published under it. a token this
+// namenode could not have issued, or one another node holds, is replaced by a new one
+// resumeFence must be called under the global lock
+func (s *DosNameNodeServer) resumeFence(id string, reported FencingToken, resync bool) FencingToken {
+	epoch := s.lamport.Epoch()
+	switch {
+	case resync || reported == 0:
+		return s.issueFence()
+	case reported.Epoch() > epoch, reported.Epoch() == epoch && uint32(reported) > s.fences:
+		return s.issueFence()
+	}
+	held := false
+	s.meta.ForEach(func(entry *MetaHeapEntry) {
+		held = held || entry.Id != id && entry.Fence == reported
+	})
+	if held {
+		return s.issueFence()
+	}
+	return reported
+}
@@ -0,0 +1,103 @@
+package namenode
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIssueFence(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ns"), nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	first := openTestNameNode(t, dir, 1)
+	issued := make(map[FencingToken]bool)
+	var last FencingToken
+	first.Transactional(func() {
+		for range 3 {
+			fence := first.issueFence()
+			if fence <= last {
+				t.Errorf("%s issued after %s", fence, last)
+			}
+			issued[fence] = true
+			last = fence
+		}
+	})
+
+	// a restarted namenode starts its counter over in a new epoch
+	restarted := openTestNameNode(t, dir, 1)
+	restarted.Transactional(func() {
+		fence := restarted.issueFence()
+		if issued[fence] || fence <= last {
+			t.Errorf("%s was issued by the previous namenode", fence)
+		}
+		if fence.Epoch() != restarted.lamport.Epoch() {
+			t.Errorf("%s is not in epoch %d", fence, restarted.lamport.Epoch())
+		}
+	})
+}
+
+func TestFencingTokenString(t *testing.T) {
+	tests := []struct {
+		fence FencingToken
+		want  string
+	}{
+		{fence: 0, want: "0.0"},
+		{fence: FencingToken(NewLamport(1, 3)), want: "1.3"},
+		{fence: FencingToken(NewLamport(12, 0)), want: "12.0"},
+	}
+	for _, tt := range tests {
+		if got := tt.fence.String(); got != tt.want {
+			t.Errorf("String() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestResumeFence(t *testing.T) {
+	// tokens 1 and 2 of the epoch were issued, the other node holds 2
+	tests := []struct {
+		name    string
+		epoch   int // relative to the namenode's
+		counter uint32
+		resync  bool
+		resumed bool
+	}{
+		{name: "same epoch", counter: 1, resumed: true},
+		{name: "previous epoch", epoch: -1, counter: 5, resumed: true},
+		{name: "resynced store", counter: 1, resync: true},
+		{name: "never issued", counter: 3},
+		{name: "later epoch", epoch: 1, counter: 1},
+		{name: "held by another node", counter: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestNameNode(t, 1)
+			layout(t, s, []*MetaHeapEntry{{Id: "other"}}, nil)
+			s.Transactional(func() {
+				s.issueFence()
+				s.meta.Get("other").Fence = s.issueFence()
+				epoch := s.lamport.Epoch()
+				reported := FencingToken(NewLamport(uint32(int(epoch)+tt.epoch), tt.counter))
+
+				fence := s.resumeFence("node", reported, tt.resync)
+				want := FencingToken(NewLamport(epoch, 3))
+				if tt.resumed {
+					want = reported
+				}
+				if fence != want {
+					t.Errorf("fence = %s reporting %s, want %s", fence, reported, want)
+				}
+			})
+		})
+	}
+}
+
+func TestResumeFenceOfFencedNode(t *testing.T) {
+	s := newTestNameNode(t, 1)
+	s.Transactional(func() {
+		if fence := s.resumeFence("node", 0, false); fence == 0 {
+			t.Error("a fenced node was not issued a token")
+		}
+	})
+}
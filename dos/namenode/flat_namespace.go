@@ -2,15 +2,21 @@ package namenode
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"slices"
+	"strings"
 )
 
 /**
 	this file creates the implementation for the flat namespace
 	accesses to the flat namespace are single threaded and are synced by a global lock
+
+	the namespace file has one object per line: its name, then a tab and a json record of its replication
+	and placement constraints. a line with only a name is an object of the cluster's replication
 **/
 
 var (
@@ -20,12 +26,22 @@ var (
 )
 
 type FlatNamespaceEntry struct {
-	name  string
-	nodes map[string]bool
+	name        string
+	nodes       map[string]bool
+	constraints *PlacementConstraints // nil when the object may be placed anywhere
+	replication int                   // replicas the object keeps
 }
 
 type FlatNamespace struct {
-	ns []FlatNamespaceEntry
+	ns          []FlatNamespaceEntry
+	path        string // file the namespace was loaded from and is saved to
+	replication int    // replicas of objects that do not say otherwise
+}
+
+// what the namespace file keeps of an object besides its name
+type objectRecord struct {
+	Replication int                   `json:"replication"`
+	Constraints *PlacementConstraints `json:"constraints,omitempty"`
 }
 
 func NewFlatNamespace() *FlatNamespace {
@@ -43,15 +59,27 @@ func (fn *FlatNamespace) Exists(name string) bool {
 	return false
 }
 
-func (fn *FlatNamespace) AddObject(name string) error {
+func (fn *FlatNamespace) AddObject(name string, constraints *PlacementConstraints, replication int) error {
 	fn.ns = append(fn.ns, FlatNamespaceEntry{
-		name:  name,
-		nodes: make(map[string]bool),
+		name:        name,
+		nodes:       make(map[string]bool),
+		constraints: constraints,
+		replication: replication,
 	})
 
 	return nil
 }
 
+// Constraints returns the placement constraints the object was created with, nil when it has none
+func (fn *FlatNamespace) Constraints(forObject string) *PlacementConstraints {
+	for _, object := range fn.ns {
+		if object.name == forObject {
+			return object.constraints
+		}
+	}
+	return nil
+}
+
 func (fn *FlatNamespace) DeleteObject(name string) error {
 	newNs := make([]FlatNamespaceEntry, 0)
 	found := false
@@ -70,6 +98,33 @@ func (fn *FlatNamespace) DeleteObject(name string) error {
 	return ErrObjectDoestNotExist
 }
 
+// Replication returns the replicas the object keeps, zero when it does not exist
+func (fn *FlatNamespace) Replication(forObject string) int {
+	for _, object := range fn.ns {
+		if object.name == forObject {
+			return fn.replicationOf(object)
+		}
+	}
+	return 0
+}
+
+func (fn *FlatNamespace) replicationOf(object FlatNamespaceEntry) int {
+	if object.replication == 0 {
+		return fn.replication
+	}
+	return object.replication
+}
+
+func (fn *FlatNamespace) SetReplication(forObject string, replication int) error {
+	for i := range fn.ns {
+		if fn.ns[i].name == forObject {
+			fn.ns[i].replication = replication
+			return nil
+		}
+	}
+	return ErrObjectDoestNotExist
+}
+
 func (fn *FlatNamespace) AddNode(forObject string, nodeId string) {
 	for _, object := range fn.ns {
 		if object.name == forObject {
@@ -85,6 +140,17 @@ func (fn *FlatNamespace) RemoveNode(nodeId string) {
 	}
 }
 
+// FailReplica stops counting the node as a replica of the object
+// its copy failed to apply a command, or was moved to another node
+func (fn *FlatNamespace) FailReplica(forObject string, nodeId string) {
+	for _, object := range fn.ns {
+		if object.name == forObject {
+			delete(object.nodes, nodeId)
+			return
+		}
+	}
+}
+
 func (fn *FlatNamespace) Nodes(forObject string) ([]string, error) {
 	i := slices.IndexFunc(fn.ns, func(e FlatNamespaceEntry) bool {
 		return e.name == forObject
@@ -93,7 +159,7 @@ func (fn *FlatNamespace) Nodes(forObject string) ([]string, error) {
 		return nil, ErrObjectDoestNotExist
 	}
 
-	nodes := make([]string, len(fn.ns[i].nodes))
+	nodes := make([]string, 0, len(fn.ns[i].nodes))
 	for k := range fn.ns[i].nodes {
 		nodes = append(nodes, k)
 	}
@@ -103,7 +169,6 @@ func (fn *FlatNamespace) Nodes(forObject string) ([]string, error) {
 func (fn *FlatNamespace) Objects(forNode string) []string {
 	objects := make([]string, 0)
 	for _, object := range fn.ns {
-		fmt.Println(object)
 		_, ok := object.nodes[forNode]
 		if ok {
 			// if object is storing this node
@@ -113,18 +178,79 @@ func (fn *FlatNamespace) Objects(forNode string) []string {
 	return objects
 }
 
-func (fn *FlatNamespace) Load(fnFile string) error {
+func (fn *FlatNamespace) Len() int {
+	return len(fn.ns)
+}
+
+// UnderReplicated counts the objects placed on fewer nodes than their replication
+func (fn *FlatNamespace) UnderReplicated() int {
+	count := 0
+	for _, object := range fn.ns {
+		if len(object.nodes) < fn.replicationOf(object) {
+			count++
+		}
+	}
+	return count
+}
+
+// List returns the sorted names that start with prefix
+func (fn *FlatNamespace) List(prefix string) []string {
+	names := make([]string, 0)
+	for _, object := range fn.ns {
+		if strings.HasPrefix(object.name, prefix) {
+			names = append(names, object.name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Load reads the namespace file, objects that do not record their replication keep replication replicas
+// the namespace is saved back to the same file
+func (fn *FlatNamespace) Load(fnFile string, replication int) error {
 	f, err := os.OpenFile(fnFile, os.O_RDONLY, 0666)
 	if err != nil {
 		return ErrLoadFlatNamespace
 	}
 	defer f.Close()
+	fn.path = fnFile
+	fn.replication = replication
 	fn.ns = make([]FlatNamespaceEntry, 0)
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
+		name, data, found := strings.Cut(scanner.Text(), "\t")
+		record := objectRecord{Replication: replication}
+		if found {
+			if err := json.Unmarshal([]byte(data), &record); err != nil {
+				return fmt.Errorf("%w: object %s: %v", ErrLoadFlatNamespace, name, err)
+			}
+		}
 		fn.ns = append(fn.ns, FlatNamespaceEntry{
-			name: scanner.Text(),
+			name:        name,
+			nodes:       make(map[string]bool),
+			constraints: record.Constraints,
+			replication: record.Replication,
 		})
 	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%w: %v", ErrLoadFlatNamespace, err)
+	}
 	return nil
 }
+
+// Save writes the namespace back to the file it was loaded from, replicas are not saved
+// datanodes report what they hold when they register
+func (fn *FlatNamespace) Save() error {
+	if len(fn.path) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, object := range fn.ns {
+		data, err := json.Marshal(objectRecord{Replication: fn.replicationOf(object), Constraints: object.constraints})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&buf, "%s\t%s\n", object.name, data)
+	}
+	return writeFileAtomic(fn.path, buf.Bytes())
+}
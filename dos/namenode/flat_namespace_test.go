@@ -0,0 +1,144 @@
+package namenode
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFlatNamespaceLoad(t *testing.T) {
+	tests := []struct {
+		name        string
+		line        string
+		replication int
+		constraints *PlacementConstraints
+	}{
+		{
+			name:        "a legacy line keeps the cluster's replication",
+			line:        "object",
+			replication: 3,
+		},
+		{
+			name:        "a record keeps its own replication",
+			line:        "object\t{\"replication\":1}",
+			replication: 1,
+		},
+		{
+			name:        "a record without replication keeps the cluster's",
+			line:        "object\t{}",
+			replication: 3,
+		},
+		{
+			name:        "a record keeps its constraints",
+			line:        "object\t{\"replication\":2,\"constraints\":{\"required\":{\"disk\":\"ssd\"}}}",
+			replication: 2,
+			constraints: &PlacementConstraints{Required: map[string]string{"disk": "ssd"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "ns")
+			if err := os.WriteFile(path, []byte(tt.line+"\n"), 0666); err != nil {
+				t.Fatal(err)
+			}
+			fn := NewFlatNamespace()
+			if err := fn.Load(path, 3); err != nil {
+				t.Fatal(err)
+			}
+			if !fn.Exists("object") {
+				t.Fatal("object was not loaded")
+			}
+			if got := fn.Replication("object"); got != tt.replication {
+				t.Errorf("replication = %d, want %d", got, tt.replication)
+			}
+			got := fn.Constraints("object")
+			if (got == nil) != (tt.constraints == nil) {
+				t.Fatalf("constraints = %v, want %v", got, tt.constraints)
+			}
+			if got != nil && !maps.Equal(got.Required, tt.constraints.Required) {
+				t.Errorf("required = %v, want %v", got.Required, tt.constraints.Required)
+			}
+		})
+	}
+}
+
+func TestFlatNamespaceLoadRejectsBadRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ns")
+	if err := os.WriteFile(path, []byte("object\t{not json\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewFlatNamespace().Load(path, 3); err == nil {
+		t.Fatal("loaded a namespace with a broken record")
+	}
+}
+
+func TestFlatNamespaceSaveRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ns")
+	if err := os.WriteFile(path, []byte("legacy\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	fn := NewFlatNamespace()
+	if err := fn.Load(path, 2); err != nil {
+		t.Fatal(err)
+	}
+	fn.AddObject("single", nil, 1)
+	fn.AddObject("placed", &PlacementConstraints{Preferred: map[string]string{"zone": "a"}}, 4)
+	fn.AddObject("gone", nil, 2)
+	fn.DeleteObject("gone")
+	if err := fn.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewFlatNamespace()
+	// a different cluster replication shows which objects recorded their own
+	if err := loaded.Load(path, 5); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		object      string
+		exists      bool
+		replication int
+	}{
+		{object: "legacy", exists: true, replication: 2},
+		{object: "single", exists: true, replication: 1},
+		{object: "placed", exists: true, replication: 4},
+		{object: "gone", exists: false, replication: 0},
+	}
+	for _, tt := range tests {
+		if got := loaded.Exists(tt.object); got != tt.exists {
+			t.Errorf("%s exists = %v, want %v", tt.object, got, tt.exists)
+		}
+		if got := loaded.Replication(tt.object); got != tt.replication {
+			t.Errorf("%s replication = %d, want %d", tt.object, got, tt.replication)
+		}
+	}
+	if got := loaded.Constraints("placed"); got == nil || got.Preferred["zone"] != "a" {
+		t.Errorf("placed constraints = %v", got)
+	}
+}
+
+func TestFlatNamespaceUnderReplicated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ns")
+	if err := os.WriteFile(path, []byte("legacy\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	fn := NewFlatNamespace()
+	if err := fn.Load(path, 2); err != nil {
+		t.Fatal(err)
+	}
+	fn.AddObject("single", nil, 1)
+
+	if got := fn.UnderReplicated(); got != 2 {
+		t.Fatalf("under replicated = %d, want 2", got)
+	}
+	fn.AddNode("legacy", "a")
+	fn.AddNode("single", "a")
+	if got := fn.UnderReplicated(); got != 1 {
+		t.Fatalf("under replicated = %d, want 1", got)
+	}
+	fn.AddNode("legacy", "b")
+	if got := fn.UnderReplicated(); got != 0 {
+		t.Fatalf("under replicated = %d, want 0", got)
+	}
+}
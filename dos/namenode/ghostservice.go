@@ -3,6 +3,7 @@ package namenode
 import (
 	"errors"
 	"math/rand"
+	"slices"
 	"time"
 
 	"github.com/mrowaha/dos/api"
@@ -18,60 +19,74 @@ type GhostNodeEntry struct {
 	Id        string
 	CommandCh chan<- map[string][]byte
 	CloseCh   <-chan struct{}
+	Since     time.Time // when the ghost connected
+	// failure domain the ghost registers in once it turns into a datanode
+	Zone string
+	Rack string
 }
 
 type GhostNodesMap map[string]*GhostNodeEntry
 
-func (s *DosNameNodeServer) randomGhost() *GhostNodeEntry {
-	rand.Seed(time.Now().UnixNano())
-	keys := make([]string, 0, len(s.ghosts))
-	for key := range s.ghosts {
-		keys = append(keys, key)
+// pickGhost picks the ghost that takes over the objects of the failed node, called under the global lock
+// like a replica it goes to the zone and then the rack holding the fewest of the objects' other replicas,
+// ghosts that spread them as far are picked at random
+func (s *DosNameNodeServer) pickGhost(failedNode string, replicas map[string][]string) *GhostNodeEntry {
+	if len(s.ghosts) == 0 {
+		return nil
 	}
-
-	if len(keys) > 0 {
-		randomKey := keys[rand.Intn(len(keys))]
-		randomValue := s.ghosts[randomKey]
-		return randomValue
+	placed := s.placedOn(nil)
+	for _, nodes := range replicas {
+		for _, node := range nodes {
+			if entry := s.meta.Get(node); entry != nil && node != failedNode {
+				placed.add(entry)
+			}
+		}
 	}
-	return nil
+	candidates := make([]*MetaHeapEntry, 0, len(s.ghosts))
+	for _, ghost := range s.ghosts {
+		candidates = append(candidates, &MetaHeapEntry{Id: ghost.Id, Zone: ghost.Zone, Rack: ghost.Rack})
+	}
+	spread := placed.spreadOut(candidates)
+	return s.ghosts[spread[rand.Intn(len(spread))].Id]
 }
 
 func (s *DosNameNodeServer) InitiateSpawn(failedNode string) error {
 
 	s.logger.Printf("initiating spawn protocol\n")
 
-	// select a random ghost node
-	randomGhostNode := s.randomGhost()
-	if randomGhostNode == nil {
-		return ErrNoGhostNode
-	}
-
+	var ghost *GhostNodeEntry
 	var requiredObjects []string
+	replicas := make(map[string][]string)
 	s.Transactional(func() {
 		requiredObjects = s.flatNS.Objects(failedNode)
+		for _, object := range requiredObjects {
+			replicas[object], _ = s.flatNS.Nodes(object)
+		}
+		ghost = s.pickGhost(failedNode, replicas)
 	})
+	if ghost == nil {
+		return ErrNoGhostNode
+	}
+	s.logger.Printf("spawning ghost %s in zone %q rack %q", ghost.Id, ghost.Zone, ghost.Rack)
 
 	// now we have required objects
 	s.logger.Printf("failing node %s had objects %v", failedNode, requiredObjects)
 
 	aggregate := make(map[string][]byte, len(requiredObjects))
-	resultsCh := s.BroadcastDistributedRead(requiredObjects)
+	resultsCh := s.BroadcastDistributedRead(requiredObjects, "")
 	for result := range resultsCh {
-		typedResult := result.([]*api.NodeHeartBeat_Object)
-		for _, ob := range typedResult {
-			if _, ok := aggregate[ob.Name]; !ok {
+		for _, ob := range result.Objects {
+			if _, ok := aggregate[ob.Name]; !ok && slices.Contains(replicas[ob.Name], result.Node) {
 				aggregate[ob.Name] = ob.Data
 			}
 		}
 	}
 
-	// now we have a randomly selected ghost node
-	randomGhostNode.CommandCh <- aggregate
+	ghost.CommandCh <- aggregate
 
 	s.Transactional(func() {
 		for _, object := range requiredObjects {
-			s.flatNS.AddNode(object, randomGhostNode.Id)
+			s.flatNS.AddNode(object, ghost.Id)
 		}
 	})
 
@@ -83,14 +98,25 @@ func (s *DosNameNodeServer) Spawn(req *api.SpawnWait, stream grpc.ServerStreamin
 
 	commandCh := make(chan map[string][]byte)
 	closech := make(chan struct{})
-	s.ghosts[ghostNodeId] = &GhostNodeEntry{
+	ghost := &GhostNodeEntry{
 		Id:        ghostNodeId,
 		CommandCh: commandCh,
 		CloseCh:   closech,
+		Since:     time.Now(),
+		Zone:      req.Zone,
+		Rack:      req.Rack,
 	}
+	s.Transactional(func() {
+		s.ghosts[ghostNodeId] = ghost
+	})
 
 	defer func() {
-		closech <- struct{}{}
+		s.Transactional(func() {
+			// a ghost that reconnected under the same name already replaced this entry
+			if s.ghosts[ghostNodeId] == ghost {
+				delete(s.ghosts, ghostNodeId)
+			}
+		})
 		close(closech)
 		s.logger.Printf("[ghostnode %s] stream closed", ghostNodeId)
 	}()
@@ -112,9 +138,14 @@ func (s *DosNameNodeServer) Spawn(req *api.SpawnWait, stream grpc.ServerStreamin
 				})
 			}
 
+			// the ghost starts at the current cut, its register command confirms it
+			var lamport Lamport
+			s.Transactional(func() {
+				lamport = s.lamport
+			})
 			stream.Send(&api.SpawnCommand{
 				Status:   api.SpawnCommand_DONE,
-				Lamport:  s.lamport,
+				Lamport:  uint64(lamport),
 				Commands: commands,
 			})
 		case <-stream.Context().Done():
@@ -0,0 +1,51 @@
+package namenode
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPickGhost(t *testing.T) {
+	ghosts := []*GhostNodeEntry{
+		{Id: "g1", Zone: "z1", Rack: "r1"},
+		{Id: "g2", Zone: "z1", Rack: "r2"},
+		{Id: "g3", Zone: "z2", Rack: "r1"},
+	}
+	tests := []struct {
+		name     string
+		failed   string
+		replicas map[string][]string
+		want     []string // any of them
+	}{
+		{name: "no other replicas", failed: "a", replicas: map[string][]string{"o1": {"a"}}, want: []string{"g1", "g2", "g3"}},
+		{name: "the other replica in z1", failed: "d", replicas: map[string][]string{"o1": {"a", "d"}}, want: []string{"g3"}},
+		{name: "the other replica in z2", failed: "a", replicas: map[string][]string{"o1": {"a", "d"}}, want: []string{"g1", "g2"}},
+		{name: "a rack without replicas", failed: "e", replicas: map[string][]string{"o1": {"a", "e"}, "o2": {"d", "e"}}, want: []string{"g2"}},
+		{name: "most objects", failed: "e", replicas: map[string][]string{"o1": {"a", "e"}, "o2": {"b", "e"}, "o3": {"d", "e"}}, want: []string{"g3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestNameNode(t, 1)
+			layout(t, s, topology(), nil)
+			s.Transactional(func() {
+				for _, ghost := range ghosts {
+					s.ghosts[ghost.Id] = ghost
+				}
+				for range 20 {
+					if ghost := s.pickGhost(tt.failed, tt.replicas); !slices.Contains(tt.want, ghost.Id) {
+						t.Fatalf("picked %s, want one of %v", ghost.Id, tt.want)
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestPickGhostWithoutGhosts(t *testing.T) {
+	s := newTestNameNode(t, 1)
+	s.Transactional(func() {
+		if ghost := s.pickGhost("a", map[string][]string{"o1": {"a"}}); ghost != nil {
+			t.Errorf("picked %s, want none", ghost.Id)
+		}
+	})
+}
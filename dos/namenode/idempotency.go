@@ -0,0 +1,155 @@
+package namenode
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mrowaha/dos/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+/**
+	mutations carry an idempotency key in their request meta, the same key on every retry of one mutation
+	the namenode remembers the outcome of recent keys, so a retry of a create that went through is answered
+	CREATED instead of ErrObjectAlreadyExists, and a retried update is not broadcast twice
+	a retry that arrives while the first attempt is still running waits for it
+	outcomes worth retrying are not remembered, the next retry runs the mutation again
+**/
+
+var (
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+)
+
+type idempotentCall struct {
+	fingerprint [sha256.Size]byte // method and request the key was first used with
+	done        chan struct{}
+	res         any
+	err         error
+	expires     time.Time
+}
+
+type IdempotencyCache struct {
+	lock  sync.Mutex
+	ttl   time.Duration
+	max   int
+	calls map[string]*idempotentCall
+	keys  []string // in the order they were first seen, which is also the order they expire in
+}
+
+func NewIdempotencyCache(ttl time.Duration, max int) *IdempotencyCache {
+	return &IdempotencyCache{
+		ttl:   ttl,
+		max:   max,
+		calls: make(map[string]*idempotentCall),
+		keys:  make([]string, 0),
+	}
+}
+
+// begin returns the call of the key, first is set if the caller has to run it
+func (c *IdempotencyCache) begin(key string, fingerprint [sha256.Size]byte) (*idempotentCall, bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := time.Now()
+	c.evict(now, 0)
+	if call, ok := c.calls[key]; ok {
+		if call.fingerprint != fingerprint {
+			return nil, false, ErrIdempotencyKeyReused
+		}
+		return call, false, nil
+	}
+
+	c.evict(now, 1)
+	call := &idempotentCall{
+		fingerprint: fingerprint,
+		done:        make(chan struct{}),
+		expires:     now.Add(c.ttl),
+	}
+	c.calls[key] = call
+	c.keys = append(c.keys, key)
+	return call, true, nil
+}
+
+// finish hands the outcome to the retries waiting on the call
+func (c *IdempotencyCache) finish(key string, call *idempotentCall, res any, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	call.res, call.err = res, err
+	close(call.done)
+	if retryable(err) && c.calls[key] == call {
+		delete(c.calls, key)
+	}
+}
+
+// evict drops expired keys and the oldest keys beyond the limit, leaving room for as many new ones
+// it must be called with the lock held
+func (c *IdempotencyCache) evict(now time.Time, room int) {
+	drop := 0
+	for _, key := range c.keys {
+		call, ok := c.calls[key]
+		if ok && now.Before(call.expires) && len(c.keys)-drop+room <= c.max {
+			break
+		}
+		if ok {
+			delete(c.calls, key)
+		}
+		drop++
+	}
+	c.keys = c.keys[drop:]
+}
+
+// retryable reports whether an outcome may be different on another attempt
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.OK, codes.AlreadyExists, codes.NotFound, codes.InvalidArgument, codes.FailedPrecondition:
+		return false
+	}
+	return true
+}
+
+// fingerprint identifies the method and the request without its meta
+func fingerprint(method string, req proto.Message) [sha256.Size]byte {
+	clone := proto.Clone(req).ProtoReflect()
+	if fd := clone.Descriptor().Fields().ByName("meta"); fd != nil {
+		clone.Clear(fd)
+	}
+	data, _ := proto.MarshalOptions{Deterministic: true}.Marshal(clone.Interface())
+	return sha256.Sum256(append([]byte(method+"\x00"), data...))
+}
+
+// Intercept answers requests whose idempotency key was seen before with the outcome of the first attempt
+func (c *IdempotencyCache) Intercept(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	withMeta, ok := req.(interface{ GetMeta() *api.RequestMeta })
+	if !ok {
+		return handler(ctx, req)
+	}
+	key := withMeta.GetMeta().GetIdempotencyKey()
+	message, ok := req.(proto.Message)
+	if len(key) == 0 || !ok {
+		return handler(ctx, req)
+	}
+
+	call, first, err := c.begin(key, fingerprint(info.FullMethod, message))
+	if err != nil {
+		return nil, toStatus(err, req)
+	}
+	if first {
+		res, err := handler(ctx, req)
+		c.finish(key, call, res, err)
+		return res, err
+	}
+
+	select {
+	case <-call.done:
+		return call.res, call.err
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+}
@@ -0,0 +1,116 @@
+package namenode
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mrowaha/dos/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIdempotencyCacheOutcomes(t *testing.T) {
+	create := sha256.Sum256([]byte("create a"))
+	tests := []struct {
+		name        string
+		err         error // outcome of the first attempt
+		fingerprint [sha256.Size]byte
+		first       bool // the retry runs the mutation again
+		wantErr     error
+	}{
+		{name: "went through", fingerprint: create},
+		{name: "failed for good", err: status.Error(codes.AlreadyExists, "exists"), fingerprint: create},
+		{name: "worth retrying", err: status.Error(codes.Unavailable, "no datanodes"), fingerprint: create, first: true},
+		{name: "key of another request", fingerprint: sha256.Sum256([]byte("create b")), wantErr: ErrIdempotencyKeyReused},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewIdempotencyCache(time.Minute, 10)
+			call, first, err := c.begin("key", create)
+			if err != nil || !first {
+				t.Fatalf("begin = %v, %v", first, err)
+			}
+			c.finish("key", call, "res", tt.err)
+
+			retry, first, err := c.begin("key", tt.fingerprint)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if first != tt.first {
+				t.Fatalf("first = %v, want %v", first, tt.first)
+			}
+			if !first && (retry.res != "res" || retry.err != tt.err) {
+				t.Errorf("retry answered %v, %v", retry.res, retry.err)
+			}
+		})
+	}
+}
+
+func TestIdempotencyCacheEviction(t *testing.T) {
+	c := NewIdempotencyCache(time.Minute, 2)
+	for _, key := range []string{"a", "b", "c"} {
+		call, _, _ := c.begin(key, sha256.Sum256([]byte(key)))
+		c.finish(key, call, nil, nil)
+	}
+	if len(c.calls) != 2 || c.calls["a"] != nil {
+		t.Errorf("cache holds %d keys, a is kept: %v", len(c.calls), c.calls["a"] != nil)
+	}
+
+	c.lock.Lock()
+	c.evict(time.Now().Add(2*time.Minute), 0)
+	c.lock.Unlock()
+	if len(c.calls) != 0 || len(c.keys) != 0 {
+		t.Errorf("expired keys kept: %v", c.keys)
+	}
+}
+
+func TestIdempotentRetryWaitsForFirstAttempt(t *testing.T) {
+	c := NewIdempotencyCache(time.Minute, 10)
+	req := &api.CreateObjectRequest{Meta: &api.RequestMeta{IdempotencyKey: "key"}, Name: "a", Data: []byte("data")}
+	info := &grpc.UnaryServerInfo{FullMethod: "/NameService/CreateObject"}
+
+	release := make(chan struct{})
+	var runs atomic.Int32
+	handler := func(ctx context.Context, req any) (any, error) {
+		runs.Add(1)
+		<-release
+		return &api.CreateObjectResponse{}, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]any, 2)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// a retry carries a new timestamp in its meta and the same key
+			retry := &api.CreateObjectRequest{Meta: &api.RequestMeta{IdempotencyKey: "key"}, Name: req.Name, Data: req.Data}
+			results[i], _ = c.Intercept(context.Background(), retry, info, handler)
+		}()
+	}
+	eventually(t, func() bool { return runs.Load() == 1 })
+	close(release)
+	wg.Wait()
+
+	if runs.Load() != 1 {
+		t.Errorf("mutation ran %d times", runs.Load())
+	}
+	if results[0] != results[1] {
+		t.Errorf("retry answered %v, first attempt %v", results[1], results[0])
+	}
+
+	// the key of a finished call can not be used for another request
+	other := &api.CreateObjectRequest{Meta: &api.RequestMeta{IdempotencyKey: "key"}, Name: "b"}
+	if _, err := c.Intercept(context.Background(), other, info, handler); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("err = %v, want %s", err, codes.InvalidArgument)
+	}
+}
@@ -0,0 +1,168 @@
+package namenode
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mrowaha/dos/api"
+)
+
+/**
+	maintenance takes a datanode out of placement for a short window without giving up on its replicas
+	while in maintenance no new objects or copied replicas are placed on it, but it keeps serving reads
+	and leases while it is up. when its stream goes down the node is parked instead of failed: it stays
+	in the meta, its replicas stay in the namespace and no ghost takes over
+
+	every broadcast from the start of the window is buffered for the node, so when it registers again
+	it resumes from its own lamport and is sent what it missed, instead of being wiped and resynced.
+	a node that missed more commands than the buffer holds resyncs as usual. a node still away when
+	its window ends is handled like any failed datanode
+**/
+
+var (
+	ErrNodeInMaintenance   = errors.New("datanode is in maintenance")
+	ErrNodeDecommissioning = errors.New("datanode is being decommissioned")
+)
+
+type Maintenance struct {
+	Until      time.Time
+	Since      Lamport       // lamport when the window started, every broadcast after it is buffered
+	Commands   []CommandNode // in lamport order
+	Overflowed bool          // the buffer no longer holds every command since the window started
+	timer      *time.Timer
+}
+
+// record buffers a broadcast command, up to limit of them
+func (m *Maintenance) record(cmd CommandNode, limit int) {
+	if m.Overflowed {
+		return
+	}
+	if len(m.Commands) >= limit {
+		m.Overflowed = true
+		m.Commands = nil
+		return
+	}
+	m.Commands = append(m.Commands, cmd)
+}
+
+// missed returns the buffered commands after lamport
+// it returns false when a node at lamport can not catch up from the buffer
+func (m *Maintenance) missed(lamport Lamport) ([]CommandNode, bool) {
+	if m.Overflowed || lamport < m.Since {
+		return nil, false
+	}
+	missed := make([]CommandNode, 0, len(m.Commands))
+	for _, cmd := range m.Commands {
+		if cmd.stamp() > lamport {
+			missed = append(missed, cmd)
+		}
+	}
+	return missed, true
+}
+
+// stamp is the lamport of a broadcast command, zero for commands that are not broadcast
+func (c *CommandNode) stamp() Lamport {
+	switch c.command {
+	case api.CommandNodeRes_COMMIT:
+		return c.commit.Lamport
+	case api.CommandNodeRes_DELETE:
+		return c.delete.Lamport
+	case api.CommandNodeRes_UPDATE:
+		return c.update.Lamport
+	case api.CommandNodeRes_DISTRIBUTED_READ:
+		return c.distributedRead.Lamport
+	case api.CommandNodeRes_SNAPSHOT, api.CommandNodeRes_DELETE_SNAPSHOT:
+		return c.snapshot.Lamport
+	}
+	return 0
+}
+
+func (s *DosNameNodeServer) StartMaintenance(ctx context.Context, req *api.StartMaintenanceReq) (*api.StartMaintenanceRes, error) {
+	s.logger.Printf("attempting request [start maintenance %s]\n", req.Name)
+
+	window := s.config.MaintenanceWindow
+	if req.Window != nil && req.Window.AsDuration() > 0 {
+		window = req.Window.AsDuration()
+	}
+
+	var res *api.StartMaintenanceRes
+	var transactionErr error
+	s.Transactional(func() {
+		entry := s.meta.Get(req.Name)
+		switch {
+		case entry == nil:
+			transactionErr = ErrDataNodeNotFound
+			return
+		case entry.State == NodeDecommissioning || entry.State == NodeDecommissioned:
+			transactionErr = ErrNodeDecommissioning
+			return
+		case entry.State == NodeMaintenance:
+			// a longer job than planned, the window starts over
+			entry.Maintenance.Until = time.Now().Add(window)
+			entry.Maintenance.timer.Reset(window)
+		default:
+			entry.State = NodeMaintenance
+			entry.Maintenance = &Maintenance{
+				Until: time.Now().Add(window),
+				Since: s.lamport,
+				timer: time.AfterFunc(window, func() {
+					s.logger.Printf("[datanode %s] maintenance window ended", entry.Id)
+					s.endMaintenance(entry)
+				}),
+			}
+		}
+		s.logger.Printf("[datanode %s] in maintenance until %s", entry.Id, entry.Maintenance.Until.Format(time.TimeOnly))
+		res = &api.StartMaintenanceRes{Node: s.nodeSummary(entry)}
+	})
+	if transactionErr != nil {
+		return nil, transactionErr
+	}
+	return res, nil
+}
+
+func (s *DosNameNodeServer) EndMaintenance(ctx context.Context, req *api.EndMaintenanceReq) (*api.EndMaintenanceRes, error) {
+	s.logger.Printf("attempting request [end maintenance %s]\n", req.Name)
+
+	var entry *MetaHeapEntry
+	s.Transactional(func() {
+		entry = s.meta.Get(req.Name)
+	})
+	if entry == nil {
+		return nil, ErrDataNodeNotFound
+	}
+	// a node that is not in maintenance is left as it is
+	return &api.EndMaintenanceRes{Node: s.endMaintenance(entry)}, nil
+}
+
+// endMaintenance makes the node active again, or fails it if it is still away
+func (s *DosNameNodeServer) endMaintenance(entry *MetaHeapEntry) *api.NodeSummary {
+	var summary *api.NodeSummary
+	ended, away := false, false
+	s.Transactional(func() {
+		if entry.State != NodeMaintenance || s.meta.Get(entry.Id) != entry {
+			// ended already, or the node left meanwhile
+			summary = s.nodeSummary(entry)
+			return
+		}
+		entry.Maintenance.timer.Stop()
+		entry.Maintenance = nil
+		entry.State = NodeActive
+		ended = true
+		away = entry.Session.Closed()
+		if away {
+			s.meta.DeleteNode(entry.Id)
+		}
+		summary = s.nodeSummary(entry)
+	})
+
+	if !away {
+		if ended {
+			s.logger.Printf("[datanode %s] out of maintenance", entry.Id)
+		}
+		return summary
+	}
+	s.logger.Printf("[datanode %s] did not return from maintenance", entry.Id)
+	s.lose(entry.Id)
+	return summary
+}
@@ -0,0 +1,129 @@
+package namenode
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mrowaha/dos/api"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestMaintenanceMissed(t *testing.T) {
+	buffered := &Maintenance{
+		Since:    NewLamport(1, 3),
+		Commands: []CommandNode{deleteAt(NewLamport(1, 4)), deleteAt(NewLamport(1, 5)), deleteAt(NewLamport(1, 6))},
+	}
+	tests := []struct {
+		name        string
+		maintenance *Maintenance
+		lamport     Lamport
+		want        []Lamport
+		ok          bool
+	}{
+		{name: "away since the window started", maintenance: buffered, lamport: NewLamport(1, 3), want: stamps(buffered.Commands), ok: true},
+		{name: "left during the window", maintenance: buffered, lamport: NewLamport(1, 5), want: []Lamport{NewLamport(1, 6)}, ok: true},
+		{name: "missed nothing", maintenance: buffered, lamport: NewLamport(1, 6), ok: true},
+		{name: "behind the window", maintenance: buffered, lamport: NewLamport(1, 2), ok: false},
+		{name: "buffer overflowed", maintenance: &Maintenance{Since: NewLamport(1, 3), Overflowed: true}, lamport: NewLamport(1, 3), ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			missed, ok := tt.maintenance.missed(tt.lamport)
+			if ok != tt.ok || !slices.Equal(stamps(missed), tt.want) {
+				t.Errorf("missed = %v, %v, want %v, %v", stamps(missed), ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestMaintenanceRecord(t *testing.T) {
+	m := &Maintenance{Since: NewLamport(1, 0)}
+	for counter := uint32(1); counter <= 2; counter++ {
+		m.record(deleteAt(NewLamport(1, counter)), 2)
+	}
+	if m.Overflowed || len(m.Commands) != 2 {
+		t.Fatalf("buffer = %v, overflowed %v", stamps(m.Commands), m.Overflowed)
+	}
+	m.record(deleteAt(NewLamport(1, 3)), 2)
+	m.record(deleteAt(NewLamport(1, 4)), 2)
+	if !m.Overflowed || len(m.Commands) != 0 {
+		t.Errorf("buffer = %v, overflowed %v, want it dropped", stamps(m.Commands), m.Overflowed)
+	}
+}
+
+// state returns the state of the node, false when it is not in the meta
+func state(s *DosNameNodeServer, node string) (NodeState, bool) {
+	var st NodeState
+	var ok bool
+	s.Transactional(func() {
+		if entry := s.meta.Get(node); entry != nil {
+			st, ok = entry.State, true
+		}
+	})
+	return st, ok
+}
+
+func TestMaintenanceParksNode(t *testing.T) {
+	tests := []struct {
+		name    string
+		returns bool // the node registers again within its window
+	}{
+		{name: "back within the window", returns: true},
+		{name: "away past the window"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestNameNode(t, 1, "object")
+			node := registerFake(t, s, 0, "object")
+			window := time.Hour
+			if !tt.returns {
+				window = 100 * time.Millisecond
+			}
+			_, err := s.StartMaintenance(context.Background(), &api.StartMaintenanceReq{Name: node.id, Window: durationpb.New(window)})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the node goes down for its maintenance
+			node.cancel()
+			var session *DataNodeSession
+			s.Transactional(func() { session = s.meta.Get(node.id).Session })
+			eventually(t, session.Closed)
+			if st, ok := state(s, node.id); !ok || st != NodeMaintenance {
+				t.Fatalf("state = %v, registered %v, want it parked", st, ok)
+			}
+			if got := replicas(s, "object"); !slices.Equal(got, []string{node.id}) {
+				t.Fatalf("replicas = %v, the parked node keeps its replica", got)
+			}
+
+			if !tt.returns {
+				eventually(t, func() bool {
+					_, ok := state(s, node.id)
+					return !ok
+				})
+				if got := replicas(s, "object"); len(got) != 0 {
+					t.Errorf("replicas = %v after the node failed", got)
+				}
+				return
+			}
+
+			registerFake(t, s, 0, "object")
+			eventually(t, func() bool {
+				back := false
+				s.Transactional(func() { back = !s.meta.Get(node.id).Session.Closed() })
+				return back
+			})
+			if _, err := s.EndMaintenance(context.Background(), &api.EndMaintenanceReq{Name: node.id}); err != nil {
+				t.Fatal(err)
+			}
+			if st, ok := state(s, node.id); !ok || st != NodeActive {
+				t.Errorf("state = %v, registered %v, want it active", st, ok)
+			}
+			if got := replicas(s, "object"); !slices.Equal(got, []string{node.id}) {
+				t.Errorf("replicas = %v after maintenance", got)
+			}
+		})
+	}
+}
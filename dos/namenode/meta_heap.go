@@ -3,15 +3,49 @@ package namenode
 import (
 	"container/heap"
 	"slices"
+	"time"
 )
 
+// NodeState tells whether new objects may be placed on a datanode
+type NodeState int
+
+const (
+	NodeActive NodeState = iota
+	NodeDecommissioning
+	NodeDecommissioned
+	NodeMaintenance
+)
+
+func (state NodeState) String() string {
+	switch state {
+	case NodeActive:
+		return "active"
+	case NodeDecommissioning:
+		return "decommissioning"
+	case NodeDecommissioned:
+		return "decommissioned"
+	case NodeMaintenance:
+		return "maintenance"
+	}
+	return "unknown"
+}
+
 type MetaHeapEntry struct {
-	Id        string
-	Lease     string
-	CommandCh chan<- CommandNode
-	ResChs    map[string]chan interface{}
-	ClosedCh  <-chan struct{}
-	Size      float32
+	Id       string
+	NodeId   string // uuid of the datanode process behind the name
+	Fence    FencingToken
+	Lease    string
+	Session  *DataNodeSession
+	Size     float32
+	Capacity float32 // bytes the node can hold, zero when it does not say
+	Zone     string  // failure domain the node advertised, empty when it did not say
+	Rack     string
+	Labels   map[string]string // matched by the placement constraints of objects
+	Joined   time.Time
+	State    NodeState
+	Drain    *DrainProgress // set once the node is decommissioned
+
+	Maintenance *Maintenance // set while the node is in maintenance
 }
 
 type MetaHeap []*MetaHeapEntry
@@ -22,7 +56,7 @@ func (h *MetaHeap) Len() int {
 
 func (h *MetaHeap) Less(i, j int) bool {
 	a, b := (*h)[i], (*h)[j]
-	return a.Size > b.Size
+	return a.Size < b.Size
 }
 
 func (h *MetaHeap) Swap(i, j int) {
@@ -64,11 +98,27 @@ func (d *DataNodeMeta) Count() int {
 	return d.heap.Len()
 }
 
-func (d *DataNodeMeta) Next() *MetaHeapEntry {
-	if d.heap.Len() == 0 {
-		return nil
+// CountActive counts the nodes new objects can be placed on
+func (d *DataNodeMeta) CountActive() int {
+	count := 0
+	for _, entry := range *d.heap {
+		if entry.State == NodeActive {
+			count++
+		}
 	}
-	return heap.Pop(d.heap).(*MetaHeapEntry)
+	return count
+}
+
+// Candidates lists the nodes new replicas may be placed on, passing over the ones in skip
+// the heap is left as it is, the placement policy chooses among them
+func (d *DataNodeMeta) Candidates(skip map[string]bool) []*MetaHeapEntry {
+	candidates := make([]*MetaHeapEntry, 0, d.heap.Len())
+	for _, entry := range *d.heap {
+		if entry.State == NodeActive && !skip[entry.Id] && !entry.Session.Closed() {
+			candidates = append(candidates, entry)
+		}
+	}
+	return candidates
 }
 
 func (d *DataNodeMeta) Exists(id string) bool {
@@ -80,6 +130,24 @@ func (d *DataNodeMeta) Exists(id string) bool {
 	return false
 }
 
+func (d *DataNodeMeta) ExistsNodeId(nodeId string) bool {
+	for _, entry := range *d.heap {
+		if entry.NodeId == nodeId {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *DataNodeMeta) Get(id string) *MetaHeapEntry {
+	for _, entry := range *d.heap {
+		if entry.Id == id {
+			return entry
+		}
+	}
+	return nil
+}
+
 func (d *DataNodeMeta) ForEach(cb func(entry *MetaHeapEntry)) {
 	for _, entry := range *d.heap {
 		cb(entry)
@@ -91,6 +159,10 @@ func (d *DataNodeMeta) UpdateSize(id string, size float32) bool {
 		return c.Id == id
 	})
 
+	if idx == -1 {
+		// the node left
+		return false
+	}
 	if (*d.heap)[idx].Size == size {
 		return false
 	}
@@ -100,8 +172,10 @@ func (d *DataNodeMeta) UpdateSize(id string, size float32) bool {
 }
 
 // this function will return the lease services for the corresponding nodes
-func (d *DataNodeMeta) LeaseServices(nodes []string) ([]string, error) {
+// along with the fencing token each service stamps on its publications
+func (d *DataNodeMeta) LeaseServices(nodes []string) ([]string, []uint64, error) {
 	services := make([]string, 0)
+	fences := make([]uint64, 0)
 	for _, el := range *d.heap {
 		i := slices.IndexFunc(nodes, func(node string) bool {
 			return el.Id == node
@@ -109,9 +183,10 @@ func (d *DataNodeMeta) LeaseServices(nodes []string) ([]string, error) {
 		if i != -1 {
 			//this el is in the nodes slice
 			services = append(services, el.Lease)
+			fences = append(fences, uint64(el.Fence))
 		}
 	}
-	return services, nil
+	return services, fences, nil
 }
 
 func (d *DataNodeMeta) DeleteNode(nodeId string) {
@@ -3,10 +3,13 @@ package namenode
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"net"
 	"os"
+	"slices"
 	"sync"
+	"sync/atomic"
 
 	"github.com/mrowaha/dos/api"
 	"google.golang.org/grpc"
@@ -22,13 +25,27 @@ type DosNameNodeServer struct {
 	api.UnimplementedNameServiceServer
 	api.UnimplementedDataServiceServer
 	api.UnimplementedGhostServiceServer
-	logger  *log.Logger
-	flatNS  *FlatNamespace
-	config  *NameNodeConfig
-	meta    *DataNodeMeta
-	lock    sync.Mutex
-	ghosts  GhostNodesMap
-	lamport int32
+	api.UnimplementedAdminServiceServer
+	logger      *log.Logger
+	flatNS      *FlatNamespace
+	config      *NameNodeConfig
+	meta        *DataNodeMeta
+	lock        sync.Mutex
+	ghosts      GhostNodesMap
+	snapshots   SnapshotsMap
+	creating    map[string]bool // objects whose creates are in flight
+	moving      map[string]bool // objects whose replica is being copied, true once written meanwhile
+	moves       sync.Mutex      // replica copies run one at a time
+	rebalancing bool            // a rebalance round is running
+	converging  map[string]bool // objects whose replicas are added or removed to match their replication
+	lamport     Lamport         // only changes under the global lock
+	recent      *recentBroadcasts
+	previous    Lamport       // highest lamport of a previous epoch a datanode registered with
+	requests    atomic.Uint64 // last request id handed to a datanode command
+	clusterId   string
+	fences      uint32 // fencing tokens issued in this epoch
+
+	idempotency *IdempotencyCache
 }
 
 func NewDosNameNodeServer(logFilePath string, flatNSPath string, opts ...ConfigFunc) (*DosNameNodeServer, error) {
@@ -39,26 +56,59 @@ func NewDosNameNodeServer(logFilePath string, flatNSPath string, opts ...ConfigF
 	defer f.Close()
 
 	logger := log.New(os.Stdout, "", log.Ltime|log.Lmicroseconds)
+	config := NewNameNodeConfig(opts...)
+
 	flatNS := NewFlatNamespace()
-	err = flatNS.Load(flatNSPath)
+	err = flatNS.Load(flatNSPath, config.Replication)
 	if err != nil {
 		return nil, err
 	}
 
-	config := NewNameNodeConfig(opts...)
+	epoch, err := nextEpoch(config.EpochFile)
+	if err != nil {
+		return nil, err
+	}
+	clusterId, err := loadClusterId(config.ClusterFile)
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := loadSnapshots(config.SnapshotFile)
+	if err != nil {
+		return nil, err
+	}
+	logger.Printf("starting cluster %s epoch %d", clusterId, epoch)
+	if len(config.JoinToken) == 0 {
+		logger.Printf("no join token configured, any datanode can register")
+	}
 
 	meta := NewDataNodeMeta()
 
 	return &DosNameNodeServer{
-		logger:  logger,
-		flatNS:  flatNS,
-		config:  config,
-		meta:    meta,
-		lamport: 0,
-		ghosts:  make(GhostNodesMap),
+		logger:     logger,
+		flatNS:     flatNS,
+		config:     config,
+		meta:       meta,
+		lamport:    NewLamport(epoch, 0),
+		ghosts:     make(GhostNodesMap),
+		snapshots:  snapshots,
+		creating:   make(map[string]bool),
+		moving:     make(map[string]bool),
+		converging: make(map[string]bool),
+		recent:     &recentBroadcasts{limit: config.CatchUpBacklog},
+		clusterId:  clusterId,
+
+		idempotency: NewIdempotencyCache(config.IdempotencyTTL, config.IdempotencyKeys),
 	}, nil
 }
 
+// saveNamespace writes the namespace to its file, called under the global lock
+// the change already reached the datanodes, a failed save is logged and the next change saves it again
+func (s *DosNameNodeServer) saveNamespace() {
+	if err := s.flatNS.Save(); err != nil {
+		s.logger.Printf("failed to save the namespace: %v", err)
+	}
+}
+
 type Transaction func()
 
 func (s *DosNameNodeServer) Transactional(fn Transaction) {
@@ -71,10 +121,17 @@ func (s *DosNameNodeServer) Transactional(fn Transaction) {
 // it accepts a listener object so that the grpc server can connect to it
 // this is a blocking procedure
 func (s *DosNameNodeServer) Serve(listener *net.Listener) {
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(
+		// retries are answered from the idempotency cache with the status of the first attempt
+		grpc.ChainUnaryInterceptor(s.idempotency.Intercept, statusInterceptor),
+	)
 	api.RegisterNameServiceServer(grpcServer, s)
 	api.RegisterDataServiceServer(grpcServer, s)
 	api.RegisterGhostServiceServer(grpcServer, s)
+	api.RegisterAdminServiceServer(grpcServer, s)
+	if s.config.RebalanceInterval > 0 {
+		go s.rebalancer()
+	}
 	if err := grpcServer.Serve(*listener); err != nil {
 		log.Fatalf("failed to start name node service %v", err)
 	}
@@ -92,73 +149,96 @@ func (s *DosNameNodeServer) CreateObject(ctx context.Context, req *api.CreateObj
 	// 	return nil, ErrToleranceNotEnough
 	// }
 
+	replication := s.config.Replication
+	if req.Replication != 0 {
+		replication = int(req.Replication)
+	}
+
 	var err error
+	picked := make([]*DataNodeSession, 0, replication)
+	tried := make(map[string]bool)
+	constraints := constraintsFrom(req.Constraints)
 
 	s.Transactional(func() {
-		if s.flatNS.Exists(req.Name) {
+		if s.flatNS.Exists(req.Name) || s.creating[req.Name] {
 			err = ErrObjectAlreadyExists
 			return
 		}
-		successes := 0
-		pickedEntries := make([]*MetaHeapEntry, 0)
-		// for this to be successfull the number of successess should be equal to replication
-		for i := 1; i <= s.config.Replication; i++ {
-			if s.meta.Count() == 0 {
-				err = ErrNotEnoughDataNodes
-				return
-			}
+		if s.meta.CountActive() < replication {
+			err = ErrNotEnoughDataNodes
+			return
+		}
+		placed := s.pick(replication, constraints, nil, nil)
+		if len(placed) < replication && constraints != nil {
+			err = fmt.Errorf("%w matching the placement constraints of %s", ErrNotEnoughDataNodes, req.Name)
+			return
+		}
+		for _, entry := range placed {
+			log.Printf("selected %s", entry.Id)
+			tried[entry.Id] = true
+			picked = append(picked, entry.Session)
+		}
+		// the name is held while the replicas are written, outside of the lock
+		s.creating[req.Name] = true
+	})
+	if err != nil {
+		return nil, err
+	}
 
-			nextMetaEntry := s.meta.Next()
-			ackChan := make(chan interface{})
-			messageTag := CreateMessageTag(req.Name, nextMetaEntry.Id)
-			nextMetaEntry.ResChs[messageTag] = ackChan
-			log.Printf("selected %s", nextMetaEntry.Id)
-			select {
-			case nextMetaEntry.CommandCh <- CommandNode{command: api.CommandNodeRes_CREATE, tag: messageTag,
-				create: CreateCommand{
-					Name: req.Name,
-					Data: req.Data,
-				}}:
-				s.logger.Printf("create message tagged with %s\n", messageTag)
-				ack, ok := <-ackChan
-				if ok {
-					// channel was not closed / cleanedup
-					if ack.(bool) {
-						s.logger.Printf("object %s replicated to %s\n", req.Name, nextMetaEntry.Id)
-						successes++
-					}
-				}
-				pickedEntries = append(pickedEntries, nextMetaEntry)
-
-			case <-nextMetaEntry.ClosedCh:
-				close(nextMetaEntry.CommandCh)
+	// concurrent creates are not serialized by the lock, so they are pipelined into the same frames
+	command := CommandNode{
+		command: api.CommandNodeRes_CREATE,
+		create: CreateCommand{
+			Name: req.Name,
+			Data: req.Data,
+		},
+	}
+	replicas := make([]string, 0, len(picked))
+	for len(picked) != 0 {
+		for _, ack := range await(request(picked, command)) {
+			if ack.res.Ok() {
+				s.logger.Printf("object %s replicated to %s\n", req.Name, ack.node)
+				replicas = append(replicas, ack.node)
+			} else {
+				s.logger.Printf("failed to replicate %s to %s: %v\n", req.Name, ack.node, ack.res.Err())
 			}
-
-			delete(nextMetaEntry.ResChs, messageTag)
 		}
-		for _, entry := range pickedEntries {
-			// re-register these nodes
-			s.meta.RegisterNode(entry)
+		missing := replication - len(replicas)
+		if missing == 0 {
+			break
 		}
 
-		if successes != s.config.Replication {
+		// nodes that failed or left are replaced by nodes that were not tried yet
+		picked = picked[:0]
+		s.Transactional(func() {
+			for _, entry := range s.pick(missing, constraints, replicas, tried) {
+				s.logger.Printf("retrying %s on %s\n", req.Name, entry.Id)
+				tried[entry.Id] = true
+				picked = append(picked, entry.Session)
+			}
+		})
+	}
+
+	s.Transactional(func() {
+		delete(s.creating, req.Name)
+		if len(replicas) != replication {
 			err = ErrFailedObjectReplication
 			return
 		}
 
-		s.flatNS.AddObject(req.Name)
+		s.flatNS.AddObject(req.Name, constraints, replication)
 		s.logger.Printf("added object [%s] to flatNS with OPEN\n", req.Name)
-		for _, entry := range pickedEntries {
-			s.flatNS.AddNode(req.Name, entry.Id)
+		for _, node := range replicas {
+			s.flatNS.AddNode(req.Name, node)
 		}
 		s.logger.Printf("flatNS entries updated: %v\b", s.flatNS)
-
+		s.saveNamespace()
 	})
 	// check error after meta transactional
 	if err != nil {
 		return nil, err
 	}
-	s.BroadcastCommit(req.Name)
+	s.BroadcastCommit(req.Name, replicas)
 	return &api.CreateObjectResponse{
 		Meta: &api.ResponseMeta{Ts: timestamppb.Now(), Status: api.ResponseMeta_CREATED},
 	}, nil
@@ -182,7 +262,9 @@ func (s *DosNameNodeServer) DeleteObject(ctx context.Context, req *api.DeleteObj
 
 		if err := s.flatNS.DeleteObject(req.Name); err != nil {
 			transactionErr = err
+			return
 		}
+		s.saveNamespace()
 	})
 
 	if transactionErr != nil {
@@ -233,9 +315,10 @@ func (s *DosNameNodeServer) LeaseObject(ctx context.Context, req *api.LeaseObjec
 		}
 
 		// we have the nodes here. now we need to map to lease addresses
-		leaseServices, _ := s.meta.LeaseServices(nodes)
+		leaseServices, fences, _ := s.meta.LeaseServices(nodes)
 		res = &api.LeaseObjectRes{
 			Leasers: leaseServices,
+			Fences:  fences,
 		}
 	})
 
@@ -245,3 +328,74 @@ func (s *DosNameNodeServer) LeaseObject(ctx context.Context, req *api.LeaseObjec
 
 	return res, nil
 }
+
+/*
+*
+name node reads an object through a distributed read. if a snapshot is named
+the datanodes answer from their frozen view of the object at the snapshot's lamport cut
+a live read only takes the copy of a node the namespace counts as a replica, a node left with a copy
+by a failed drop still answers with it. the replicas at a snapshot's cut are not kept, so a snapshot
+read takes any copy
+*/
+func (s *DosNameNodeServer) GetObject(ctx context.Context, req *api.GetObjectReq) (*api.GetObjectRes, error) {
+	s.logger.Printf("attempting request [get %s]\n", req.Name)
+
+	var transactionErr error
+	var replicas []string
+	s.Transactional(func() {
+		if len(req.Snapshot) != 0 {
+			// the object may have been deleted since, so only the snapshot is checked here
+			entry, ok := s.snapshots[req.Snapshot]
+			if !ok {
+				transactionErr = ErrSnapshotDoesNotExist
+			} else if entry.Lamport == 0 {
+				// datanodes that have not applied it yet would answer with nothing
+				transactionErr = ErrSnapshotInProgress
+			}
+			return
+		}
+		replicas, transactionErr = s.flatNS.Nodes(req.Name)
+	})
+
+	if transactionErr != nil {
+		return nil, transactionErr
+	}
+
+	var data []byte
+	found := false
+	for result := range s.BroadcastDistributedRead([]string{req.Name}, req.Snapshot) {
+		if len(req.Snapshot) == 0 && !slices.Contains(replicas, result.Node) {
+			continue
+		}
+		for _, ob := range result.Objects {
+			if !found && ob.Name == req.Name {
+				data = ob.Data
+				found = true
+			}
+		}
+	}
+
+	if !found {
+		return nil, ErrObjectDoestNotExist
+	}
+
+	return &api.GetObjectRes{
+		Meta: &api.ResponseMeta{Ts: timestamppb.Now(), Status: api.ResponseMeta_READ},
+		Data: data,
+	}, nil
+}
+
+// ListObjects lists the namespace, it does not reach out to the datanodes
+func (s *DosNameNodeServer) ListObjects(ctx context.Context, req *api.ListObjectsReq) (*api.ListObjectsRes, error) {
+	s.logger.Printf("attempting request [list %s]\n", req.Prefix)
+
+	var names []string
+	s.Transactional(func() {
+		names = s.flatNS.List(req.Prefix)
+	})
+
+	return &api.ListObjectsRes{
+		Meta:  &api.ResponseMeta{Ts: timestamppb.Now(), Status: api.ResponseMeta_LISTED},
+		Names: names,
+	}, nil
+}
@@ -1,16 +1,55 @@
 package namenode
 
+import "time"
+
 type NameNodeConfig struct {
-	Replication int
-	Tolerance   int
+	Replication     int
+	Tolerance       int
+	EpochFile       string
+	ClusterFile     string
+	SnapshotFile    string
+	JoinToken       string
+	IdempotencyTTL  time.Duration
+	IdempotencyKeys int
+	// maintenance windows last this long unless the operator asks for another length,
+	// and at most this many commands are buffered for a datanode away for maintenance
+	MaintenanceWindow  time.Duration
+	MaintenanceBacklog int
+	// the last broadcasts kept for datanodes that register after missing them
+	CatchUpBacklog int
+	// the rebalancer runs a round every interval, zero turns it off. nodes whose size is within
+	// threshold of the mean are left alone, and moves copy no more than bandwidth bytes a second
+	RebalanceInterval  time.Duration
+	RebalanceThreshold float64
+	RebalanceBandwidth int
+	RebalanceMoves     int // most moves in one round
+	Placement          PlacementPolicy
 }
 
 type ConfigFunc func(*NameNodeConfig)
 
 func defaultNameNodeConfig() *NameNodeConfig {
 	return &NameNodeConfig{
-		Replication: 2,
-		Tolerance:   0,
+		Replication:  2,
+		Tolerance:    0,
+		EpochFile:    "namenode-epoch",
+		ClusterFile:  "namenode-cluster",
+		SnapshotFile: "namenode-snapshots",
+		JoinToken:    "",
+		// long enough to outlast any client's retries
+		IdempotencyTTL:  10 * time.Minute,
+		IdempotencyKeys: 10000,
+
+		MaintenanceWindow:  10 * time.Minute,
+		MaintenanceBacklog: 10000,
+		CatchUpBacklog:     1000,
+
+		RebalanceInterval:  5 * time.Minute,
+		RebalanceThreshold: 0.1,
+		RebalanceBandwidth: 8 << 20,
+		RebalanceMoves:     100,
+
+		Placement: LeastUsedPlacement{},
 	}
 }
 
@@ -33,3 +72,71 @@ func WithTolerance(n int) ConfigFunc {
 		cfg.Tolerance = n
 	}
 }
+
+// the epoch file keeps the cluster epoch across namenode restarts
+func WithEpochFile(path string) ConfigFunc {
+	return func(cfg *NameNodeConfig) {
+		cfg.EpochFile = path
+	}
+}
+
+// the cluster file keeps the cluster id datanodes are bound to
+func WithClusterFile(path string) ConfigFunc {
+	return func(cfg *NameNodeConfig) {
+		cfg.ClusterFile = path
+	}
+}
+
+// the snapshot file keeps the snapshots that were taken
+func WithSnapshotFile(path string) ConfigFunc {
+	return func(cfg *NameNodeConfig) {
+		cfg.SnapshotFile = path
+	}
+}
+
+// datanodes must present the join token to register
+func WithJoinToken(token string) ConfigFunc {
+	return func(cfg *NameNodeConfig) {
+		cfg.JoinToken = token
+	}
+}
+
+// outcomes of idempotency keys are kept for ttl, and for no more than keys keys at once
+func WithIdempotency(ttl time.Duration, keys int) ConfigFunc {
+	return func(cfg *NameNodeConfig) {
+		cfg.IdempotencyTTL = ttl
+		cfg.IdempotencyKeys = keys
+	}
+}
+
+// window is the default length of a maintenance window, backlog the most commands buffered for a datanode in one
+func WithMaintenance(window time.Duration, backlog int) ConfigFunc {
+	return func(cfg *NameNodeConfig) {
+		cfg.MaintenanceWindow = window
+		cfg.MaintenanceBacklog = backlog
+	}
+}
+
+// backlog is the most recent broadcasts kept for datanodes that missed them, zero keeps none
+func WithCatchUp(backlog int) ConfigFunc {
+	return func(cfg *NameNodeConfig) {
+		cfg.CatchUpBacklog = backlog
+	}
+}
+
+// threshold is the fraction of the mean node size a node may be off by before objects are moved,
+// bandwidth the bytes a second moves may copy, zero for no limit
+func WithRebalance(interval time.Duration, threshold float64, bandwidth int) ConfigFunc {
+	return func(cfg *NameNodeConfig) {
+		cfg.RebalanceInterval = interval
+		cfg.RebalanceThreshold = threshold
+		cfg.RebalanceBandwidth = bandwidth
+	}
+}
+
+// the placement policy chooses the datanodes of new replicas
+func WithPlacement(policy PlacementPolicy) ConfigFunc {
+	return func(cfg *NameNodeConfig) {
+		cfg.Placement = policy
+	}
+}
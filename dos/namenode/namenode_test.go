@@ -0,0 +1,108 @@
+package namenode
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mrowaha/dos/api"
+)
+
+// leaveCopy puts a copy of the object in the fake's store without the namespace counting it, like a failed drop
+func (f *fakeDataNode) leaveCopy(object string, data string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.store[object] = []byte(data)
+}
+
+func TestGetObjectReadsReplicas(t *testing.T) {
+	tests := []struct {
+		name    string
+		replica bool // whether node-1 holds the replica
+		want    string
+		err     error
+	}{
+		{name: "replica and a leftover copy", replica: true, want: "object"},
+		{name: "only a leftover copy", err: ErrObjectDoestNotExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestNameNode(t, 1, "object")
+			// the leftover copy answers first
+			stale := registerFake(t, s, 0)
+			stale.leaveCopy("object", "stale")
+			if tt.replica {
+				registerFake(t, s, 1, "object")
+			}
+
+			res, err := s.GetObject(context.Background(), &api.GetObjectReq{Name: "object"})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if err == nil && string(res.Data) != tt.want {
+				t.Errorf("data = %q, want %q", res.Data, tt.want)
+			}
+		})
+	}
+}
+
+// stageLeft leaves a staged create of the object in the fake, like a create that failed after staging it there
+func (f *fakeDataNode) stageLeft(object string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stage[object] = []byte("stale")
+}
+
+func TestCommitLeavesFailedStages(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		// commit creates or copies object onto node-1 only
+		commit func(t *testing.T, s *DosNameNodeServer)
+	}{
+		{
+			name: "create",
+			commit: func(t *testing.T, s *DosNameNodeServer) {
+				_, err := s.CreateObject(context.Background(), &api.CreateObjectRequest{
+					Name:        "object",
+					Data:        []byte("object"),
+					Constraints: &api.PlacementConstraints{Required: labels("disk", "ssd")},
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name:  "copy",
+			lines: []string{"object"},
+			commit: func(t *testing.T, s *DosNameNodeServer) {
+				registerFake(t, s, 2, "object")
+				var target *MetaHeapEntry
+				s.Transactional(func() {
+					target = s.meta.Get("node-1")
+				})
+				if _, err := s.copyReplicaTo("object", target); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestNameNode(t, 1, tt.lines...)
+			stale := registerFake(t, s, 0)
+			stale.stageLeft("object")
+			target := registerFake(t, s, 1)
+			s.Transactional(func() {
+				s.meta.Get(target.id).Labels = labels("disk", "ssd")
+			})
+
+			tt.commit(t, s)
+			eventually(t, func() bool { return target.holds("object") })
+			if stale.holds("object") {
+				t.Error("the stage a failed create left was committed")
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package namenode
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+/**
+	a placement policy decides which datanodes receive the replicas of a new object
+	the namenode hands it the nodes that may take the object: active, connected and not holding it yet,
+	narrowed to the ones the object's constraints allow, then to the failure domains the object has the fewest
+	replicas in, then to the ones carrying the most of its preferred labels.
+	policies are called under the global lock and only choose, they never change the meta
+**/
+
+var (
+	ErrUnknownPlacementPolicy = errors.New("unknown placement policy")
+)
+
+type PlacementPolicy interface {
+	// Place chooses n distinct nodes out of candidates, n is never more than len(candidates)
+	Place(candidates []*MetaHeapEntry, n int) []*MetaHeapEntry
+}
+
+// names of the policies for flags and configs
+const (
+	PlaceLeastUsed  = "least-used"
+	PlaceRandom     = "random"
+	PlaceRoundRobin = "round-robin"
+	PlaceTwoChoices = "two-choices"
+	PlaceWeighted   = "weighted"
+)
+
+func NewPlacementPolicy(name string) (PlacementPolicy, error) {
+	switch name {
+	case PlaceLeastUsed:
+		return LeastUsedPlacement{}, nil
+	case PlaceRandom:
+		return RandomPlacement{}, nil
+	case PlaceRoundRobin:
+		return &RoundRobinPlacement{}, nil
+	case PlaceTwoChoices:
+		return TwoChoicesPlacement{}, nil
+	case PlaceWeighted:
+		return WeightedPlacement{}, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownPlacementPolicy, name)
+}
+
+// pick chooses up to n nodes for new replicas of an object held by replicas with the configured policy,
+// passing over the ones in skip and the ones constraints rule out. replicas are picked one at a time so each is
+// spread out from the ones before it. it is called under the global lock
+func (s *DosNameNodeServer) pick(n int, constraints *PlacementConstraints, replicas []string, skip map[string]bool) []*MetaHeapEntry {
+	candidates := constraints.allowed(s.meta.Candidates(skip))
+	placed := s.placedOn(replicas)
+	picked := make([]*MetaHeapEntry, 0, n)
+	for len(picked) < n && len(candidates) != 0 {
+		entry := s.config.Placement.Place(constraints.preferred(placed.spreadOut(candidates)), 1)[0]
+		picked = append(picked, entry)
+		placed.add(entry)
+		candidates = slices.DeleteFunc(candidates, func(candidate *MetaHeapEntry) bool {
+			return candidate == entry
+		})
+	}
+	return picked
+}
+
+// LeastUsedPlacement picks the nodes with the least data
+type LeastUsedPlacement struct{}
+
+func (LeastUsedPlacement) Place(candidates []*MetaHeapEntry, n int) []*MetaHeapEntry {
+	sorted := slices.Clone(candidates)
+	slices.SortStableFunc(sorted, func(a, b *MetaHeapEntry) int {
+		switch {
+		case a.Size < b.Size:
+			return -1
+		case a.Size > b.Size:
+			return 1
+		}
+		return 0
+	})
+	return sorted[:n]
+}
+
+// RandomPlacement picks nodes uniformly at random
+type RandomPlacement struct{}
+
+func (RandomPlacement) Place(candidates []*MetaHeapEntry, n int) []*MetaHeapEntry {
+	shuffled := slices.Clone(candidates)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled[:n]
+}
+
+// RoundRobinPlacement walks the nodes in name order, every object starting where the last one stopped
+type RoundRobinPlacement struct {
+	next atomic.Uint64
+}
+
+func (p *RoundRobinPlacement) Place(candidates []*MetaHeapEntry, n int) []*MetaHeapEntry {
+	sorted := slices.Clone(candidates)
+	slices.SortFunc(sorted, func(a, b *MetaHeapEntry) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+	start := int(p.next.Add(uint64(n)) - uint64(n))
+	picked := make([]*MetaHeapEntry, 0, n)
+	for i := range n {
+		picked = append(picked, sorted[(start+i)%len(sorted)])
+	}
+	return picked
+}
+
+// TwoChoicesPlacement picks each replica by sampling two nodes and keeping the one with less data
+// it spreads load nearly as well as least used, without every create landing on the same node
+type TwoChoicesPlacement struct{}
+
+func (TwoChoicesPlacement) Place(candidates []*MetaHeapEntry, n int) []*MetaHeapEntry {
+	left := slices.Clone(candidates)
+	picked := make([]*MetaHeapEntry, 0, n)
+	for range n {
+		i := rand.Intn(len(left))
+		if len(left) > 1 {
+			// a second, different node
+			j := rand.Intn(len(left) - 1)
+			if j >= i {
+				j++
+			}
+			if left[j].Size < left[i].Size {
+				i = j
+			}
+		}
+		picked = append(picked, left[i])
+		left = slices.Delete(left, i, i+1)
+	}
+	return picked
+}
+
+// WeightedPlacement picks nodes at random, weighted by their free capacity
+// a node that reports no capacity is weighted as if it had the largest capacity reported,
+// so with no capacities reported it is plain random placement
+type WeightedPlacement struct{}
+
+func (WeightedPlacement) Place(candidates []*MetaHeapEntry, n int) []*MetaHeapEntry {
+	largest := float32(0)
+	for _, entry := range candidates {
+		largest = max(largest, entry.Capacity)
+	}
+
+	left := slices.Clone(candidates)
+	weights := make([]float64, len(left))
+	for i, entry := range left {
+		capacity := entry.Capacity
+		if capacity == 0 {
+			capacity = largest
+		}
+		// full nodes keep a small chance, every node stays pickable
+		weights[i] = max(float64(capacity-entry.Size), 1)
+	}
+
+	picked := make([]*MetaHeapEntry, 0, n)
+	for range n {
+		total := 0.0
+		for _, weight := range weights {
+			total += weight
+		}
+		target := rand.Float64() * total
+		i := 0
+		for ; i < len(weights)-1; i++ {
+			target -= weights[i]
+			if target < 0 {
+				break
+			}
+		}
+		picked = append(picked, left[i])
+		left = slices.Delete(left, i, i+1)
+		weights = slices.Delete(weights, i, i+1)
+	}
+	return picked
+}
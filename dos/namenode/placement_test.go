@@ -0,0 +1,183 @@
+package namenode
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func ids(entries []*MetaHeapEntry) []string {
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Id)
+	}
+	return names
+}
+
+func TestNewPlacementPolicy(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		err  error
+	}{
+		{name: PlaceLeastUsed, want: "namenode.LeastUsedPlacement"},
+		{name: PlaceRandom, want: "namenode.RandomPlacement"},
+		{name: PlaceRoundRobin, want: "*namenode.RoundRobinPlacement"},
+		{name: PlaceTwoChoices, want: "namenode.TwoChoicesPlacement"},
+		{name: PlaceWeighted, want: "namenode.WeightedPlacement"},
+		{name: "fullest", err: ErrUnknownPlacementPolicy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := NewPlacementPolicy(tt.name)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if tt.err == nil && fmt.Sprintf("%T", policy) != tt.want {
+				t.Errorf("policy = %T, want %s", policy, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlacementPicksDistinctCandidates(t *testing.T) {
+	candidates := []*MetaHeapEntry{
+		{Id: "a", Size: 30, Capacity: 100},
+		{Id: "b", Size: 10},
+		{Id: "c", Size: 100, Capacity: 100},
+		{Id: "d", Size: 20, Capacity: 50},
+	}
+	for _, name := range []string{PlaceLeastUsed, PlaceRandom, PlaceRoundRobin, PlaceTwoChoices, PlaceWeighted} {
+		t.Run(name, func(t *testing.T) {
+			policy, _ := NewPlacementPolicy(name)
+			for range 20 {
+				for n := 1; n <= len(candidates); n++ {
+					picked := policy.Place(candidates, n)
+					if len(picked) != n {
+						t.Fatalf("picked %v, want %d nodes", ids(picked), n)
+					}
+					for i, entry := range picked {
+						if !slices.Contains(candidates, entry) || slices.Contains(picked[:i], entry) {
+							t.Fatalf("picked %v out of %v", ids(picked), ids(candidates))
+						}
+					}
+				}
+			}
+			if got := ids(candidates); !slices.Equal(got, []string{"a", "b", "c", "d"}) {
+				t.Errorf("candidates reordered to %v", got)
+			}
+		})
+	}
+}
+
+func TestLeastUsedPlacement(t *testing.T) {
+	candidates := []*MetaHeapEntry{{Id: "a", Size: 30}, {Id: "b", Size: 10}, {Id: "c", Size: 10}, {Id: "d", Size: 20}}
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{n: 1, want: []string{"b"}},
+		{n: 2, want: []string{"b", "c"}},
+		{n: 4, want: []string{"b", "c", "d", "a"}},
+	}
+	for _, tt := range tests {
+		if got := ids(LeastUsedPlacement{}.Place(candidates, tt.n)); !slices.Equal(got, tt.want) {
+			t.Errorf("Place(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestRoundRobinPlacement(t *testing.T) {
+	// handed out of name order, placed in it
+	candidates := []*MetaHeapEntry{{Id: "c"}, {Id: "a"}, {Id: "b"}}
+	policy := &RoundRobinPlacement{}
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{n: 1, want: []string{"a"}},
+		{n: 2, want: []string{"b", "c"}},
+		{n: 2, want: []string{"a", "b"}},
+		{n: 3, want: []string{"c", "a", "b"}},
+	}
+	for _, tt := range tests {
+		if got := ids(policy.Place(candidates, tt.n)); !slices.Equal(got, tt.want) {
+			t.Errorf("Place(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestTwoChoicesPlacement(t *testing.T) {
+	// with two nodes both are always sampled
+	candidates := []*MetaHeapEntry{{Id: "a", Size: 30}, {Id: "b", Size: 10}}
+	for range 50 {
+		if got := ids(TwoChoicesPlacement{}.Place(candidates, 1)); !slices.Equal(got, []string{"b"}) {
+			t.Fatalf("Place(1) = %v, want the node with less data", got)
+		}
+	}
+}
+
+func TestWeightedPlacement(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []*MetaHeapEntry
+		least      string // picked least often
+	}{
+		{
+			name:       "full node",
+			candidates: []*MetaHeapEntry{{Id: "a", Size: 1000, Capacity: 1000}, {Id: "b", Capacity: 1000}},
+			least:      "a",
+		},
+		{
+			name:       "node reporting no capacity",
+			candidates: []*MetaHeapEntry{{Id: "a", Size: 900, Capacity: 1000}, {Id: "b"}},
+			least:      "a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			picks := make(map[string]int)
+			for range 1000 {
+				picks[WeightedPlacement{}.Place(tt.candidates, 1)[0].Id]++
+			}
+			for _, entry := range tt.candidates {
+				if entry.Id != tt.least && picks[entry.Id] <= picks[tt.least] {
+					t.Errorf("picks = %v, want %s picked least", picks, tt.least)
+				}
+			}
+		})
+	}
+}
+
+func TestPick(t *testing.T) {
+	tests := []struct {
+		name   string
+		n      int
+		skip   map[string]bool
+		closed string // node whose session closed
+		want   []string
+	}{
+		{name: "least used first", n: 2, want: []string{"a", "b"}},
+		{name: "skipping a node", n: 2, skip: map[string]bool{"a": true}, want: []string{"b", "c"}},
+		{name: "more than there are nodes", n: 5, skip: map[string]bool{"b": true}, want: []string{"a", "c"}},
+		{name: "closed session", n: 3, closed: "c", want: []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestNameNode(t, 1)
+			layout(t, s, []*MetaHeapEntry{{Id: "a", Size: 1}, {Id: "b", Size: 2}, {Id: "c", Size: 3}, {Id: "d", Size: 4, State: NodeMaintenance}}, nil)
+			if tt.closed != "" {
+				s.meta.Get(tt.closed).Session.Close()
+			}
+
+			var picked []*MetaHeapEntry
+			s.Transactional(func() {
+				picked = s.pick(tt.n, nil, nil, tt.skip)
+			})
+			if got := ids(picked); !slices.Equal(got, tt.want) {
+				t.Errorf("picked %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package namenode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mrowaha/dos/api"
+)
+
+/**
+	placement only looks at sizes when an object is created, so a datanode that joins later stays
+	nearly empty. the rebalancer evens sizes out in rounds: it takes the sizes datanodes report in their
+	heartbeats, plans moves from the largest nodes to the smallest until every node is within the
+	threshold of the mean, and runs the moves one after the other under a bandwidth limit
+
+	a move copies the object to the new node like a decommission does, then drops the source from
+	the object's replicas in the namespace, and only then broadcasts a delete that the source alone applies
+	only active nodes take part, nodes in maintenance or being decommissioned keep what they have
+
+	a round first repairs placements. a replica on a node the object's constraints no longer allow is moved
+	to one they do, and an object re-replicated while a domain was down, or placed before a node in a new
+	domain joined, has a replica moved to a domain it is missing from. moves that even out sizes only go to
+	nodes the constraints allow, and never narrow an object's spread or lose it a preferred label
+**/
+
+var (
+	ErrRebalanceRunning = errors.New("a rebalance round is already running")
+	ErrMoveOutdated     = errors.New("replica move no longer applies")
+)
+
+type ReplicaMove struct {
+	Object string
+	From   string
+	To     string
+	Size   int // estimated
+}
+
+// the size a node is planned to have, called load to tell it apart from the reported size
+type nodeLoad struct {
+	entry   *MetaHeapEntry
+	load    float64
+	objects []string
+}
+
+// planMoves plans the moves of a round and returns them with the mean node size, called under the global lock
+func (s *DosNameNodeServer) planMoves() ([]ReplicaMove, float64) {
+	loads := make([]*nodeLoad, 0, s.meta.Count())
+	total := 0.0
+	s.meta.ForEach(func(entry *MetaHeapEntry) {
+		if entry.State != NodeActive || entry.Session.Closed() {
+			return
+		}
+		loads = append(loads, &nodeLoad{
+			entry:   entry,
+			load:    float64(entry.Size),
+			objects: s.flatNS.Objects(entry.Id),
+		})
+		total += float64(entry.Size)
+	})
+	planned := make(map[string]bool) // an object is moved once per round
+	moves := s.planRepairs(loads, planned)
+	if len(loads) < 2 || total == 0 {
+		// there are no sizes to even out, placements are repaired all the same
+		return moves, 0
+	}
+	mean := total / float64(len(loads))
+	high := mean * (1 + s.config.RebalanceThreshold)
+	low := mean * (1 - s.config.RebalanceThreshold)
+
+	for len(moves) < s.config.RebalanceMoves {
+		slices.SortFunc(loads, func(a, b *nodeLoad) int {
+			switch {
+			case a.load < b.load:
+				return -1
+			case a.load > b.load:
+				return 1
+			}
+			return 0
+		})
+		under, over := loads[0], loads[len(loads)-1]
+		if over.load <= high && under.load >= low {
+			break
+		}
+		if len(over.objects) == 0 {
+			break
+		}
+		// object sizes are not known ahead of the copy, the source's average stands in for them
+		size := over.load / float64(len(over.objects))
+		if under.load+size >= over.load {
+			// the move would only swap which of them is larger
+			break
+		}
+
+		i := slices.IndexFunc(over.objects, func(object string) bool {
+			if planned[object] {
+				return false
+			}
+			nodes, err := s.flatNS.Nodes(object)
+			if err != nil || slices.Contains(nodes, under.entry.Id) {
+				return false
+			}
+			constraints := s.flatNS.Constraints(object)
+			return constraints.allows(under.entry) &&
+				constraints.preference(under.entry) >= constraints.preference(over.entry) &&
+				s.keepsSpread(nodes, over.entry.Id, under.entry)
+		})
+		if i == -1 {
+			break
+		}
+		object := over.objects[i]
+		over.objects = slices.Delete(over.objects, i, i+1)
+		under.objects = append(under.objects, object)
+		over.load -= size
+		under.load += size
+		planned[object] = true
+		moves = append(moves, ReplicaMove{Object: object, From: over.entry.Id, To: under.entry.Id, Size: int(size)})
+	}
+	return moves, mean
+}
+
+// planRepairs plans moves for the objects with replicas on nodes their constraints rule out, or that span fewer
+// domains than they could, called under the global lock. each replica goes to the least loaded node placement would pick
+func (s *DosNameNodeServer) planRepairs(loads []*nodeLoad, planned map[string]bool) []ReplicaMove {
+	moves := make([]ReplicaMove, 0)
+	for _, object := range s.flatNS.List("") {
+		if len(moves) == s.config.RebalanceMoves {
+			break
+		}
+		nodes, err := s.flatNS.Nodes(object)
+		if err != nil {
+			continue
+		}
+		before := s.placedOn(nodes)
+		constraints := s.flatNS.Constraints(object)
+
+		for _, source := range loads {
+			if !slices.Contains(nodes, source.entry.Id) {
+				continue
+			}
+			// where the object would be without this replica, and which nodes could take it instead
+			after := s.placedOn(slices.DeleteFunc(slices.Clone(nodes), func(node string) bool {
+				return node == source.entry.Id
+			}))
+			candidates := make([]*MetaHeapEntry, 0, len(loads))
+			for _, target := range loads {
+				if !slices.Contains(nodes, target.entry.Id) {
+					candidates = append(candidates, target.entry)
+				}
+			}
+			candidates = constraints.preferred(after.spreadOut(constraints.allowed(candidates)))
+			if len(candidates) == 0 {
+				break
+			}
+			var under *nodeLoad
+			for _, target := range loads {
+				if slices.Contains(candidates, target.entry) && (under == nil || target.load < under.load) {
+					under = target
+				}
+			}
+			after.add(under.entry)
+			if constraints.allows(source.entry) && after.compare(before) <= 0 {
+				// the replica is where it may be, and moving it does not widen the spread
+				continue
+			}
+
+			size := source.load / float64(len(source.objects))
+			source.objects = slices.DeleteFunc(source.objects, func(o string) bool { return o == object })
+			under.objects = append(under.objects, object)
+			source.load -= size
+			under.load += size
+			planned[object] = true
+			moves = append(moves, ReplicaMove{Object: object, From: source.entry.Id, To: under.entry.Id, Size: int(size)})
+			break
+		}
+	}
+	return moves
+}
+
+// rebalancer runs a round every interval for the lifetime of the namenode
+func (s *DosNameNodeServer) rebalancer() {
+	ticker := time.NewTicker(s.config.RebalanceInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		moves, _, err := s.startRebalance()
+		if err != nil {
+			continue
+		}
+		if len(moves) != 0 {
+			s.logger.Printf("rebalancing with %d moves", len(moves))
+		}
+	}
+}
+
+// startRebalance plans a round and runs it in the background
+func (s *DosNameNodeServer) startRebalance() ([]ReplicaMove, float64, error) {
+	var moves []ReplicaMove
+	var mean float64
+	var transactionErr error
+	s.Transactional(func() {
+		if s.rebalancing {
+			transactionErr = ErrRebalanceRunning
+			return
+		}
+		moves, mean = s.planMoves()
+		s.rebalancing = len(moves) != 0
+	})
+	if transactionErr != nil || len(moves) == 0 {
+		return nil, mean, transactionErr
+	}
+
+	go func() {
+		defer s.Transactional(func() {
+			s.rebalancing = false
+		})
+		s.rebalance(moves)
+	}()
+	return moves, mean, nil
+}
+
+// rebalance runs the moves of a round, pausing between them to stay within the bandwidth limit
+func (s *DosNameNodeServer) rebalance(moves []ReplicaMove) {
+	moved, copied := 0, 0
+	for _, move := range moves {
+		start := time.Now()
+		size, err := s.moveReplica(move)
+		if err != nil {
+			s.logger.Printf("failed to move %s from %s to %s: %v", move.Object, move.From, move.To, err)
+			continue
+		}
+		moved++
+		copied += size
+
+		if s.config.RebalanceBandwidth > 0 {
+			took := time.Duration(float64(size) / float64(s.config.RebalanceBandwidth) * float64(time.Second))
+			time.Sleep(took - time.Since(start))
+		}
+	}
+	s.logger.Printf("rebalance round moved %d of %d replicas, %d bytes", moved, len(moves), copied)
+}
+
+// moveReplica copies the object to the destination, then drops it from the source
+func (s *DosNameNodeServer) moveReplica(move ReplicaMove) (int, error) {
+	var target *MetaHeapEntry
+	var err error
+	s.Transactional(func() {
+		nodes, nodesErr := s.flatNS.Nodes(move.Object)
+		if nodesErr != nil {
+			err = nodesErr
+			return
+		}
+		if !slices.Contains(nodes, move.From) || slices.Contains(nodes, move.To) {
+			err = ErrMoveOutdated
+			return
+		}
+		target = s.meta.Get(move.To)
+		if target == nil {
+			err = fmt.Errorf("%w: %s left", ErrMoveOutdated, move.To)
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := s.copyReplicaTo(move.Object, target)
+	if err != nil {
+		return 0, err
+	}
+
+	var calls []pendingCall
+	s.Transactional(func() {
+		nodes, nodesErr := s.flatNS.Nodes(move.Object)
+		if nodesErr != nil || !slices.Contains(nodes, move.To) || !slices.Contains(nodes, move.From) {
+			// deleted meanwhile, or the new copy already failed. the source stays a replica
+			err = ErrMoveOutdated
+			return
+		}
+		if len(nodes) <= s.flatNS.Replication(move.Object) {
+			// a replica was dropped meanwhile, the copy took its place and the source stays
+			err = fmt.Errorf("%w: %s lost a replica meanwhile", ErrMoveOutdated, move.Object)
+			return
+		}
+		calls = s.dropReplica(move.Object, move.From)
+	})
+	if err != nil {
+		return size, err
+	}
+	s.awaitDrop(move.Object, move.From, calls)
+	s.logger.Printf("moved %s from %s to %s", move.Object, move.From, move.To)
+	return size, nil
+}
+
+func (s *DosNameNodeServer) Rebalance(ctx context.Context, req *api.RebalanceReq) (*api.RebalanceRes, error) {
+	s.logger.Printf("attempting request [rebalance dry run %v]\n", req.DryRun)
+
+	var moves []ReplicaMove
+	var mean float64
+	var err error
+	if req.DryRun {
+		s.Transactional(func() {
+			moves, mean = s.planMoves()
+		})
+	} else {
+		moves, mean, err = s.startRebalance()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := &api.RebalanceRes{Mean: mean}
+	for _, move := range moves {
+		res.Moves = append(res.Moves, &api.ReplicaMove{
+			Object: move.Object,
+			From:   move.From,
+			To:     move.To,
+			Size:   uint64(move.Size),
+		})
+	}
+	return res, nil
+}
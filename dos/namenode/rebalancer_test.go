@@ -0,0 +1,240 @@
+package namenode
+
+import (
+	"slices"
+	"testing"
+)
+
+type testObject struct {
+	name        string
+	nodes       []string
+	constraints *PlacementConstraints
+}
+
+// layout registers nodes on an open session each, in order, and places objects on them
+// nodes registered in ascending size keep their order in the meta
+func layout(t *testing.T, s *DosNameNodeServer, nodes []*MetaHeapEntry, objects []testObject) {
+	t.Helper()
+	s.Transactional(func() {
+		for _, entry := range nodes {
+			entry.Session, _ = newTestSession(t)
+			s.meta.RegisterNode(entry)
+		}
+		for _, object := range objects {
+			s.flatNS.AddObject(object.name, object.constraints, 0)
+			for _, node := range object.nodes {
+				s.flatNS.AddNode(object.name, node)
+			}
+		}
+	})
+}
+
+// labels pairs up keys and values
+func labels(pairs ...string) map[string]string {
+	labels := make(map[string]string)
+	for i := 0; i < len(pairs); i += 2 {
+		labels[pairs[i]] = pairs[i+1]
+	}
+	return labels
+}
+
+func TestPlanMoves(t *testing.T) {
+	onA := func(constraints *PlacementConstraints, names ...string) []testObject {
+		objects := make([]testObject, 0, len(names))
+		for _, name := range names {
+			objects = append(objects, testObject{name: name, nodes: []string{"a"}, constraints: constraints})
+		}
+		return objects
+	}
+	tests := []struct {
+		name    string
+		nodes   []*MetaHeapEntry
+		objects []testObject
+		limit   int
+		want    []ReplicaMove
+	}{
+		{
+			name:    "balanced",
+			nodes:   []*MetaHeapEntry{{Id: "a", Size: 10}, {Id: "b", Size: 10}},
+			objects: []testObject{{name: "o1", nodes: []string{"a"}}, {name: "o2", nodes: []string{"b"}}},
+		},
+		{
+			name:    "an empty node",
+			nodes:   []*MetaHeapEntry{{Id: "a", Size: 40}, {Id: "b"}},
+			objects: onA(nil, "o1", "o2", "o3", "o4"),
+			want:    []ReplicaMove{{Object: "o1", From: "a", To: "b", Size: 10}, {Object: "o2", From: "a", To: "b", Size: 10}},
+		},
+		{
+			name:    "more moves than a round takes",
+			nodes:   []*MetaHeapEntry{{Id: "a", Size: 40}, {Id: "b"}},
+			objects: onA(nil, "o1", "o2", "o3", "o4"),
+			limit:   1,
+			want:    []ReplicaMove{{Object: "o1", From: "a", To: "b", Size: 10}},
+		},
+		{
+			name:    "the empty node is in maintenance",
+			nodes:   []*MetaHeapEntry{{Id: "a", Size: 40}, {Id: "b", State: NodeMaintenance}},
+			objects: onA(nil, "o1", "o2", "o3", "o4"),
+		},
+		{
+			name:    "the empty node is being decommissioned",
+			nodes:   []*MetaHeapEntry{{Id: "a", Size: 40}, {Id: "b", State: NodeDecommissioning}, {Id: "c", Size: 20}},
+			objects: append(onA(nil, "o1", "o2", "o3", "o4"), testObject{name: "o5", nodes: []string{"c"}}, testObject{name: "o6", nodes: []string{"c"}}),
+			want:    []ReplicaMove{{Object: "o1", From: "a", To: "c", Size: 10}},
+		},
+		{
+			name:    "the empty node lacks a required label",
+			nodes:   []*MetaHeapEntry{{Id: "a", Size: 40, Labels: labels("disk", "ssd")}, {Id: "b"}},
+			objects: onA(&PlacementConstraints{Required: map[string]string{"disk": "ssd"}}, "o1", "o2", "o3", "o4"),
+		},
+		{
+			name:    "the empty node lacks a preferred label",
+			nodes:   []*MetaHeapEntry{{Id: "a", Size: 40, Labels: labels("tier", "fast")}, {Id: "b"}},
+			objects: onA(&PlacementConstraints{Preferred: map[string]string{"tier": "fast"}}, "o1", "o2", "o3", "o4"),
+		},
+		{
+			name:  "the empty node is in the zone of the other replica",
+			nodes: []*MetaHeapEntry{{Id: "a", Size: 40, Zone: "z1"}, {Id: "b", Zone: "z2"}, {Id: "c", Size: 25, Zone: "z2"}},
+			objects: []testObject{
+				{name: "o1", nodes: []string{"a", "c"}},
+				{name: "o2", nodes: []string{"a", "c"}},
+				{name: "o3", nodes: []string{"a", "c"}},
+				{name: "o4", nodes: []string{"a", "c"}},
+			},
+		},
+		{
+			name:  "the empty node is in the zone of the moved replica",
+			nodes: []*MetaHeapEntry{{Id: "a", Size: 40, Zone: "z1"}, {Id: "b", Zone: "z1"}, {Id: "c", Size: 25, Zone: "z2"}},
+			objects: []testObject{
+				{name: "o1", nodes: []string{"a", "c"}},
+				{name: "o2", nodes: []string{"a", "c"}},
+				{name: "o3", nodes: []string{"a", "c"}},
+				{name: "o4", nodes: []string{"a", "c"}},
+			},
+			want: []ReplicaMove{{Object: "o1", From: "a", To: "b", Size: 10}, {Object: "o2", From: "a", To: "b", Size: 10}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestNameNode(t, 1)
+			if tt.limit != 0 {
+				s.config.RebalanceMoves = tt.limit
+			}
+			layout(t, s, tt.nodes, tt.objects)
+
+			var moves []ReplicaMove
+			s.Transactional(func() {
+				moves, _ = s.planMoves()
+			})
+			if !slices.Equal(moves, tt.want) {
+				t.Errorf("moves = %v, want %v", moves, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanRepairs(t *testing.T) {
+	requireSsd := &PlacementConstraints{Required: map[string]string{"disk": "ssd"}}
+	tests := []struct {
+		name    string
+		nodes   []*MetaHeapEntry // in ascending size, the order repairs look for a source in
+		objects []testObject
+		limit   int
+		want    []ReplicaMove
+	}{
+		{
+			name:    "placed where allowed",
+			nodes:   []*MetaHeapEntry{{Id: "a", Size: 10, Labels: labels("disk", "ssd")}, {Id: "b", Size: 11}},
+			objects: []testObject{{name: "o1", nodes: []string{"a"}, constraints: requireSsd}},
+		},
+		{
+			name:    "on a node without a required label",
+			nodes:   []*MetaHeapEntry{{Id: "a", Size: 10}, {Id: "b", Size: 11, Labels: labels("disk", "ssd")}},
+			objects: []testObject{{name: "o1", nodes: []string{"a"}, constraints: requireSsd}},
+			want:    []ReplicaMove{{Object: "o1", From: "a", To: "b", Size: 10}},
+		},
+		{
+			name:    "no node allowed",
+			nodes:   []*MetaHeapEntry{{Id: "a", Size: 10}, {Id: "b", Size: 11}},
+			objects: []testObject{{name: "o1", nodes: []string{"a"}, constraints: requireSsd}},
+		},
+		{
+			name: "to the least loaded allowed node",
+			nodes: []*MetaHeapEntry{
+				{Id: "a", Size: 10},
+				{Id: "b", Size: 11},
+				{Id: "c", Size: 12, Labels: labels("disk", "ssd")},
+				{Id: "d", Size: 13, Labels: labels("disk", "ssd")},
+			},
+			objects: []testObject{{name: "o1", nodes: []string{"a"}, constraints: requireSsd}},
+			want:    []ReplicaMove{{Object: "o1", From: "a", To: "c", Size: 10}},
+		},
+		{
+			name: "to a node with a preferred label",
+			nodes: []*MetaHeapEntry{
+				{Id: "a", Size: 10},
+				{Id: "b", Size: 11, Labels: labels("disk", "ssd")},
+				{Id: "c", Size: 12, Labels: labels("disk", "ssd", "tier", "fast")},
+			},
+			objects: []testObject{{
+				name:        "o1",
+				nodes:       []string{"a"},
+				constraints: &PlacementConstraints{Required: map[string]string{"disk": "ssd"}, Preferred: map[string]string{"tier": "fast"}},
+			}},
+			want: []ReplicaMove{{Object: "o1", From: "a", To: "c", Size: 10}},
+		},
+		{
+			name:    "more repairs than a round takes",
+			nodes:   []*MetaHeapEntry{{Id: "a", Size: 10}, {Id: "b", Size: 11, Labels: labels("disk", "ssd")}},
+			objects: []testObject{{name: "o1", nodes: []string{"a"}, constraints: requireSsd}, {name: "o2", nodes: []string{"a"}, constraints: requireSsd}},
+			limit:   1,
+			want:    []ReplicaMove{{Object: "o1", From: "a", To: "b", Size: 5}},
+		},
+		{
+			name:    "a zone joined",
+			nodes:   []*MetaHeapEntry{{Id: "a", Size: 10, Zone: "z1"}, {Id: "b", Size: 11, Zone: "z1"}, {Id: "c", Size: 12, Zone: "z2"}},
+			objects: []testObject{{name: "o1", nodes: []string{"a", "b"}}},
+			want:    []ReplicaMove{{Object: "o1", From: "a", To: "c", Size: 10}},
+		},
+		{
+			name:    "spread over the zones",
+			nodes:   []*MetaHeapEntry{{Id: "a", Size: 10, Zone: "z1"}, {Id: "b", Size: 11, Zone: "z1"}, {Id: "c", Size: 12, Zone: "z2"}},
+			objects: []testObject{{name: "o1", nodes: []string{"a", "c"}}},
+		},
+		{
+			name:    "no sizes reported yet",
+			nodes:   []*MetaHeapEntry{{Id: "a"}, {Id: "b", Labels: labels("disk", "ssd")}},
+			objects: []testObject{{name: "o1", nodes: []string{"a"}, constraints: requireSsd}},
+			want:    []ReplicaMove{{Object: "o1", From: "a", To: "b"}},
+		},
+		{
+			name: "a rack joined",
+			nodes: []*MetaHeapEntry{
+				{Id: "a", Size: 10, Zone: "z1", Rack: "r1"},
+				{Id: "b", Size: 11, Zone: "z1", Rack: "r1"},
+				{Id: "c", Size: 12, Zone: "z1", Rack: "r2"},
+			},
+			objects: []testObject{{name: "o1", nodes: []string{"a", "b"}}},
+			want:    []ReplicaMove{{Object: "o1", From: "a", To: "c", Size: 10}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestNameNode(t, 1)
+			// sizes are left as they are, every move is a repair
+			s.config.RebalanceThreshold = 1
+			if tt.limit != 0 {
+				s.config.RebalanceMoves = tt.limit
+			}
+			layout(t, s, tt.nodes, tt.objects)
+
+			var moves []ReplicaMove
+			s.Transactional(func() {
+				moves, _ = s.planMoves()
+			})
+			if !slices.Equal(moves, tt.want) {
+				t.Errorf("moves = %v, want %v", moves, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package namenode
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/mrowaha/dos/api"
+)
+
+/**
+	every object keeps its own replication, the cluster's unless it was created with another one
+	setting it only records the new number. the replicas are added or removed in the background until the object
+	has as many as it keeps, and another change meanwhile is picked up by the same convergence
+
+	added replicas are copied and placed like the ones of a decommission. removed replicas are dropped from the
+	namespace before the node deletes its copy, first the ones on nodes the object should not be on, then the ones
+	whose removal keeps the object spread widest, and of those the ones on the fullest nodes
+**/
+
+var (
+	ErrInvalidReplication = errors.New("replication must be at least one")
+)
+
+func (s *DosNameNodeServer) SetReplication(ctx context.Context, req *api.SetReplicationReq) (*api.SetReplicationRes, error) {
+	s.logger.Printf("attempting request [set replication of %s to %d]\n", req.Name, req.Replication)
+	if req.Replication == 0 {
+		return nil, ErrInvalidReplication
+	}
+	replication := int(req.Replication)
+
+	var res *api.SetReplicationRes
+	var transactionErr error
+	start := false
+	s.Transactional(func() {
+		nodes, err := s.flatNS.Nodes(req.Name)
+		if err != nil {
+			transactionErr = err
+			return
+		}
+		// the nodes that hold a replica or could take one
+		available := len(nodes)
+		for _, entry := range s.flatNS.Constraints(req.Name).allowed(s.meta.Candidates(nil)) {
+			if !slices.Contains(nodes, entry.Id) {
+				available++
+			}
+		}
+		if available < replication {
+			transactionErr = fmt.Errorf("%w to keep %d replicas of %s", ErrNotEnoughDataNodes, replication, req.Name)
+			return
+		}
+
+		s.flatNS.SetReplication(req.Name, replication)
+		s.saveNamespace()
+		if !s.converging[req.Name] {
+			s.converging[req.Name] = true
+			start = true
+		}
+		res = &api.SetReplicationRes{
+			Meta:     &api.ResponseMeta{Status: api.ResponseMeta_UPDATED},
+			Replicas: uint32(len(nodes)),
+		}
+	})
+	if transactionErr != nil {
+		return nil, transactionErr
+	}
+
+	if start {
+		go s.converge(req.Name)
+	}
+	return res, nil
+}
+
+// converge adds or removes replicas of the object until it has as many as its replication
+// it stops once the object is deleted, or when no node can take another replica
+func (s *DosNameNodeServer) converge(object string) {
+	tried := make(map[string]bool)
+	failures := 0
+	for {
+		var replicas []string
+		var drop string
+		var calls []pendingCall
+		done, stuck := false, false
+		s.Transactional(func() {
+			nodes, err := s.flatNS.Nodes(object)
+			replication := s.flatNS.Replication(object)
+			if err != nil || len(nodes) == replication {
+				delete(s.converging, object)
+				done = true
+				return
+			}
+			replicas = nodes
+			if len(nodes) < replication {
+				return
+			}
+			// dropped right away, so the next round counts without it
+			drop = s.excessReplica(object, nodes)
+			if len(drop) == 0 {
+				delete(s.converging, object)
+				stuck = true
+				return
+			}
+			calls = s.dropReplica(object, drop)
+		})
+		switch {
+		case done:
+			s.logger.Printf("done converging %s to its replication", object)
+			return
+		case stuck:
+			s.logger.Printf("stopped converging %s to its replication: no registered replica to drop", object)
+			return
+		case len(drop) != 0:
+			s.awaitDrop(object, drop, calls)
+			continue
+		}
+
+		for _, replica := range replicas {
+			tried[replica] = true
+		}
+		_, err := s.copyReplica(object, replicas, tried)
+		if err == nil || errors.Is(err, ErrObjectDoestNotExist) {
+			continue
+		}
+		failures++
+		if failures < maxMoveAttempts && !errors.Is(err, ErrNoReplicaTarget) {
+			continue
+		}
+		s.Transactional(func() {
+			delete(s.converging, object)
+		})
+		s.logger.Printf("stopped converging %s to its replication: %v", object, err)
+		return
+	}
+}
+
+// excessReplica chooses the replica of the object to remove, called under the global lock
+// replicas on nodes that are not registered, like ghosts, are never chosen
+func (s *DosNameNodeServer) excessReplica(object string, nodes []string) string {
+	constraints := s.flatNS.Constraints(object)
+	misplaced := func(entry *MetaHeapEntry) bool {
+		return !constraints.allows(entry) || entry.State != NodeActive
+	}
+	spreadWithout := make(map[string]*domains)
+	entries := make([]*MetaHeapEntry, 0, len(nodes))
+	for _, node := range nodes {
+		entry := s.meta.Get(node)
+		if entry == nil {
+			continue
+		}
+		entries = append(entries, entry)
+		spreadWithout[node] = s.placedOn(slices.DeleteFunc(slices.Clone(nodes), func(other string) bool {
+			return other == node
+		}))
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+
+	// the first in this order is removed
+	return slices.MinFunc(entries, func(a, b *MetaHeapEntry) int {
+		if misplaced(a) != misplaced(b) {
+			if misplaced(a) {
+				return -1
+			}
+			return 1
+		}
+		if c := spreadWithout[b.Id].compare(spreadWithout[a.Id]); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(constraints.preference(a), constraints.preference(b)); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Size, a.Size)
+	}).Id
+}
+
+// dropReplica stops counting node as a replica of the object and tells it to delete its copy,
+// called under the global lock. reads stop going to the node before its copy is deleted
+func (s *DosNameNodeServer) dropReplica(object string, node string) []pendingCall {
+	s.flatNS.FailReplica(object, node)
+	s.lamport++
+	return s.queueBroadcast(CommandNode{
+		command: api.CommandNodeRes_DELETE,
+		delete:  DeleteCommand{Lamport: s.lamport, Type: DELETE, Name: object, Node: node},
+	})
+}
+
+// awaitDrop waits for the node to delete its dropped copy
+func (s *DosNameNodeServer) awaitDrop(object string, node string, calls []pendingCall) {
+	for _, ack := range await(calls) {
+		if ack.node == node && !ack.res.Ok() {
+			// it is no longer a replica either way, the leftover copy is never served
+			s.logger.Printf("failed to drop %s from %s: %v", object, node, ack.res.Err())
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package namenode
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mrowaha/dos/api"
+)
+
+func TestSetReplicationRejects(t *testing.T) {
+	s := newTestNameNode(t, 1, "object", "pinned\t{\"replication\":1,\"constraints\":{\"required\":{\"disk\":\"ssd\"}}}")
+	registerFake(t, s, 0, "object", "pinned")
+	registerFake(t, s, 1)
+
+	tests := []struct {
+		name        string
+		object      string
+		replication uint32
+		err         error
+	}{
+		{name: "no replicas", object: "object", replication: 0, err: ErrInvalidReplication},
+		{name: "unknown object", object: "missing", replication: 1, err: ErrObjectDoestNotExist},
+		{name: "more replicas than nodes", object: "object", replication: 3, err: ErrNotEnoughDataNodes},
+		{name: "more replicas than allowed nodes", object: "pinned", replication: 2, err: ErrNotEnoughDataNodes},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.SetReplication(context.Background(), &api.SetReplicationReq{Name: tt.object, Replication: tt.replication})
+			if !errors.Is(err, tt.err) {
+				t.Errorf("err = %v, want %v", err, tt.err)
+			}
+		})
+	}
+	var replication int
+	s.Transactional(func() {
+		replication = s.flatNS.Replication("object")
+	})
+	if replication != 1 {
+		t.Errorf("replication = %d after rejected changes, want 1", replication)
+	}
+}
+
+func TestSetReplicationConverges(t *testing.T) {
+	s := newTestNameNode(t, 1, "object")
+	nodes := []*fakeDataNode{registerFake(t, s, 0, "object"), registerFake(t, s, 1), registerFake(t, s, 2)}
+
+	for _, replication := range []int{3, 1, 2} {
+		res, err := s.SetReplication(context.Background(), &api.SetReplicationReq{Name: "object", Replication: uint32(replication)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Meta.Status != api.ResponseMeta_UPDATED {
+			t.Errorf("status = %s", res.Meta.Status)
+		}
+		eventually(t, func() bool {
+			converging := true
+			s.Transactional(func() {
+				converging = s.converging["object"]
+			})
+			return !converging && len(replicas(s, "object")) == replication
+		})
+		got := replicas(s, "object")
+		for _, node := range nodes {
+			if slices.Contains(got, node.id) != node.holds("object") {
+				t.Errorf("replicas = %v, but %s holding it is %v", got, node.id, node.holds("object"))
+			}
+		}
+	}
+}
+
+func TestExcessReplica(t *testing.T) {
+	tests := []struct {
+		name        string
+		nodes       []*MetaHeapEntry
+		replicas    []string
+		constraints *PlacementConstraints
+		want        string
+	}{
+		{
+			name:     "the fullest node",
+			nodes:    []*MetaHeapEntry{{Id: "a", Size: 1}, {Id: "b", Size: 3}, {Id: "c", Size: 2}},
+			replicas: []string{"a", "b", "c"},
+			want:     "b",
+		},
+		{
+			name:     "a node in maintenance",
+			nodes:    []*MetaHeapEntry{{Id: "a", Size: 1, State: NodeMaintenance}, {Id: "b", Size: 3}},
+			replicas: []string{"a", "b"},
+			want:     "a",
+		},
+		{
+			name:        "a node the constraints rule out",
+			nodes:       []*MetaHeapEntry{{Id: "a", Size: 1}, {Id: "b", Size: 3, Labels: labels("disk", "ssd")}},
+			replicas:    []string{"a", "b"},
+			constraints: &PlacementConstraints{Required: labels("disk", "ssd")},
+			want:        "a",
+		},
+		{
+			name:     "keeping the spread",
+			nodes:    []*MetaHeapEntry{{Id: "a", Size: 1, Zone: "z1"}, {Id: "b", Size: 2, Zone: "z1"}, {Id: "c", Size: 3, Zone: "z2"}},
+			replicas: []string{"a", "b", "c"},
+			want:     "b",
+		},
+		{
+			name:        "keeping a preferred label",
+			nodes:       []*MetaHeapEntry{{Id: "a", Size: 1}, {Id: "b", Size: 3, Labels: labels("tier", "fast")}},
+			replicas:    []string{"a", "b"},
+			constraints: &PlacementConstraints{Preferred: labels("tier", "fast")},
+			want:        "a",
+		},
+		{
+			name:     "never a ghost",
+			nodes:    []*MetaHeapEntry{{Id: "a", Size: 1}},
+			replicas: []string{"a", "ghost"},
+			want:     "a",
+		},
+		{
+			name:     "only ghosts",
+			replicas: []string{"ghost"},
+			want:     "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestNameNode(t, 1)
+			layout(t, s, tt.nodes, []testObject{{name: "object", nodes: tt.replicas, constraints: tt.constraints}})
+
+			var got string
+			s.Transactional(func() {
+				got = s.excessReplica("object", tt.replicas)
+			})
+			if got != tt.want {
+				t.Errorf("excessReplica = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package namenode
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mrowaha/dos/api"
+	"google.golang.org/grpc"
+)
+
+/**
+	this file creates the namenode side session of a connected datanode
+	a session is an actor that owns the datanode's stream. it is the only writer of the stream,
+	commands are queued in the order they are requested and drained in batches that go out as one frame,
+	and acks coming back from the datanode are matched against its pending table by request id
+	request ids come from one namenode wide counter, so an ack that outlived its session can not be
+	mistaken for the ack of a newer command
+**/
+
+var (
+	ErrSessionClosed = errors.New("datanode session is closed")
+)
+
+// the most commands the session writes in one turn before looking at its queue again
+const maxSessionBatch = 64
+
+type DataNodeSession struct {
+	id     string
+	stream grpc.BidiStreamingServer[api.NodeHeartBeat, api.CommandNodeRes]
+	mux    *DataNodeCommandMux
+	logger *log.Logger
+	ids    *atomic.Uint64
+	fence  FencingToken // stamped on every command, along with its epoch
+
+	lock    sync.Mutex
+	queue   []CommandNode
+	pending map[uint64]chan Ack
+
+	wake      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewDataNodeSession(id string, stream grpc.BidiStreamingServer[api.NodeHeartBeat, api.CommandNodeRes], ids *atomic.Uint64, fence FencingToken, logger *log.Logger) *DataNodeSession {
+	return &DataNodeSession{
+		id:      id,
+		stream:  stream,
+		mux:     NewDataNodeCommandMux(logger),
+		logger:  logger,
+		ids:     ids,
+		fence:   fence,
+		queue:   make([]CommandNode, 0),
+		pending: make(map[uint64]chan Ack),
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+func (ds *DataNodeSession) Id() string {
+	return ds.id
+}
+
+// the channel is closed once the datanode is gone
+func (ds *DataNodeSession) Done() <-chan struct{} {
+	return ds.done
+}
+
+// Closed tells whether the datanode is gone
+func (ds *DataNodeSession) Closed() bool {
+	select {
+	case <-ds.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Request assigns the command a request id and queues it behind everything queued before it
+// the returned channel receives the datanode's ack for that id
+func (ds *DataNodeSession) Request(cmd CommandNode) (uint64, <-chan Ack, error) {
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	select {
+	case <-ds.done:
+		return 0, nil, ErrSessionClosed
+	default:
+	}
+
+	// assigned under the session lock, so ids increase in the order commands are sent
+	cmd.id = ds.ids.Add(1)
+	ackCh := make(chan Ack, 1) // resolving never blocks the receiving side
+	ds.pending[cmd.id] = ackCh
+	ds.queue = append(ds.queue, cmd)
+	select {
+	case ds.wake <- struct{}{}:
+	default:
+		// the writer has already been woken up
+	}
+	return cmd.id, ackCh, nil
+}
+
+// Resolve hands an ack to whoever waits on the request id
+// every request is answered once, so duplicate and late acks are dropped and false is returned
+func (ds *DataNodeSession) Resolve(id uint64, res Ack) bool {
+	ds.lock.Lock()
+	ackCh, ok := ds.pending[id]
+	delete(ds.pending, id)
+	ds.lock.Unlock()
+	if !ok {
+		if id == 0 || id > ds.ids.Load() {
+			ds.logger.Printf("[datanode %s] dropping ack for unknown request %d", ds.id, id)
+		} else {
+			ds.logger.Printf("[datanode %s] dropping duplicate or late ack for request %d", ds.id, id)
+		}
+		return false
+	}
+	ackCh <- res
+	return true
+}
+
+// Backlog counts the commands waiting to be sent and the ones sent and waiting on an ack
+func (ds *DataNodeSession) Backlog() (queued int, awaiting int) {
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	queued = len(ds.queue)
+	return queued, len(ds.pending) - queued
+}
+
+func (ds *DataNodeSession) forget(id uint64) {
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	delete(ds.pending, id)
+}
+
+// Close stops the writer and releases everyone waiting on an ack
+func (ds *DataNodeSession) Close() {
+	ds.closeOnce.Do(func() {
+		ds.lock.Lock()
+		defer ds.lock.Unlock()
+		close(ds.done)
+		ds.queue = nil
+		ds.pending = make(map[uint64]chan Ack)
+	})
+}
+
+// Run writes queued commands to the stream until the session is closed or the stream fails
+func (ds *DataNodeSession) Run() error {
+	for {
+		select {
+		case <-ds.wake:
+		case <-ds.done:
+			return ErrSessionClosed
+		case <-ds.stream.Context().Done():
+			return ds.stream.Context().Err()
+		}
+
+		for batch := ds.next(); len(batch) != 0; batch = ds.next() {
+			if err := ds.send(batch); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// next takes the oldest commands off the queue
+func (ds *DataNodeSession) next() []CommandNode {
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	n := min(len(ds.queue), maxSessionBatch)
+	batch := ds.queue[:n:n]
+	ds.queue = ds.queue[n:]
+	return batch
+}
+
+// send writes a batch as a single frame, the datanode applies it in one transaction
+func (ds *DataNodeSession) send(batch []CommandNode) error {
+	frame := make([]*api.CommandNodeRes, 0, len(batch))
+	for i := range batch {
+		apicmd := ds.mux.Encode(&batch[i])
+		if apicmd == nil {
+			ds.logger.Printf("[datanode %s] dropping command %s with no wire form", ds.id, batch[i].command)
+			continue
+		}
+		apicmd.Epoch = ds.fence.Epoch()
+		apicmd.Fence = uint64(ds.fence)
+		frame = append(frame, apicmd)
+	}
+
+	switch len(frame) {
+	case 0:
+		return nil
+	case 1:
+		return ds.stream.Send(frame[0])
+	}
+	ds.logger.Printf("[datanode %s] sending a batch of %d commands", ds.id, len(frame))
+	return ds.stream.Send(&api.CommandNodeRes{
+		Command: api.CommandNodeRes_BATCH,
+		Batch:   frame,
+		Epoch:   ds.fence.Epoch(),
+		Fence:   uint64(ds.fence),
+	})
+}
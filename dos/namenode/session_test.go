@@ -0,0 +1,155 @@
+package namenode
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mrowaha/dos/api"
+	"google.golang.org/grpc"
+)
+
+// recordingStream keeps the frames a session writes
+type recordingStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	mu     sync.Mutex
+	frames []*api.CommandNodeRes
+}
+
+func (r *recordingStream) Context() context.Context { return r.ctx }
+
+func (r *recordingStream) Recv() (*api.NodeHeartBeat, error) {
+	<-r.ctx.Done()
+	return nil, io.EOF
+}
+
+func (r *recordingStream) Send(cmd *api.CommandNodeRes) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.frames = append(r.frames, cmd)
+	return nil
+}
+
+// sent returns the sizes of the frames written so far, a single command is a frame of one
+func (r *recordingStream) sent() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sizes := make([]int, 0, len(r.frames))
+	for _, frame := range r.frames {
+		if frame.Command == api.CommandNodeRes_BATCH {
+			sizes = append(sizes, len(frame.Batch))
+		} else {
+			sizes = append(sizes, 1)
+		}
+	}
+	return sizes
+}
+
+func newTestSession(t *testing.T) (*DataNodeSession, *recordingStream) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	stream := &recordingStream{ctx: ctx}
+	fence := FencingToken(NewLamport(3, 1))
+	return NewDataNodeSession("node-0", stream, &atomic.Uint64{}, fence, log.New(io.Discard, "", 0)), stream
+}
+
+func TestSessionFrames(t *testing.T) {
+	tests := []struct {
+		name   string
+		queued int
+		frames []int
+	}{
+		{name: "single command", queued: 1, frames: []int{1}},
+		{name: "queued together", queued: 5, frames: []int{5}},
+		{name: "more than a frame holds", queued: maxSessionBatch + 6, frames: []int{maxSessionBatch, 6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, stream := newTestSession(t)
+			var last uint64
+			for i := range tt.queued {
+				id, _, err := session.Request(deleteAt(NewLamport(3, uint32(i+1))))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if id <= last {
+					t.Fatalf("request id %d after %d", id, last)
+				}
+				last = id
+			}
+			if queued, _ := session.Backlog(); queued != tt.queued {
+				t.Errorf("backlog = %d, want %d", queued, tt.queued)
+			}
+
+			done := make(chan error)
+			go func() { done <- session.Run() }()
+			eventually(t, func() bool { return len(stream.sent()) == len(tt.frames) })
+			session.Close()
+			if err := <-done; !errors.Is(err, ErrSessionClosed) {
+				t.Errorf("run ended with %v", err)
+			}
+
+			got := stream.sent()
+			for i := range tt.frames {
+				if got[i] != tt.frames[i] {
+					t.Fatalf("frames = %v, want %v", got, tt.frames)
+				}
+			}
+			// every command carries the session's token and epoch, in the order it was queued
+			var next uint64 = 1
+			for _, frame := range stream.frames {
+				commands := frame.Batch
+				if frame.Command != api.CommandNodeRes_BATCH {
+					commands = []*api.CommandNodeRes{frame}
+				}
+				for _, cmd := range commands {
+					if cmd.RequestId != next || cmd.Fence != uint64(session.fence) || cmd.Epoch != 3 {
+						t.Fatalf("command %d sent as %d with fence %d epoch %d", next, cmd.RequestId, cmd.Fence, cmd.Epoch)
+					}
+					next++
+				}
+			}
+		})
+	}
+}
+
+func TestSessionResolve(t *testing.T) {
+	session, _ := newTestSession(t)
+	id, ackCh, err := session.Request(deleteAt(NewLamport(3, 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		id   uint64
+		want bool
+	}{
+		{name: "ack", id: id, want: true},
+		{name: "duplicate ack", id: id, want: false},
+		{name: "unknown request", id: id + 10, want: false},
+		{name: "no request id", id: 0, want: false},
+	}
+	for _, tt := range tests {
+		if got := session.Resolve(tt.id, Ack{}); got != tt.want {
+			t.Errorf("%s: resolved = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if ack := <-ackCh; !ack.Ok() {
+		t.Errorf("ack = %v", ack)
+	}
+
+	session.Close()
+	if !session.Closed() {
+		t.Error("session is not closed")
+	}
+	if _, _, err := session.Request(deleteAt(NewLamport(3, 2))); !errors.Is(err, ErrSessionClosed) {
+		t.Errorf("request on a closed session: %v", err)
+	}
+}
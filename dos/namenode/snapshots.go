@@ -0,0 +1,152 @@
+package namenode
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mrowaha/dos/api"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+/**
+	this file contains the snapshot service of the name node
+	a snapshot is a lamport cut over the broadcast order. datanodes freeze a copy-on-write
+	view of their store when they deliver the snapshot command, so every replica agrees on
+	the exact set of commands that precede the snapshot
+
+	the snapshots that were taken are kept in the snapshot file, one per line as "<lamport> <name>",
+	so a restarted namenode still serves them. a snapshot is only readable once every datanode has it
+	names are checked before anything is taken, a name must fit on that line and name a dir on the datanodes
+**/
+
+var (
+	ErrSnapshotAlreadyExists = errors.New("snapshot already exists")
+	ErrSnapshotDoesNotExist  = errors.New("snapshot does not exist")
+	ErrSnapshotInProgress    = errors.New("snapshot is still being taken")
+	ErrLoadSnapshots         = errors.New("failed to load snapshots")
+	ErrInvalidSnapshotName   = errors.New("invalid snapshot name")
+)
+
+type SnapshotEntry struct {
+	Name    string
+	Lamport Lamport // zero while the snapshot broadcast is in flight
+}
+
+type SnapshotsMap map[string]*SnapshotEntry
+
+// loadSnapshots reads the snapshot file, a missing file is a cluster without snapshots
+func loadSnapshots(snapshotFile string) (SnapshotsMap, error) {
+	snapshots := make(SnapshotsMap)
+	f, err := os.Open(snapshotFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return snapshots, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrLoadSnapshots, err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lamport, name, found := strings.Cut(scanner.Text(), " ")
+		value, err := strconv.ParseUint(lamport, 10, 64)
+		if !found || err != nil || value == 0 {
+			return nil, fmt.Errorf("%w: bad line %q", ErrLoadSnapshots, scanner.Text())
+		}
+		snapshots[name] = &SnapshotEntry{Name: name, Lamport: Lamport(value)}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrLoadSnapshots, err)
+	}
+	return snapshots, nil
+}
+
+// saveSnapshots writes the snapshots that were taken to the snapshot file, called under the global lock
+// the snapshot already reached the datanodes, a failed save is logged and the next change saves it again
+func (s *DosNameNodeServer) saveSnapshots() {
+	var buf bytes.Buffer
+	for _, entry := range s.snapshots {
+		if entry.Lamport != 0 {
+			fmt.Fprintf(&buf, "%d %s\n", uint64(entry.Lamport), entry.Name)
+		}
+	}
+	if err := writeFileAtomic(s.config.SnapshotFile, buf.Bytes()); err != nil {
+		s.logger.Printf("failed to save the snapshots: %v", err)
+	}
+}
+
+// validSnapshotName rejects empty and dot-only names, and names with a slash or control characters
+func validSnapshotName(name string) bool {
+	if len(name) == 0 || name == "." || name == ".." || strings.ContainsRune(name, '/') {
+		return false
+	}
+	return !strings.ContainsFunc(name, unicode.IsControl)
+}
+
+func (s *DosNameNodeServer) CreateSnapshot(ctx context.Context, req *api.CreateSnapshotReq) (*api.CreateSnapshotRes, error) {
+	s.logger.Printf("attempting request [snapshot %q]\n", req.Name)
+	if !validSnapshotName(req.Name) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSnapshotName, req.Name)
+	}
+
+	var transactionErr error
+	entry := &SnapshotEntry{Name: req.Name}
+	s.Transactional(func() {
+		if _, ok := s.snapshots[req.Name]; ok {
+			transactionErr = ErrSnapshotAlreadyExists
+			return
+		}
+		s.snapshots[req.Name] = entry
+	})
+
+	if transactionErr != nil {
+		return nil, transactionErr
+	}
+
+	lamport := s.BroadcastSnapshot(req.Name, SNAPSHOT)
+	s.Transactional(func() {
+		entry.Lamport = lamport
+		s.saveSnapshots()
+	})
+	s.logger.Printf("created snapshot [%s] @lamport%s", req.Name, lamport)
+	return &api.CreateSnapshotRes{
+		Meta:    &api.ResponseMeta{Ts: timestamppb.Now(), Status: api.ResponseMeta_SNAPSHOTTED},
+		Lamport: uint64(lamport),
+	}, nil
+}
+
+func (s *DosNameNodeServer) DeleteSnapshot(ctx context.Context, req *api.DeleteSnapshotReq) (*api.DeleteSnapshotRes, error) {
+	s.logger.Printf("attempting request [delete snapshot %s]\n", req.Name)
+
+	var transactionErr error
+	s.Transactional(func() {
+		entry, ok := s.snapshots[req.Name]
+		if !ok {
+			transactionErr = ErrSnapshotDoesNotExist
+			return
+		}
+		if entry.Lamport == 0 {
+			// its broadcast could be ordered after the delete
+			transactionErr = ErrSnapshotInProgress
+			return
+		}
+		delete(s.snapshots, req.Name)
+		s.saveSnapshots()
+	})
+
+	if transactionErr != nil {
+		return nil, transactionErr
+	}
+
+	s.BroadcastSnapshot(req.Name, DELETESNAPSHOT)
+	s.logger.Printf("deleted snapshot [%s]", req.Name)
+	return &api.DeleteSnapshotRes{
+		Meta: &api.ResponseMeta{Ts: timestamppb.Now(), Status: api.ResponseMeta_DELETED},
+	}, nil
+}
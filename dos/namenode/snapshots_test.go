@@ -0,0 +1,183 @@
+package namenode
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrowaha/dos/api"
+)
+
+func TestLoadSnapshots(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    *string // nil for a missing file
+		want    map[string]Lamport
+		wantErr bool
+	}{
+		{name: "missing file", data: nil, want: map[string]Lamport{}},
+		{name: "empty file", data: ptr(""), want: map[string]Lamport{}},
+		{
+			name: "snapshots",
+			data: ptr("4294967298 nightly\n8589934593 before upgrade\n"),
+			want: map[string]Lamport{"nightly": NewLamport(1, 2), "before upgrade": NewLamport(2, 1)},
+		},
+		{name: "no name", data: ptr("12\n"), wantErr: true},
+		{name: "bad lamport", data: ptr("x nightly\n"), wantErr: true},
+		{name: "in flight", data: ptr("0 nightly\n"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "snapshots")
+			if tt.data != nil {
+				if err := os.WriteFile(path, []byte(*tt.data), 0666); err != nil {
+					t.Fatal(err)
+				}
+			}
+			snapshots, err := loadSnapshots(path)
+			if tt.wantErr {
+				if !errors.Is(err, ErrLoadSnapshots) {
+					t.Fatalf("err = %v, want %v", err, ErrLoadSnapshots)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(snapshots) != len(tt.want) {
+				t.Fatalf("snapshots = %v, want %v", snapshots, tt.want)
+			}
+			for name, lamport := range tt.want {
+				if entry := snapshots[name]; entry == nil || entry.Name != name || entry.Lamport != lamport {
+					t.Errorf("snapshot %s = %v, want @lamport%s", name, entry, lamport)
+				}
+			}
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestSnapshotsSurviveRestart(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ns"), nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	s := openTestNameNode(t, dir, 1)
+	registerFake(t, s, 0)
+	ctx := context.Background()
+	for _, name := range []string{"kept", "deleted"} {
+		if _, err := s.CreateSnapshot(ctx, &api.CreateSnapshotReq{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.DeleteSnapshot(ctx, &api.DeleteSnapshotReq{Name: "deleted"}); err != nil {
+		t.Fatal(err)
+	}
+	var lamport Lamport
+	s.Transactional(func() {
+		lamport = s.snapshots["kept"].Lamport
+	})
+
+	restarted := openTestNameNode(t, dir, 1)
+	entry, ok := restarted.snapshots["kept"]
+	if !ok || entry.Lamport != lamport {
+		t.Errorf("kept = %v, want @lamport%s", entry, lamport)
+	}
+	if _, ok := restarted.snapshots["deleted"]; ok {
+		t.Error("a deleted snapshot came back")
+	}
+}
+
+func TestSnapshotInFlight(t *testing.T) {
+	s := newTestNameNode(t, 1)
+	// registered while its broadcast is still out
+	s.snapshots["taking"] = &SnapshotEntry{Name: "taking"}
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{
+			name: "read",
+			call: func() error {
+				_, err := s.GetObject(ctx, &api.GetObjectReq{Name: "object", Snapshot: "taking"})
+				return err
+			},
+			want: ErrSnapshotInProgress,
+		},
+		{
+			name: "delete",
+			call: func() error {
+				_, err := s.DeleteSnapshot(ctx, &api.DeleteSnapshotReq{Name: "taking"})
+				return err
+			},
+			want: ErrSnapshotInProgress,
+		},
+		{
+			name: "read an unknown snapshot",
+			call: func() error {
+				_, err := s.GetObject(ctx, &api.GetObjectReq{Name: "object", Snapshot: "missing"})
+				return err
+			},
+			want: ErrSnapshotDoesNotExist,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateSnapshotRejectsNames(t *testing.T) {
+	s := newTestNameNode(t, 1)
+	registerFake(t, s, 0)
+	tests := []struct {
+		name string
+		ok   bool
+	}{
+		{name: "", ok: false},
+		{name: ".", ok: false},
+		{name: "..", ok: false},
+		{name: "a/b", ok: false},
+		{name: "two\nlines", ok: false},
+		{name: "tab\there", ok: false},
+		{name: "nul\x00", ok: false},
+		{name: "before upgrade", ok: true},
+		{name: "...", ok: true},
+		{name: ".hidden", ok: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.CreateSnapshot(context.Background(), &api.CreateSnapshotReq{Name: tt.name})
+			if tt.ok && err != nil {
+				t.Errorf("err = %v, want the snapshot taken", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrInvalidSnapshotName) {
+				t.Errorf("err = %v, want %v", err, ErrInvalidSnapshotName)
+			}
+			var taken bool
+			s.Transactional(func() {
+				_, taken = s.snapshots[tt.name]
+			})
+			if taken != tt.ok {
+				t.Errorf("snapshot registered = %v, want %v", taken, tt.ok)
+			}
+		})
+	}
+
+	// what was taken loads back
+	snapshots, err := loadSnapshots(s.config.SnapshotFile)
+	if err != nil || len(snapshots) != 3 {
+		t.Errorf("loaded %v, %v, want the valid snapshots", snapshots, err)
+	}
+}
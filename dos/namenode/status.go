@@ -0,0 +1,104 @@
+package namenode
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+/**
+	namenode errors reach clients as grpc statuses
+	the code says what kind of failure it is, and an ErrorInfo detail carries the exact reason
+	in the namenode's error domain along with the name of the object or snapshot the call was about
+**/
+
+const ErrorDomain = "dos.namenode"
+
+// reasons of the ErrorInfo detail, stable across releases so clients can branch on them
+const (
+	ReasonObjectExists       = "OBJECT_ALREADY_EXISTS"
+	ReasonObjectNotFound     = "OBJECT_NOT_FOUND"
+	ReasonSnapshotExists     = "SNAPSHOT_ALREADY_EXISTS"
+	ReasonSnapshotNotFound   = "SNAPSHOT_NOT_FOUND"
+	ReasonSnapshotInProgress = "SNAPSHOT_IN_PROGRESS"
+	ReasonNotEnoughDataNodes = "NOT_ENOUGH_DATANODES"
+	ReasonReplicationFailed  = "REPLICATION_FAILED"
+	ReasonToleranceNotEnough = "TOLERANCE_NOT_ENOUGH"
+	ReasonKeyReused          = "IDEMPOTENCY_KEY_REUSED"
+	ReasonNodeNotFound       = "NODE_NOT_FOUND"
+	ReasonNodeState          = "NODE_STATE"
+	ReasonRebalanceRunning   = "REBALANCE_RUNNING"
+	ReasonInvalidReplication = "INVALID_REPLICATION"
+	ReasonInvalidSnapshot    = "INVALID_SNAPSHOT_NAME"
+	ReasonInternal           = "INTERNAL"
+)
+
+type errorStatus struct {
+	err    error
+	code   codes.Code
+	reason string
+}
+
+var errorStatuses = []errorStatus{
+	{ErrObjectAlreadyExists, codes.AlreadyExists, ReasonObjectExists},
+	{ErrObjectDoestNotExist, codes.NotFound, ReasonObjectNotFound},
+	{ErrSnapshotAlreadyExists, codes.AlreadyExists, ReasonSnapshotExists},
+	{ErrSnapshotDoesNotExist, codes.NotFound, ReasonSnapshotNotFound},
+	{ErrSnapshotInProgress, codes.FailedPrecondition, ReasonSnapshotInProgress},
+	{ErrNotEnoughDataNodes, codes.Unavailable, ReasonNotEnoughDataNodes},
+	{ErrFailedObjectReplication, codes.Unavailable, ReasonReplicationFailed},
+	{ErrToleranceNotEnough, codes.Unavailable, ReasonToleranceNotEnough},
+	{ErrIdempotencyKeyReused, codes.InvalidArgument, ReasonKeyReused},
+	{ErrDataNodeNotFound, codes.NotFound, ReasonNodeNotFound},
+	{ErrNodeInMaintenance, codes.FailedPrecondition, ReasonNodeState},
+	{ErrNodeDecommissioning, codes.FailedPrecondition, ReasonNodeState},
+	{ErrRebalanceRunning, codes.FailedPrecondition, ReasonRebalanceRunning},
+	{ErrInvalidReplication, codes.InvalidArgument, ReasonInvalidReplication},
+	{ErrInvalidSnapshotName, codes.InvalidArgument, ReasonInvalidSnapshot},
+}
+
+// toStatus converts an error of a namenode call into a grpc status error
+// req is the request of the call, its name ends up in the metadata of the detail
+func toStatus(err error, req interface{}) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		// already carries a status
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	code, reason := codes.Internal, ReasonInternal
+	for _, es := range errorStatuses {
+		if errors.Is(err, es.err) {
+			code, reason = es.code, es.reason
+			break
+		}
+	}
+
+	info := &errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: ErrorDomain,
+	}
+	if named, ok := req.(interface{ GetName() string }); ok {
+		info.Metadata = map[string]string{"name": named.GetName()}
+	}
+	st, detailErr := status.New(code, err.Error()).WithDetails(info)
+	if detailErr != nil {
+		return status.Error(code, err.Error())
+	}
+	return st.Err()
+}
+
+// statusInterceptor converts the errors of every unary call, so handlers keep returning plain errors
+func statusInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	res, err := handler(ctx, req)
+	return res, toStatus(err, req)
+}
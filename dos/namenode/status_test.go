@@ -0,0 +1,71 @@
+package namenode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mrowaha/dos/api"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		code   codes.Code
+		reason string // empty when the status carries no detail
+	}{
+		{name: "not found", err: ErrObjectDoestNotExist, code: codes.NotFound, reason: ReasonObjectNotFound},
+		{name: "wrapped", err: fmt.Errorf("get: %w", ErrSnapshotDoesNotExist), code: codes.NotFound, reason: ReasonSnapshotNotFound},
+		{name: "exists", err: ErrObjectAlreadyExists, code: codes.AlreadyExists, reason: ReasonObjectExists},
+		{name: "in flight", err: ErrSnapshotInProgress, code: codes.FailedPrecondition, reason: ReasonSnapshotInProgress},
+		{name: "no datanodes", err: ErrNotEnoughDataNodes, code: codes.Unavailable, reason: ReasonNotEnoughDataNodes},
+		{name: "key reused", err: ErrIdempotencyKeyReused, code: codes.InvalidArgument, reason: ReasonKeyReused},
+		{name: "snapshot name", err: fmt.Errorf("%w: %q", ErrInvalidSnapshotName, ".."), code: codes.InvalidArgument, reason: ReasonInvalidSnapshot},
+		{name: "node state", err: ErrNodeDecommissioning, code: codes.FailedPrecondition, reason: ReasonNodeState},
+		{name: "unknown", err: errors.New("boom"), code: codes.Internal, reason: ReasonInternal},
+		{name: "cancelled", err: context.Canceled, code: codes.Canceled},
+		{name: "deadline", err: fmt.Errorf("read: %w", context.DeadlineExceeded), code: codes.DeadlineExceeded},
+		{name: "already a status", err: status.Error(codes.PermissionDenied, "no"), code: codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := toStatus(tt.err, &api.GetObjectReq{Name: "object"})
+			st := status.Convert(err)
+			if st.Code() != tt.code {
+				t.Fatalf("code = %s, want %s", st.Code(), tt.code)
+			}
+			var info *errdetails.ErrorInfo
+			for _, detail := range st.Details() {
+				info, _ = detail.(*errdetails.ErrorInfo)
+			}
+			if len(tt.reason) == 0 {
+				if info != nil {
+					t.Errorf("detail = %v, want none", info)
+				}
+				return
+			}
+			if info == nil || info.Domain != ErrorDomain || info.Reason != tt.reason || info.Metadata["name"] != "object" {
+				t.Errorf("detail = %v, want %s of object", info, tt.reason)
+			}
+			if st.Message() != tt.err.Error() {
+				t.Errorf("message = %q, want %q", st.Message(), tt.err.Error())
+			}
+		})
+	}
+
+	if err := toStatus(nil, nil); err != nil {
+		t.Errorf("toStatus(nil) = %v", err)
+	}
+	// a request without a name sends no metadata
+	st := status.Convert(toStatus(ErrRebalanceRunning, &api.RebalanceReq{}))
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && len(info.Metadata) != 0 {
+			t.Errorf("metadata = %v, want none", info.Metadata)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package namenode
+
+import (
+	"cmp"
+	"slices"
+)
+
+/**
+	datanodes advertise the zone and rack they sit in when they register, these are their failure domains.
+	the replicas of an object are spread over as many zones as there are, and within them over as many racks,
+	so one rack or zone going down never takes every copy with it
+
+	spreading narrows the candidates handed to the placement policy: each replica goes to the zones holding
+	the fewest replicas of the object, and within those to the racks holding the fewest. the policy still picks
+	among what is left, and when there are fewer domains than replicas some domains hold more than one
+	nodes that advertise nothing share one unnamed domain, a cluster without labels places as before
+	ghosts advertise the domain they register in as well, the objects of a failed node are spread the same way
+**/
+
+// domain is the rack of a node, rack names are only unique within their zone
+type domain struct {
+	zone string
+	rack string
+}
+
+func (e *MetaHeapEntry) domain() domain {
+	return domain{zone: e.Zone, rack: e.Rack}
+}
+
+// placement of the replicas of one object over the failure domains
+type domains struct {
+	zones map[string]int
+	racks map[domain]int
+}
+
+// placedOn counts the replicas on nodes in each zone and rack, called under the global lock
+// replicas on nodes that are not registered, like ghosts, are in no domain
+func (s *DosNameNodeServer) placedOn(nodes []string) *domains {
+	d := &domains{
+		zones: make(map[string]int),
+		racks: make(map[domain]int),
+	}
+	for _, node := range nodes {
+		if entry := s.meta.Get(node); entry != nil {
+			d.add(entry)
+		}
+	}
+	return d
+}
+
+func (d *domains) add(entry *MetaHeapEntry) {
+	d.zones[entry.Zone]++
+	d.racks[entry.domain()]++
+}
+
+// compare orders placements by the zones they span, then by the racks
+func (d *domains) compare(other *domains) int {
+	if c := cmp.Compare(len(d.zones), len(other.zones)); c != 0 {
+		return c
+	}
+	return cmp.Compare(len(d.racks), len(other.racks))
+}
+
+// spreadOut keeps the candidates in the zones with the fewest replicas, and of those the ones in the racks with the fewest
+func (d *domains) spreadOut(candidates []*MetaHeapEntry) []*MetaHeapEntry {
+	fewest := func(count func(entry *MetaHeapEntry) int, entries []*MetaHeapEntry) []*MetaHeapEntry {
+		least := -1
+		for _, entry := range entries {
+			if n := count(entry); least == -1 || n < least {
+				least = n
+			}
+		}
+		return slices.DeleteFunc(slices.Clone(entries), func(entry *MetaHeapEntry) bool {
+			return count(entry) != least
+		})
+	}
+	inZones := fewest(func(entry *MetaHeapEntry) int { return d.zones[entry.Zone] }, candidates)
+	return fewest(func(entry *MetaHeapEntry) int { return d.racks[entry.domain()] }, inZones)
+}
+
+// keepsSpread tells whether moving the replica on from to the node to leaves the object over as many domains
+func (s *DosNameNodeServer) keepsSpread(nodes []string, from string, to *MetaHeapEntry) bool {
+	after := s.placedOn(slices.DeleteFunc(slices.Clone(nodes), func(node string) bool {
+		return node == from
+	}))
+	after.add(to)
+	return after.compare(s.placedOn(nodes)) >= 0
+}
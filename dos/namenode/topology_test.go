@@ -0,0 +1,108 @@
+package namenode
+
+import (
+	"slices"
+	"testing"
+)
+
+// cluster of two zones, z1 with two racks and z2 with one, and a node that advertised nothing
+func topology() []*MetaHeapEntry {
+	return []*MetaHeapEntry{
+		{Id: "a", Size: 1, Zone: "z1", Rack: "r1"},
+		{Id: "b", Size: 2, Zone: "z1", Rack: "r1"},
+		{Id: "c", Size: 3, Zone: "z1", Rack: "r2"},
+		{Id: "d", Size: 4, Zone: "z2", Rack: "r1"},
+		{Id: "e", Size: 5},
+	}
+}
+
+func TestSpreadOut(t *testing.T) {
+	tests := []struct {
+		name     string
+		replicas []string
+		want     []string
+	}{
+		{name: "no replicas yet", want: []string{"a", "b", "c", "d", "e"}},
+		{name: "one in z1", replicas: []string{"a"}, want: []string{"d", "e"}},
+		{name: "one in each zone", replicas: []string{"a", "d", "e"}, want: []string{"c"}},
+		{name: "every rack taken", replicas: []string{"a", "c", "d", "e"}, want: []string{"d", "e"}},
+		{name: "on a node that left", replicas: []string{"ghost"}, want: []string{"a", "b", "c", "d", "e"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestNameNode(t, 1)
+			nodes := topology()
+			layout(t, s, nodes, nil)
+
+			var got []string
+			s.Transactional(func() {
+				got = ids(s.placedOn(tt.replicas).spreadOut(nodes))
+			})
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("spreadOut = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeepsSpread(t *testing.T) {
+	tests := []struct {
+		name     string
+		replicas []string
+		from, to string
+		want     bool
+	}{
+		{name: "within a rack", replicas: []string{"a", "d"}, from: "a", to: "b", want: true},
+		{name: "to another rack", replicas: []string{"a", "b"}, from: "a", to: "c", want: true},
+		{name: "to another zone", replicas: []string{"a", "c"}, from: "a", to: "d", want: true},
+		{name: "into the zone of another replica", replicas: []string{"a", "d"}, from: "a", to: "e", want: true},
+		{name: "into the rack of another replica", replicas: []string{"a", "c"}, from: "c", to: "b", want: false},
+		{name: "out of the only replica's zone", replicas: []string{"a", "d"}, from: "d", to: "c", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestNameNode(t, 1)
+			layout(t, s, topology(), nil)
+
+			var got bool
+			s.Transactional(func() {
+				got = s.keepsSpread(tt.replicas, tt.from, s.meta.Get(tt.to))
+			})
+			if got != tt.want {
+				t.Errorf("keepsSpread = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPickSpreadsReplicas(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int
+		replicas []string
+		want     []string
+	}{
+		// least used picks a, then the least used of the other zones, then the other rack of z1
+		{name: "new object", n: 4, want: []string{"a", "d", "e", "c"}},
+		{name: "more replicas than domains", n: 5, want: []string{"a", "d", "e", "c", "b"}},
+		{name: "re-replicating", n: 1, replicas: []string{"a", "d"}, want: []string{"e"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestNameNode(t, 1)
+			layout(t, s, topology(), nil)
+			skip := make(map[string]bool)
+			for _, node := range tt.replicas {
+				skip[node] = true
+			}
+
+			var picked []*MetaHeapEntry
+			s.Transactional(func() {
+				picked = s.pick(tt.n, nil, tt.replicas, skip)
+			})
+			if got := ids(picked); !slices.Equal(got, tt.want) {
+				t.Errorf("picked %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,16 +5,28 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"time"
 
 	dos "github.com/mrowaha/dos/namenode"
 )
 
 var (
-	port      int
-	logfile   string
-	nsFile    string
-	repl      int
-	tolerance int
+	port        int
+	logfile     string
+	nsFile      string
+	repl        int
+	tolerance   int
+	epochFile   string
+	clusterFile string
+	snapFile    string
+	joinToken   string
+	mwindow     time.Duration
+	mbacklog    int
+	cbacklog    int
+	rinterval   time.Duration
+	rthreshold  float64
+	rbandwidth  int
+	placement   string
 )
 
 func main() {
@@ -23,8 +35,24 @@ func main() {
 	flag.StringVar(&nsFile, "nsfile", "namenode-ns.txt", "flat namespace file pth")
 	flag.IntVar(&repl, "repl", 2, "replication factor")
 	flag.IntVar(&tolerance, "tol", 1, "tolerance factor")
+	flag.StringVar(&epochFile, "epochfile", "namenode-epoch", "cluster epoch file path")
+	flag.StringVar(&clusterFile, "clusterfile", "namenode-cluster", "cluster id file path")
+	flag.StringVar(&snapFile, "snapshotfile", "namenode-snapshots", "snapshot registry file path")
+	flag.StringVar(&joinToken, "token", "", "join token datanodes must present to register")
+	flag.DurationVar(&mwindow, "maintenance-window", 10*time.Minute, "default length of a datanode maintenance window")
+	flag.IntVar(&mbacklog, "maintenance-backlog", 10000, "most commands buffered for a datanode away for maintenance")
+	flag.IntVar(&cbacklog, "catchup-backlog", 1000, "recent commands kept for datanodes that reconnect after missing them")
+	flag.DurationVar(&rinterval, "rebalance-interval", 5*time.Minute, "time between rebalance rounds, 0 turns the rebalancer off")
+	flag.Float64Var(&rthreshold, "rebalance-threshold", 0.1, "fraction of the mean size a datanode may be off by before objects are moved")
+	flag.IntVar(&rbandwidth, "rebalance-bandwidth", 8<<20, "bytes a second the rebalancer may copy, 0 for no limit")
+	flag.StringVar(&placement, "placement", dos.PlaceLeastUsed, "placement policy of new replicas: least-used, random, round-robin, two-choices or weighted")
 	flag.Parse()
 
+	policy, err := dos.NewPlacementPolicy(placement)
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
@@ -32,7 +60,7 @@ func main() {
 	defer listener.Close()
 
 	log.Printf("listening on port %d", port)
-	service, err := dos.NewDosNameNodeServer(logfile, nsFile, dos.WithReplication(repl), dos.WithTolerance(tolerance))
+	service, err := dos.NewDosNameNodeServer(logfile, nsFile, dos.WithReplication(repl), dos.WithTolerance(tolerance), dos.WithEpochFile(epochFile), dos.WithClusterFile(clusterFile), dos.WithSnapshotFile(snapFile), dos.WithJoinToken(joinToken), dos.WithMaintenance(mwindow, mbacklog), dos.WithCatchUp(cbacklog), dos.WithRebalance(rinterval, rthreshold, rbandwidth), dos.WithPlacement(policy))
 	if err != nil {
 		log.Fatalln(err.Error())
 	}